@@ -112,14 +112,55 @@ func getBaseOptions(t *testing.T) integration.ProgramTestOptions {
 }
 
 func getConfigsAndSecrets(t *testing.T) (map[string]string, map[string]string) {
+	values, err := readEnvFile(filepath.Join(getCwd(t), ".env"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Skipf("Skipping test due failure on reading .env file! Err: %s", err)
+		return nil, nil
+	}
+
+	// The environment variables take precedence over the .env file, so the CI
+	// can point the examples to the ESXi simulator.
+	for _, key := range []string{"ESXI_HOST", "ESXI_USERNAME", "ESXI_PASSWORD", "ESXI_SSH_PORT", "ESXI_SSL_PORT", "ESXI_HOST_KEY_FINGERPRINT"} {
+		if value, ok := os.LookupEnv(key); ok {
+			values[key] = value
+		}
+	}
+	if len(values["ESXI_HOST"]) == 0 {
+		t.Skipf("Skipping test, set ESXI_HOST in the .env file or the environment")
+		return nil, nil
+	}
+
 	configs := make(map[string]string)
 	secrets := make(map[string]string)
+	for key, value := range values {
+		switch key {
+		case "ESXI_HOST":
+			configs["esxi-native:config:host"] = value
+		case "ESXI_USERNAME":
+			configs["esxi-native:config:username"] = value
+		case "ESXI_PASSWORD":
+			secrets["esxi-native:config:password"] = value
+		case "ESXI_SSH_PORT":
+			configs["esxi-native:config:sshPort"] = value
+		case "ESXI_SSL_PORT":
+			configs["esxi-native:config:sslPort"] = value
+		case "ESXI_HOST_KEY_FINGERPRINT":
+			configs["esxi-native:config:hostKeyFingerprint"] = value
+		}
+	}
+
+	return configs, secrets
+}
+
+// readEnvFile reads the key-value pairs of the .env file, an empty map is
+// returned with the error when the file can't be read.
+func readEnvFile(path string) (map[string]string, error) {
+	values := make(map[string]string)
 
 	// Open the .env file
-	file, err := os.Open(filepath.Join(getCwd(t), ".env"))
+	file, err := os.Open(path)
 	if err != nil {
-		t.Skipf("Skipping test due failure on reading .env file! Err: %s", err)
-		return nil, nil
+		return values, err
 	}
 	defer func(file *os.File) {
 		e := file.Close()
@@ -144,29 +185,10 @@ func getConfigsAndSecrets(t *testing.T) (map[string]string, map[string]string) {
 			continue
 		}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		switch key {
-		case "ESXI_HOST":
-			configs["esxi-native:config:host"] = value
-		case "ESXI_USERNAME":
-			configs["esxi-native:config:username"] = value
-		case "ESXI_PASSWORD":
-			secrets["esxi-native:config:password"] = value
-		case "ESXI_SSH_PORT":
-			configs["esxi-native:config:sshPort"] = value
-		case "ESXI_SSL_PORT":
-			configs["esxi-native:config:sslPort"] = value
-		}
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 	}
 
-	if err = scanner.Err(); err != nil {
-		t.Skipf("Skipping test due failure on reading .env file! Err: %s", err)
-		return nil, nil
-	}
-
-	return configs, secrets
+	return values, scanner.Err()
 }
 
 func getCwd(t *testing.T) string {
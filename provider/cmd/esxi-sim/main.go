@@ -0,0 +1,133 @@
+// Command esxi-sim serves an in-memory esxi host over ssh. It emulates the
+// shell commands, vim-cmd, esxcli and vmkfstools used by the provider, so the
+// examples and the pulumi up/refresh/destroy cycles can run without an esxi
+// host. The state is lost when the simulator exits.
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/simulator"
+)
+
+func main() {
+	var listen, username, hostKeyFile, authorizedKeysFile, diskStores, nics string
+	flag.StringVar(&listen, "listen", "127.0.0.1:2222", "the address the ssh server listens on")
+	flag.StringVar(&username, "username", "root", "the user name accepted by the ssh server")
+	flag.StringVar(&hostKeyFile, "host-key", "", "the private host key file, a new key is generated when empty")
+	flag.StringVar(&authorizedKeysFile, "authorized-keys", "", "an authorized_keys file with the public keys accepted for the user")
+	flag.StringVar(&diskStores, "datastores", "", "comma separated datastores to add to datastore1")
+	flag.StringVar(&nics, "nics", "", "comma separated physical nics to add to vmnic0 and vmnic1")
+	portForwarding := flag.Bool("port-forwarding", false, "accept the tcp port forwards, to act as the bastion of another simulator")
+	flag.Parse()
+
+	// The password is read from the environment to keep it out of the process list.
+	password := os.Getenv("ESXI_SIM_PASSWORD")
+
+	hostKey, err := loadHostKey(hostKeyFile)
+	if err != nil {
+		log.Fatalf("failed to load the host key: %s", err)
+	}
+	authorizedKeys, err := loadAuthorizedKeys(authorizedKeysFile)
+	if err != nil {
+		log.Fatalf("failed to load the authorized keys: %s", err)
+	}
+
+	fake := esxi.NewFakeExecutor()
+	for _, diskStore := range splitList(diskStores) {
+		fake.AddDiskStore(diskStore)
+	}
+	for _, nic := range splitList(nics) {
+		fake.AddPhysicalNic(nic)
+	}
+
+	server, err := simulator.NewServer(fake, simulator.Config{
+		UserName:       username,
+		Password:       password,
+		AuthorizedKeys: authorizedKeys,
+		HostKey:        hostKey,
+		PortForwarding: *portForwarding,
+	})
+	if err != nil {
+		log.Fatalf("failed to start the simulator: %s, set ESXI_SIM_PASSWORD or -authorized-keys", err)
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %s", listen, err)
+	}
+	fmt.Printf("esxi simulator listening on %s, host key fingerprint %s\n",
+		listener.Addr(), ssh.FingerprintSHA256(hostKey.PublicKey()))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		if closeErr := server.Close(); closeErr != nil {
+			log.Printf("failed to stop the simulator: %s", closeErr)
+		}
+	}()
+
+	if err = server.Serve(listener); err != nil {
+		log.Fatalf("simulator stopped: %s", err)
+	}
+}
+
+func loadHostKey(path string) (ssh.Signer, error) {
+	if len(path) == 0 {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return ssh.NewSignerFromKey(privateKey)
+	}
+
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(pemBytes)
+}
+
+func loadAuthorizedKeys(path string) ([]ssh.PublicKey, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []ssh.PublicKey
+	for len(strings.TrimSpace(string(content))) > 0 {
+		key, _, _, rest, err := ssh.ParseAuthorizedKey(content)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		content = rest
+	}
+	return keys, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -15,11 +15,19 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	dotnetgen "github.com/pulumi/pulumi/pkg/v3/codegen/dotnet"
 	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
@@ -162,9 +170,71 @@ func writeGoClient(pkg *schema.Package, outDir string) {
 		panic(err)
 	}
 
+	// The config module imports the package of the types of the config
+	// variables, even though their getters return strings, which doesn't compile.
+	for filename, contents := range files {
+		if strings.HasSuffix(filename, ".go") {
+			if files[filename], err = removeUnusedImports(filename, contents); err != nil {
+				panic(err)
+			}
+		}
+	}
+
 	mustWriteFiles(outDir, files)
 }
 
+// removeUnusedImports removes the imports of the Go source which none of its
+// selectors refers to.
+func removeUnusedImports(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	removed := false
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		specs := gen.Specs[:0]
+		for _, spec := range gen.Specs {
+			imported := spec.(*ast.ImportSpec)
+			importPath, _ := strconv.Unquote(imported.Path.Value)
+			name := path.Base(importPath)
+			if imported.Name != nil {
+				name = imported.Name.Name
+			}
+			if name == "_" || name == "." || used[name] {
+				specs = append(specs, spec)
+			} else {
+				removed = true
+			}
+		}
+		gen.Specs = specs
+	}
+	if !removed {
+		return src, nil
+	}
+
+	var buffer bytes.Buffer
+	if err = format.Node(&buffer, fset, file); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
 func mustWriteFiles(rootDir string, files map[string][]byte) {
 	for filename, contents := range files {
 		mustWriteFile(rootDir, filename, contents)
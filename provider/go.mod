@@ -5,11 +5,13 @@ go 1.20
 require (
 	github.com/golang/protobuf v1.5.3
 	github.com/jszwec/csvutil v1.8.0
+	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51
 	github.com/pkg/errors v0.9.1
 	github.com/pulumi/pulumi/pkg/v3 v3.77.1
 	github.com/pulumi/pulumi/sdk/v3 v3.77.1
 	github.com/stretchr/testify v1.8.4
 	github.com/tmc/scp v0.0.0-20170824174625-f7b48647feef
+	github.com/vmware/govmomi v0.30.6
 	golang.org/x/crypto v0.12.0
 	google.golang.org/grpc v1.57.0
 	google.golang.org/protobuf v1.31.0
@@ -128,7 +130,6 @@ require (
 	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
-	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51 // indirect
 	github.com/kevinburke/ssh_config v1.2.0 // indirect
 	github.com/kylelemons/godebug v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.12 // indirect
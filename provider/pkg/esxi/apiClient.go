@@ -0,0 +1,496 @@
+package esxi
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // the thumbprints of the api are SHA-1 sums
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/session/keepalive"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	volumesPath = "/vmfs/volumes/"
+
+	// apiKeepAliveInterval stays well below the idle session timeout of hostd,
+	// 30 minutes by default.
+	apiKeepAliveInterval = 5 * time.Minute
+)
+
+// CertificateMismatchError is returned when the tls certificate presented by
+// the ESXi host doesn't match the configured thumbprint.
+type CertificateMismatchError struct {
+	Host       string
+	Thumbprint string
+	Expected   string
+}
+
+func (e *CertificateMismatchError) Error() string {
+	return fmt.Sprintf("certificate verification failed for %s: the presented certificate %s does not match the sslThumbprint %s, "+
+		"the host certificate was renewed or the connection is being intercepted", e.Host, e.Thumbprint, e.Expected)
+}
+
+// errNoCertificateVerification is returned when the tls certificate of the api
+// transport isn't verified, the insecure mode accepting any certificate is an
+// explicit opt-in.
+var errNoCertificateVerification = errors.New("no certificate verification configured for the api transport, " +
+	"set sslThumbprint, or insecureSkipHostKeyVerification to accept any certificate")
+
+// IsSslThumbprint returns true for a SHA-1 or SHA-256 thumbprint, in hex with
+// or without colons, e.g. the SHA-1 thumbprint shown by the host client.
+func IsSslThumbprint(thumbprint string) bool {
+	digits := normalizeThumbprint(thumbprint)
+	if len(digits) != 2*sha256.Size && len(digits) != 2*sha1.Size {
+		return false
+	}
+	_, err := hex.DecodeString(digits)
+	return err == nil
+}
+
+func normalizeThumbprint(thumbprint string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(thumbprint), ":", ""))
+}
+
+// certificateThumbprint returns the thumbprint of the certificate with the
+// hash of the expected thumbprint, SHA-256 or SHA-1.
+func certificateThumbprint(cert *x509.Certificate, expected string) string {
+	if len(expected) == 2*sha256.Size {
+		sum := sha256.Sum256(cert.Raw)
+		return strings.ToUpper(hex.EncodeToString(sum[:]))
+	}
+	return normalizeThumbprint(soap.ThumbprintSHA1(cert))
+}
+
+// getTLSConfig builds the certificate verification of the api transport. The
+// certificates of the hosts are usually self-signed, they are verified against
+// the thumbprint. Without it the connection fails, unless the verification is
+// explicitly skipped.
+func (c *ConnectionInfo) getTLSConfig() (*tls.Config, error) {
+	if len(c.SslThumbprint) > 0 {
+		expected := normalizeThumbprint(c.SslThumbprint)
+		return &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // the chain isn't verified, the thumbprint pins the certificate
+			VerifyConnection: func(state tls.ConnectionState) error {
+				if len(state.PeerCertificates) == 0 {
+					return fmt.Errorf("%s presented no certificate", c.Host)
+				}
+				if presented := certificateThumbprint(state.PeerCertificates[0], expected); presented != expected {
+					return &CertificateMismatchError{Host: c.Host, Thumbprint: presented, Expected: expected}
+				}
+				return nil
+			},
+		}, nil
+	}
+	if c.InsecureSkipHostKeyVerification {
+		logging.V(logLevel).Infof("getTLSConfig: insecureSkipHostKeyVerification is set, the certificate of %s isn't verified", c.Host)
+		return &tls.Config{InsecureSkipVerify: true}, nil //nolint:gosec // explicitly skipped by the config
+	}
+	return nil, fmt.Errorf("%s: %w", c.Host, errNoCertificateVerification)
+}
+
+// apiClient manages the resources through the SOAP API of the host agent, it
+// allows managing the hosts on which ssh is disabled. The ids of the resources
+// are the same as with the ssh transport: the managed object ids of the
+// virtual machines and resource pools, the names of the virtual switches and
+// the /vmfs/volumes paths of the virtual disks.
+type apiClient struct {
+	client     *govmomi.Client
+	finder     *find.Finder
+	datacenter *object.Datacenter
+	host       *object.HostSystem
+}
+
+// sessionRoundTripper bounds the api calls running at once on the host, like
+// the sessions of the remote commands.
+type sessionRoundTripper struct {
+	soap.RoundTripper
+	sessions semaphore
+}
+
+func (rt sessionRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	release, err := rt.sessions.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return rt.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+// loginRoundTripper logs in again when the session expired, e.g. after the
+// host restarted or a keepalive was missed, and retries the call once.
+type loginRoundTripper struct {
+	soap.RoundTripper
+	login func(ctx context.Context) error
+	mutex sync.Mutex
+}
+
+func (rt *loginRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	err := rt.RoundTripper.RoundTrip(ctx, req, res)
+	if _, isLogin := req.(*methods.LoginBody); isLogin || !isNotAuthenticated(err) {
+		return err
+	}
+
+	logging.V(logLevel).Infof("RoundTrip: the api session expired, logging in again")
+	rt.mutex.Lock()
+	err = rt.login(ctx)
+	rt.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to log in again to the esxi host api: %w", err)
+	}
+	// The response holds the fault of the first call.
+	body := reflect.ValueOf(res).Elem()
+	body.Set(reflect.Zero(body.Type()))
+	return rt.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+func isNotAuthenticated(err error) bool {
+	if err == nil || !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.NotAuthenticated)
+	return ok
+}
+
+func newAPIClient(ctx context.Context, connection *ConnectionInfo, tunnel *bastion, sessions semaphore) (*apiClient, error) {
+	if len(connection.Password) == 0 {
+		return nil, fmt.Errorf("the api transport requires the password to be configured")
+	}
+	tlsConfig, err := connection.getTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &url.URL{
+		Scheme: "https",
+		Host:   net.JoinHostPort(connection.Host, connection.SslPort),
+		Path:   vim25.Path,
+		User:   url.UserPassword(connection.UserName, connection.Password),
+	}
+	soapClient := soap.NewClient(endpoint, false)
+	// The tls connections are dialed through the bastion when there is one,
+	// and verified with the config of the connection.
+	dialer := &net.Dialer{Timeout: sshDialTimeout}
+	dial := dialer.DialContext
+	if tunnel != nil {
+		dial = tunnel.DialContext
+	}
+	soapClient.DefaultTransport().DialTLSContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+
+	vimClient, err := vim25.NewClient(ctx, soapClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to esxi host api: %w", err)
+	}
+	// The keepalive pings the host while the session is idle, so it survives
+	// the idle timeout of hostd during the long runs.
+	keepAlive := keepalive.NewHandlerSOAP(soapClient, apiKeepAliveInterval, nil)
+	relogin := &loginRoundTripper{RoundTripper: keepAlive}
+	relogin.login = func(ctx context.Context) error {
+		_, err := methods.Login(ctx, keepAlive, &types.Login{
+			This:     *vimClient.ServiceContent.SessionManager,
+			UserName: connection.UserName,
+			Password: connection.Password,
+		})
+		return err
+	}
+	vimClient.RoundTripper = sessionRoundTripper{RoundTripper: relogin, sessions: sessions}
+	client := &govmomi.Client{
+		Client:         vimClient,
+		SessionManager: session.NewManager(vimClient),
+	}
+	if err = client.Login(ctx, endpoint.User); err != nil {
+		return nil, fmt.Errorf("failed to connect to esxi host api: %w", err)
+	}
+
+	api := &apiClient{
+		client: client,
+		finder: find.NewFinder(client.Client, true),
+	}
+	api.datacenter, err = api.finder.DefaultDatacenter(ctx)
+	if err == nil {
+		api.finder.SetDatacenter(api.datacenter)
+		api.host, err = api.finder.DefaultHostSystem(ctx)
+	}
+	if err != nil {
+		api.close()
+		return nil, fmt.Errorf("failed to find the esxi host: %w", err)
+	}
+
+	return api, nil
+}
+
+func (api *apiClient) close() {
+	if err := api.client.Logout(context.Background()); err != nil {
+		logging.V(logLevel).Infof("close: failed to log out of the esxi host api: %s", err)
+	}
+}
+
+func (api *apiClient) vimClient() *vim25.Client {
+	return api.client.Client
+}
+
+// waitTask waits for the completion of the task started by a method call.
+func waitTask(ctx context.Context, t *object.Task, err error) error {
+	if err != nil {
+		return err
+	}
+	return t.Wait(ctx)
+}
+
+func (api *apiClient) networkSystem(ctx context.Context) (*object.HostNetworkSystem, *types.HostNetworkInfo, error) {
+	networkSystem, err := api.host.ConfigManager().NetworkSystem(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the network system: %w", err)
+	}
+	var properties mo.HostNetworkSystem
+	err = networkSystem.Properties(ctx, networkSystem.Reference(), []string{"networkInfo"}, &properties)
+	if err != nil || properties.NetworkInfo == nil {
+		return nil, nil, fmt.Errorf("failed to get the network info: %w", err)
+	}
+	return networkSystem, properties.NetworkInfo, nil
+}
+
+func (api *apiClient) datastore(ctx context.Context, name string) (*object.Datastore, error) {
+	datastore, err := api.finder.Datastore(ctx, name)
+	if err != nil {
+		var available []string
+		if datastores, listErr := api.finder.DatastoreList(ctx, "*"); listErr == nil {
+			for _, ds := range datastores {
+				available = append(available, ds.Name())
+			}
+		}
+		return nil, fmt.Errorf("disk store %s does not exist; available disk stores: %s", name, strings.Join(available, " "))
+	}
+	return datastore, nil
+}
+
+// resourcePools returns the resource pools of the host, the root pool included.
+func (api *apiClient) resourcePools(ctx context.Context) ([]mo.ResourcePool, error) {
+	manager := view.NewManager(api.vimClient())
+	containerView, err := manager.CreateContainerView(ctx, api.vimClient().ServiceContent.RootFolder, []string{"ResourcePool"}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = containerView.Destroy(context.Background())
+	}()
+
+	var pools []mo.ResourcePool
+	err = containerView.Retrieve(ctx, []string{"ResourcePool"}, []string{"name", "parent", "config"}, &pools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the resource pools: %w", err)
+	}
+	return pools, nil
+}
+
+// resourcePoolPath returns the path of the pool below the root pool, "/" for the root pool.
+func resourcePoolPath(pools []mo.ResourcePool, id string) (string, bool) {
+	byId := make(map[string]mo.ResourcePool, len(pools))
+	for _, pool := range pools {
+		byId[pool.Self.Value] = pool
+	}
+
+	var names []string
+	for {
+		pool, ok := byId[id]
+		if !ok {
+			return "", false
+		}
+		if pool.Parent == nil || pool.Parent.Type != "ResourcePool" {
+			break
+		}
+		names = append([]string{pool.Name}, names...)
+		id = pool.Parent.Value
+	}
+	if len(names) == 0 {
+		return "/", true
+	}
+	return strings.Join(names, "/"), true
+}
+
+// resourcePoolId returns the id of the pool at the path, the pools can also
+// be referred to by their name alone.
+func (api *apiClient) resourcePoolId(ctx context.Context, name string) (string, error) {
+	pools, err := api.resourcePools(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name = strings.Trim(name, "/")
+	if name == "" || name == basePool {
+		name = "/"
+	}
+	for _, pool := range pools {
+		if path, _ := resourcePoolPath(pools, pool.Self.Value); path == name {
+			return pool.Self.Value, nil
+		}
+	}
+	for _, pool := range pools {
+		if pool.Name == name {
+			return pool.Self.Value, nil
+		}
+	}
+	return "", fmt.Errorf("resource pool %s not found", name)
+}
+
+func (api *apiClient) resourcePool(id string) *object.ResourcePool {
+	return object.NewResourcePool(api.vimClient(), types.ManagedObjectReference{Type: "ResourcePool", Value: id})
+}
+
+func (api *apiClient) virtualMachine(id string) *object.VirtualMachine {
+	return object.NewVirtualMachine(api.vimClient(), types.ManagedObjectReference{Type: "VirtualMachine", Value: id})
+}
+
+func (api *apiClient) retrieve(ctx context.Context, ref types.ManagedObjectReference, properties []string, dst interface{}) error {
+	return property.DefaultCollector(api.vimClient()).RetrieveOne(ctx, ref, properties, dst)
+}
+
+// datastorePath converts a /vmfs/volumes path to a "[datastore] path" datastore path.
+func datastorePath(path string) (string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, volumesPath), "/", 2)
+	if !strings.HasPrefix(path, volumesPath) || len(parts) != 2 || parts[0] == "" {
+		return "", fmt.Errorf("invalid datastore file path: '%s'", path)
+	}
+	return (&object.DatastorePath{Datastore: parts[0], Path: parts[1]}).String(), nil
+}
+
+// volumePath converts a "[datastore] path" datastore path to a /vmfs/volumes path.
+func volumePath(path string) string {
+	var datastorePath object.DatastorePath
+	if !datastorePath.FromString(path) {
+		return path
+	}
+	return volumesPath + datastorePath.Datastore + "/" + datastorePath.Path
+}
+
+// methodFault returns the fault of a failed method call or task, nil for the other errors.
+func methodFault(err error) types.BaseMethodFault {
+	var taskErr task.Error
+	switch {
+	case errors.As(err, &taskErr):
+		return taskErr.Fault()
+	case soap.IsSoapFault(err):
+		vimFault := soap.ToSoapFault(err).VimFault()
+		if fault, ok := vimFault.(types.BaseMethodFault); ok || vimFault == nil {
+			return fault
+		}
+		// The faults of the property collector are values, e.g. ManagedObjectNotFound.
+		pointer := reflect.New(reflect.TypeOf(vimFault))
+		pointer.Elem().Set(reflect.ValueOf(vimFault))
+		fault, _ := pointer.Interface().(types.BaseMethodFault)
+		return fault
+	case soap.IsVimFault(err):
+		return soap.ToVimFault(err)
+	}
+	return nil
+}
+
+// isNotFoundFault returns true for the faults of the objects that do not exist.
+func isNotFoundFault(err error) bool {
+	var notFound *find.NotFoundError
+	if errors.As(err, &notFound) {
+		return true
+	}
+
+	switch methodFault(err).(type) {
+	case *types.ManagedObjectNotFound, *types.NotFound, *types.FileNotFound:
+		return true
+	}
+	return false
+}
+
+// isAlreadyExists returns true for the faults of the files and objects that already exist.
+func isAlreadyExists(err error) bool {
+	switch methodFault(err).(type) {
+	case *types.FileAlreadyExists, *types.AlreadyExists, *types.DuplicateName:
+		return true
+	}
+	return false
+}
+
+// hostInfo detects the release and the features of the host, like detectHostInfo
+// with the ssh transport, from the environment browser of its compute resource.
+func (api *apiClient) hostInfo(ctx context.Context) *HostInfo {
+	about := api.vimClient().ServiceContent.About
+	info := &HostInfo{Version: about.Version, Build: about.Build, HasOvftool: hasOvftool()}
+
+	var host mo.HostSystem
+	var compute mo.ComputeResource
+	err := api.retrieve(ctx, api.host.Reference(), []string{"parent"}, &host)
+	if err == nil && host.Parent != nil {
+		err = api.retrieve(ctx, *host.Parent, []string{"environmentBrowser"}, &compute)
+	}
+	if err != nil || compute.EnvironmentBrowser == nil {
+		logging.V(logLevel).Infof("hostInfo: failed to get the environment browser: %s", err)
+		return info
+	}
+
+	browser := *compute.EnvironmentBrowser
+	descriptors, err := methods.QueryConfigOptionDescriptor(ctx, api.vimClient(), &types.QueryConfigOptionDescriptor{This: browser})
+	if err != nil {
+		logging.V(logLevel).Infof("hostInfo: failed to get the virtual hardware versions: %s", err)
+		return info
+	}
+	for _, descriptor := range descriptors.Returnval {
+		version, err := strconv.Atoi(strings.TrimPrefix(descriptor.Key, "vmx-"))
+		if err != nil || descriptor.CreateSupported == nil || !*descriptor.CreateSupported {
+			continue
+		}
+		info.VirtualHWVersions = append(info.VirtualHWVersions, version)
+		if descriptor.DefaultConfigOption != nil && *descriptor.DefaultConfigOption {
+			info.DefaultVirtualHWVer = version
+		}
+	}
+	sort.Ints(info.VirtualHWVersions)
+	if info.DefaultVirtualHWVer == 0 && len(info.VirtualHWVersions) > 0 {
+		info.DefaultVirtualHWVer = info.VirtualHWVersions[len(info.VirtualHWVersions)-1]
+	}
+
+	if info.DefaultVirtualHWVer > 0 {
+		option, err := methods.QueryConfigOptionEx(ctx, api.vimClient(), &types.QueryConfigOptionEx{
+			This: browser,
+			Spec: &types.EnvironmentBrowserConfigOptionQuerySpec{Key: hardwareVersion(info.DefaultVirtualHWVer)},
+		})
+		if err == nil && option.Returnval != nil {
+			for _, descriptor := range option.Returnval.GuestOSDescriptor {
+				info.GuestOSs = append(info.GuestOSs, descriptor.Id)
+			}
+		}
+	}
+	return info
+}
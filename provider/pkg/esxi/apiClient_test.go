@@ -0,0 +1,490 @@
+package esxi
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// testNetworkSystem completes the network system of the simulator with the
+// vswitch specs and the update methods used by the api transport.
+type testNetworkSystem struct {
+	simulator.HostNetworkSystem
+}
+
+func (s *testNetworkSystem) AddVirtualSwitch(c *types.AddVirtualSwitch) soap.HasFault {
+	body := s.HostNetworkSystem.AddVirtualSwitch(c)
+	if body.Fault() == nil {
+		s.setVirtualSwitchSpec(c.VswitchName, c.Spec)
+	}
+	return body
+}
+
+func (s *testNetworkSystem) UpdateVirtualSwitch(c *types.UpdateVirtualSwitch) soap.HasFault {
+	if !s.setVirtualSwitchSpec(c.VswitchName, &c.Spec) {
+		return &methods.UpdateVirtualSwitchBody{Fault_: simulator.Fault("", &types.NotFound{})}
+	}
+	return &methods.UpdateVirtualSwitchBody{Res: &types.UpdateVirtualSwitchResponse{}}
+}
+
+func (s *testNetworkSystem) UpdatePortGroup(c *types.UpdatePortGroup) soap.HasFault {
+	for i := range s.NetworkInfo.Portgroup {
+		if s.NetworkInfo.Portgroup[i].Spec.Name == c.PgName {
+			s.NetworkInfo.Portgroup[i].Spec = c.Portgrp
+			return &methods.UpdatePortGroupBody{Res: &types.UpdatePortGroupResponse{}}
+		}
+	}
+	return &methods.UpdatePortGroupBody{Fault_: simulator.Fault("", &types.NotFound{})}
+}
+
+func (s *testNetworkSystem) setVirtualSwitchSpec(name string, spec *types.HostVirtualSwitchSpec) bool {
+	for i := range s.NetworkInfo.Vswitch {
+		if s.NetworkInfo.Vswitch[i].Name == name {
+			s.NetworkInfo.Vswitch[i].Spec = *spec
+			s.NetworkInfo.Vswitch[i].Mtu = spec.Mtu
+			return true
+		}
+	}
+	return false
+}
+
+// newAPIHost returns a host managed with the api transport of an esxi simulator.
+func newAPIHost(t *testing.T) *Host {
+	t.Helper()
+	esxi, err := NewHost(context.Background(), newAPIConnection(t))
+	require.NoError(t, err)
+	t.Cleanup(esxi.Close)
+
+	esxi.sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+	return esxi
+}
+
+// newAPIConnection starts an esxi simulator and returns the api connection to
+// it, verifying its certificate against the thumbprint.
+func newAPIConnection(t *testing.T) ConnectionInfo {
+	t.Helper()
+	model := simulator.ESX()
+	require.NoError(t, model.Create())
+	t.Cleanup(model.Remove)
+
+	hostSystem := simulator.Map.Any("HostSystem").(*simulator.HostSystem)
+	networkSystem := simulator.Map.Get(*hostSystem.ConfigManager.NetworkSystem).(*simulator.HostNetworkSystem)
+	networkSystem.NetworkInfo.Pnic = append(networkSystem.NetworkInfo.Pnic, types.PhysicalNic{Device: "vmnic1"})
+	simulator.Map.Put(&testNetworkSystem{*networkSystem})
+
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	t.Cleanup(server.Close)
+
+	host, port, err := net.SplitHostPort(server.URL.Host)
+	require.NoError(t, err)
+	return ConnectionInfo{
+		Transport:     TransportAPI,
+		Host:          host,
+		SslPort:       port,
+		UserName:      "root",
+		Password:      "secret",
+		SslThumbprint: soap.ThumbprintSHA1(server.Certificate()),
+	}
+}
+
+func TestAPIRequiresPassword(t *testing.T) {
+	_, err := NewHost(context.Background(), ConnectionInfo{Transport: TransportAPI, Host: "127.0.0.1", SslPort: "443", UserName: "root"})
+	require.ErrorContains(t, err, "requires the password")
+
+	_, err = NewHost(context.Background(), ConnectionInfo{Transport: "telnet"})
+	require.ErrorContains(t, err, "unknown transport 'telnet'")
+}
+
+func TestAPICertificateVerification(t *testing.T) {
+	connection := newAPIConnection(t)
+	ctx := context.Background()
+
+	unverified := connection
+	unverified.SslThumbprint = ""
+	_, err := NewHost(ctx, unverified)
+	require.ErrorIs(t, err, errNoCertificateVerification)
+
+	mismatch := connection
+	mismatch.SslThumbprint = strings.Repeat("AB:", 19) + "AB"
+	_, err = NewHost(ctx, mismatch)
+	var mismatchErr *CertificateMismatchError
+	require.ErrorAs(t, err, &mismatchErr)
+	require.Equal(t, normalizeThumbprint(connection.SslThumbprint), mismatchErr.Thumbprint)
+
+	insecure := unverified
+	insecure.InsecureSkipHostKeyVerification = true
+	esxi, err := NewHost(ctx, insecure)
+	require.NoError(t, err)
+	esxi.Close()
+
+	esxi, err = NewHost(ctx, connection)
+	require.NoError(t, err)
+	esxi.Close()
+
+	require.True(t, IsSslThumbprint(connection.SslThumbprint))
+	require.True(t, IsSslThumbprint(strings.Repeat("ab", 32)))
+	require.False(t, IsSslThumbprint("AB:CD"))
+	require.False(t, IsSslThumbprint(strings.Repeat("zz", 20)))
+}
+
+func TestAPISessionExpired(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	// The session is gone, e.g. after the idle timeout of hostd, the calls log
+	// in again.
+	_, err := methods.Logout(ctx, esxi.api.vimClient(), &types.Logout{This: *esxi.api.vimClient().ServiceContent.SessionManager})
+	require.NoError(t, err)
+	_, err = esxi.api.resourcePools(ctx)
+	require.NoError(t, err)
+}
+
+func TestAPIRemoteCommandsUnavailable(t *testing.T) {
+	esxi := newAPIHost(t)
+
+	_, err := esxi.Execute(context.Background(), "vmware --version", "version")
+	require.ErrorIs(t, err, errNoExecutor)
+}
+
+func TestAPIHostLocks(t *testing.T) {
+	esxi := newAPIHost(t)
+	require.Equal(t, DefaultMaxConcurrentSessions, cap(esxi.sessions))
+
+	// The api calls wait for a session slot, like the remote commands.
+	for i := 0; i < cap(esxi.sessions); i++ {
+		release, err := esxi.sessions.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := esxi.api.resourcePools(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The topology changes are serialized, like with the ssh transport.
+	unlock, err := esxi.lockTopology(context.Background())
+	require.NoError(t, err)
+	defer unlock()
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = VirtualSwitchCreate(ctx, resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1")}, esxi)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAPIResourcePoolLifecycle(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":      resource.NewStringProperty("pool-test"),
+		"cpuMin":    resource.NewNumberProperty(200),
+		"cpuShares": resource.NewStringProperty("high"),
+		"memMax":    resource.NewNumberProperty(4096),
+		"memShares": resource.NewStringProperty("2000"),
+	}
+	id, result, err := ResourcePoolCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, "pool-test", result["name"].StringValue())
+	require.Equal(t, 200.0, result["cpuMin"].NumberValue())
+	require.Equal(t, "high", result["cpuShares"].StringValue())
+	require.Equal(t, 0.0, result["cpuMax"].NumberValue())
+	require.Equal(t, 4096.0, result["memMax"].NumberValue())
+	require.Equal(t, "2000", result["memShares"].StringValue())
+
+	child := resource.PropertyMap{"name": resource.NewStringProperty("pool-test/child")}
+	childId, result, err := ResourcePoolCreate(ctx, child, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pool-test/child", result["name"].StringValue())
+
+	inputs["name"] = resource.NewStringProperty("pool-renamed")
+	inputs["cpuShares"] = resource.NewStringProperty("low")
+	_, result, err = ResourcePoolUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pool-renamed", result["name"].StringValue())
+	require.Equal(t, "low", result["cpuShares"].StringValue())
+
+	_, result, err = ResourcePoolRead(ctx, childId, child, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pool-renamed/child", result["name"].StringValue())
+
+	importedId, imported, _, err := ResourcePoolImport(ctx, "/pool-renamed/child", esxi)
+	require.NoError(t, err)
+	require.Equal(t, childId, importedId)
+	require.Equal(t, "pool-renamed/child", imported["name"].StringValue())
+	require.NotContains(t, imported, resource.PropertyKey("id"))
+	_, _, _, err = ResourcePoolImport(ctx, "pool-missing", esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+
+	require.NoError(t, ResourcePoolDelete(ctx, childId, esxi))
+	require.NoError(t, ResourcePoolDelete(ctx, id, esxi))
+	_, _, err = ResourcePoolRead(ctx, id, inputs, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
+
+func TestAPIVirtualSwitchLifecycle(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":            resource.NewStringProperty("vSwitch-test"),
+		"mtu":             resource.NewNumberProperty(9000),
+		"promiscuousMode": resource.NewBoolProperty(true),
+		"upLinks": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("vmnic1")}),
+		}),
+	}
+	id, result, err := VirtualSwitchCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch-test", id)
+	require.Equal(t, 9000.0, result["mtu"].NumberValue())
+	require.Equal(t, 128.0, result["ports"].NumberValue())
+	require.Equal(t, "listen", result["linkDiscoveryMode"].StringValue())
+	require.True(t, result["promiscuousMode"].BoolValue())
+	require.False(t, result["forgedTransmits"].BoolValue())
+	require.Equal(t, "vmnic1", result["uplinks"].ArrayValue()[0].ObjectValue()["name"].StringValue())
+
+	_, _, err = VirtualSwitchCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "it already exists")
+
+	inputs["forgedTransmits"] = resource.NewBoolProperty(true)
+	inputs["upLinks"] = resource.NewArrayProperty(nil)
+	_, result, err = VirtualSwitchUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	require.True(t, result["forgedTransmits"].BoolValue())
+	require.NotContains(t, result, resource.PropertyKey("uplinks"))
+
+	inputs["upLinks"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("vmnic9")}),
+	})
+	_, _, err = VirtualSwitchUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.ErrorContains(t, err, "uplink not found: vmnic9")
+
+	require.NoError(t, VirtualSwitchDelete(ctx, id, esxi))
+	require.Error(t, VirtualSwitchDelete(ctx, id, esxi))
+}
+
+func TestAPIPortGroupLifecycle(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	_, _, err := VirtualSwitchCreate(ctx, resource.PropertyMap{
+		"name":       resource.NewStringProperty("vSwitch-test"),
+		"macChanges": resource.NewBoolProperty(true),
+	}, esxi)
+	require.NoError(t, err)
+
+	inputs := resource.PropertyMap{
+		"name":            resource.NewStringProperty("pg-test"),
+		"vSwitch":         resource.NewStringProperty("vSwitch-test"),
+		"vlan":            resource.NewNumberProperty(42),
+		"promiscuousMode": resource.NewStringProperty("true"),
+	}
+	id, result, err := PortGroupCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch-test/pg-test", id)
+	require.Equal(t, 42.0, result["vlan"].NumberValue())
+	require.Equal(t, "true", result["promiscuousMode"].StringValue())
+	// inherited from the vswitch
+	require.Equal(t, "true", result["macChanges"].StringValue())
+	require.Equal(t, "false", result["forgedTransmits"].StringValue())
+
+	inputs["vlan"] = resource.NewNumberProperty(7)
+	inputs["macChanges"] = resource.NewStringProperty("false")
+	_, result, err = PortGroupUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	require.Equal(t, 7.0, result["vlan"].NumberValue())
+	require.Equal(t, "false", result["macChanges"].StringValue())
+
+	_, result, err = PortGroupRead(ctx, id, resource.PropertyMap{}, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch-test", result["vSwitch"].StringValue())
+
+	require.NoError(t, PortGroupDelete(ctx, id, esxi))
+	_, _, err = PortGroupRead(ctx, id, resource.PropertyMap{}, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
+
+func TestAPIVirtualDiskLifecycle(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":      resource.NewStringProperty("disk-test"),
+		"diskStore": resource.NewStringProperty("LocalDS_0"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty(vdThin),
+		"size":      resource.NewNumberProperty(2),
+	}
+	id, result, err := VirtualDiskCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "/vmfs/volumes/LocalDS_0/disks/disk-test.vmdk", id)
+	require.Equal(t, "LocalDS_0", result["diskStore"].StringValue())
+	require.Equal(t, "disks", result["directory"].StringValue())
+	require.Equal(t, "disk-test.vmdk", result["name"].StringValue())
+
+	_, _, err = VirtualDiskCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "virtual disk /vmfs/volumes/LocalDS_0/disks/disk-test.vmdk already exists, import it instead")
+
+	inputs["diskStore"] = resource.NewStringProperty("missing")
+	_, _, err = VirtualDiskCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "disk store missing does not exist; available disk stores: LocalDS_0")
+
+	require.NoError(t, VirtualDiskDelete(ctx, id, esxi))
+	_, _, err = VirtualDiskRead(ctx, id, inputs, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
+
+func TestAPIVirtualMachineLifecycle(t *testing.T) {
+	esxi := newAPIHost(t)
+	ctx := context.Background()
+
+	_, _, err := VirtualDiskCreate(ctx, resource.PropertyMap{
+		"name":      resource.NewStringProperty("data"),
+		"diskStore": resource.NewStringProperty("LocalDS_0"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty(vdThin),
+		"size":      resource.NewNumberProperty(1),
+	}, esxi)
+	require.NoError(t, err)
+
+	inputs := resource.PropertyMap{
+		"name":         resource.NewStringProperty("vm-test"),
+		"diskStore":    resource.NewStringProperty("LocalDS_0"),
+		"os":           resource.NewStringProperty("centos-64"),
+		"memSize":      resource.NewNumberProperty(1024),
+		"numVCpus":     resource.NewNumberProperty(2),
+		"bootDiskSize": resource.NewNumberProperty(8),
+		"notes":        resource.NewStringProperty("created by the test"),
+		"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"virtualNetwork": resource.NewStringProperty("VM Network"),
+				"nicType":        resource.NewStringProperty("vmxnet3"),
+				"macAddress":     resource.NewStringProperty("00:50:56:00:00:01"),
+			}),
+		}),
+		"virtualDisks": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"virtualDiskId": resource.NewStringProperty("/vmfs/volumes/LocalDS_0/disks/data.vmdk"),
+				"slot":          resource.NewStringProperty("0:1"),
+			}),
+		}),
+		"info": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"key":   resource.NewStringProperty("metadata"),
+				"value": resource.NewStringProperty("{{ .Name }}"),
+			}),
+		}),
+	}
+	id, result, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, "vm-test", result["name"].StringValue())
+	require.Equal(t, vmTurnedOn, result["power"].StringValue())
+	require.Equal(t, "centos-64", result["os"].StringValue())
+	require.Equal(t, 1024.0, result["memSize"].NumberValue())
+	require.Equal(t, 2.0, result["numVCpus"].NumberValue())
+	require.Equal(t, 8.0, result["bootDiskSize"].NumberValue())
+	require.Equal(t, "LocalDS_0", result["diskStore"].StringValue())
+	require.Equal(t, "/", result["resourcePoolName"].StringValue())
+	require.Equal(t, "created by the test", result["notes"].StringValue())
+
+	nic := result["networkInterfaces"].ArrayValue()[0].ObjectValue()
+	require.Equal(t, "VM Network", nic["virtualNetwork"].StringValue())
+	require.Equal(t, "vmxnet3", nic["nicType"].StringValue())
+	require.Equal(t, "00:50:56:00:00:01", nic["macAddress"].StringValue())
+
+	disk := result["virtualDisks"].ArrayValue()[0].ObjectValue()
+	require.Equal(t, "0:1", disk["slot"].StringValue())
+	require.Equal(t, "/vmfs/volumes/LocalDS_0/disks/data.vmdk", disk["virtualDiskId"].StringValue())
+
+	info := result["info"].ArrayValue()[0].ObjectValue()
+	require.Equal(t, "metadata", info["key"].StringValue())
+	require.Equal(t, "vm-test", info["value"].StringValue())
+
+	got, err := VirtualMachineGet(ctx, resource.PropertyMap{"name": resource.NewStringProperty("vm-test")}, esxi)
+	require.NoError(t, err)
+	require.Equal(t, id, got["id"].StringValue())
+
+	inputs["memSize"] = resource.NewNumberProperty(2048)
+	inputs["bootDiskSize"] = resource.NewNumberProperty(16)
+	inputs["power"] = resource.NewStringProperty(vmTurnedOff)
+	delete(inputs, "virtualDisks")
+	_, _, err = VirtualMachineUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+
+	_, result, err = VirtualMachineRead(ctx, id, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, vmTurnedOff, result["power"].StringValue())
+	require.Equal(t, 2048.0, result["memSize"].NumberValue())
+	require.Equal(t, 16.0, result["bootDiskSize"].NumberValue())
+	require.NotContains(t, result, resource.PropertyKey("virtualDisks"))
+
+	importedId, imported, _, err := VirtualMachineImport(ctx, "vm-test", esxi)
+	require.NoError(t, err)
+	require.Equal(t, id, importedId)
+	require.Equal(t, 2048.0, imported["memSize"].NumberValue())
+	require.NotContains(t, imported, resource.PropertyKey("ipAddress"))
+
+	inputs["bootDiskSize"] = resource.NewNumberProperty(4)
+	_, _, err = VirtualMachineUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.ErrorContains(t, err, "not able to shrink")
+
+	require.NoError(t, VirtualMachineDelete(ctx, id, esxi))
+	_, _, err = VirtualMachineRead(ctx, id, inputs, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+
+	// the attached disks are kept
+	_, _, err = VirtualDiskRead(ctx, "/vmfs/volumes/LocalDS_0/disks/data.vmdk", resource.PropertyMap{}, esxi)
+	require.NoError(t, err)
+}
+
+func TestGuestId(t *testing.T) {
+	tests := []struct {
+		os      string
+		guestId string
+	}{
+		{os: "centos", guestId: "centosGuest"},
+		{os: "centos-64", guestId: "centos64Guest"},
+		{os: "windows9-64", guestId: "windows9_64Guest"},
+		{os: "otherlinux-64", guestId: "otherLinux64Guest"},
+		{os: "other-64", guestId: "otherGuest64"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.guestId, guestId(test.os))
+		require.Equal(t, test.os, vmxGuestOS(test.guestId))
+	}
+}
+
+func TestDatastorePath(t *testing.T) {
+	path, err := datastorePath("/vmfs/volumes/datastore1/dir/disk.vmdk")
+	require.NoError(t, err)
+	require.Equal(t, "[datastore1] dir/disk.vmdk", path)
+	require.Equal(t, "/vmfs/volumes/datastore1/dir/disk.vmdk", volumePath(path))
+
+	_, err = datastorePath("/tmp/disk.vmdk")
+	require.Error(t, err)
+}
+
+func TestAPIHostInfo(t *testing.T) {
+	esxi := newAPIHost(t)
+
+	require.NotNil(t, esxi.Info)
+	require.NotEmpty(t, esxi.Info.Version)
+	require.NotEmpty(t, esxi.Info.VirtualHWVersions)
+	require.Contains(t, esxi.Info.VirtualHWVersions, esxi.Info.DefaultVirtualHWVer)
+	require.NotEmpty(t, esxi.Info.GuestOSs)
+}
@@ -0,0 +1,253 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func (api *apiClient) createVirtualSwitch(ctx context.Context, vs VirtualSwitch) (string, resource.PropertyMap, error) {
+	networkSystem, networkInfo, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if findVirtualSwitch(networkInfo, vs.Name) != nil {
+		return "", nil, fmt.Errorf("failed to create vswitch: %s, it already exists", vs.Name)
+	}
+
+	spec, err := vs.spec(networkInfo)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = networkSystem.AddVirtualSwitch(ctx, vs.Name, spec); err != nil {
+		return "", nil, fmt.Errorf("failed to create vswitch: %w", err)
+	}
+
+	return api.readVirtualSwitch(ctx, vs.Name)
+}
+
+func (api *apiClient) updateVirtualSwitch(ctx context.Context, vs VirtualSwitch) (string, resource.PropertyMap, error) {
+	networkSystem, networkInfo, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	spec, err := vs.spec(networkInfo)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to update vswitch: %w", err)
+	}
+	if err = networkSystem.UpdateVirtualSwitch(ctx, vs.Name, *spec); err != nil {
+		return "", nil, fmt.Errorf("failed to update vswitch: %w", err)
+	}
+
+	return api.readVirtualSwitch(ctx, vs.Name)
+}
+
+func (api *apiClient) deleteVirtualSwitch(ctx context.Context, name string) error {
+	networkSystem, _, err := api.networkSystem(ctx)
+	if err != nil {
+		return err
+	}
+	if err = networkSystem.RemoveVirtualSwitch(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete vswitch: %w", err)
+	}
+	return nil
+}
+
+func (api *apiClient) readVirtualSwitch(ctx context.Context, name string) (string, resource.PropertyMap, error) {
+	_, networkInfo, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	vSwitch := findVirtualSwitch(networkInfo, name)
+	if vSwitch == nil {
+		return "", nil, &NotFoundError{Kind: "virtual switch", Id: name}
+	}
+
+	vs := VirtualSwitch{
+		Id:                name,
+		Name:              name,
+		Ports:             int(vSwitch.Spec.NumPorts),
+		Mtu:               int(vSwitch.Mtu),
+		LinkDiscoveryMode: "listen",
+	}
+	if vs.Mtu == 0 {
+		vs.Mtu = 1500
+	}
+	if bridge, ok := vSwitch.Spec.Bridge.(*types.HostVirtualSwitchBondBridge); ok {
+		for _, nic := range bridge.NicDevice {
+			vs.Uplinks = append(vs.Uplinks, Uplink{Name: nic})
+		}
+		if bridge.LinkDiscoveryProtocolConfig != nil {
+			vs.LinkDiscoveryMode = bridge.LinkDiscoveryProtocolConfig.Operation
+		}
+	}
+	if security := vSwitch.Spec.Policy; security != nil && security.Security != nil {
+		vs.PromiscuousMode = security.Security.AllowPromiscuous != nil && *security.Security.AllowPromiscuous
+		vs.MacChanges = security.Security.MacChanges != nil && *security.Security.MacChanges
+		vs.ForgedTransmits = security.Security.ForgedTransmits != nil && *security.Security.ForgedTransmits
+	}
+
+	result := vs.toMap()
+	return vs.Id, resource.NewPropertyMapFromMap(result), nil
+}
+
+func (vs *VirtualSwitch) spec(networkInfo *types.HostNetworkInfo) (*types.HostVirtualSwitchSpec, error) {
+	spec := &types.HostVirtualSwitchSpec{
+		NumPorts: int32(vs.Ports),
+		Mtu:      int32(vs.Mtu),
+		Policy: &types.HostNetworkPolicy{
+			Security: &types.HostNetworkSecurityPolicy{
+				AllowPromiscuous: types.NewBool(vs.PromiscuousMode),
+				MacChanges:       types.NewBool(vs.MacChanges),
+				ForgedTransmits:  types.NewBool(vs.ForgedTransmits),
+			},
+		},
+	}
+
+	if len(vs.Uplinks) > 0 {
+		bridge := &types.HostVirtualSwitchBondBridge{
+			LinkDiscoveryProtocolConfig: &types.LinkDiscoveryProtocolConfig{
+				Protocol:  string(types.LinkDiscoveryProtocolConfigProtocolTypeCdp),
+				Operation: vs.LinkDiscoveryMode,
+			},
+		}
+		for _, upLink := range vs.Uplinks {
+			if !hasPhysicalNic(networkInfo, upLink.Name) {
+				return nil, fmt.Errorf("uplink not found: %s", upLink.Name)
+			}
+			bridge.NicDevice = append(bridge.NicDevice, upLink.Name)
+		}
+		spec.Bridge = bridge
+	}
+
+	return spec, nil
+}
+
+func (api *apiClient) createPortGroup(ctx context.Context, pg PortGroup) (string, resource.PropertyMap, error) {
+	networkSystem, _, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = networkSystem.AddPortGroup(ctx, pg.spec()); err != nil {
+		return "", nil, fmt.Errorf("failed to create port group: %w", err)
+	}
+
+	return api.readPortGroup(ctx, pg)
+}
+
+func (api *apiClient) updatePortGroup(ctx context.Context, pg PortGroup) (string, resource.PropertyMap, error) {
+	networkSystem, _, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	if err = networkSystem.UpdatePortGroup(ctx, pg.Name, pg.spec()); err != nil {
+		return "", nil, fmt.Errorf("failed to update port group: %w", err)
+	}
+
+	return api.readPortGroup(ctx, pg)
+}
+
+func (api *apiClient) deletePortGroup(ctx context.Context, name string) error {
+	networkSystem, _, err := api.networkSystem(ctx)
+	if err != nil {
+		return err
+	}
+	if err = networkSystem.RemovePortGroup(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete port group: %w", err)
+	}
+	return nil
+}
+
+// readPortGroup reads the port group, its security policy is the effective
+// policy: the policies it does not override are inherited from the vswitch.
+func (api *apiClient) readPortGroup(ctx context.Context, pg PortGroup) (string, resource.PropertyMap, error) {
+	_, networkInfo, err := api.networkSystem(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var portGroup *types.HostPortGroup
+	for i := range networkInfo.Portgroup {
+		if networkInfo.Portgroup[i].Spec.Name == pg.Name {
+			portGroup = &networkInfo.Portgroup[i]
+		}
+	}
+	if portGroup == nil {
+		return "", nil, &NotFoundError{Kind: "port group", Id: pg.Name}
+	}
+
+	pg.VSwitch = portGroup.Spec.VswitchName
+	pg.Vlan = int(portGroup.Spec.VlanId)
+	pg.Id = fmt.Sprintf("%s/%s", pg.VSwitch, pg.Name)
+
+	var promiscuous, macChanges, forgedTransmits bool
+	if vSwitch := findVirtualSwitch(networkInfo, pg.VSwitch); vSwitch != nil && vSwitch.Spec.Policy != nil && vSwitch.Spec.Policy.Security != nil {
+		security := vSwitch.Spec.Policy.Security
+		promiscuous = security.AllowPromiscuous != nil && *security.AllowPromiscuous
+		macChanges = security.MacChanges != nil && *security.MacChanges
+		forgedTransmits = security.ForgedTransmits != nil && *security.ForgedTransmits
+	}
+	if security := portGroup.Spec.Policy.Security; security != nil {
+		if security.AllowPromiscuous != nil {
+			promiscuous = *security.AllowPromiscuous
+		}
+		if security.MacChanges != nil {
+			macChanges = *security.MacChanges
+		}
+		if security.ForgedTransmits != nil {
+			forgedTransmits = *security.ForgedTransmits
+		}
+	}
+	pg.PromiscuousMode = strconv.FormatBool(promiscuous)
+	pg.MacChanges = strconv.FormatBool(macChanges)
+	pg.ForgedTransmits = strconv.FormatBool(forgedTransmits)
+
+	result := pg.toMap()
+	return pg.Id, resource.NewPropertyMapFromMap(result), nil
+}
+
+// spec returns the port group specification, the security policies left
+// empty are inherited from the vswitch.
+func (pg *PortGroup) spec() types.HostPortGroupSpec {
+	policy := func(value string) *bool {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return &parsed
+		}
+		return nil
+	}
+
+	return types.HostPortGroupSpec{
+		Name:        pg.Name,
+		VlanId:      int32(pg.Vlan),
+		VswitchName: pg.VSwitch,
+		Policy: types.HostNetworkPolicy{
+			Security: &types.HostNetworkSecurityPolicy{
+				AllowPromiscuous: policy(pg.PromiscuousMode),
+				MacChanges:       policy(pg.MacChanges),
+				ForgedTransmits:  policy(pg.ForgedTransmits),
+			},
+		},
+	}
+}
+
+func findVirtualSwitch(networkInfo *types.HostNetworkInfo, name string) *types.HostVirtualSwitch {
+	for i := range networkInfo.Vswitch {
+		if networkInfo.Vswitch[i].Name == name {
+			return &networkInfo.Vswitch[i]
+		}
+	}
+	return nil
+}
+
+func hasPhysicalNic(networkInfo *types.HostNetworkInfo, device string) bool {
+	for _, pnic := range networkInfo.Pnic {
+		if pnic.Device == device {
+			return true
+		}
+	}
+	return false
+}
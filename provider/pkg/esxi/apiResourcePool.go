@@ -0,0 +1,140 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const unlimited = -1
+
+func (api *apiClient) createResourcePool(ctx context.Context, parentPool string, rp ResourcePool) (string, resource.PropertyMap, error) {
+	//  Check if already exists
+	if id, err := api.resourcePoolId(ctx, rp.Name); err == nil {
+		rp.Id = id
+		return api.readResourcePool(ctx, rp)
+	}
+
+	parentPoolId, err := api.resourcePoolId(ctx, parentPool)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get parent pool id: %w", err)
+	}
+
+	pool, err := api.resourcePool(parentPoolId).Create(ctx, rp.Name, rp.configSpec())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create resource pool %s: %w", rp.Name, err)
+	}
+
+	rp.Id = pool.Reference().Value
+	return api.readResourcePool(ctx, rp)
+}
+
+func (api *apiClient) updateResourcePool(ctx context.Context, rp ResourcePool) (string, resource.PropertyMap, error) {
+	pools, err := api.resourcePools(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	path, found := resourcePoolPath(pools, rp.Id)
+	if !found {
+		return "", nil, fmt.Errorf("failed to get resource pool name: resource pool %s not found", rp.Id)
+	}
+
+	// Only the name of the pool changes, the pool is not moved to another parent.
+	name := ""
+	if path != rp.Name {
+		name = rp.Name[strings.LastIndex(rp.Name, "/")+1:]
+	}
+	spec := rp.configSpec()
+	if err = api.resourcePool(rp.Id).UpdateConfig(ctx, name, &spec); err != nil {
+		return "", nil, fmt.Errorf("failed to update resource pool: %w", err)
+	}
+
+	return api.readResourcePool(ctx, rp)
+}
+
+func (api *apiClient) deleteResourcePool(ctx context.Context, id string) error {
+	task, err := api.resourcePool(id).Destroy(ctx)
+	if err = waitTask(ctx, task, err); err != nil {
+		return fmt.Errorf("failed to delete resource pool: %w", err)
+	}
+	return nil
+}
+
+func (api *apiClient) readResourcePool(ctx context.Context, rp ResourcePool) (string, resource.PropertyMap, error) {
+	pools, err := api.resourcePools(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pool *mo.ResourcePool
+	for i := range pools {
+		if pools[i].Self.Value == rp.Id {
+			pool = &pools[i]
+		}
+	}
+	if pool == nil || pool.Config.CpuAllocation.Reservation == nil {
+		return "", nil, &NotFoundError{Kind: "resource pool", Id: rp.Id}
+	}
+
+	rp.Name, _ = resourcePoolPath(pools, rp.Id)
+	rp.CpuMin, rp.CpuMinExpandable, rp.CpuMax, rp.CpuShares = fromResourceAllocation(pool.Config.CpuAllocation)
+	rp.MemMin, rp.MemMinExpandable, rp.MemMax, rp.MemShares = fromResourceAllocation(pool.Config.MemoryAllocation)
+
+	result := rp.toMap()
+	return rp.Id, resource.NewPropertyMapFromMap(result), nil
+}
+
+func (rp *ResourcePool) configSpec() types.ResourceConfigSpec {
+	return types.ResourceConfigSpec{
+		CpuAllocation:    toResourceAllocation(rp.CpuMin, rp.CpuMinExpandable, rp.CpuMax, rp.CpuShares),
+		MemoryAllocation: toResourceAllocation(rp.MemMin, rp.MemMinExpandable, rp.MemMax, rp.MemShares),
+	}
+}
+
+func toResourceAllocation(min int, expandable string, max int, shares string) types.ResourceAllocationInfo {
+	limit := int64(unlimited)
+	if max > 0 {
+		limit = int64(max)
+	}
+
+	sharesInfo := &types.SharesInfo{Level: types.SharesLevel(shares)}
+	if !Contains([]string{"low", "normal", "high"}, shares) {
+		custom, _ := strconv.Atoi(shares)
+		sharesInfo = &types.SharesInfo{Level: types.SharesLevelCustom, Shares: int32(custom)}
+	}
+
+	return types.ResourceAllocationInfo{
+		Reservation:           types.NewInt64(int64(min)),
+		ExpandableReservation: types.NewBool(expandable == trueValue),
+		Limit:                 types.NewInt64(limit),
+		Shares:                sharesInfo,
+	}
+}
+
+func fromResourceAllocation(allocation types.ResourceAllocationInfo) (int, string, int, string) {
+	var min, max int
+	if allocation.Reservation != nil {
+		min = int(*allocation.Reservation)
+	}
+	if allocation.Limit != nil && *allocation.Limit > 0 {
+		max = int(*allocation.Limit)
+	}
+
+	expandable := strconv.FormatBool(allocation.ExpandableReservation != nil && *allocation.ExpandableReservation)
+
+	shares := "normal"
+	if allocation.Shares != nil {
+		if allocation.Shares.Level == types.SharesLevelCustom {
+			shares = strconv.Itoa(int(allocation.Shares.Shares))
+		} else {
+			shares = string(allocation.Shares.Level)
+		}
+	}
+
+	return min, expandable, max, shares
+}
@@ -0,0 +1,215 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const kilobytesPerGigabyte = 1024 * 1024
+
+// apiDiskTypes maps the vmkfstools disk types to the virtual disk manager types.
+var apiDiskTypes = map[string]types.VirtualDiskType{
+	vdThin:             types.VirtualDiskTypeThin,
+	vdZeroedThick:      types.VirtualDiskTypePreallocated,
+	vdEagerZeroedThick: types.VirtualDiskTypeEagerZeroedThick,
+}
+
+func (api *apiClient) createVirtualDisk(ctx context.Context, vd VirtualDisk) (string, resource.PropertyMap, error) {
+	if _, err := api.datastore(ctx, vd.DiskStore); err != nil {
+		return "", nil, fmt.Errorf("failed to validate disk store: %w", err)
+	}
+
+	// id is just the full path name
+	id := fmt.Sprintf("%s%s/%s/%s", volumesPath, vd.DiskStore, vd.Directory, vd.Name)
+	directory, _ := datastorePath(path.Dir(id))
+	name, err := datastorePath(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fileManager := object.NewFileManager(api.vimClient())
+	if err = fileManager.MakeDirectory(ctx, directory, api.datacenter, true); err != nil && !isAlreadyExists(err) {
+		return "", nil, fmt.Errorf("failed to create virtual disk directory: %w", err)
+	}
+
+	if _, err = api.virtualDiskInfo(ctx, id); err == nil {
+		return "", nil, fmt.Errorf("virtual disk %s already exists, import it instead", id)
+	}
+
+	diskType, ok := apiDiskTypes[vd.DiskType]
+	if !ok {
+		diskType = types.VirtualDiskTypeThin
+	}
+	spec := &types.FileBackedVirtualDiskSpec{
+		VirtualDiskSpec: types.VirtualDiskSpec{
+			DiskType:    string(diskType),
+			AdapterType: string(types.VirtualDiskAdapterTypeLsiLogic),
+		},
+		CapacityKb: int64(vd.Size) * kilobytesPerGigabyte,
+	}
+	task, err := object.NewVirtualDiskManager(api.vimClient()).CreateVirtualDisk(ctx, name, api.datacenter, spec)
+	if err = waitTask(ctx, task, err); err != nil {
+		return "", nil, fmt.Errorf("failed to create virtual disk: %s err: %w", vd.Name, err)
+	}
+
+	return api.readVirtualDisk(ctx, id)
+}
+
+func (api *apiClient) updateVirtualDisk(ctx context.Context, vd VirtualDisk) error {
+	info, err := api.virtualDiskInfo(ctx, vd.Id)
+	if err != nil {
+		return err
+	}
+
+	current := int(info.CapacityKb / kilobytesPerGigabyte)
+	if current > vd.Size {
+		return fmt.Errorf("not able to shrink virtual disk: %s", vd.Id)
+	}
+	if current == vd.Size {
+		return nil
+	}
+
+	name, _ := datastorePath(vd.Id)
+	manager := object.NewVirtualDiskManager(api.vimClient())
+	request := types.ExtendVirtualDisk_Task{
+		This:          manager.Reference(),
+		Name:          name,
+		Datacenter:    types.NewReference(api.datacenter.Reference()),
+		NewCapacityKb: int64(vd.Size) * kilobytesPerGigabyte,
+	}
+	response, err := methods.ExtendVirtualDisk_Task(ctx, api.vimClient(), &request)
+	if err != nil {
+		return err
+	}
+	return object.NewTask(api.vimClient(), response.Returnval).Wait(ctx)
+}
+
+func (api *apiClient) deleteVirtualDisk(ctx context.Context, id string) error {
+	name, err := datastorePath(id)
+	if err != nil {
+		return fmt.Errorf("invalid virtual disk id: '%s'", id)
+	}
+
+	task, err := object.NewVirtualDiskManager(api.vimClient()).DeleteVirtualDisk(ctx, name, api.datacenter)
+	if err = waitTask(ctx, task, err); err != nil {
+		if !isNotFoundFault(err) {
+			return fmt.Errorf("failed to destroy virtual disk: %w", err)
+		}
+		logging.V(logLevel).Infof("already deleted:%s", id)
+	}
+
+	//  Delete the directory when it is empty, ignore the errors.
+	directory := path.Dir(id)
+	if files, err := api.searchDatastore(ctx, directory, "*", nil); err == nil && len(files) == 0 {
+		directoryName, _ := datastorePath(directory)
+		task, err = object.NewFileManager(api.vimClient()).DeleteDatastoreFile(ctx, directoryName, api.datacenter)
+		if err = waitTask(ctx, task, err); err != nil {
+			logging.V(logLevel).Infof("deleteVirtualDisk: failed to remove the empty directory %s: %s", directory, err)
+		}
+	}
+
+	return nil
+}
+
+func (api *apiClient) readVirtualDisk(ctx context.Context, id string) (string, resource.PropertyMap, error) {
+	vd, err := parseVirtualDiskId(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	info, err := api.virtualDiskInfo(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	vd.Size = int(info.CapacityKb / kilobytesPerGigabyte)
+	// The api tells the thin disks apart, the kind of the thick disks is unknown.
+	vd.DiskType = esxiUnknown
+	if info.Thin != nil && *info.Thin {
+		vd.DiskType = vdThin
+	}
+
+	result := vd.toMap()
+	return vd.Id, resource.NewPropertyMapFromMap(result), nil
+}
+
+// virtualDiskInfo returns the information of the disk at the /vmfs/volumes path.
+func (api *apiClient) virtualDiskInfo(ctx context.Context, id string) (*types.VmDiskFileInfo, error) {
+	query := &types.VmDiskFileQuery{
+		Details: &types.VmDiskFileQueryFlags{CapacityKb: true, Thin: types.NewBool(true), DiskType: true},
+	}
+	files, err := api.searchDatastore(ctx, path.Dir(id), path.Base(id), []types.BaseFileQuery{query})
+	if isNotFoundFault(err) {
+		return nil, &NotFoundError{Kind: "virtual disk", Id: id}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("virtual disk %s doesn't exist, err: %w", id, err)
+	}
+	for _, file := range files {
+		if info, ok := file.(*types.VmDiskFileInfo); ok {
+			return info, nil
+		}
+	}
+	return nil, &NotFoundError{Kind: "virtual disk", Id: id}
+}
+
+// searchDatastore returns the files matching the pattern in the directory at the /vmfs/volumes path.
+func (api *apiClient) searchDatastore(ctx context.Context, directory, pattern string, query []types.BaseFileQuery) ([]types.BaseFileInfo, error) {
+	name, err := datastorePath(directory)
+	if err != nil {
+		return nil, err
+	}
+	var dsPath object.DatastorePath
+	dsPath.FromString(name)
+	datastore, err := api.datastore(ctx, dsPath.Datastore)
+	if err != nil {
+		return nil, err
+	}
+	browser, err := datastore.Browser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &types.HostDatastoreBrowserSearchSpec{
+		Query:        query,
+		MatchPattern: []string{pattern},
+		Details:      &types.FileQueryFlags{FileType: true, FileSize: true},
+	}
+	task, err := browser.SearchDatastore(ctx, name, spec)
+	if err != nil {
+		return nil, err
+	}
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	results, ok := info.Result.(types.HostDatastoreBrowserSearchResults)
+	if !ok {
+		return nil, fmt.Errorf("unexpected datastore search result %T", info.Result)
+	}
+	return results.File, nil
+}
+
+// parseVirtualDiskId returns the disk store, directory and name of the disk at the /vmfs/volumes path.
+func parseVirtualDiskId(id string) (VirtualDisk, error) {
+	name, err := datastorePath(id)
+	if err != nil || path.Dir(id) == path.Clean(volumesPath) {
+		return VirtualDisk{}, fmt.Errorf("invalid virtual disk id: '%s'", id)
+	}
+
+	var datastorePath object.DatastorePath
+	datastorePath.FromString(name)
+	directory, diskName := path.Split(datastorePath.Path)
+	return VirtualDisk{
+		Id:        id,
+		DiskStore: datastorePath.Datastore,
+		Directory: path.Clean(directory),
+		Name:      diskName,
+	}, nil
+}
@@ -0,0 +1,660 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// bootDiskSlot is the slot of the boot disk, scsi0:0.
+	bootDiskSlot = "0:0"
+	bootDiskUnit = 0
+
+	defaultNicType = "e1000"
+	guestIdSuffix  = "Guest"
+)
+
+// guestIds maps the vmx guestOS values which do not follow the naming rule of
+// guestId to their api guest ids.
+var guestIds = map[string]string{
+	"other":             "otherGuest",
+	"other-64":          "otherGuest64",
+	"otherlinux":        "otherLinuxGuest",
+	"otherlinux-64":     "otherLinux64Guest",
+	"windows7srv-64":    "windows7Server64Guest",
+	"windows8srv-64":    "windows8Server64Guest",
+	"windows9srv-64":    "windows9Server64Guest",
+	"windows2019srv-64": "windows2019srv_64Guest",
+}
+
+var vmProperties = []string{"name", "config", "resourcePool", "runtime.powerState", "guest.ipAddress", "summary.quickStats.uptimeSeconds"}
+
+func (api *apiClient) createVirtualMachine(ctx context.Context, vm VirtualMachine, esxi *Host) (VirtualMachine, error) {
+	datastore, err := api.datastore(ctx, vm.DiskStore)
+	if err != nil {
+		return VirtualMachine{}, fmt.Errorf("failed to validate disk store: %w", err)
+	}
+
+	id, err := api.virtualMachineId(ctx, vm.Name)
+	switch {
+	case err == nil:
+		// VM already exists, power off guest if it's powered on or suspended
+		vm.Id = id
+		currentPowerState := api.virtualMachinePowerState(ctx, id)
+		if currentPowerState == vmTurnedOn || currentPowerState == vmTurnedSuspended {
+			api.powerOffVirtualMachine(ctx, id, vm.ShutdownTimeout, esxi.sleep)
+		}
+	case vm.SourcePath == "none":
+		vm.Id, err = api.createPlainVirtualMachine(ctx, vm, datastore)
+		if err != nil {
+			return VirtualMachine{}, err
+		}
+	default:
+		// ovftool deploys through the api of the host as well.
+		if err = esxi.buildVirtualMachineFromSource(ctx, vm); err != nil {
+			return VirtualMachine{}, err
+		}
+		vm.Id, err = api.virtualMachineId(ctx, vm.Name)
+		if err != nil {
+			return VirtualMachine{}, fmt.Errorf("failed to get VM ID: %w", err)
+		}
+	}
+
+	if len(vm.OvfProperties) > 0 {
+		if api.virtualMachinePowerState(ctx, vm.Id) != vmTurnedOn {
+			return VirtualMachine{}, fmt.Errorf("failed to power on after ovfProperties injection")
+		}
+
+		// Allow cloud-init to process.
+		if err = esxi.sleep(ctx, time.Duration(vm.OvfPropertiesTimer)*time.Second); err != nil {
+			return VirtualMachine{}, err
+		}
+		api.powerOffVirtualMachine(ctx, vm.Id, vm.ShutdownTimeout, esxi.sleep)
+	}
+
+	if err = api.reconfigureVirtualMachine(ctx, true, vm); err != nil {
+		return VirtualMachine{}, fmt.Errorf("failed to update the virtual machine: %w", err)
+	}
+
+	return vm, nil
+}
+
+// createPlainVirtualMachine creates a virtual machine with a boot disk of the
+// requested size, its other devices are added by reconfigureVirtualMachine.
+func (api *apiClient) createPlainVirtualMachine(ctx context.Context, vm VirtualMachine, datastore *object.Datastore) (string, error) {
+	pool, err := api.resourcePoolId(ctx, vm.ResourcePoolName)
+	if err != nil {
+		return "", fmt.Errorf("failed to use Resource Pool ID:%s", vm.ResourcePoolName)
+	}
+	folders, err := api.datacenter.Folders(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var devices object.VirtualDeviceList
+	controller, err := devices.CreateSCSIController("lsilogic")
+	if err != nil {
+		return "", err
+	}
+	devices = append(devices, controller)
+
+	disk := devices.CreateDisk(controller.(types.BaseVirtualController), datastore.Reference(),
+		datastore.Path(fmt.Sprintf("%s/%s.vmdk", vm.Name, vm.Name)))
+	disk.CapacityInKB = int64(vm.BootDiskSize) * kilobytesPerGigabyte
+	setDiskType(disk, vm.BootDiskType)
+	disk.UnitNumber = types.NewInt32(bootDiskUnit)
+	devices = append(devices, disk)
+
+	deviceChange, err := devices.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+	if err != nil {
+		return "", err
+	}
+	// The boot disk is created with the virtual machine.
+	deviceChange[len(deviceChange)-1].GetVirtualDeviceConfigSpec().FileOperation = types.VirtualDeviceConfigSpecFileOperationCreate
+
+	spec := types.VirtualMachineConfigSpec{
+		Name:         vm.Name,
+		GuestId:      guestId(vm.Os),
+		Version:      hardwareVersion(vm.VirtualHWVer),
+		MemoryMB:     int64(vm.MemSize),
+		NumCPUs:      int32(vm.NumVCpus),
+		Annotation:   vm.Notes,
+		Firmware:     vm.BootFirmware,
+		BootOptions:  &types.VirtualMachineBootOptions{EfiSecureBootEnabled: types.NewBool(vm.EfiSecureBoot)},
+		Files:        &types.VirtualMachineFileInfo{VmPathName: fmt.Sprintf("[%s]", datastore.Name())},
+		DeviceChange: deviceChange,
+	}
+
+	task, err := folders.VmFolder.CreateVM(ctx, spec, api.resourcePool(pool), api.host)
+	if err != nil {
+		return "", fmt.Errorf("failed to register guest err:%w", err)
+	}
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to register guest err:%w", err)
+	}
+	ref, ok := info.Result.(types.ManagedObjectReference)
+	if !ok {
+		return "", fmt.Errorf("failed to register guest: unexpected result %T", info.Result)
+	}
+	return ref.Value, nil
+}
+
+// reconfigureVirtualMachine applies the settings of the virtual machine, the
+// way updateVmxContents edits its vmx file with the ssh transport.
+func (api *apiClient) reconfigureVirtualMachine(ctx context.Context, isNew bool, vm VirtualMachine) error {
+	var current mo.VirtualMachine
+	if err := api.retrieve(ctx, api.virtualMachine(vm.Id).Reference(), []string{"config"}, &current); err != nil {
+		return fmt.Errorf("failed to get the virtual machine config: %w", err)
+	}
+	if current.Config == nil {
+		return fmt.Errorf("failed to get the virtual machine config: %s", vm.Id)
+	}
+
+	spec := types.VirtualMachineConfigSpec{
+		MemoryMB: int64(vm.MemSize),
+		NumCPUs:  int32(vm.NumVCpus),
+		Firmware: vm.BootFirmware,
+		BootOptions: &types.VirtualMachineBootOptions{
+			EfiSecureBootEnabled: types.NewBool(vm.EfiSecureBoot),
+		},
+	}
+	if vm.Os != "" {
+		spec.GuestId = guestId(vm.Os)
+	}
+	if vm.Notes != "" {
+		spec.Annotation = vm.Notes
+	}
+	for _, prop := range vm.Info {
+		value, err := ParseTemplate(prop.Value, vm)
+		if err != nil {
+			return fmt.Errorf("unable to parse templated info property '%s', err: %w", prop.Key, err)
+		}
+		logging.V(logLevel).Infof("SAVING %s => %s", prop.Key, value)
+		spec.ExtraConfig = append(spec.ExtraConfig, &types.OptionValue{Key: "guestinfo." + prop.Key, Value: value})
+	}
+
+	devices := object.VirtualDeviceList(current.Config.Hardware.Device)
+	deviceChange, err := api.virtualDiskChanges(ctx, devices, vm)
+	if err != nil {
+		return err
+	}
+	nicChange, err := api.networkInterfaceChanges(ctx, devices, isNew, vm.NetworkInterfaces)
+	if err != nil {
+		return err
+	}
+	spec.DeviceChange = append(deviceChange, nicChange...)
+
+	machine := api.virtualMachine(vm.Id)
+	if version := hardwareVersion(vm.VirtualHWVer); vm.VirtualHWVer != 0 && version != current.Config.Version {
+		task, err := machine.UpgradeVM(ctx, version)
+		if err = waitTask(ctx, task, err); err != nil {
+			return fmt.Errorf("failed to upgrade the virtual hardware to %s: %w", version, err)
+		}
+	}
+
+	task, err := machine.Reconfigure(ctx, spec)
+	return waitTask(ctx, task, err)
+}
+
+// virtualDiskChanges grows the boot disk and replaces the other disks of the
+// virtual machine with the configured ones, the disk files are left untouched.
+func (api *apiClient) virtualDiskChanges(ctx context.Context, devices object.VirtualDeviceList, vm VirtualMachine) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	var changes []types.BaseVirtualDeviceConfigSpec
+
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		disk := device.(*types.VirtualDisk)
+		if slot := diskSlot(devices, disk); slot != bootDiskSlot {
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationRemove,
+				Device:    disk,
+			})
+			continue
+		}
+
+		capacity := int64(vm.BootDiskSize) * kilobytesPerGigabyte
+		if disk.CapacityInKB > capacity {
+			return nil, fmt.Errorf("failed to grow boot disk: not able to shrink virtual disk: %s", diskFileName(disk))
+		}
+		if disk.CapacityInKB < capacity {
+			disk.CapacityInKB = capacity
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationEdit,
+				Device:    disk,
+			})
+		}
+	}
+
+	added := devices
+	for _, vd := range vm.VirtualDisks {
+		if vd.VirtualDiskId == "" {
+			continue
+		}
+		bus, unit, err := parseDiskSlot(vd.Slot)
+		if err != nil {
+			return nil, err
+		}
+		fileName, err := datastorePath(vd.VirtualDiskId)
+		if err != nil {
+			return nil, err
+		}
+
+		controller := scsiController(added, bus)
+		if controller == nil {
+			device, err := added.CreateSCSIController("lsilogic")
+			if err != nil {
+				return nil, err
+			}
+			device.(types.BaseVirtualSCSIController).GetVirtualSCSIController().BusNumber = bus
+			controller = device.(types.BaseVirtualController)
+			added = append(added, device)
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationAdd,
+				Device:    device,
+			})
+		}
+
+		var datastore types.ManagedObjectReference
+		var dsPath object.DatastorePath
+		if dsPath.FromString(fileName) {
+			if ds, err := api.datastore(ctx, dsPath.Datastore); err == nil {
+				datastore = ds.Reference()
+			}
+		}
+		disk := added.CreateDisk(controller, datastore, fileName)
+		disk.UnitNumber = types.NewInt32(unit)
+		added = append(added, disk)
+		changes = append(changes, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationAdd,
+			Device:    disk,
+		})
+	}
+
+	return changes, nil
+}
+
+// networkInterfaceChanges replaces the network interfaces of the new virtual
+// machines, only the missing interfaces are added to the existing ones.
+func (api *apiClient) networkInterfaceChanges(ctx context.Context, devices object.VirtualDeviceList, isNew bool, networkInterfaces []NetworkInterface) ([]types.BaseVirtualDeviceConfigSpec, error) {
+	var changes []types.BaseVirtualDeviceConfigSpec
+
+	existing := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+	if isNew {
+		for _, device := range existing {
+			changes = append(changes, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationRemove,
+				Device:    device,
+			})
+		}
+		existing = nil
+	}
+
+	for i, ni := range networkInterfaces {
+		if len(ni.VirtualNetwork) == 0 || i < len(existing) {
+			continue
+		}
+
+		network, err := api.finder.Network(ctx, ni.VirtualNetwork)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the virtual network %s: %w", ni.VirtualNetwork, err)
+		}
+		backing, err := network.EthernetCardBackingInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		nicType := ni.NicType
+		if nicType == "" {
+			nicType = defaultNicType
+		}
+		device, err := devices.CreateEthernetCard(nicType, backing)
+		if err != nil {
+			return nil, err
+		}
+		if ni.MacAddress != "" {
+			card := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+			card.AddressType = string(types.VirtualEthernetCardMacTypeManual)
+			card.MacAddress = ni.MacAddress
+		}
+		changes = append(changes, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationAdd,
+			Device:    device,
+		})
+	}
+
+	return changes, nil
+}
+
+func (api *apiClient) readVirtualMachine(ctx context.Context, vm VirtualMachine, sleep func(context.Context, time.Duration) error) (VirtualMachine, error) {
+	var properties mo.VirtualMachine
+	err := api.retrieve(ctx, api.virtualMachine(vm.Id).Reference(), vmProperties, &properties)
+	if err != nil && !isNotFoundFault(err) {
+		return VirtualMachine{}, fmt.Errorf("failed to get the virtual machine %s: %w", vm.Id, err)
+	}
+	if err != nil || properties.Config == nil {
+		logging.V(logLevel).Infof("readVirtualMachine: failed to get the virtual machine %s: %s", vm.Id, err)
+		return VirtualMachine{}, &NotFoundError{Kind: "virtual machine", Id: vm.Id}
+	}
+
+	config := properties.Config
+	vm.Name = properties.Name
+	vm.MemSize = int(config.Hardware.MemoryMB)
+	vm.NumVCpus = int(config.Hardware.NumCPU)
+	vm.VirtualHWVer, _ = strconv.Atoi(strings.TrimPrefix(config.Version, "vmx-"))
+	vm.Os = vmxGuestOS(config.GuestId)
+	vm.BootFirmware = config.Firmware
+	if config.BootOptions != nil && config.BootOptions.EfiSecureBootEnabled != nil {
+		vm.EfiSecureBoot = *config.BootOptions.EfiSecureBootEnabled
+	}
+	vm.Notes = config.Annotation
+
+	var dsPath object.DatastorePath
+	if dsPath.FromString(config.Files.VmPathName) {
+		vm.DiskStore = dsPath.Datastore
+	}
+
+	if properties.ResourcePool != nil {
+		pools, err := api.resourcePools(ctx)
+		if err == nil {
+			vm.ResourcePoolName, _ = resourcePoolPath(pools, properties.ResourcePool.Value)
+		}
+	}
+
+	devices := object.VirtualDeviceList(config.Hardware.Device)
+	vm.VirtualDisks = []VMVirtualDisk{}
+	vm.BootDiskSize, vm.BootDiskType = 0, esxiUnknown
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		disk := device.(*types.VirtualDisk)
+		slot := diskSlot(devices, disk)
+		if slot == bootDiskSlot {
+			vm.BootDiskSize = int(disk.CapacityInKB / kilobytesPerGigabyte)
+			vm.BootDiskType = diskType(disk)
+			continue
+		}
+		vm.VirtualDisks = append(vm.VirtualDisks, VMVirtualDisk{
+			Slot:          slot,
+			VirtualDiskId: volumePath(diskFileName(disk)),
+		})
+	}
+
+	vm.NetworkInterfaces = nil
+	for _, device := range devices.SelectByType((*types.VirtualEthernetCard)(nil)) {
+		card := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		ni := NetworkInterface{NicType: nicType(device)}
+		if backing, ok := card.Backing.(*types.VirtualEthernetCardNetworkBackingInfo); ok {
+			ni.VirtualNetwork = backing.DeviceName
+		}
+		if card.AddressType == string(types.VirtualEthernetCardMacTypeManual) {
+			ni.MacAddress = card.MacAddress
+		}
+		vm.NetworkInterfaces = append(vm.NetworkInterfaces, ni)
+	}
+
+	vm.Info = nil
+	for _, option := range config.ExtraConfig {
+		value := option.GetOptionValue()
+		if key := strings.TrimPrefix(value.Key, "guestinfo."); key != value.Key {
+			vm.Info = append(vm.Info, KeyValuePair{Key: key, Value: fmt.Sprint(value.Value)})
+		}
+	}
+
+	vm.Power = powerState(properties.Runtime.PowerState)
+	logging.V(logLevel).Infof("readVirtualMachine: Power => %s", vm.Power)
+
+	// Get IP address (need vmware tools installed)
+	vm.IpAddress = ""
+	if vm.Power == vmTurnedOn {
+		vm.IpAddress = api.virtualMachineIpAddress(ctx, vm.Id, vm.StartupTimeout, sleep)
+		logging.V(logLevel).Infof("readVirtualMachine: IpAddress found => %s", vm.IpAddress)
+	}
+
+	return vm, nil
+}
+
+// virtualMachineIpAddress waits for the ip address reported by the vmware
+// tools until the uptime of the guest, or the time waited, reaches the startup timeout.
+func (api *apiClient) virtualMachineIpAddress(ctx context.Context, id string, startupTimeout int, sleep func(context.Context, time.Duration) error) string {
+	for waited := 0; ; waited += vmSleepBetweenPowerStateChecks {
+		var properties mo.VirtualMachine
+		err := api.retrieve(ctx, api.virtualMachine(id).Reference(), []string{"guest.ipAddress", "summary.quickStats.uptimeSeconds"}, &properties)
+		if err != nil {
+			return ""
+		}
+		if properties.Guest != nil && properties.Guest.IpAddress != "" {
+			return properties.Guest.IpAddress
+		}
+		if int(properties.Summary.QuickStats.UptimeSeconds) >= startupTimeout || waited >= startupTimeout {
+			return ""
+		}
+		if sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second) != nil {
+			return ""
+		}
+	}
+}
+
+func (api *apiClient) updateVirtualMachine(ctx context.Context, vm VirtualMachine, sleep func(context.Context, time.Duration) error) error {
+	currentPowerState := api.virtualMachinePowerState(ctx, vm.Id)
+	if currentPowerState == vmTurnedOn || currentPowerState == vmTurnedSuspended {
+		api.powerOffVirtualMachine(ctx, vm.Id, vm.ShutdownTimeout, sleep)
+	}
+
+	if err := api.reconfigureVirtualMachine(ctx, false, vm); err != nil {
+		return fmt.Errorf("failed to update the virtual machine: %w", err)
+	}
+
+	if vm.Power == vmTurnedOn {
+		if err := api.powerOnVirtualMachine(ctx, vm.Id); err != nil {
+			return fmt.Errorf("failed to power on: %w", err)
+		}
+	}
+	return nil
+}
+
+func (api *apiClient) deleteVirtualMachine(ctx context.Context, id string, sleep func(context.Context, time.Duration) error) error {
+	api.powerOffVirtualMachine(ctx, id, vmDefaultShutdownTimeout, sleep)
+
+	// detach the storage so it doesn't get deleted with the virtual machine
+	var current mo.VirtualMachine
+	err := api.retrieve(ctx, api.virtualMachine(id).Reference(), []string{"config"}, &current)
+	if err == nil && current.Config != nil {
+		devices := object.VirtualDeviceList(current.Config.Hardware.Device)
+		var changes []types.BaseVirtualDeviceConfigSpec
+		for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+			if diskSlot(devices, device.(*types.VirtualDisk)) != bootDiskSlot {
+				changes = append(changes, &types.VirtualDeviceConfigSpec{
+					Operation: types.VirtualDeviceConfigSpecOperationRemove,
+					Device:    device,
+				})
+			}
+		}
+		if len(changes) > 0 {
+			task, err := api.virtualMachine(id).Reconfigure(ctx, types.VirtualMachineConfigSpec{DeviceChange: changes})
+			if err = waitTask(ctx, task, err); err != nil {
+				logging.V(logLevel).Infof("VirtualMachineDelete: failed clean storage from id: %s (to be deleted)", id)
+			}
+		}
+	}
+
+	task, err := api.virtualMachine(id).Destroy(ctx)
+	if err = waitTask(ctx, task, err); err != nil {
+		return fmt.Errorf("failed to destroy vm: %w", err)
+	}
+	return nil
+}
+
+func (api *apiClient) virtualMachineId(ctx context.Context, name string) (string, error) {
+	machine, err := api.finder.VirtualMachine(ctx, name)
+	if err != nil {
+		logging.V(logLevel).Infof("getVirtualMachineId: Failed get vm id => %s", err)
+		return "", fmt.Errorf("unable to find a virtual machine corresponding to the name '%s'", name)
+	}
+	return machine.Reference().Value, nil
+}
+
+func (api *apiClient) virtualMachinePowerState(ctx context.Context, id string) string {
+	state, err := api.virtualMachine(id).PowerState(ctx)
+	if err != nil {
+		return esxiUnknown
+	}
+	return powerState(state)
+}
+
+func (api *apiClient) powerOnVirtualMachine(ctx context.Context, id string) error {
+	if api.virtualMachinePowerState(ctx, id) == vmTurnedOn {
+		return nil
+	}
+	task, err := api.virtualMachine(id).PowerOn(ctx)
+	return waitTask(ctx, task, err)
+}
+
+// powerOffVirtualMachine shuts the guest down, and powers the virtual machine
+// off when it is still running after the shutdownTimeout (in seconds).
+func (api *apiClient) powerOffVirtualMachine(ctx context.Context, id string, shutdownTimeout int, sleep func(context.Context, time.Duration) error) {
+	machine := api.virtualMachine(id)
+	switch api.virtualMachinePowerState(ctx, id) {
+	case vmTurnedOff:
+		// VM is already turned off, no need to do anything.
+		return
+	case vmTurnedOn:
+		if shutdownTimeout > 0 && machine.ShutdownGuest(ctx) == nil {
+			for i := 0; i <= shutdownTimeout/vmSleepBetweenPowerStateChecks; i++ {
+				if api.virtualMachinePowerState(ctx, id) == vmTurnedOff {
+					// VM is successfully shut down.
+					return
+				}
+				if sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second) != nil {
+					return
+				}
+			}
+		}
+	}
+
+	task, err := machine.PowerOff(ctx)
+	if err = waitTask(ctx, task, err); err != nil {
+		logging.V(logLevel).Infof("powerOffVirtualMachine: failed to power off %s: %s", id, err)
+	}
+}
+
+func powerState(state types.VirtualMachinePowerState) string {
+	switch state {
+	case types.VirtualMachinePowerStatePoweredOff:
+		return vmTurnedOff
+	case types.VirtualMachinePowerStatePoweredOn:
+		return vmTurnedOn
+	case types.VirtualMachinePowerStateSuspended:
+		return vmTurnedSuspended
+	default:
+		return esxiUnknown
+	}
+}
+
+// guestId converts the vmx guestOS to the api guest id, e.g. centos-64 to centos64Guest.
+func guestId(os string) string {
+	if id, ok := guestIds[os]; ok {
+		return id
+	}
+	if base, is64 := strings.CutSuffix(os, "-64"); is64 {
+		if last := base[len(base)-1]; last >= '0' && last <= '9' {
+			// e.g. windows9-64 is windows9_64Guest
+			return base + "_64" + guestIdSuffix
+		}
+		return base + "64" + guestIdSuffix
+	}
+	return os + guestIdSuffix
+}
+
+// vmxGuestOS converts the api guest id to the vmx guestOS, e.g. centos64Guest to centos-64.
+func vmxGuestOS(id string) string {
+	for os, guest := range guestIds {
+		if guest == id {
+			return os
+		}
+	}
+	base := strings.TrimSuffix(id, guestIdSuffix)
+	if trimmed, is64 := strings.CutSuffix(base, "64"); is64 {
+		return strings.TrimSuffix(trimmed, "_") + "-64"
+	}
+	return base
+}
+
+func hardwareVersion(version int) string {
+	return fmt.Sprintf("vmx-%02d", version)
+}
+
+// nicType returns the vmx virtualDev of the ethernet card, e.g. vmxnet3 for a VirtualVmxnet3.
+func nicType(device types.BaseVirtualDevice) string {
+	return strings.ToLower(strings.TrimPrefix(reflect.TypeOf(device).Elem().Name(), "Virtual"))
+}
+
+// diskSlot returns the "bus:unit" slot of the disk.
+func diskSlot(devices object.VirtualDeviceList, disk *types.VirtualDisk) string {
+	var bus int32 = -1
+	if controller, ok := devices.FindByKey(disk.ControllerKey).(types.BaseVirtualController); ok {
+		bus = controller.GetVirtualController().BusNumber
+	}
+	var unit int32
+	if disk.UnitNumber != nil {
+		unit = *disk.UnitNumber
+	}
+	return fmt.Sprintf("%d:%d", bus, unit)
+}
+
+func parseDiskSlot(slot string) (int32, int32, error) {
+	parts := strings.Split(slot, ":")
+	const slotParts = 2
+	if len(parts) == slotParts {
+		bus, busErr := strconv.Atoi(parts[0])
+		unit, unitErr := strconv.Atoi(parts[1])
+		if busErr == nil && unitErr == nil {
+			return int32(bus), int32(unit), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid virtual disk slot '%s', expected bus:unit", slot)
+}
+
+func scsiController(devices object.VirtualDeviceList, bus int32) types.BaseVirtualController {
+	for _, device := range devices.SelectByType((*types.VirtualSCSIController)(nil)) {
+		if controller := device.(types.BaseVirtualController); controller.GetVirtualController().BusNumber == bus {
+			return controller
+		}
+	}
+	return nil
+}
+
+func diskFileName(disk *types.VirtualDisk) string {
+	if backing, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+		return backing.GetVirtualDeviceFileBackingInfo().FileName
+	}
+	return ""
+}
+
+func diskType(disk *types.VirtualDisk) string {
+	backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+	switch {
+	case !ok:
+		return esxiUnknown
+	case backing.ThinProvisioned != nil && *backing.ThinProvisioned:
+		return vdThin
+	case backing.EagerlyScrub != nil && *backing.EagerlyScrub:
+		return vdEagerZeroedThick
+	default:
+		return vdZeroedThick
+	}
+}
+
+func setDiskType(disk *types.VirtualDisk, diskType string) {
+	backing, ok := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+	if !ok {
+		return
+	}
+	backing.ThinProvisioned = types.NewBool(diskType == vdThin)
+	backing.EagerlyScrub = types.NewBool(diskType == vdEagerZeroedThick)
+}
@@ -1,31 +1,76 @@
 package esxi
 
+import "regexp"
+
+const (
+	// DefaultAutoNamingPattern names the resources after their URN name, with
+	// a random suffix.
+	DefaultAutoNamingPattern = "${name}-${random}"
+	// DefaultAutoNamingRandomLength is the length of the random part of the names.
+	DefaultAutoNamingRandomLength = 7
+	// DefaultAutoNamingCharset are the characters of the random part of the names.
+	DefaultAutoNamingCharset = "0123456789abcdef"
+)
+
+// invalidNameChars matches the characters not allowed in the generated names,
+// the names of the resources are also file names, or paths on the host.
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// AutoNamingConfig is how the names of the resources without one are
+// generated, from the provider config.
+type AutoNamingConfig struct {
+	// Disabled leaves the resources without a name, failing their check.
+	Disabled bool
+	// Pattern of the names, with the ${name}, ${stack}, ${project} and
+	// ${random} placeholders.
+	Pattern string
+	// RandomLength is the length of the random part of the names.
+	RandomLength int
+	// Charset are the characters of the random part of the names.
+	Charset string
+	// Case is 'lower' or 'upper' to change the case of the names, they are
+	// kept as they are else.
+	Case string
+}
+
 type AutoNamingSpec struct {
 	PropertyName string
 	MinLength    int
 	MaxLength    int
+	// InvalidChars matches the characters not allowed in the names by the
+	// host, they are replaced with '-' in the generated names.
+	InvalidChars *regexp.Regexp
+	AutoNamingConfig
 }
 
 type AutoNamingService struct {
-	rules map[string]AutoNamingSpec
+	rules  map[string]AutoNamingSpec
+	config AutoNamingConfig
 }
 
 func NewAutoNamingService() *AutoNamingService {
+	return NewAutoNamingServiceWithConfig(AutoNamingConfig{})
+}
+
+// NewAutoNamingServiceWithConfig returns the auto-naming service generating
+// the names with the config, the names fitting the limits of the host.
+func NewAutoNamingServiceWithConfig(config AutoNamingConfig) *AutoNamingService {
 	return &AutoNamingService{
 		rules: map[string]AutoNamingSpec{
-			"esxi-native:index:PortGroup":      {"name", 3, 250},
-			"esxi-native:index:ResourcePool":   {"name", 5, 250},
-			"esxi-native:index:VirtualDisk":    {"name", 3, 250},
-			"esxi-native:index:VirtualMachine": {"name", 5, 250},
-			"esxi-native:index:VirtualSwitch":  {"name", 3, 250},
+			"esxi-native:index:PortGroup":      {"name", 3, 59, invalidNameChars, config},
+			"esxi-native:index:ResourcePool":   {"name", 5, 80, invalidNameChars, config},
+			"esxi-native:index:VirtualDisk":    {"name", 3, 245, invalidNameChars, config},
+			"esxi-native:index:VirtualMachine": {"name", 5, 80, invalidNameChars, config},
+			"esxi-native:index:VirtualSwitch":  {"name", 3, 31, invalidNameChars, config},
 		},
+		config: config,
 	}
 }
 
 func (service *AutoNamingService) GetAutoNamingSpec(token string) *AutoNamingSpec {
 	// AutoNaming
 	autoNameSpec, ok := service.rules[token]
-	if !ok {
+	if !ok || service.config.Disabled {
 		return nil
 	}
 
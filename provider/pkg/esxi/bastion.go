@@ -0,0 +1,219 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"golang.org/x/crypto/ssh"
+)
+
+const defaultBastionPort = "22"
+
+var errBastionClosed = errors.New("the bastion connection is closed")
+
+// bastion is the ssh jump host the connections to the esxi host are tunnelled
+// through. Its connection is opened on first use and replaced when it broke.
+type bastion struct {
+	address      string
+	clientConfig *ssh.ClientConfig
+	// agentConn is the connection to the ssh-agent, nil when it isn't used.
+	agentConn io.Closer
+
+	mutex  sync.Mutex
+	client *ssh.Client
+	closed bool
+}
+
+// hasBastion returns true when the esxi host is reached through a bastion.
+func (c *ConnectionInfo) hasBastion() bool {
+	return len(c.BastionHost) > 0
+}
+
+// bastionConnection returns the connection info of the bastion itself, so its
+// authentication and host key verification are built like the ones of the host.
+// The ssh agent and the known hosts file, or the keys trusted on first use,
+// are shared with the esxi host, the host key fingerprint only applies to the
+// esxi host.
+func (c *ConnectionInfo) bastionConnection() *ConnectionInfo {
+	bastion := &ConnectionInfo{
+		Host:            c.BastionHost,
+		SSHPort:         c.BastionPort,
+		UserName:        c.BastionUser,
+		Password:        c.BastionPassword,
+		PrivateKey:      c.BastionPrivateKey,
+		PrivateKeyPath:  c.BastionPrivateKeyPath,
+		UseSSHAgent:     c.UseSSHAgent,
+		KnownHostsFile:  c.KnownHostsFile,
+		TrustOnFirstUse: c.TrustOnFirstUse,
+		TrustedHostKeys: c.TrustedHostKeys,
+
+		InsecureSkipHostKeyVerification: c.InsecureSkipHostKeyVerification,
+	}
+	if len(bastion.SSHPort) == 0 {
+		bastion.SSHPort = defaultBastionPort
+	}
+	if len(bastion.UserName) == 0 {
+		bastion.UserName = c.UserName
+	}
+	return bastion
+}
+
+func newBastion(connection *ConnectionInfo) (*bastion, error) {
+	bastionConnection := connection.bastionConnection()
+	authMethods, agentConn, err := bastionConnection.getAuthMethods()
+	if err != nil {
+		return nil, fmt.Errorf("bastion %s: %w", bastionConnection.Host, err)
+	}
+	hostKeyCallback, err := bastionConnection.getHostKeyCallback()
+	if err != nil {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+		return nil, fmt.Errorf("bastion %s: %w", bastionConnection.Host, err)
+	}
+
+	return &bastion{
+		address:   bastionConnection.getSSHConnection(),
+		agentConn: agentConn,
+		clientConfig: &ssh.ClientConfig{
+			User:            bastionConnection.UserName,
+			Auth:            authMethods,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sshDialTimeout,
+		},
+	}, nil
+}
+
+// DialContext opens a tcp connection to the address through the bastion. When
+// the bastion connection is broken it is dialed again once.
+func (b *bastion) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	for retried := false; ; retried = true {
+		client, err := b.connect()
+		if err != nil {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		conn, err := client.Dial(network, address)
+		if err == nil {
+			return conn, nil
+		}
+		var openErr *ssh.OpenChannelError
+		if retried || errors.As(err, &openErr) {
+			// The bastion itself refused the connection, e.g. forwarding is disabled or the host is unreachable.
+			return nil, fmt.Errorf("failed to reach %s through bastion %s: %w", address, b.address, err)
+		}
+		logging.V(logLevel).Infof("DialContext: bastion connection broken, reconnecting: %s", err)
+		b.reset(client)
+	}
+}
+
+// connect returns the connection to the bastion, dialing it when required.
+func (b *bastion) connect() (*ssh.Client, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return nil, errBastionClosed
+	}
+	if b.client != nil {
+		return b.client, nil
+	}
+
+	logging.V(logLevel).Infof("connect: dialing bastion %s", b.address)
+	client, err := ssh.Dial("tcp", b.address, b.clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to bastion %s: %w", b.address, err)
+	}
+	b.client = client
+	return client, nil
+}
+
+// reset drops the broken client, unless it was already replaced.
+func (b *bastion) reset(client *ssh.Client) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == client {
+		b.client = nil
+	}
+	_ = client.Close()
+}
+
+// forward listens on a local port and forwards its connections to the address
+// through the bastion, for the local tools like ovftool that can't be given a
+// dialer. It returns the local address and the function stopping the forward.
+func (b *bastion) forward(ctx context.Context, address string) (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to listen for the port forward to %s: %w", address, err)
+	}
+	logging.V(logLevel).Infof("forward: forwarding %s to %s through bastion %s", listener.Addr(), address, b.address)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer local.Close()
+				remote, err := b.DialContext(ctx, "tcp", address)
+				if err != nil {
+					logging.V(logLevel).Infof("forward: %s", err)
+					return
+				}
+				defer remote.Close()
+				pipe(local, remote)
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), func() {
+		_ = listener.Close()
+		wg.Wait()
+	}, nil
+}
+
+// pipe copies the data both ways until one of the sides closes its connection.
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go copyConn(a, b)
+	go copyConn(b, a)
+	<-done
+	_ = a.Close()
+	_ = b.Close()
+	<-done
+}
+
+// Close closes the connection to the bastion.
+func (b *bastion) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	if b.client != nil {
+		_ = b.client.Close()
+		b.client = nil
+	}
+	if b.agentConn != nil {
+		_ = b.agentConn.Close()
+		b.agentConn = nil
+	}
+}
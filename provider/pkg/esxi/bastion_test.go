@@ -0,0 +1,77 @@
+package esxi
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBastionConnection(t *testing.T) {
+	connection := ConnectionInfo{
+		Host:               "esxi",
+		UserName:           "root",
+		Password:           "secret",
+		HostKeyFingerprint: "SHA256:esxi",
+		KnownHostsFile:     "/tmp/known_hosts",
+		BastionHost:        "bastion",
+		BastionPassword:    "bastion-secret",
+	}
+	require.True(t, connection.hasBastion())
+
+	bastion := connection.bastionConnection()
+	require.Equal(t, "bastion:22", bastion.getSSHConnection())
+	require.Equal(t, "root", bastion.UserName)
+	require.Equal(t, "bastion-secret", bastion.Password)
+	require.Equal(t, "/tmp/known_hosts", bastion.KnownHostsFile)
+	require.Empty(t, bastion.HostKeyFingerprint)
+
+	connection.BastionPort = "2222"
+	connection.BastionUser = "jump"
+	bastion = connection.bastionConnection()
+	require.Equal(t, "bastion:2222", bastion.getSSHConnection())
+	require.Equal(t, "jump", bastion.UserName)
+}
+
+func TestBastionForward(t *testing.T) {
+	server := newTestSSHServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+
+	tunnel, err := newBastion(&ConnectionInfo{
+		BastionHost: host, BastionPort: port, BastionUser: "jump", BastionPassword: "secret",
+		TrustOnFirstUse: true, TrustedHostKeys: map[string]string{server.listener.Addr().String(): server.fingerprint},
+	})
+	require.NoError(t, err)
+	defer tunnel.Close()
+
+	localAddress, stop, err := tunnel.forward(context.Background(), "esxi:443")
+	require.NoError(t, err)
+	defer stop()
+
+	echo := func() {
+		conn, err := net.Dial("tcp", localAddress)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		reply := make([]byte, 4)
+		_, err = io.ReadFull(conn, reply)
+		require.NoError(t, err)
+		require.Equal(t, "ping", string(reply))
+	}
+	echo()
+	echo()
+	require.EqualValues(t, 1, server.dials.Load())
+
+	// The bastion connection is dialed again once it broke.
+	server.dropConnections()
+	echo()
+	require.EqualValues(t, 2, server.dials.Load())
+
+	tunnel.Close()
+	_, err = tunnel.DialContext(context.Background(), "tcp", "esxi:443")
+	require.ErrorIs(t, err, errBastionClosed)
+}
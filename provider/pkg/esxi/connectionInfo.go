@@ -2,16 +2,186 @@ package esxi
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// The transports used to manage the resources of the esxi host.
+const (
+	// TransportSSH runs esxcli, vim-cmd and vmkfstools commands over ssh.
+	TransportSSH = "ssh"
+	// TransportAPI calls the SOAP API of the host agent on the SslPort.
+	TransportAPI = "api"
 )
 
 type ConnectionInfo struct {
+	// Transport is TransportSSH or TransportAPI, ssh is used when empty.
+	Transport string
+
 	Host     string
 	SSHPort  string
 	SslPort  string
 	UserName string
 	Password string
+
+	// PrivateKey is the PEM encoded private key used for public-key authentication.
+	PrivateKey string
+	// PrivateKeyPath is the path to a PEM encoded private key, used when PrivateKey is empty.
+	PrivateKeyPath string
+	// PrivateKeyPassphrase decrypts PrivateKey/PrivateKeyPath when the key is encrypted.
+	PrivateKeyPassphrase string
+	// UseSSHAgent enables authentication through the agent listening on SSH_AUTH_SOCK.
+	UseSSHAgent bool
+
+	// HostKeyFingerprint is the expected SHA256 fingerprint of the host key.
+	HostKeyFingerprint string
+	// KnownHostsFile is an OpenSSH known_hosts file used to verify the host key.
+	KnownHostsFile string
+	// TrustOnFirstUse records the key of an unknown host into the known hosts
+	// file when one is set, else into TrustedHostKeys.
+	TrustOnFirstUse bool
+	// TrustedHostKeys are the SHA256 fingerprints of the host keys recorded by
+	// TrustOnFirstUse, by ssh address, they are kept in the provider state.
+	TrustedHostKeys map[string]string
+	// SslThumbprint is the expected SHA-1 or SHA-256 thumbprint of the tls
+	// certificate of the host, verified by the api transport.
+	SslThumbprint string
+	// InsecureSkipHostKeyVerification accepts any host key, and any tls
+	// certificate with the api transport, it is only used when no other
+	// verification is configured.
+	InsecureSkipHostKeyVerification bool
+
+	// BastionHost is the ssh jump host the esxi host is reached through, the
+	// host is connected to directly when it is empty.
+	BastionHost string
+	// BastionPort is the ssh port of the bastion, 22 when empty.
+	BastionPort string
+	// BastionUser is the user on the bastion, UserName is used when empty.
+	BastionUser string
+	// BastionPassword, BastionPrivateKey and BastionPrivateKeyPath authenticate on the bastion,
+	// the ssh agent is used as well with UseSSHAgent.
+	BastionPassword       string
+	BastionPrivateKey     string
+	BastionPrivateKeyPath string
+
+	// Retry is the retry policy of the connections and of the remote commands
+	// safe to run again, the DefaultRetryPolicy values are used for the unset fields.
+	Retry RetryPolicy
+
+	// MaxConcurrentSessions bounds the remote operations running at once on the
+	// host, DefaultMaxConcurrentSessions is used when it is not set.
+	MaxConcurrentSessions int
+
+	// RecordFile is the JSON lines file the remote operations are appended to,
+	// for a ReplayExecutor to serve them back, nothing is recorded when empty.
+	RecordFile string
+
+	// ReadOnly refuses the commands changing the host, only the known read
+	// commands run, and the resources can't be created, updated or deleted.
+	ReadOnly bool
+	// DryRun logs the commands changing the host instead of running them, and
+	// fails the resource changes once their commands are logged.
+	DryRun bool
+}
+
+func (c *ConnectionInfo) maxConcurrentSessions() int {
+	if c.MaxConcurrentSessions > 0 {
+		return c.MaxConcurrentSessions
+	}
+	return DefaultMaxConcurrentSessions
 }
 
 func (c *ConnectionInfo) getSSHConnection() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.SSHPort)
 }
+
+// hasPrivateKey returns true when a private key is configured, either inline or as a path.
+func (c *ConnectionInfo) hasPrivateKey() bool {
+	return len(c.PrivateKey) > 0 || len(c.PrivateKeyPath) > 0
+}
+
+// getAuthMethods builds the ssh authentication methods in order of preference:
+// the configured private key, the ssh-agent and finally the password as a fallback.
+// The connection to the ssh-agent is returned when it is used, for the caller
+// to close it along with its ssh connections.
+func (c *ConnectionInfo) getAuthMethods() ([]ssh.AuthMethod, io.Closer, error) {
+	var methods []ssh.AuthMethod
+
+	if c.hasPrivateKey() {
+		signer, err := c.getPrivateKeySigner()
+		if err != nil {
+			return nil, nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	var agentConn net.Conn
+	if c.UseSSHAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if len(socket) == 0 {
+			return nil, nil, fmt.Errorf("ssh agent authentication requested, but SSH_AUTH_SOCK is not set")
+		}
+		// The agent connection is kept open until it is closed by the caller,
+		// signers are requested lazily on every handshake.
+		var err error
+		agentConn, err = net.Dial("unix", socket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to ssh agent: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	}
+
+	if len(c.Password) > 0 {
+		password := c.Password
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				// Reply password to all questions
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+
+				return answers, nil
+			}))
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, fmt.Errorf("no ssh authentication method configured, provide a password, a private key or enable the ssh agent")
+	}
+
+	if agentConn == nil {
+		return methods, nil, nil
+	}
+	return methods, agentConn, nil
+}
+
+func (c *ConnectionInfo) getPrivateKeySigner() (ssh.Signer, error) {
+	key := []byte(c.PrivateKey)
+	if len(key) == 0 {
+		var err error
+		key, err = os.ReadFile(c.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key '%s': %w", c.PrivateKeyPath, err)
+		}
+	}
+
+	var signer ssh.Signer
+	var err error
+	if len(c.PrivateKeyPassphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(c.PrivateKeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	logging.V(logLevel).Infof("getPrivateKeySigner: using %s key", signer.PublicKey().Type())
+
+	return signer, nil
+}
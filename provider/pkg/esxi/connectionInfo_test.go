@@ -0,0 +1,120 @@
+package esxi
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestGetAuthMethods(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, []byte(privateKey), 0o600))
+
+	tests := []struct {
+		name       string
+		connection ConnectionInfo
+		methods    int
+		err        string
+	}{
+		{
+			name:       "No credentials",
+			connection: ConnectionInfo{},
+			err:        "no ssh authentication method configured, provide a password, a private key or enable the ssh agent",
+		},
+		{
+			name:       "Password only",
+			connection: ConnectionInfo{Password: "secret"},
+			methods:    2,
+		},
+		{
+			name:       "Private key only",
+			connection: ConnectionInfo{PrivateKey: privateKey},
+			methods:    1,
+		},
+		{
+			name:       "Private key path with password fallback",
+			connection: ConnectionInfo{PrivateKeyPath: keyPath, Password: "secret"},
+			methods:    3,
+		},
+		{
+			name:       "Invalid private key",
+			connection: ConnectionInfo{PrivateKey: "not a key"},
+			err:        "failed to parse private key: ssh: no key found",
+		},
+		{
+			name:       "Agent without socket",
+			connection: ConnectionInfo{UseSSHAgent: true},
+			err:        "ssh agent authentication requested, but SSH_AUTH_SOCK is not set",
+		},
+	}
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			methods, _, err := tt.connection.getAuthMethods()
+			if len(tt.err) > 0 {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, methods, tt.methods)
+		})
+	}
+}
+
+func TestSSHAgentConnectionClosed(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	served := make(chan struct{}, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = agent.ServeAgent(agent.NewKeyring(), conn)
+				served <- struct{}{}
+			}()
+		}
+	}()
+
+	// The agent connections of the executor and of the bastion are closed
+	// with them.
+	connection := &ConnectionInfo{
+		Host: "esxi.local", SSHPort: "22", UserName: "root", UseSSHAgent: true, InsecureSkipHostKeyVerification: true,
+		BastionHost: "jump.local",
+	}
+	executor, err := newSSHExecutor(connection, nil)
+	require.NoError(t, err)
+	tunnel, err := newBastion(connection)
+	require.NoError(t, err)
+	executor.Close()
+	tunnel.Close()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-served:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the ssh agent connection wasn't closed")
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package esxi
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// DiffRules are how the changes of the inputs of a resource are applied, the
+// changes of the other inputs are updated in place.
+type DiffRules struct {
+	// Replaces are the inputs whose change replaces the resource.
+	Replaces []string
+	// GrowOnly are the sizes grown in place and replacing the resource when
+	// they shrink, with their default value.
+	GrowOnly map[string]float64
+	// Names are the inputs naming the resource on its host. The replacement is
+	// created before the resource is deleted when one of them changes, else
+	// the resource is deleted first, two resources can't have the same names.
+	Names []string
+}
+
+// diffInputs returns the changes of the inputs of the resource, with the
+// detailed diff of every changed input and whether it replaces the resource.
+func diffInputs(rules DiffRules, olds, news resource.PropertyMap) *pulumirpc.DiffResponse {
+	diff := olds.Diff(news)
+	if !diff.AnyChanges() {
+		return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_NONE}
+	}
+
+	response := &pulumirpc.DiffResponse{
+		Changes:         pulumirpc.DiffResponse_DIFF_SOME,
+		HasDetailedDiff: true,
+		DetailedDiff:    map[string]*pulumirpc.PropertyDiff{},
+	}
+	renamed := false
+	for _, key := range diff.ChangedKeys() {
+		name := string(key)
+		replace := Contains(rules.Replaces, name) || shrinks(name, rules, olds, news)
+		kind := pulumirpc.PropertyDiff_UPDATE
+		switch {
+		case diff.Added(key) && replace:
+			kind = pulumirpc.PropertyDiff_ADD_REPLACE
+		case diff.Added(key):
+			kind = pulumirpc.PropertyDiff_ADD
+		case diff.Deleted(key) && replace:
+			kind = pulumirpc.PropertyDiff_DELETE_REPLACE
+		case diff.Deleted(key):
+			kind = pulumirpc.PropertyDiff_DELETE
+		case replace:
+			kind = pulumirpc.PropertyDiff_UPDATE_REPLACE
+		}
+
+		response.Diffs = append(response.Diffs, name)
+		response.DetailedDiff[name] = &pulumirpc.PropertyDiff{Kind: kind, InputDiff: true}
+		if replace {
+			response.Replaces = append(response.Replaces, name)
+		}
+		renamed = renamed || Contains(rules.Names, name)
+	}
+	response.DeleteBeforeReplace = len(response.Replaces) > 0 && !renamed
+	return response
+}
+
+// shrinks returns true when the size is smaller in the new inputs, an unknown
+// new size is grown in place.
+func shrinks(name string, rules DiffRules, olds, news resource.PropertyMap) bool {
+	defaultSize, ok := rules.GrowOnly[name]
+	if !ok {
+		return false
+	}
+	size := func(inputs resource.PropertyMap) (float64, bool) {
+		property, has := inputs[resource.PropertyKey(name)]
+		switch {
+		case !has || property.IsNull():
+			return defaultSize, true
+		case property.IsNumber():
+			return property.NumberValue(), true
+		default:
+			return 0, false
+		}
+	}
+	oldSize, oldKnown := size(olds)
+	newSize, newKnown := size(news)
+	return oldKnown && newKnown && newSize < oldSize
+}
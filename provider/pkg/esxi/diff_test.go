@@ -0,0 +1,211 @@
+package esxi
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceDiff(t *testing.T) {
+	vm := resource.PropertyMap{
+		"name":         resource.NewStringProperty("web"),
+		"diskStore":    resource.NewStringProperty("datastore1"),
+		"memSize":      resource.NewNumberProperty(512),
+		"numVCpus":     resource.NewNumberProperty(1),
+		"bootDiskSize": resource.NewNumberProperty(16),
+		"info": resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+			"key":   resource.NewStringProperty("role"),
+			"value": resource.NewStringProperty("web"),
+		})}),
+	}
+	disk := resource.PropertyMap{
+		"name":      resource.NewStringProperty("data"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+		"size":      resource.NewNumberProperty(10),
+	}
+	portGroup := resource.PropertyMap{
+		"name":    resource.NewStringProperty("web"),
+		"vSwitch": resource.NewStringProperty("vSwitch0"),
+	}
+
+	tests := []struct {
+		name                string
+		token               string
+		olds                resource.PropertyMap
+		news                resource.PropertyMap
+		detailedDiff        map[string]pulumirpc.PropertyDiff_Kind
+		deleteBeforeReplace bool
+	}{
+		{name: "no change", token: "esxi-native:index:VirtualMachine", olds: vm, news: resource.PropertyMap{}},
+		{
+			name:  "vm sizing",
+			token: "esxi-native:index:VirtualMachine",
+			olds:  vm,
+			news: resource.PropertyMap{
+				"memSize":  resource.NewNumberProperty(1024),
+				"numVCpus": resource.NewNumberProperty(2),
+				"notes":    resource.NewStringProperty("web server"),
+			},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{
+				"memSize":  pulumirpc.PropertyDiff_UPDATE,
+				"numVCpus": pulumirpc.PropertyDiff_UPDATE,
+				"notes":    pulumirpc.PropertyDiff_ADD,
+			},
+		},
+		{
+			name:  "vm info",
+			token: "esxi-native:index:VirtualMachine",
+			olds:  vm,
+			news: resource.PropertyMap{"info": resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+				"key":   resource.NewStringProperty("role"),
+				"value": resource.NewStringProperty("db"),
+			})})},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{"info": pulumirpc.PropertyDiff_UPDATE},
+		},
+		{
+			name:                "vm boot disk type",
+			token:               "esxi-native:index:VirtualMachine",
+			olds:                vm,
+			news:                resource.PropertyMap{"bootDiskType": resource.NewStringProperty("zeroedthick")},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"bootDiskType": pulumirpc.PropertyDiff_ADD_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:  "vm ovf properties",
+			token: "esxi-native:index:VirtualMachine",
+			olds:  vm,
+			news: resource.PropertyMap{"ovfProperties": resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+				"key":   resource.NewStringProperty("hostname"),
+				"value": resource.NewStringProperty("web"),
+			})})},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"ovfProperties": pulumirpc.PropertyDiff_ADD_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:                "vm ovf properties timer",
+			token:               "esxi-native:index:VirtualMachine",
+			olds:                vm,
+			news:                resource.PropertyMap{"ovfPropertiesTimer": resource.NewNumberProperty(120)},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"ovfPropertiesTimer": pulumirpc.PropertyDiff_ADD_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:                "vm disk store",
+			token:               "esxi-native:index:VirtualMachine",
+			olds:                vm,
+			news:                resource.PropertyMap{"diskStore": resource.NewStringProperty("nvme")},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"diskStore": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:  "vm renamed",
+			token: "esxi-native:index:VirtualMachine",
+			olds:  vm,
+			news: resource.PropertyMap{
+				"name":      resource.NewStringProperty("web2"),
+				"ovfSource": resource.NewStringProperty("/images/web.ova"),
+			},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{
+				"name":      pulumirpc.PropertyDiff_UPDATE_REPLACE,
+				"ovfSource": pulumirpc.PropertyDiff_ADD_REPLACE,
+			},
+		},
+		{
+			name:                "vm boot disk shrinks",
+			token:               "esxi-native:index:VirtualMachine",
+			olds:                vm,
+			news:                resource.PropertyMap{"bootDiskSize": resource.NewNumberProperty(8)},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"bootDiskSize": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:         "disk grows",
+			token:        "esxi-native:index:VirtualDisk",
+			olds:         disk,
+			news:         resource.PropertyMap{"size": resource.NewNumberProperty(20)},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{"size": pulumirpc.PropertyDiff_UPDATE},
+		},
+		{
+			name:         "disk size unknown",
+			token:        "esxi-native:index:VirtualDisk",
+			olds:         disk,
+			news:         resource.PropertyMap{"size": resource.MakeComputed(resource.NewNumberProperty(0))},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{"size": pulumirpc.PropertyDiff_UPDATE},
+		},
+		{
+			name:                "disk shrinks",
+			token:               "esxi-native:index:VirtualDisk",
+			olds:                disk,
+			news:                resource.PropertyMap{"size": resource.NewNumberProperty(5)},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"size": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:                "disk type",
+			token:               "esxi-native:index:VirtualDisk",
+			olds:                disk,
+			news:                resource.PropertyMap{"diskType": resource.NewStringProperty("eagerzeroedthick")},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"diskType": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:         "disk moved",
+			token:        "esxi-native:index:VirtualDisk",
+			olds:         disk,
+			news:         resource.PropertyMap{"directory": resource.NewStringProperty("other")},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{"directory": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+		},
+		{
+			name:                "port group switch",
+			token:               "esxi-native:index:PortGroup",
+			olds:                portGroup,
+			news:                resource.PropertyMap{"vSwitch": resource.NewStringProperty("vSwitch1")},
+			detailedDiff:        map[string]pulumirpc.PropertyDiff_Kind{"vSwitch": pulumirpc.PropertyDiff_UPDATE_REPLACE},
+			deleteBeforeReplace: true,
+		},
+		{
+			name:         "port group vlan",
+			token:        "esxi-native:index:PortGroup",
+			olds:         portGroup,
+			news:         resource.PropertyMap{"vlan": resource.NewNumberProperty(10)},
+			detailedDiff: map[string]pulumirpc.PropertyDiff_Kind{"vlan": pulumirpc.PropertyDiff_ADD},
+		},
+	}
+	service := NewResourceService()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			news := test.olds.Copy()
+			for key, value := range test.news {
+				news[key] = value
+			}
+			response, err := service.Diff(test.token, test.olds, news)
+			require.NoError(t, err)
+			if len(test.detailedDiff) == 0 {
+				require.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, response.GetChanges())
+				return
+			}
+
+			require.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, response.GetChanges())
+			require.True(t, response.GetHasDetailedDiff())
+			require.Len(t, response.GetDetailedDiff(), len(test.detailedDiff))
+			var replaces []string
+			for key, kind := range test.detailedDiff {
+				require.Equal(t, kind, response.GetDetailedDiff()[key].GetKind(), key)
+				require.Contains(t, response.GetDiffs(), key)
+				if kind == pulumirpc.PropertyDiff_ADD_REPLACE || kind == pulumirpc.PropertyDiff_UPDATE_REPLACE ||
+					kind == pulumirpc.PropertyDiff_DELETE_REPLACE {
+					replaces = append(replaces, key)
+				}
+			}
+			require.ElementsMatch(t, replaces, response.GetReplaces())
+			require.Equal(t, test.deleteBeforeReplace, response.GetDeleteBeforeReplace())
+		})
+	}
+
+	_, err := service.Diff("esxi-native:index:Unknown", vm, vm)
+	require.ErrorContains(t, err, "unknown operation")
+}
@@ -0,0 +1,663 @@
+package esxi
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	fakeDefaultPorts = 128
+	fakeMaxPorts     = 4088
+	fakeDefaultMtu   = 1500
+	fakeMinMtu       = 1280
+	fakeMaxMtu       = 9000
+	fakeMaxVlan      = 4095
+)
+
+type fakeSecurityPolicy struct {
+	promiscuous     bool
+	macChange       bool
+	forgedTransmits bool
+}
+
+type fakeVSwitch struct {
+	name     string
+	ports    int
+	mtu      int
+	cdp      string
+	uplinks  []string
+	security fakeSecurityPolicy
+}
+
+func newFakeVSwitch(name string, ports int) *fakeVSwitch {
+	return &fakeVSwitch{name: name, ports: ports, mtu: fakeDefaultMtu, cdp: "listen"}
+}
+
+// fakePortGroup is a port group, its security policy overrides are nil when
+// the one of its virtual switch is used.
+type fakePermission struct {
+	principal string
+	group     bool
+	role      string
+}
+
+type fakePortGroup struct {
+	name            string
+	vSwitch         string
+	vlan            int
+	promiscuous     *bool
+	macChange       *bool
+	forgedTransmits *bool
+}
+
+// fakeField is a field of an esxcli output record, the numeric fields are right aligned in tables.
+type fakeField struct {
+	name    string
+	value   string
+	numeric bool
+}
+
+type fakeRecord []fakeField
+
+const (
+	// esxcliObject outputs a single record as indented key values.
+	esxcliObject = iota
+	// esxcliList outputs the records as indented key values, titled with their first value.
+	esxcliList
+	// esxcliTable outputs the records as a table with a header.
+	esxcliTable
+)
+
+type fakeEsxcliOutput struct {
+	kind    int
+	records []fakeRecord
+}
+
+// fakeEsxcliCommand describes the options of an esxcli command, by long name,
+// along with their short names.
+type fakeEsxcliCommand struct {
+	options  map[string]string
+	flags    []string
+	required []string
+	run      func(fake *FakeExecutor, options map[string]string) (*fakeEsxcliOutput, error)
+}
+
+var fakeEsxcliCommands = map[string]fakeEsxcliCommand{
+	"network vswitch standard add": {
+		options:  map[string]string{"vswitch-name": "v", "ports": "P"},
+		required: []string{"vswitch-name"},
+		run:      (*FakeExecutor).addVSwitch,
+	},
+	"network vswitch standard remove": {
+		options:  map[string]string{"vswitch-name": "v"},
+		required: []string{"vswitch-name"},
+		run:      (*FakeExecutor).removeVSwitch,
+	},
+	"network vswitch standard set": {
+		options:  map[string]string{"vswitch-name": "v", "mtu": "m", "cdp-status": "c"},
+		required: []string{"vswitch-name"},
+		run:      (*FakeExecutor).setVSwitch,
+	},
+	"network vswitch standard list": {
+		options: map[string]string{"vswitch-name": "v"},
+		run:     (*FakeExecutor).listVSwitches,
+	},
+	"network vswitch standard uplink add": {
+		options:  map[string]string{"vswitch-name": "v", "uplink-name": "u"},
+		required: []string{"vswitch-name", "uplink-name"},
+		run:      (*FakeExecutor).addUplink,
+	},
+	"network vswitch standard uplink remove": {
+		options:  map[string]string{"vswitch-name": "v", "uplink-name": "u"},
+		required: []string{"vswitch-name", "uplink-name"},
+		run:      (*FakeExecutor).removeUplink,
+	},
+	"network vswitch standard policy security get": {
+		options:  map[string]string{"vswitch-name": "v"},
+		required: []string{"vswitch-name"},
+		run:      (*FakeExecutor).getVSwitchSecurity,
+	},
+	"network vswitch standard policy security set": {
+		options:  map[string]string{"vswitch-name": "v", "allow-forged-transmits": "f", "allow-mac-change": "m", "allow-promiscuous": "p"},
+		required: []string{"vswitch-name"},
+		run:      (*FakeExecutor).setVSwitchSecurity,
+	},
+	"network vswitch standard portgroup add": {
+		options:  map[string]string{"portgroup-name": "p", "vswitch-name": "v"},
+		required: []string{"portgroup-name", "vswitch-name"},
+		run:      (*FakeExecutor).addPortGroup,
+	},
+	"network vswitch standard portgroup remove": {
+		options:  map[string]string{"portgroup-name": "p", "vswitch-name": "v"},
+		required: []string{"portgroup-name", "vswitch-name"},
+		run:      (*FakeExecutor).removePortGroup,
+	},
+	"network vswitch standard portgroup set": {
+		options:  map[string]string{"portgroup-name": "p", "vlan-id": "v"},
+		required: []string{"portgroup-name"},
+		run:      (*FakeExecutor).setPortGroup,
+	},
+	"network vswitch standard portgroup list": {
+		run: (*FakeExecutor).listPortGroups,
+	},
+	"network vswitch standard portgroup policy security get": {
+		options:  map[string]string{"portgroup-name": "p"},
+		required: []string{"portgroup-name"},
+		run:      (*FakeExecutor).getPortGroupSecurity,
+	},
+	"network vswitch standard portgroup policy security set": {
+		options: map[string]string{"portgroup-name": "p", "allow-forged-transmits": "f", "allow-mac-change": "m",
+			"allow-promiscuous": "o", "use-vswitch": "u"},
+		flags:    []string{"use-vswitch"},
+		required: []string{"portgroup-name"},
+		run:      (*FakeExecutor).setPortGroupSecurity,
+	},
+	"system version get": {
+		run: (*FakeExecutor).getSystemVersion,
+	},
+	"storage filesystem list": {
+		run: (*FakeExecutor).listFilesystems,
+	},
+	"storage filesystem rescan": {
+		run: func(*FakeExecutor, map[string]string) (*fakeEsxcliOutput, error) { return nil, nil },
+	},
+	"system permission list": {
+		run: (*FakeExecutor).listPermissions,
+	},
+}
+
+func (fake *FakeExecutor) esxcli(args []string) fakeResult {
+	formatter := ""
+	if len(args) > 0 && strings.HasPrefix(args[0], "--formatter=") {
+		formatter = strings.TrimPrefix(args[0], "--formatter=")
+		args = args[1:]
+	}
+	var namespace []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		namespace, args = append(namespace, args[0]), args[1:]
+	}
+	command, ok := fakeEsxcliCommands[strings.Join(namespace, " ")]
+	if !ok {
+		return fakeResult{stdout: esxcliError("Unknown command or namespace %s", strings.Join(namespace, " ")).Error() + "\n", status: fakeStatusFailure}
+	}
+
+	options, err := command.parse(args)
+	if err == nil {
+		var output *fakeEsxcliOutput
+		if output, err = command.run(fake, options); err == nil {
+			return fakeOK(output.format(formatter))
+		}
+	}
+	return fakeResult{stdout: err.Error() + "\n", status: fakeStatusFailure}
+}
+
+func (command fakeEsxcliCommand) parse(args []string) (map[string]string, error) {
+	options := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		var name, value string
+		hasValue := false
+		switch arg := args[i]; {
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue = strings.Cut(arg[2:], "=")
+			if _, ok := command.options[name]; !ok {
+				return nil, esxcliError("Invalid option %s", arg)
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) == 2:
+			for long, short := range command.options {
+				if short == arg[1:] {
+					name = long
+				}
+			}
+			if name == "" {
+				return nil, esxcliError("Invalid option %s", arg)
+			}
+		default:
+			return nil, esxcliError("Invalid argument %s", arg)
+		}
+
+		if Contains(command.flags, name) {
+			options[name] = "true"
+			continue
+		}
+		if !hasValue {
+			i++
+			if i >= len(args) {
+				return nil, esxcliError("Missing value for option --%s", name)
+			}
+			value = args[i]
+		}
+		options[name] = value
+	}
+
+	for _, name := range command.required {
+		if _, ok := options[name]; !ok {
+			return nil, esxcliError("Missing required parameter -%s|--%s", command.options[name], name)
+		}
+	}
+	return options, nil
+}
+
+func (output *fakeEsxcliOutput) format(formatter string) string {
+	if output == nil || len(output.records) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	if formatter == "csv" {
+		writer := csv.NewWriter(&builder)
+		var header []string
+		for _, field := range output.records[0] {
+			header = append(header, strings.ReplaceAll(field.name, " ", ""))
+		}
+		_ = writer.Write(header)
+		for _, record := range output.records {
+			var values []string
+			for _, field := range record {
+				values = append(values, field.value)
+			}
+			_ = writer.Write(values)
+		}
+		writer.Flush()
+		return builder.String()
+	}
+
+	switch output.kind {
+	case esxcliTable:
+		header := make(fakeRecord, len(output.records[0]))
+		dashes := make(fakeRecord, len(output.records[0]))
+		for i, field := range output.records[0] {
+			width := len(field.name)
+			for _, record := range output.records {
+				if len(record[i].value) > width {
+					width = len(record[i].value)
+				}
+			}
+			header[i] = fakeField{value: fmt.Sprintf("%-*s", width, field.name)}
+			dashes[i] = fakeField{value: strings.Repeat("-", width)}
+		}
+		for _, record := range append([]fakeRecord{header, dashes}, output.records...) {
+			var cells []string
+			for i, field := range record {
+				format := "%-*s"
+				if field.numeric {
+					format = "%*s"
+				}
+				cells = append(cells, fmt.Sprintf(format, len(dashes[i].value), field.value))
+			}
+			builder.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+		}
+	case esxcliList:
+		for i, record := range output.records {
+			if i > 0 {
+				builder.WriteString("\n")
+			}
+			builder.WriteString(record[0].value + "\n")
+			for _, field := range record {
+				builder.WriteString(fmt.Sprintf("   %s: %s\n", field.name, field.value))
+			}
+		}
+	default:
+		for _, field := range output.records[0] {
+			builder.WriteString(fmt.Sprintf("   %s: %s\n", field.name, field.value))
+		}
+	}
+	return builder.String()
+}
+
+// fakeEsxcliError is the error message of a failed esxcli command.
+type fakeEsxcliError string
+
+func (err fakeEsxcliError) Error() string {
+	return "Error: " + string(err)
+}
+
+func esxcliError(format string, args ...any) error {
+	return fakeEsxcliError(fmt.Sprintf(format, args...))
+}
+
+func (fake *FakeExecutor) findVSwitch(name string) (*fakeVSwitch, error) {
+	for _, vSwitch := range fake.vSwitches {
+		if vSwitch.name == name {
+			return vSwitch, nil
+		}
+	}
+	return nil, esxcliError("Unable to find vswitch %s", name)
+}
+
+func (fake *FakeExecutor) findPortGroup(name string) (*fakePortGroup, error) {
+	for _, portGroup := range fake.portGroups {
+		if portGroup.name == name {
+			return portGroup, nil
+		}
+	}
+	return nil, esxcliError("Unable to find portgroup %s", name)
+}
+
+func parseFakeBool(name string, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, esxcliError("Invalid value %s for option --%s", value, name)
+}
+
+func (fake *FakeExecutor) addVSwitch(options map[string]string) (*fakeEsxcliOutput, error) {
+	name := options["vswitch-name"]
+	if _, err := fake.findVSwitch(name); err == nil {
+		return nil, esxcliError("A virtual switch with this name already exists: %s", name)
+	}
+	ports := fakeDefaultPorts
+	if value, ok := options["ports"]; ok {
+		var err error
+		if ports, err = strconv.Atoi(value); err != nil || ports < 1 || ports > fakeMaxPorts {
+			return nil, esxcliError("Invalid number of ports %s", value)
+		}
+	}
+	fake.vSwitches = append(fake.vSwitches, newFakeVSwitch(name, ports))
+	return nil, nil
+}
+
+func (fake *FakeExecutor) removeVSwitch(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	for _, portGroup := range fake.portGroups {
+		if portGroup.vSwitch == vSwitch.name {
+			return nil, esxcliError("Unable to remove vswitch %s, it still has port groups", vSwitch.name)
+		}
+	}
+	for i := range fake.vSwitches {
+		if fake.vSwitches[i] == vSwitch {
+			fake.vSwitches = append(fake.vSwitches[:i], fake.vSwitches[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (fake *FakeExecutor) setVSwitch(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	mtu := vSwitch.mtu
+	if value, ok := options["mtu"]; ok {
+		if mtu, err = strconv.Atoi(value); err != nil || mtu < fakeMinMtu || mtu > fakeMaxMtu {
+			return nil, esxcliError("Invalid MTU %s, it must be between %d and %d", value, fakeMinMtu, fakeMaxMtu)
+		}
+	}
+	cdp := vSwitch.cdp
+	if value, ok := options["cdp-status"]; ok {
+		if !Contains([]string{"down", "listen", "advertise", "both"}, value) {
+			return nil, esxcliError("Invalid CDP status %s", value)
+		}
+		cdp = value
+	}
+	vSwitch.mtu, vSwitch.cdp = mtu, cdp
+	return nil, nil
+}
+
+func (fake *FakeExecutor) listVSwitches(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitches := fake.vSwitches
+	if name, ok := options["vswitch-name"]; ok {
+		vSwitch, err := fake.findVSwitch(name)
+		if err != nil {
+			return nil, err
+		}
+		vSwitches = []*fakeVSwitch{vSwitch}
+	}
+
+	output := &fakeEsxcliOutput{kind: esxcliList}
+	for _, vSwitch := range vSwitches {
+		var portGroups []string
+		for _, portGroup := range fake.portGroups {
+			if portGroup.vSwitch == vSwitch.name {
+				portGroups = append(portGroups, portGroup.name)
+			}
+		}
+		output.records = append(output.records, fakeRecord{
+			{name: "Name", value: vSwitch.name},
+			{name: "Class", value: "cswitch"},
+			{name: "Num Ports", value: "2560"},
+			{name: "Used Ports", value: strconv.Itoa(len(vSwitch.uplinks) + 1)},
+			{name: "Configured Ports", value: strconv.Itoa(vSwitch.ports)},
+			{name: "MTU", value: strconv.Itoa(vSwitch.mtu)},
+			{name: "CDP Status", value: vSwitch.cdp},
+			{name: "Beacon Enabled", value: "false"},
+			{name: "Uplinks", value: strings.Join(vSwitch.uplinks, ", ")},
+			{name: "Portgroups", value: strings.Join(portGroups, ", ")},
+		})
+	}
+	return output, nil
+}
+
+func (fake *FakeExecutor) addUplink(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	uplink := options["uplink-name"]
+	if !Contains(fake.nics, uplink) {
+		return nil, esxcliError("Not a valid pnic %s", uplink)
+	}
+	for _, other := range fake.vSwitches {
+		if Contains(other.uplinks, uplink) {
+			return nil, esxcliError("Uplink %s is already used by vswitch %s", uplink, other.name)
+		}
+	}
+	vSwitch.uplinks = append(vSwitch.uplinks, uplink)
+	return nil, nil
+}
+
+func (fake *FakeExecutor) removeUplink(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	uplink := options["uplink-name"]
+	for i := range vSwitch.uplinks {
+		if vSwitch.uplinks[i] == uplink {
+			vSwitch.uplinks = append(vSwitch.uplinks[:i], vSwitch.uplinks[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, esxcliError("Uplink %s is not used by vswitch %s", uplink, vSwitch.name)
+}
+
+func (fake *FakeExecutor) getSystemVersion(map[string]string) (*fakeEsxcliOutput, error) {
+	return &fakeEsxcliOutput{kind: esxcliObject, records: []fakeRecord{{
+		{name: "Product", value: "VMware ESXi"},
+		{name: "Version", value: "7.0.3"},
+		{name: "Build", value: "Releasebuild-21930508"},
+		{name: "Update", value: "3"},
+		{name: "Patch", value: "95"},
+	}}}, nil
+}
+
+func (fake *FakeExecutor) getVSwitchSecurity(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	return &fakeEsxcliOutput{kind: esxcliObject, records: []fakeRecord{{
+		{name: "Allow Promiscuous", value: strconv.FormatBool(vSwitch.security.promiscuous)},
+		{name: "Allow MAC Address Change", value: strconv.FormatBool(vSwitch.security.macChange)},
+		{name: "Allow Forged Transmits", value: strconv.FormatBool(vSwitch.security.forgedTransmits)},
+	}}}, nil
+}
+
+func (fake *FakeExecutor) setVSwitchSecurity(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	security := vSwitch.security
+	settings := map[string]*bool{
+		"allow-promiscuous":      &security.promiscuous,
+		"allow-mac-change":       &security.macChange,
+		"allow-forged-transmits": &security.forgedTransmits,
+	}
+	for name, setting := range settings {
+		if value, ok := options[name]; ok {
+			if *setting, err = parseFakeBool(name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	vSwitch.security = security
+	return nil, nil
+}
+
+func (fake *FakeExecutor) addPortGroup(options map[string]string) (*fakeEsxcliOutput, error) {
+	vSwitch, err := fake.findVSwitch(options["vswitch-name"])
+	if err != nil {
+		return nil, err
+	}
+	name := options["portgroup-name"]
+	if _, err = fake.findPortGroup(name); err == nil {
+		return nil, esxcliError("A portgroup with the name %s already exists", name)
+	}
+	fake.portGroups = append(fake.portGroups, &fakePortGroup{name: name, vSwitch: vSwitch.name})
+	return nil, nil
+}
+
+func (fake *FakeExecutor) removePortGroup(options map[string]string) (*fakeEsxcliOutput, error) {
+	portGroup, err := fake.findPortGroup(options["portgroup-name"])
+	if err != nil {
+		return nil, err
+	}
+	if portGroup.vSwitch != options["vswitch-name"] {
+		return nil, esxcliError("Portgroup %s is not on vswitch %s", portGroup.name, options["vswitch-name"])
+	}
+	for i := range fake.portGroups {
+		if fake.portGroups[i] == portGroup {
+			fake.portGroups = append(fake.portGroups[:i], fake.portGroups[i+1:]...)
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (fake *FakeExecutor) setPortGroup(options map[string]string) (*fakeEsxcliOutput, error) {
+	portGroup, err := fake.findPortGroup(options["portgroup-name"])
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := options["vlan-id"]; ok {
+		vlan, err := strconv.Atoi(value)
+		if err != nil || vlan < 0 || vlan > fakeMaxVlan {
+			return nil, esxcliError("Invalid VLAN id %s, it must be between 0 and %d", value, fakeMaxVlan)
+		}
+		portGroup.vlan = vlan
+	}
+	return nil, nil
+}
+
+func (fake *FakeExecutor) listPortGroups(map[string]string) (*fakeEsxcliOutput, error) {
+	output := &fakeEsxcliOutput{kind: esxcliTable}
+	for _, portGroup := range fake.portGroups {
+		clients := 0
+		for _, vm := range fake.vms {
+			if vm.poweredOn && Contains(fake.vmNetworks(vm), portGroup.name) {
+				clients++
+			}
+		}
+		output.records = append(output.records, fakeRecord{
+			{name: "Name", value: portGroup.name},
+			{name: "Virtual Switch", value: portGroup.vSwitch},
+			{name: "Active Clients", value: strconv.Itoa(clients), numeric: true},
+			{name: "VLAN ID", value: strconv.Itoa(portGroup.vlan), numeric: true},
+		})
+	}
+	return output, nil
+}
+
+func (fake *FakeExecutor) getPortGroupSecurity(options map[string]string) (*fakeEsxcliOutput, error) {
+	portGroup, err := fake.findPortGroup(options["portgroup-name"])
+	if err != nil {
+		return nil, err
+	}
+	vSwitch, err := fake.findVSwitch(portGroup.vSwitch)
+	if err != nil {
+		return nil, err
+	}
+	effective := func(override *bool, inherited bool) string {
+		if override != nil {
+			return strconv.FormatBool(*override)
+		}
+		return strconv.FormatBool(inherited)
+	}
+	return &fakeEsxcliOutput{kind: esxcliObject, records: []fakeRecord{{
+		{name: "Allow Promiscuous", value: effective(portGroup.promiscuous, vSwitch.security.promiscuous)},
+		{name: "Allow MAC Address Change", value: effective(portGroup.macChange, vSwitch.security.macChange)},
+		{name: "Allow Forged Transmits", value: effective(portGroup.forgedTransmits, vSwitch.security.forgedTransmits)},
+		{name: "Override Port Group Allow Promiscuous", value: strconv.FormatBool(portGroup.promiscuous != nil)},
+		{name: "Override Port Group Allow MAC Address Change", value: strconv.FormatBool(portGroup.macChange != nil)},
+		{name: "Override Port Group Allow Forged Transmits", value: strconv.FormatBool(portGroup.forgedTransmits != nil)},
+	}}}, nil
+}
+
+func (fake *FakeExecutor) setPortGroupSecurity(options map[string]string) (*fakeEsxcliOutput, error) {
+	portGroup, err := fake.findPortGroup(options["portgroup-name"])
+	if err != nil {
+		return nil, err
+	}
+	updated := *portGroup
+	if options["use-vswitch"] == "true" {
+		updated.promiscuous, updated.macChange, updated.forgedTransmits = nil, nil, nil
+	}
+	settings := map[string]**bool{
+		"allow-promiscuous":      &updated.promiscuous,
+		"allow-mac-change":       &updated.macChange,
+		"allow-forged-transmits": &updated.forgedTransmits,
+	}
+	for name, setting := range settings {
+		if value, ok := options[name]; ok {
+			allowed, err := parseFakeBool(name, value)
+			if err != nil {
+				return nil, err
+			}
+			*setting = &allowed
+		}
+	}
+	*portGroup = updated
+	return nil, nil
+}
+
+func (fake *FakeExecutor) listFilesystems(map[string]string) (*fakeEsxcliOutput, error) {
+	const size = 500 << 30
+	output := &fakeEsxcliOutput{kind: esxcliTable}
+	for _, diskStore := range fake.diskStores {
+		hash := sha1.Sum([]byte(diskStore))
+		uuid := fmt.Sprintf("%x-%x-%x-%x", hash[0:4], hash[4:8], hash[8:10], hash[10:16])
+		output.records = append(output.records, fakeRecord{
+			{name: "Mount Point", value: fakeVolumesDir + "/" + uuid},
+			{name: "Volume Name", value: diskStore},
+			{name: "UUID", value: uuid},
+			{name: "Mounted", value: "true"},
+			{name: "Type", value: "VMFS-6"},
+			{name: "Size", value: strconv.Itoa(size), numeric: true},
+			{name: "Free", value: strconv.Itoa(size / 2), numeric: true},
+		})
+	}
+	return output, nil
+}
+
+func (fake *FakeExecutor) listPermissions(map[string]string) (*fakeEsxcliOutput, error) {
+	output := &fakeEsxcliOutput{kind: esxcliTable}
+	for _, permission := range fake.permissions {
+		output.records = append(output.records, fakeRecord{
+			{name: "Principal", value: permission.principal},
+			{name: "Is Group", value: strconv.FormatBool(permission.group)},
+			{name: "Role", value: permission.role},
+			{name: "Role Description", value: permission.role},
+		})
+	}
+	return output, nil
+}
@@ -0,0 +1,457 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// The exit statuses of the fake commands.
+const (
+	fakeStatusFailure    = 1
+	fakeStatusUsage      = 2
+	fakeStatusNotFound   = 127
+	fakeStatusVmkfstools = 255
+)
+
+// FakeExitError is returned by the FakeExecutor for the commands exiting with a
+// non-zero status, its message is the one of ssh.ExitError.
+type FakeExitError struct {
+	Status int
+}
+
+func (err *FakeExitError) Error() string {
+	return fmt.Sprintf("Process exited with status %d", err.Status)
+}
+
+// ExitStatus returns the exit status of the command, like ssh.ExitError.
+func (err *FakeExitError) ExitStatus() int {
+	return err.Status
+}
+
+// FakeHandler answers a scripted command, matches are the submatches of the pattern it was registered with.
+type FakeHandler func(matches []string) (string, error)
+
+type fakeScript struct {
+	pattern *regexp.Regexp
+	handler FakeHandler
+}
+
+// FakeExecutor is an in-memory esxi host to unit test the resources without
+// a real host. The commands are run by a small shell supporting quoting,
+// pipes, && and redirections to /dev/null, which emulates the busybox tools,
+// vim-cmd, esxcli and vmkfstools used by the resources on top of a model of
+// the datastores, virtual machines, resource pools and virtual switches.
+// Scripted handlers registered with Handle take precedence over the emulation.
+type FakeExecutor struct {
+	mutex sync.Mutex
+
+	scripts  []fakeScript
+	commands []string
+
+	files      map[string]*fakeFile
+	diskStores []string
+	nics       []string
+	// permissions are the roles of the users and groups on the host.
+	permissions []fakePermission
+	vSwitches   []*fakeVSwitch
+	portGroups  []*fakePortGroup
+	pools       []*fakePool
+	vms         []*fakeVM
+	lastVMId    int
+	lastPoolId  int
+}
+
+var _ Executor = (*FakeExecutor)(nil)
+
+// NewFakeExecutor returns a fake host with the datastore1 datastore, the
+// vmnic0 and vmnic1 physical nics and the default vSwitch0 virtual switch with
+// its "Management Network" and "VM Network" port groups.
+func NewFakeExecutor() *FakeExecutor {
+	fake := &FakeExecutor{
+		files:      make(map[string]*fakeFile),
+		lastPoolId: -1,
+	}
+	for _, dir := range []string{"/", "/etc", "/etc/vmware", "/etc/vmware/hostd", "/tmp", "/vmfs", fakeVolumesDir} {
+		fake.files[dir] = &fakeFile{dir: true}
+	}
+
+	fake.AddDiskStore("datastore1")
+	fake.AddPhysicalNic("vmnic0")
+	fake.AddPhysicalNic("vmnic1")
+	for _, principal := range []string{"dcui", "root", "vpxuser"} {
+		fake.AddPermission(principal, false, "Admin")
+	}
+
+	vSwitch0 := newFakeVSwitch("vSwitch0", fakeDefaultPorts)
+	vSwitch0.uplinks = []string{"vmnic0"}
+	fake.vSwitches = append(fake.vSwitches, vSwitch0)
+	fake.portGroups = append(fake.portGroups,
+		&fakePortGroup{name: "Management Network", vSwitch: vSwitch0.name},
+		&fakePortGroup{name: "VM Network", vSwitch: vSwitch0.name})
+
+	return fake
+}
+
+// AddDiskStore adds a datastore mounted under /vmfs/volumes.
+func (fake *FakeExecutor) AddDiskStore(name string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.diskStores = append(fake.diskStores, name)
+	fake.files[fakeVolumesDir+"/"+name] = &fakeFile{dir: true}
+}
+
+// AddPhysicalNic adds a physical nic, which can be used as a virtual switch uplink.
+func (fake *FakeExecutor) AddPhysicalNic(name string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.nics = append(fake.nics, name)
+}
+
+// AddPermission grants the role to the user, or to the group, on the host.
+func (fake *FakeExecutor) AddPermission(principal string, group bool, role string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.permissions = append(fake.permissions, fakePermission{principal: principal, group: group, role: role})
+}
+
+// Handle scripts the answer of the commands matching the pattern. The latest
+// registered handler wins when several patterns match a command.
+func (fake *FakeExecutor) Handle(pattern string, handler FakeHandler) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.scripts = append(fake.scripts, fakeScript{regexp.MustCompile(pattern), handler})
+}
+
+// Commands returns the commands executed so far.
+func (fake *FakeExecutor) Commands() []string {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	return append([]string(nil), fake.commands...)
+}
+
+// ReadFile returns the content of the file at path on the fake host.
+func (fake *FakeExecutor) ReadFile(path string) (string, bool) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	file, ok := fake.lookup(path)
+	if !ok || file.dir {
+		return "", false
+	}
+	return file.content, true
+}
+
+// StoreFile stores the content as is in the file at path on the fake host,
+// the parent directory must exist.
+func (fake *FakeExecutor) StoreFile(path string, content string) error {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	return fake.writeFile(path, content)
+}
+
+func (fake *FakeExecutor) Execute(ctx context.Context, command string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("Execute: %s", shortCmdDesc)
+	if err := ctx.Err(); err != nil {
+		return failedToConnect, err
+	}
+
+	fake.mutex.Lock()
+	fake.commands = append(fake.commands, command)
+	scripts := fake.scripts
+	fake.mutex.Unlock()
+
+	for i := len(scripts) - 1; i >= 0; i-- {
+		if matches := scripts[i].pattern.FindStringSubmatch(command); matches != nil {
+			stdout, err := scripts[i].handler(matches)
+			return strings.TrimSpace(stdout), err
+		}
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	output, status := fake.run(command)
+	stdout := strings.TrimSpace(output)
+	if status != 0 {
+		return stdout, &FakeExitError{Status: status}
+	}
+	return stdout, nil
+}
+
+func (fake *FakeExecutor) WriteFile(ctx context.Context, content string, path string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("WriteFile: %s", shortCmdDesc)
+	if err := ctx.Err(); err != nil {
+		return failedToConnect, err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	// Like the scp upload of the ssh executor, the content ends with a new line.
+	if err := fake.writeFile(path, content+"\n"); err != nil {
+		return failedToConnect, err
+	}
+	return content, nil
+}
+
+func (fake *FakeExecutor) CopyFile(ctx context.Context, localPath string, hostPath string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("CopyFile: %s", shortCmdDesc)
+	if err := ctx.Err(); err != nil {
+		return failedToConnect, err
+	}
+
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return "Failed to copy file to esxi host!", err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if err = fake.writeFile(hostPath, string(content)); err != nil {
+		return "Failed to copy file to esxi host!", err
+	}
+	return "", nil
+}
+
+// fakeResult is the outcome of a program run by the fake shell.
+type fakeResult struct {
+	stdout string
+	stderr string
+	status int
+}
+
+func fakeOK(stdout string) fakeResult {
+	return fakeResult{stdout: stdout}
+}
+
+func fakeFail(status int, stderr string) fakeResult {
+	return fakeResult{stderr: stderr, status: status}
+}
+
+// fakeStage is a program of a pipeline.
+type fakeStage struct {
+	args          []string
+	discardStdout bool
+	discardStderr bool
+}
+
+// fakePipeline is a pipeline of the command line, run only when the previous
+// one succeeded if it's joined with &&.
+type fakePipeline struct {
+	stages        []fakeStage
+	onlyOnSuccess bool
+}
+
+// run runs the command line and returns its combined output and exit status.
+func (fake *FakeExecutor) run(command string) (string, int) {
+	pipelines, err := parseFakeCommandLine(command)
+	if err != nil {
+		return fmt.Sprintf("sh: %s\n", err), fakeStatusUsage
+	}
+
+	var output strings.Builder
+	status := 0
+	for i, pipeline := range pipelines {
+		if i > 0 && pipeline.onlyOnSuccess && status != 0 {
+			continue
+		}
+		var stdout string
+		stdout, status = fake.runPipeline(pipeline)
+		output.WriteString(stdout)
+	}
+	return output.String(), status
+}
+
+func (fake *FakeExecutor) runPipeline(pipeline fakePipeline) (string, int) {
+	var stderr strings.Builder
+	stdin := ""
+	status := 0
+	for _, stage := range pipeline.stages {
+		result := fake.runProgram(stage.args, stdin)
+		if !stage.discardStderr {
+			stderr.WriteString(result.stderr)
+		}
+		stdin = result.stdout
+		if stage.discardStdout {
+			stdin = ""
+		}
+		status = result.status
+	}
+	return stderr.String() + stdin, status
+}
+
+func (fake *FakeExecutor) runProgram(args []string, stdin string) fakeResult {
+	name := args[0]
+	if i := strings.LastIndex(name, "/"); i >= 0 && (strings.HasPrefix(name, "/bin/") || strings.HasPrefix(name, "/sbin/")) {
+		name = name[i+1:]
+	}
+	args = args[1:]
+
+	switch name {
+	case "awk":
+		return fakeAwk(args, stdin)
+	case "cat":
+		return fake.cat(args, stdin)
+	case "echo":
+		return fakeOK(strings.Join(args, " ") + "\n")
+	case "esxcli":
+		return fake.esxcli(args)
+	case "grep":
+		return fake.grep(args, stdin)
+	case "ls":
+		return fake.ls(args)
+	case "mkdir":
+		return fake.mkdir(args)
+	case "rm":
+		return fake.rm(args)
+	case "rmdir":
+		return fake.rmdir(args)
+	case "sed":
+		return fakeSed(args, stdin)
+	case "sort":
+		return fakeSort(args, stdin)
+	case "test":
+		return fake.test(args)
+	case "vim-cmd":
+		return fake.vimCmd(args)
+	case "vmkfstools":
+		return fake.vmkfstools(args)
+	case "vmware":
+		return fakeVmware(args)
+	case "wc":
+		return fakeWc(args, stdin)
+	default:
+		return fakeFail(fakeStatusNotFound, fmt.Sprintf("sh: %s: not found\n", name))
+	}
+}
+
+var fakeRedirection = regexp.MustCompile(`^[12]?>`)
+
+// parseFakeCommandLine splits the command line into pipelines of programs and
+// their arguments, following the quoting rules of the shell.
+func parseFakeCommandLine(command string) ([]fakePipeline, error) {
+	var pipelines []fakePipeline
+	current := fakePipeline{}
+	var stage fakeStage
+
+	var word strings.Builder
+	inWord, quoted := false, false
+	endWord := func() error {
+		if !inWord {
+			return nil
+		}
+		value := word.String()
+		word.Reset()
+		inWord = false
+
+		switch {
+		case !quoted && value == "~":
+			// The home directory of root.
+			value = "/"
+		case !quoted && fakeRedirection.MatchString(value):
+			target := strings.TrimLeft(value, "12>")
+			if target != "/dev/null" {
+				return fmt.Errorf("unsupported redirection %s", value)
+			}
+			if strings.HasPrefix(value, "2") {
+				stage.discardStderr = true
+			} else {
+				stage.discardStdout = true
+			}
+			return nil
+		}
+		stage.args = append(stage.args, value)
+		quoted = false
+		return nil
+	}
+	endStage := func() error {
+		if err := endWord(); err != nil {
+			return err
+		}
+		if len(stage.args) == 0 {
+			return fmt.Errorf("syntax error: missing command")
+		}
+		current.stages = append(current.stages, stage)
+		stage = fakeStage{}
+		return nil
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if err := endWord(); err != nil {
+				return nil, err
+			}
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("syntax error: unterminated quoted string")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			inWord, quoted = true, true
+			i += end + 1
+		case c == '"':
+			inWord, quoted = true, true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("syntax error: unterminated quoted string")
+			}
+		case c == '\\':
+			if i+1 < len(command) {
+				i++
+				word.WriteByte(command[i])
+				inWord, quoted = true, true
+			}
+		case c == '|':
+			if i+1 < len(command) && command[i+1] == '|' {
+				return nil, fmt.Errorf("unsupported operator ||")
+			}
+			if err := endStage(); err != nil {
+				return nil, err
+			}
+		case c == '&' || c == ';':
+			if c == '&' {
+				if i+1 >= len(command) || command[i+1] != '&' {
+					return nil, fmt.Errorf("unsupported background job")
+				}
+				i++
+			}
+			if err := endStage(); err != nil {
+				return nil, err
+			}
+			pipelines = append(pipelines, current)
+			current = fakePipeline{onlyOnSuccess: c == '&'}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if err := endStage(); err != nil {
+		return nil, err
+	}
+	return append(pipelines, current), nil
+}
@@ -0,0 +1,82 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeHost returns a host running the remote operations on an in-memory
+// fake, without waiting between the power state checks.
+func newFakeHost(t *testing.T) (*Host, *FakeExecutor) {
+	t.Helper()
+	fake := NewFakeExecutor()
+	esxi := NewHostWithExecutor(ConnectionInfo{Host: "fake", SSHPort: "22", SslPort: "443", UserName: "root"}, fake)
+	esxi.sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+	return esxi, fake
+}
+
+func TestFakeExecutorShell(t *testing.T) {
+	esxi, _ := newFakeHost(t)
+	ctx := context.Background()
+
+	_, err := esxi.WriteFile(ctx, "one\ntwo = \"2\",\nthree", "/tmp/file.txt", "write")
+	require.NoError(t, err)
+
+	tests := []struct {
+		command string
+		stdout  string
+		status  int
+	}{
+		{command: "vmware --version", stdout: fakeVersion},
+		{command: `cat "/tmp/file.txt" | grep -A1 one | grep -o 'two.*2'`, stdout: "two = \"2"},
+		{command: `cat /tmp/file.txt |sed '1!G;h;$!d' |awk '{print $1}'`, stdout: "three\ntwo\none"},
+		{command: `cat /tmp/file.txt | grep two |sed 's/^.*= //g'|sed s/,//g`, stdout: "\"2\""},
+		{command: `grep -q missing /tmp/file.txt && echo true`, status: fakeStatusFailure},
+		{command: `grep -q three /tmp/file.txt && echo true`, stdout: "true"},
+		{command: `ls -al "/tmp/" |wc -l`, stdout: "4"},
+		{command: `ls -d "/tmp/missing dir"`, stdout: "ls: /tmp/missing dir: No such file or directory", status: fakeStatusFailure},
+		{command: "cat /tmp/missing 2>/dev/null", status: fakeStatusFailure},
+		{command: "mkdir \"/vmfs/volumes/datastore2\"", stdout: "mkdir: can't create directory '/vmfs/volumes/datastore2': /vmfs/volumes/datastore2: Operation not permitted", status: fakeStatusFailure},
+		{command: "poweroff", stdout: "sh: poweroff: not found", status: fakeStatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			stdout, err := esxi.Execute(ctx, test.command, "test")
+			require.Equal(t, test.stdout, stdout)
+			if test.status == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var exitErr *FakeExitError
+			require.True(t, errors.As(err, &exitErr))
+			require.Equal(t, test.status, exitErr.Status)
+		})
+	}
+}
+
+func TestFakeExecutorHandle(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	fake.Handle(`^vim-cmd vmsvc/power\.getstate (\d+)$`, func(matches []string) (string, error) {
+		return "Retrieved runtime info\nSuspended " + matches[1], nil
+	})
+	require.Equal(t, vmTurnedSuspended, esxi.getVirtualMachinePowerState(ctx, "42"))
+
+	// The later handlers take precedence.
+	fake.Handle(`^vim-cmd vmsvc/power\.getstate`, func([]string) (string, error) {
+		return "", &FakeExitError{Status: fakeStatusFailure}
+	})
+	require.Equal(t, esxiUnknown, esxi.getVirtualMachinePowerState(ctx, "42"))
+
+	require.Equal(t, []string{
+		"vim-cmd vmsvc/power.getstate 42",
+		"vim-cmd vmsvc/power.getstate 42",
+	}, fake.Commands())
+}
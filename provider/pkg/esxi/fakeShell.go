@@ -0,0 +1,624 @@
+package esxi
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	fakeVolumesDir = "/vmfs/volumes"
+	fakeVersion    = "VMware ESXi 7.0.3 build-21930508"
+	// fakeVirtualHWVer is the highest virtual hardware version of the fake
+	// host, the default one, the versions from fakeMinVirtualHWVer are supported.
+	fakeVirtualHWVer    = 19
+	fakeMinVirtualHWVer = 4
+)
+
+// fakeFile is a file or directory of the fake host. The size of the virtual
+// disk descriptors is the one of their flat extent.
+type fakeFile struct {
+	dir      bool
+	content  string
+	size     int64
+	diskType string
+}
+
+func (file *fakeFile) length() int64 {
+	if file.diskType != "" {
+		return file.size
+	}
+	return int64(len(file.content))
+}
+
+func cleanFakePath(name string) string {
+	return path.Clean("/" + name)
+}
+
+func (fake *FakeExecutor) lookup(name string) (*fakeFile, bool) {
+	name = cleanFakePath(name)
+	if name == fakePoolsXML {
+		return &fakeFile{content: fake.poolsXML()}, true
+	}
+	file, ok := fake.files[name]
+	return file, ok
+}
+
+func (fake *FakeExecutor) children(dir string) []string {
+	dir = cleanFakePath(dir)
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var names []string
+	for name := range fake.files {
+		if name != dir && strings.HasPrefix(name, prefix) && !strings.Contains(name[len(prefix):], "/") {
+			names = append(names, name[len(prefix):])
+		}
+	}
+	if dir == path.Dir(fakePoolsXML) {
+		names = append(names, path.Base(fakePoolsXML))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkParent returns an error unless name can be created in its parent
+// directory, the datastores can't be created under /vmfs/volumes.
+func (fake *FakeExecutor) checkParent(name string) error {
+	parent := path.Dir(name)
+	file, ok := fake.files[parent]
+	switch {
+	case !ok:
+		return fmt.Errorf("%s: No such file or directory", parent)
+	case !file.dir:
+		return fmt.Errorf("%s: Not a directory", parent)
+	case parent == fakeVolumesDir:
+		return fmt.Errorf("%s: Operation not permitted", name)
+	}
+	return nil
+}
+
+func (fake *FakeExecutor) writeFile(name string, content string) error {
+	name = cleanFakePath(name)
+	if err := fake.checkParent(name); err != nil {
+		return err
+	}
+	if file, ok := fake.files[name]; ok && file.dir {
+		return fmt.Errorf("%s: Is a directory", name)
+	}
+	fake.files[name] = &fakeFile{content: content}
+	return nil
+}
+
+func (fake *FakeExecutor) removeAll(name string) {
+	name = cleanFakePath(name)
+	prefix := strings.TrimSuffix(name, "/") + "/"
+	for file := range fake.files {
+		if file == name || strings.HasPrefix(file, prefix) {
+			delete(fake.files, file)
+		}
+	}
+}
+
+// splitFakeFlags splits the single dash flags from the other arguments.
+func splitFakeFlags(args []string) (string, []string) {
+	var flags string
+	var operands []string
+	for _, arg := range args {
+		if len(arg) > 1 && strings.HasPrefix(arg, "-") {
+			flags += arg[1:]
+		} else {
+			operands = append(operands, arg)
+		}
+	}
+	return flags, operands
+}
+
+func fakeVmware(args []string) fakeResult {
+	if len(args) == 1 && (args[0] == "-v" || args[0] == "--version") {
+		return fakeOK(fakeVersion + "\n")
+	}
+	return fakeFail(fakeStatusFailure, "Usage: vmware [-v|--version] [-l]\n")
+}
+
+func (fake *FakeExecutor) mkdir(args []string) fakeResult {
+	flags, names := splitFakeFlags(args)
+	parents := strings.Contains(flags, "p")
+
+	result := fakeOK("")
+	for _, name := range names {
+		name = cleanFakePath(name)
+		if file, ok := fake.files[name]; ok {
+			if !parents || !file.dir {
+				result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("mkdir: can't create directory '%s': File exists\n", name))
+			}
+			continue
+		}
+
+		var missing []string
+		for dir := name; parents; dir = path.Dir(dir) {
+			if _, ok := fake.files[dir]; ok {
+				break
+			}
+			missing = append([]string{dir}, missing...)
+		}
+		if !parents {
+			missing = []string{name}
+		}
+		for _, dir := range missing {
+			if err := fake.checkParent(dir); err != nil {
+				result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("mkdir: can't create directory '%s': %s\n", dir, err))
+				break
+			}
+			fake.files[dir] = &fakeFile{dir: true}
+		}
+	}
+	return result
+}
+
+func (fake *FakeExecutor) rmdir(args []string) fakeResult {
+	_, names := splitFakeFlags(args)
+	result := fakeOK("")
+	for _, name := range names {
+		file, ok := fake.lookup(name)
+		switch {
+		case !ok:
+			result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("rmdir: '%s': No such file or directory\n", name))
+		case !file.dir:
+			result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("rmdir: '%s': Not a directory\n", name))
+		case len(fake.children(name)) > 0:
+			result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("rmdir: '%s': Directory not empty\n", name))
+		default:
+			delete(fake.files, cleanFakePath(name))
+		}
+	}
+	return result
+}
+
+func (fake *FakeExecutor) rm(args []string) fakeResult {
+	flags, names := splitFakeFlags(args)
+	force := strings.Contains(flags, "f")
+	recursive := strings.ContainsAny(flags, "rR")
+
+	result := fakeOK("")
+	for _, name := range names {
+		file, ok := fake.lookup(name)
+		switch {
+		case !ok:
+			if !force {
+				result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("rm: can't remove '%s': No such file or directory\n", name))
+			}
+		case file.dir && !recursive:
+			result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("rm: '%s' is a directory\n", name))
+		default:
+			fake.removeAll(name)
+		}
+	}
+	return result
+}
+
+func (fake *FakeExecutor) ls(args []string) fakeResult {
+	flags, names := splitFakeFlags(args)
+	long := strings.Contains(flags, "l")
+	all := strings.Contains(flags, "a")
+	directory := strings.Contains(flags, "d")
+	if len(names) == 0 {
+		names = []string{"/"}
+	}
+
+	line := func(name string, file *fakeFile) string {
+		if !long {
+			return name + "\n"
+		}
+		mode := "-rw-------"
+		if file.dir {
+			mode = "drwxr-xr-x"
+		}
+		return fmt.Sprintf("%s    1 root     root     %12d Jan  1 00:00 %s\n", mode, file.length(), name)
+	}
+
+	result := fakeOK("")
+	var stdout strings.Builder
+	for _, name := range names {
+		file, ok := fake.lookup(name)
+		if !ok {
+			result = fakeFail(fakeStatusFailure, result.stderr+fmt.Sprintf("ls: %s: No such file or directory\n", name))
+			continue
+		}
+		if !file.dir || directory {
+			stdout.WriteString(line(name, file))
+			continue
+		}
+
+		children := fake.children(name)
+		if long {
+			stdout.WriteString(fmt.Sprintf("total %d\n", len(children)))
+		}
+		if all {
+			stdout.WriteString(line(".", file))
+			stdout.WriteString(line("..", &fakeFile{dir: true}))
+		}
+		for _, child := range children {
+			childFile, _ := fake.lookup(path.Join(name, child))
+			stdout.WriteString(line(child, childFile))
+		}
+	}
+	result.stdout = stdout.String()
+	return result
+}
+
+func (fake *FakeExecutor) cat(args []string, stdin string) fakeResult {
+	if len(args) == 0 {
+		return fakeOK(stdin)
+	}
+	result := fakeOK("")
+	for _, name := range args {
+		file, ok := fake.lookup(name)
+		switch {
+		case !ok:
+			result.status = fakeStatusFailure
+			result.stderr += fmt.Sprintf("cat: can't open '%s': No such file or directory\n", name)
+		case file.dir:
+			result.status = fakeStatusFailure
+			result.stderr += "cat: read error: Is a directory\n"
+		default:
+			result.stdout += file.content
+		}
+	}
+	return result
+}
+
+func (fake *FakeExecutor) test(args []string) fakeResult {
+	if len(args) != 2 {
+		return fakeFail(fakeStatusUsage, "test: unsupported expression\n")
+	}
+	file, ok := fake.lookup(args[1])
+	var success bool
+	switch args[0] {
+	case "-e":
+		success = ok
+	case "-d":
+		success = ok && file.dir
+	case "-f":
+		success = ok && !file.dir
+	case "-s":
+		success = ok && (file.dir || file.length() > 0)
+	default:
+		return fakeFail(fakeStatusUsage, fmt.Sprintf("test: %s: unknown operand\n", args[0]))
+	}
+	if !success {
+		return fakeFail(fakeStatusFailure, "")
+	}
+	return fakeOK("")
+}
+
+func fakeLines(input string) []string {
+	if input == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(input, "\n"), "\n")
+}
+
+func fakeJoinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func fakeWc(args []string, stdin string) fakeResult {
+	if len(args) != 1 || args[0] != "-l" {
+		return fakeFail(fakeStatusFailure, "wc: unsupported arguments\n")
+	}
+	return fakeOK(fmt.Sprintf("%d\n", strings.Count(stdin, "\n")))
+}
+
+func fakeSort(args []string, stdin string) fakeResult {
+	if len(args) != 1 || args[0] != "-n" {
+		return fakeFail(fakeStatusFailure, "sort: unsupported arguments\n")
+	}
+	lines := fakeLines(stdin)
+	number := func(line string) int {
+		digits := strings.TrimLeft(line, " \t")
+		end := 0
+		for end < len(digits) && digits[end] >= '0' && digits[end] <= '9' {
+			end++
+		}
+		value, _ := strconv.Atoi(digits[:end])
+		return value
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return number(lines[i]) < number(lines[j])
+	})
+	return fakeOK(fakeJoinLines(lines))
+}
+
+// fakeBasicRegexp translates a POSIX basic regular expression to the RE2 syntax.
+func fakeBasicRegexp(pattern string) string {
+	const operators = "(){}|+?"
+	var re strings.Builder
+	inBracket := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case inBracket:
+			if c == ']' {
+				inBracket = false
+			}
+			re.WriteByte(c)
+		case c == '[':
+			inBracket = true
+			re.WriteByte(c)
+			// A leading ] is part of the bracket expression.
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+				re.WriteByte('^')
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+				re.WriteString(`\]`)
+			}
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			if strings.IndexByte(operators, pattern[i]) >= 0 {
+				re.WriteByte(pattern[i])
+			} else {
+				re.WriteByte('\\')
+				re.WriteByte(pattern[i])
+			}
+		case strings.IndexByte(operators, c) >= 0:
+			re.WriteByte('\\')
+			re.WriteByte(c)
+		default:
+			re.WriteByte(c)
+		}
+	}
+	return re.String()
+}
+
+func (fake *FakeExecutor) grep(args []string, stdin string) fakeResult {
+	var patterns, files []string
+	after, before, maxCount := 0, 0, -1
+	var onlyMatching, quiet, extended bool
+
+	number := func(value string) (int, error) {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("grep: invalid number '%s'", value)
+		}
+		return count, nil
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || !strings.HasPrefix(arg, "-") {
+			if len(patterns) == 0 {
+				patterns = append(patterns, arg)
+			} else {
+				files = append(files, arg)
+			}
+			continue
+		}
+		for j := 1; j < len(arg); j++ {
+			option := arg[j]
+			if strings.IndexByte("ABme", option) < 0 {
+				switch option {
+				case 'o':
+					onlyMatching = true
+				case 'q':
+					quiet = true
+				case 'E':
+					extended = true
+				default:
+					return fakeFail(fakeStatusUsage, fmt.Sprintf("grep: unrecognized option: %c\n", option))
+				}
+				continue
+			}
+
+			// The value is the rest of the argument or the next one.
+			value := arg[j+1:]
+			if value == "" {
+				i++
+				if i >= len(args) {
+					return fakeFail(fakeStatusUsage, fmt.Sprintf("grep: option requires an argument -- %c\n", option))
+				}
+				value = args[i]
+			}
+			var err error
+			switch option {
+			case 'A':
+				after, err = number(value)
+			case 'B':
+				before, err = number(value)
+			case 'm':
+				maxCount, err = number(value)
+			case 'e':
+				patterns = append(patterns, value)
+			}
+			if err != nil {
+				return fakeFail(fakeStatusUsage, err.Error()+"\n")
+			}
+			break
+		}
+	}
+	if len(patterns) == 0 {
+		return fakeFail(fakeStatusUsage, "Usage: grep [-AB N] [-m N] [-oqE] PATTERN [FILE]...\n")
+	}
+
+	var expressions []string
+	for _, pattern := range patterns {
+		if !extended {
+			pattern = fakeBasicRegexp(pattern)
+		}
+		expressions = append(expressions, "(?:"+pattern+")")
+	}
+	re, err := regexp.Compile(strings.Join(expressions, "|"))
+	if err != nil {
+		return fakeFail(fakeStatusUsage, fmt.Sprintf("grep: bad regex: %s\n", err))
+	}
+
+	input := stdin
+	if len(files) > 0 {
+		result := fake.cat(files, "")
+		if result.status != 0 {
+			return fakeFail(fakeStatusUsage, strings.ReplaceAll(result.stderr, "cat:", "grep:"))
+		}
+		input = result.stdout
+	}
+
+	lines := fakeLines(input)
+	var output []string
+	matched, lastPrinted, pendingAfter := 0, -1, 0
+	for i, line := range lines {
+		if (maxCount < 0 || matched < maxCount) && re.MatchString(line) {
+			matched++
+			if onlyMatching {
+				for _, match := range re.FindAllString(line, -1) {
+					if match != "" {
+						output = append(output, match)
+					}
+				}
+				continue
+			}
+
+			start := i - before
+			if start <= lastPrinted {
+				start = lastPrinted + 1
+			}
+			if lastPrinted >= 0 && start > lastPrinted+1 && (before > 0 || after > 0) {
+				output = append(output, "--")
+			}
+			output = append(output, lines[start:i+1]...)
+			lastPrinted, pendingAfter = i, after
+			continue
+		}
+		if pendingAfter > 0 && !onlyMatching {
+			output = append(output, line)
+			lastPrinted = i
+			pendingAfter--
+			continue
+		}
+		if maxCount >= 0 && matched >= maxCount {
+			break
+		}
+	}
+
+	if matched == 0 {
+		return fakeFail(fakeStatusFailure, "")
+	}
+	if quiet {
+		return fakeOK("")
+	}
+	return fakeOK(fakeJoinLines(output))
+}
+
+var fakeSedSubstitute = regexp.MustCompile(`^s/((?:[^/\\]|\\.)*)/((?:[^/\\]|\\.)*)/(g?)$`)
+
+func fakeSed(args []string, stdin string) fakeResult {
+	if len(args) != 1 {
+		return fakeFail(fakeStatusFailure, "sed: unsupported arguments\n")
+	}
+	lines := fakeLines(stdin)
+
+	// 1!G;h;$!d prints the lines in reverse order.
+	if args[0] == "1!G;h;$!d" {
+		for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+			lines[i], lines[j] = lines[j], lines[i]
+		}
+		return fakeOK(fakeJoinLines(lines))
+	}
+
+	matches := fakeSedSubstitute.FindStringSubmatch(args[0])
+	if matches == nil {
+		return fakeFail(fakeStatusFailure, fmt.Sprintf("sed: unsupported script: %s\n", args[0]))
+	}
+	re, err := regexp.Compile(fakeBasicRegexp(matches[1]))
+	if err != nil {
+		return fakeFail(fakeStatusFailure, fmt.Sprintf("sed: bad regex: %s\n", err))
+	}
+	replacement := strings.ReplaceAll(matches[2], "$", "$$")
+	replacement = regexp.MustCompile(`\\([0-9])`).ReplaceAllString(replacement, "$${$1}")
+	replacement = strings.ReplaceAll(replacement, "&", "${0}")
+
+	for i, line := range lines {
+		if matches[3] == "g" {
+			lines[i] = re.ReplaceAllString(line, replacement)
+		} else if loc := re.FindStringSubmatchIndex(line); loc != nil {
+			expanded := re.ExpandString(nil, replacement, line, loc)
+			lines[i] = line[:loc[0]] + string(expanded) + line[loc[1]:]
+		}
+	}
+	return fakeOK(fakeJoinLines(lines))
+}
+
+var (
+	fakeAwkPrint  = regexp.MustCompile(`^\{\s*print \$(NF|[0-9]+)\s*\}$`)
+	fakeAwkRange  = regexp.MustCompile(`^/(.*)/,/(.*)/$`)
+	fakeAwkFields = regexp.MustCompile(`^\{for\(i=([0-9]+);i<=NF-([0-9]+);\+\+i\)printf \$i" " ; printf "\\n"\}$`)
+)
+
+// fakeAwk runs the few awk programs used by the resources: printing a field,
+// printing a range of lines and printing a range of fields.
+func fakeAwk(args []string, stdin string) fakeResult {
+	if len(args) != 1 {
+		return fakeFail(fakeStatusUsage, "awk: unsupported arguments\n")
+	}
+	program := args[0]
+	lines := fakeLines(stdin)
+	var stdout strings.Builder
+
+	switch {
+	case fakeAwkPrint.MatchString(program):
+		field := fakeAwkPrint.FindStringSubmatch(program)[1]
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			index := len(fields)
+			if field != "NF" {
+				index, _ = strconv.Atoi(field)
+			}
+			switch {
+			case index == 0:
+				stdout.WriteString(line)
+			case index <= len(fields):
+				stdout.WriteString(fields[index-1])
+			}
+			stdout.WriteString("\n")
+		}
+	case fakeAwkRange.MatchString(program):
+		matches := fakeAwkRange.FindStringSubmatch(program)
+		start, err := regexp.Compile(matches[1])
+		if err != nil {
+			return fakeFail(fakeStatusUsage, fmt.Sprintf("awk: bad regex: %s\n", err))
+		}
+		end, err := regexp.Compile(matches[2])
+		if err != nil {
+			return fakeFail(fakeStatusUsage, fmt.Sprintf("awk: bad regex: %s\n", err))
+		}
+		inRange := false
+		for _, line := range lines {
+			if !inRange && start.MatchString(line) {
+				inRange = true
+			}
+			if inRange {
+				stdout.WriteString(line + "\n")
+				if end.MatchString(line) {
+					inRange = false
+				}
+			}
+		}
+	case fakeAwkFields.MatchString(program):
+		matches := fakeAwkFields.FindStringSubmatch(program)
+		first, _ := strconv.Atoi(matches[1])
+		fromEnd, _ := strconv.Atoi(matches[2])
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			for i := first; i <= len(fields)-fromEnd; i++ {
+				stdout.WriteString(fields[i-1] + " ")
+			}
+			stdout.WriteString("\n")
+		}
+	default:
+		return fakeFail(fakeStatusUsage, fmt.Sprintf("awk: unsupported program: %s\n", program))
+	}
+	return fakeOK(stdout.String())
+}
@@ -0,0 +1,630 @@
+package esxi
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const fakePoolsXML = "/etc/vmware/hostd/pools.xml"
+
+// fakeShares are the shares of the low, normal and high levels of the cpu and memory allocations.
+var fakeShares = map[string]map[string]int{
+	"cpu": {"low": 2000, "normal": 4000, "high": 8000},
+	"mem": {"low": 81920, "normal": 163840, "high": 327680},
+}
+
+type fakeAllocation struct {
+	reservation int
+	expandable  bool
+	limit       int
+	shares      int
+	level       string
+}
+
+type fakePool struct {
+	id     string
+	name   string
+	parent string
+	cpu    fakeAllocation
+	mem    fakeAllocation
+}
+
+type fakeVM struct {
+	id          string
+	name        string
+	vmxPath     string
+	pool        string
+	poweredOn   bool
+	poweredOnAt time.Time
+}
+
+// fakeDatastorePath returns the [datastore] relative/path form of a path under /vmfs/volumes.
+func fakeDatastorePath(name string) string {
+	relative := strings.TrimPrefix(name, fakeVolumesDir+"/")
+	diskStore, rest, _ := strings.Cut(relative, "/")
+	return fmt.Sprintf("[%s] %s", diskStore, rest)
+}
+
+func (fake *FakeExecutor) findVM(id string) *fakeVM {
+	for _, vm := range fake.vms {
+		if vm.id == id {
+			return vm
+		}
+	}
+	return nil
+}
+
+func (fake *FakeExecutor) findPool(id string) *fakePool {
+	for _, pool := range fake.pools {
+		if pool.id == id {
+			return pool
+		}
+	}
+	return nil
+}
+
+func (fake *FakeExecutor) vmx(vm *fakeVM) map[string]string {
+	file, ok := fake.files[vm.vmxPath]
+	if !ok {
+		return map[string]string{}
+	}
+	return ParseVMX(file.content)
+}
+
+// vmDisks returns the scsi disks of the virtual machine by controller and unit
+// number, with their absolute paths.
+func (fake *FakeExecutor) vmDisks(vm *fakeVM) ([]string, map[string]string) {
+	vmx := fake.vmx(vm)
+	re := regexp.MustCompile(`^scsi([0-9]+):([0-9]+)\.fileName$`)
+	var slots []string
+	disks := make(map[string]string)
+	for key, value := range vmx {
+		matches := re.FindStringSubmatch(key)
+		if matches == nil || strings.ToLower(vmx[fmt.Sprintf("scsi%s:%s.present", matches[1], matches[2])]) != "true" {
+			continue
+		}
+		if !strings.HasPrefix(value, "/") {
+			value = path.Join(path.Dir(vm.vmxPath), value)
+		}
+		slot := matches[1] + ":" + matches[2]
+		slots = append(slots, slot)
+		disks[slot] = value
+	}
+	sort.Strings(slots)
+	return slots, disks
+}
+
+// vmNetworks returns the port groups of the virtual machine network interfaces.
+func (fake *FakeExecutor) vmNetworks(vm *fakeVM) []string {
+	vmx := fake.vmx(vm)
+	var networks []string
+	for i := 0; i < 10; i++ {
+		if network, ok := vmx[fmt.Sprintf("ethernet%d.networkName", i)]; ok {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// vmIpAddress returns the address of a network interface of the virtual
+// machine, which is only known while the virtual machine is running.
+func (fake *FakeExecutor) vmIpAddress(vm *fakeVM, nic int) string {
+	if !vm.poweredOn || nic >= len(fake.vmNetworks(vm)) {
+		return ""
+	}
+	id, _ := strconv.Atoi(vm.id)
+	return fmt.Sprintf("192.168.%d.%d", 20+nic, 100+id%100)
+}
+
+func (fake *FakeExecutor) vimCmd(args []string) fakeResult {
+	if len(args) == 0 {
+		return fakeFail(fakeStatusFailure, "Commands available under /:\nhostsvc/ solo/ vmsvc/\n")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "vmsvc/getallvms":
+		return fakeOK(fake.getAllVMs())
+	case "solo/registervm":
+		return fake.registerVM(args)
+	case "solo/querycfgoptdesc":
+		return fakeOK(fakeConfigOptionDescriptors())
+	case "solo/querycfgopt":
+		return fakeConfigOption(args)
+	case "hostsvc/rsrc/create", "hostsvc/rsrc/pool_config_set":
+		return fake.setPool(command, args)
+	case "hostsvc/rsrc/rename", "hostsvc/rsrc/destroy", "hostsvc/rsrc/pool_config_get":
+		return fake.managePool(command, args)
+	}
+
+	operation, ok := strings.CutPrefix(command, "vmsvc/")
+	if !ok {
+		return fakeFail(fakeStatusFailure, fmt.Sprintf("Unknown command: '%s'\n", command))
+	}
+	if len(args) != 1 {
+		return fakeFail(fakeStatusFailure, "Insufficient arguments.\n")
+	}
+	vm := fake.findVM(args[0])
+	if vm == nil {
+		return fakeResult{stdout: fmt.Sprintf("Unable to find a VM corresponding to \"%s\"\n", args[0]), status: fakeStatusFailure}
+	}
+
+	invalidState := func(state string) fakeResult {
+		return fakeResult{
+			stdout: fmt.Sprintf("(vim.fault.InvalidPowerState) {\n   msg = \"The attempted operation cannot be performed in the current state (%s).\"\n}\n", state),
+			status: fakeStatusFailure,
+		}
+	}
+	switch operation {
+	case "get.summary":
+		return fakeOK(fake.vmSummary(vm))
+	case "get.config":
+		return fakeOK(fake.vmConfig(vm))
+	case "get.guest":
+		return fakeOK(fake.vmGuest(vm))
+	case "device.getdevices":
+		return fakeOK(fake.vmDevices(vm))
+	case "power.getstate":
+		state := "Powered off"
+		if vm.poweredOn {
+			state = "Powered on"
+		}
+		return fakeOK("Retrieved runtime info\n" + state + "\n")
+	case "power.on":
+		if vm.poweredOn {
+			return invalidState("Powered on")
+		}
+		vm.poweredOn, vm.poweredOnAt = true, time.Now()
+		return fakeOK("Powering on VM:\n")
+	case "power.off", "power.shutdown":
+		if !vm.poweredOn {
+			return invalidState("Powered off")
+		}
+		vm.poweredOn = false
+		if operation == "power.off" {
+			return fakeOK("Powering off VM:\n")
+		}
+		return fakeOK("")
+	case "reload":
+		return fakeOK("")
+	case "destroy":
+		if vm.poweredOn {
+			return invalidState("Powered on")
+		}
+		// Destroying a virtual machine deletes its directory and all its disks.
+		_, disks := fake.vmDisks(vm)
+		for _, disk := range disks {
+			delete(fake.files, disk)
+			delete(fake.files, fakeFlatPath(disk))
+		}
+		fake.removeAll(path.Dir(vm.vmxPath))
+		for i := range fake.vms {
+			if fake.vms[i] == vm {
+				fake.vms = append(fake.vms[:i], fake.vms[i+1:]...)
+				break
+			}
+		}
+		return fakeOK("")
+	}
+	return fakeFail(fakeStatusFailure, fmt.Sprintf("Unknown command: '%s'\n", command))
+}
+
+func (fake *FakeExecutor) getAllVMs() string {
+	var output strings.Builder
+	output.WriteString("Vmid   Name                             File                                                 Guest OS        Version   Annotation\n")
+	for _, vm := range fake.vms {
+		vmx := fake.vmx(vm)
+		output.WriteString(fmt.Sprintf("%-6s %-32s %-52s %-15s vmx-%-5s %s\n",
+			vm.id, vm.name, fakeDatastorePath(vm.vmxPath), vmx["guestOS"], vmx["virtualHW.version"], vmx["annotation"]))
+	}
+	return output.String()
+}
+
+// fakeGuestIds are the guest ids of the config option of the fake host, a
+// sample of the ones of an ESXi 7.0 host.
+var fakeGuestIds = []string{
+	"centosGuest", "centos64Guest", "centos7_64Guest", "centos8_64Guest", "debian10_64Guest", "otherGuest",
+	"otherGuest64", "otherLinuxGuest", "otherLinux64Guest", "rhel8_64Guest", "ubuntu64Guest", "windows2019srv_64Guest",
+}
+
+func fakeConfigOptionDescriptors() string {
+	var output strings.Builder
+	output.WriteString("(vim.vm.ConfigOptionDescriptor) [\n")
+	for version := fakeMinVirtualHWVer - 1; version <= fakeVirtualHWVer; version++ {
+		if version >= fakeMinVirtualHWVer {
+			output.WriteString(",\n")
+		}
+		output.WriteString(fmt.Sprintf(`   (vim.vm.ConfigOptionDescriptor) {
+      key = "%s",
+      description = "ESXi virtual machine",
+      host = (vim.HostSystem) [
+         'vim.HostSystem:ha-host'
+      ],
+      createSupported = %t,
+      defaultConfigOption = %t,
+      runSupported = true,
+      upgradeSupported = true
+   }`, hardwareVersion(version), version >= fakeMinVirtualHWVer, version == fakeVirtualHWVer))
+	}
+	output.WriteString("\n]\n")
+	return output.String()
+}
+
+func fakeConfigOption(args []string) fakeResult {
+	if len(args) != 1 {
+		return fakeFail(fakeStatusFailure, "Insufficient arguments.\n")
+	}
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("(vim.vm.ConfigOption) {\n   version = \"%s\",\n   description = \"ESXi virtual machine\",\n   guestOSDescriptor = (vim.vm.GuestOsDescriptor) [\n", args[0]))
+	for i, id := range fakeGuestIds {
+		if i > 0 {
+			output.WriteString(",\n")
+		}
+		family := "linuxGuest"
+		if strings.HasPrefix(id, "windows") {
+			family = "windowsGuest"
+		}
+		output.WriteString(fmt.Sprintf("      (vim.vm.GuestOsDescriptor) {\n         id = \"%s\",\n         family = \"%s\",\n         fullName = \"%s\"\n      }", id, family, id))
+	}
+	output.WriteString("\n   ]\n}\n")
+	return fakeOK(output.String())
+}
+
+func (fake *FakeExecutor) registerVM(args []string) fakeResult {
+	if len(args) < 1 || len(args) > 3 {
+		return fakeFail(fakeStatusFailure, "Usage: solo/registervm vmxPath [name] [resourcePool]\n")
+	}
+	vmxPath := cleanFakePath(args[0])
+	if file, ok := fake.files[vmxPath]; !ok || file.dir {
+		return fakeResult{stdout: "(vim.fault.NotFound) {\n   msg = \"The object or item referred to could not be found.\"\n}\n", status: fakeStatusFailure}
+	}
+	for _, vm := range fake.vms {
+		if vm.vmxPath == vmxPath {
+			return fakeResult{stdout: "(vim.fault.AlreadyExists) {\n   msg = \"The specified key, name, or identifier already exists.\"\n}\n", status: fakeStatusFailure}
+		}
+	}
+
+	name := ParseVMX(fake.files[vmxPath].content)["displayName"]
+	if len(args) > 1 {
+		name = args[1]
+	}
+	pool := rootPool
+	if len(args) > 2 {
+		pool = args[2]
+		if pool != rootPool && fake.findPool(pool) == nil {
+			return fakeResult{stdout: fmt.Sprintf("(vmodl.fault.ManagedObjectNotFound) {\n   obj = 'vim.ResourcePool:%s'\n}\n", pool), status: fakeStatusFailure}
+		}
+	}
+
+	fake.lastVMId++
+	vm := &fakeVM{id: strconv.Itoa(fake.lastVMId), name: name, vmxPath: vmxPath, pool: pool}
+	fake.vms = append(fake.vms, vm)
+	return fakeOK(vm.id + "\n")
+}
+
+func (fake *FakeExecutor) vmSummary(vm *fakeVM) string {
+	vmx := fake.vmx(vm)
+	powerState, uptime, ipAddress := "poweredOff", 0, "<unset>"
+	if vm.poweredOn {
+		powerState, uptime = "poweredOn", int(time.Since(vm.poweredOnAt).Seconds())
+	}
+	if ip := fake.vmIpAddress(vm, 0); ip != "" {
+		ipAddress = fmt.Sprintf("%q", ip)
+	}
+	return fmt.Sprintf(`Listsummary:
+
+(vim.vm.Summary) {
+   vm = 'vim.VirtualMachine:%s',
+   runtime = (vim.vm.RuntimeInfo) {
+      connectionState = "connected",
+      powerState = "%s",
+   },
+   guest = (vim.vm.Summary.GuestSummary) {
+      guestId = "%s",
+      ipAddress = %s,
+   },
+   config = (vim.vm.Summary.ConfigSummary) {
+      name = "%s",
+      template = false,
+      vmPathName = "%s",
+      memorySizeMB = %s,
+      numCpu = %s,
+      annotation = "%s",
+      guestId = "%s",
+   },
+   quickStats = (vim.vm.Summary.QuickStats) {
+      uptimeSeconds = %d,
+   },
+   overallStatus = "green",
+}
+`, vm.id, powerState, vmx["guestOS"], ipAddress, vm.name, fakeDatastorePath(vm.vmxPath),
+		vmx["memSize"], vmx["numvcpus"], vmx["annotation"], vmx["guestOS"], uptime)
+}
+
+func (fake *FakeExecutor) vmConfig(vm *fakeVM) string {
+	vmx := fake.vmx(vm)
+	dir := fakeDatastorePath(path.Dir(vm.vmxPath)) + "/"
+	return fmt.Sprintf(`Configuration:
+(vim.vm.ConfigInfo) {
+   name = "%s",
+   version = "vmx-%s",
+   guestId = "%s",
+   annotation = "%s",
+   files = (vim.vm.FileInfo) {
+      vmPathName = "%s",
+      snapshotDirectory = "%s",
+      suspendDirectory = "%s",
+      logDirectory = "%s"
+   },
+   hardware = (vim.vm.VirtualHardware) {
+      numCPU = %s,
+      memoryMB = %s,
+   },
+}
+`, vm.name, vmx["virtualHW.version"], vmx["guestOS"], vmx["annotation"],
+		fakeDatastorePath(vm.vmxPath), dir, dir, dir, vmx["numvcpus"], vmx["memSize"])
+}
+
+func (fake *FakeExecutor) vmDevices(vm *fakeVM) string {
+	vmx := fake.vmx(vm)
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("(vim.vm.VirtualHardware) {\n   numCPU = %s,\n   memoryMB = %s,\n   device = (vim.vm.device.VirtualDevice) [\n",
+		vmx["numvcpus"], vmx["memSize"]))
+
+	slots, disks := fake.vmDisks(vm)
+	for i, slot := range slots {
+		var size int64
+		if disk, ok := fake.files[disks[slot]]; ok {
+			size = disk.size
+		}
+		controller, unit, _ := strings.Cut(slot, ":")
+		controllerKey, _ := strconv.Atoi(controller)
+		output.WriteString(fmt.Sprintf(`      (vim.vm.device.VirtualDisk) {
+         key = %d,
+         deviceInfo = (vim.Description) {
+            label = "Hard disk %d",
+            summary = "%d KB"
+         },
+         backing = (vim.vm.device.VirtualDisk.FlatVer2BackingInfo) {
+            fileName = "%s",
+            diskMode = "persistent",
+         },
+         controllerKey = %d,
+         unitNumber = %s,
+      },
+`, 2000+i, i+1, size/1024, fakeDatastorePath(disks[slot]), 1000+controllerKey, unit))
+	}
+
+	for i, network := range fake.vmNetworks(vm) {
+		output.WriteString(fmt.Sprintf(`      (vim.vm.device.VirtualVmxnet3) {
+         key = %d,
+         deviceInfo = (vim.Description) {
+            label = "Network adapter %d",
+            summary = "%s"
+         },
+      },
+`, 4000+i, i+1, network))
+	}
+	output.WriteString("   ],\n}\n")
+	return output.String()
+}
+
+func (fake *FakeExecutor) vmGuest(vm *fakeVM) string {
+	ip := fake.vmIpAddress(vm, 0)
+	if ip == "" {
+		return `Guest information:
+
+(vim.vm.GuestInfo) {
+   toolsStatus = "toolsNotRunning",
+   toolsRunningStatus = "guestToolsNotRunning",
+   ipAddress = <unset>,
+   net = (vim.vm.GuestInfo.NicInfo) [
+   ],
+   guestState = "notRunning",
+}
+`
+	}
+
+	var nics strings.Builder
+	for i, network := range fake.vmNetworks(vm) {
+		nics.WriteString(fmt.Sprintf(`      (vim.vm.GuestInfo.NicInfo) {
+         network = "%s",
+         ipAddress = (string) [
+            "%s",
+            "fe80::250:56ff:fe00:%x"
+         ],
+         macAddress = "00:50:56:00:00:%02x",
+         connected = true,
+         deviceConfigId = %d,
+      },
+`, network, fake.vmIpAddress(vm, i), i, i, 4000+i))
+	}
+	return fmt.Sprintf(`Guest information:
+
+(vim.vm.GuestInfo) {
+   toolsStatus = "toolsOk",
+   toolsRunningStatus = "guestToolsRunning",
+   hostName = "%s",
+   ipAddress = "%s",
+   net = (vim.vm.GuestInfo.NicInfo) [
+%s   ],
+   guestState = "running",
+}
+`, vm.name, ip, nics.String())
+}
+
+// setPool creates a resource pool or sets its allocations.
+func (fake *FakeExecutor) setPool(command string, args []string) fakeResult {
+	var options, operands []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			options = append(options, arg)
+		} else {
+			operands = append(operands, arg)
+		}
+	}
+
+	var pool *fakePool
+	if command == "hostsvc/rsrc/create" {
+		if len(operands) != 2 {
+			return fakeFail(fakeStatusFailure, "Usage: create [options] parent name\n")
+		}
+		parent, name := operands[0], operands[1]
+		if parent != rootPool && fake.findPool(parent) == nil {
+			return fake.poolNotFound(parent)
+		}
+		for _, sibling := range fake.pools {
+			if sibling.parent == parent && sibling.name == name {
+				return fakeResult{stdout: "(vim.fault.DuplicateName) {\n   msg = \"The name '" + name + "' already exists.\"\n}\n", status: fakeStatusFailure}
+			}
+		}
+		pool = &fakePool{
+			name:   name,
+			parent: parent,
+			cpu:    fakeAllocation{expandable: true, limit: -1, shares: fakeShares["cpu"]["normal"], level: "normal"},
+			mem:    fakeAllocation{expandable: true, limit: -1, shares: fakeShares["mem"]["normal"], level: "normal"},
+		}
+	} else {
+		if len(operands) != 1 {
+			return fakeFail(fakeStatusFailure, "Usage: pool_config_set [options] pool\n")
+		}
+		if pool = fake.findPool(operands[0]); pool == nil {
+			return fake.poolNotFound(operands[0])
+		}
+	}
+
+	// Validate all the options before updating the pool.
+	updated := *pool
+	for _, option := range options {
+		key, value, _ := strings.Cut(strings.TrimPrefix(option, "--"), "=")
+		resource, setting, _ := strings.Cut(key, "-")
+		allocation := &updated.cpu
+		if resource == "mem" {
+			allocation = &updated.mem
+		} else if resource != "cpu" {
+			return fakeFail(fakeStatusFailure, fmt.Sprintf("Invalid option: %s\n", option))
+		}
+
+		var err error
+		switch setting {
+		case "min":
+			allocation.reservation, err = strconv.Atoi(value)
+		case "min-expandable":
+			allocation.expandable, err = strconv.ParseBool(value)
+		case "max":
+			allocation.limit, err = strconv.Atoi(value)
+		case "shares":
+			if shares, ok := fakeShares[resource][value]; ok {
+				allocation.shares, allocation.level = shares, value
+			} else {
+				allocation.shares, err = strconv.Atoi(value)
+				allocation.level = "custom"
+			}
+		default:
+			return fakeFail(fakeStatusFailure, fmt.Sprintf("Invalid option: %s\n", option))
+		}
+		if err != nil {
+			return fakeFail(fakeStatusFailure, fmt.Sprintf("Invalid value for %s: %s\n", key, value))
+		}
+	}
+	*pool = updated
+
+	if command == "hostsvc/rsrc/create" {
+		fake.lastPoolId++
+		pool.id = fmt.Sprintf("pool%d", fake.lastPoolId)
+		fake.pools = append(fake.pools, pool)
+		return fakeOK(fmt.Sprintf("'vim.ResourcePool:%s'\n", pool.id))
+	}
+	return fakeOK("")
+}
+
+func (fake *FakeExecutor) poolNotFound(id string) fakeResult {
+	return fakeResult{
+		stdout: fmt.Sprintf("(vmodl.fault.ManagedObjectNotFound) {\n   faultCause = (vmodl.MethodFault) null,\n   obj = 'vim.ResourcePool:%s',\n   msg = \"The object 'vim.ResourcePool:%s' has already been deleted or has not been completely created\"\n}\n", id, id),
+		status: fakeStatusFailure,
+	}
+}
+
+// managePool renames, destroys or gets the allocations of a resource pool.
+func (fake *FakeExecutor) managePool(command string, args []string) fakeResult {
+	if len(args) < 1 {
+		return fakeFail(fakeStatusFailure, "Insufficient arguments.\n")
+	}
+	pool := fake.findPool(args[0])
+	if pool == nil {
+		return fake.poolNotFound(args[0])
+	}
+
+	switch command {
+	case "hostsvc/rsrc/rename":
+		if len(args) != 2 {
+			return fakeFail(fakeStatusFailure, "Usage: rename pool name\n")
+		}
+		pool.name = args[1]
+		return fakeOK("")
+	case "hostsvc/rsrc/destroy":
+		// The child pools and virtual machines are moved to the parent pool.
+		for _, child := range fake.pools {
+			if child.parent == pool.id {
+				child.parent = pool.parent
+			}
+		}
+		for _, vm := range fake.vms {
+			if vm.pool == pool.id {
+				vm.pool = pool.parent
+			}
+		}
+		for i := range fake.pools {
+			if fake.pools[i] == pool {
+				fake.pools = append(fake.pools[:i], fake.pools[i+1:]...)
+				break
+			}
+		}
+		return fakeOK("")
+	}
+
+	allocation := func(name string, allocation fakeAllocation) string {
+		return fmt.Sprintf(`   %s = (vim.ResourceAllocationInfo) {
+      reservation = %d,
+      expandableReservation = %t,
+      limit = %d,
+      shares = (vim.SharesInfo) {
+         shares = %d,
+         level = "%s"
+      },
+      overheadLimit = <unset>
+   },
+`, name, allocation.reservation, allocation.expandable, allocation.limit, allocation.shares, allocation.level)
+	}
+	return fakeOK("(vim.ResourceConfigSpec) {\n   entity = 'vim.ResourcePool:" + pool.id + "',\n   changeVersion = <unset>,\n   lastModified = <unset>,\n" +
+		allocation("cpuAllocation", pool.cpu) + allocation("memoryAllocation", pool.mem) + "}\n")
+}
+
+// poolsXML returns the hostd inventory of the resource pools and the virtual machines.
+func (fake *FakeExecutor) poolsXML() string {
+	poolPath := func(pool *fakePool) string {
+		ids := []string{pool.id}
+		for parent := fake.findPool(pool.parent); parent != nil; parent = fake.findPool(parent.parent) {
+			ids = append([]string{parent.id}, ids...)
+		}
+		return "host/user/" + strings.Join(ids, "/")
+	}
+
+	var output strings.Builder
+	output.WriteString("<ConfigRoot>\n")
+	for i, pool := range fake.pools {
+		output.WriteString(fmt.Sprintf("  <resourcePool id=\"%04d\">\n    <name>%s</name>\n    <objID>%s</objID>\n    <path>%s</path>\n  </resourcePool>\n",
+			i, pool.name, pool.id, poolPath(pool)))
+	}
+	for i, vm := range fake.vms {
+		output.WriteString(fmt.Sprintf("  <vm id=\"%04d\">\n    <objID>%s</objID>\n    <resourcePool>%s</resourcePool>\n  </vm>\n",
+			len(fake.pools)+i, vm.id, vm.pool))
+	}
+	output.WriteString("</ConfigRoot>\n")
+	return output.String()
+}
@@ -0,0 +1,165 @@
+package esxi
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// fakeDiskMappings are the block mappings reported by vmkfstools -t0 for the
+// disk types, the resources detect the disk type from them.
+var fakeDiskMappings = map[string]string{
+	"thin":             "NOMP -- :",
+	"zeroedthick":      "VMFS Z- LVID:",
+	"eagerzeroedthick": "VMFS -- LVID:",
+}
+
+func fakeFlatPath(descriptor string) string {
+	return strings.TrimSuffix(descriptor, ".vmdk") + "-flat.vmdk"
+}
+
+func fakeDiskDescriptor(descriptor string, size int64, diskType string) string {
+	thin := ""
+	if diskType == "thin" {
+		thin = "ddb.thinProvisioned = \"1\"\n"
+	}
+	return fmt.Sprintf(`# Disk DescriptorFile
+version=1
+CID=fffffffe
+parentCID=ffffffff
+createType="vmfs"
+
+# Extent description
+RW %d VMFS "%s"
+
+# The Disk Data Base
+#DDB
+
+ddb.adapterType = "lsilogic"
+%s`, size/512, path.Base(fakeFlatPath(descriptor)), thin)
+}
+
+// parseFakeDiskSize parses the sizes of vmkfstools, in bytes or with a k, m or g suffix.
+func parseFakeDiskSize(value string) (int64, error) {
+	units := map[byte]int64{'k': 1 << 10, 'm': 1 << 20, 'g': 1 << 30}
+	multiplier := int64(1)
+	if len(value) > 0 {
+		if unit, ok := units[strings.ToLower(value)[len(value)-1]]; ok {
+			multiplier = unit
+			value = value[:len(value)-1]
+		}
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid size %s", value)
+	}
+	return size * multiplier, nil
+}
+
+// vmkfstools emulates the creation (-c), growth (-X), deletion (-U) and the
+// block mappings (-t0) of the virtual disks, and the query of the datastores
+// (-P), failures exit with status 255.
+func (fake *FakeExecutor) vmkfstools(args []string) fakeResult {
+	var create, grow, diskType string
+	var remove, mappings, query bool
+	var operands []string
+	for i := 0; i < len(args); i++ {
+		option := args[i]
+		switch option {
+		case "-U":
+			remove = true
+		case "-t0":
+			mappings = true
+		case "-P":
+			query = true
+		case "-c", "-X", "-d":
+			i++
+			if i >= len(args) {
+				return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Option %s requires an argument\n", option))
+			}
+			switch option {
+			case "-c":
+				create = args[i]
+			case "-X":
+				grow = args[i]
+			case "-d":
+				diskType = args[i]
+			}
+		default:
+			if strings.HasPrefix(option, "-") {
+				return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Unsupported option %s\n", option))
+			}
+			operands = append(operands, option)
+		}
+	}
+	if len(operands) != 1 {
+		return fakeFail(fakeStatusVmkfstools, "Usage: vmkfstools [-c size [-d type]] [-X size] [-U] [-t0] [-P] path\n")
+	}
+	name := cleanFakePath(operands[0])
+	if query {
+		return fake.queryDiskStore(name)
+	}
+	disk, exists := fake.files[name]
+	if exists && disk.diskType == "" {
+		exists = false
+	}
+
+	switch {
+	case create != "":
+		if diskType == "" {
+			diskType = "zeroedthick"
+		}
+		size, err := parseFakeDiskSize(create)
+		switch {
+		case err != nil:
+			return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Failed to create virtual disk: %s.\n", err))
+		case fakeDiskMappings[diskType] == "":
+			return fakeFail(fakeStatusVmkfstools, "Failed to create virtual disk: The specified disk type is invalid.\n")
+		case !strings.HasSuffix(name, ".vmdk"):
+			return fakeFail(fakeStatusVmkfstools, "Failed to create virtual disk: The disk path must end with .vmdk.\n")
+		case exists || fake.files[fakeFlatPath(name)] != nil:
+			return fakeFail(fakeStatusVmkfstools, "Failed to create virtual disk: The file already exists (39).\n")
+		}
+		if err = fake.checkParent(name); err != nil {
+			return fakeFail(fakeStatusVmkfstools, "Failed to create virtual disk: No such file or directory (1245186).\n")
+		}
+		fake.files[name] = &fakeFile{content: fakeDiskDescriptor(name, size, diskType), size: size, diskType: diskType}
+		fake.files[fakeFlatPath(name)] = &fakeFile{content: "", size: size, diskType: diskType}
+		return fakeOK("Create: 100% done.\n")
+	case !exists:
+		return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Failed to open '%s': Could not find the file (1245187).\n", name))
+	case grow != "":
+		size, err := parseFakeDiskSize(grow)
+		switch {
+		case err != nil:
+			return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Failed to extend disk: %s.\n", err))
+		case size < disk.size:
+			return fakeFail(fakeStatusVmkfstools, "Failed to extend disk : Shrinking is not supported (1).\n")
+		}
+		disk.size = size
+		disk.content = fakeDiskDescriptor(name, size, disk.diskType)
+		if flat := fake.files[fakeFlatPath(name)]; flat != nil {
+			flat.size = size
+		}
+		return fakeOK("Grow: 100% done.\n")
+	case remove:
+		delete(fake.files, name)
+		delete(fake.files, fakeFlatPath(name))
+		return fakeOK("")
+	case mappings:
+		return fakeOK(fmt.Sprintf("Mapping for file %s (%d bytes in size):\n[           0:  %11d] --> [%s   0 Offset: 0 Length: %d]\n",
+			name, disk.size, disk.size, fakeDiskMappings[disk.diskType], disk.size))
+	}
+	return fakeFail(fakeStatusVmkfstools, "No operation specified\n")
+}
+
+func (fake *FakeExecutor) queryDiskStore(name string) fakeResult {
+	for _, diskStore := range fake.diskStores {
+		if name == fakeVolumesDir+"/"+diskStore {
+			return fakeOK(fmt.Sprintf("VMFS-6.82 (Raw Major Version: 24) file system spanning 1 partitions.\n"+
+				"File system label (if any): %s\nMode: public\n", diskStore))
+		}
+	}
+	return fakeFail(fakeStatusVmkfstools, fmt.Sprintf("Could not retrieve the attributes of '%s': No such file or directory\n", name))
+}
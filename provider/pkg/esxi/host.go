@@ -1,133 +1,200 @@
 package esxi
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net"
-	"os"
-	"strings"
 	"time"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
-	"github.com/tmc/scp"
-	"golang.org/x/crypto/ssh"
 )
 
-const (
-	failedToConnect = "failed to connect to esxi host"
-
-	attempts = 10
-)
+// Executor runs the commands and transfers the files of the resource operations
+// on the esxi host.
+type Executor interface {
+	// Execute runs the shell command on the host and returns its trimmed combined output.
+	Execute(ctx context.Context, command string, shortCmdDesc string) (string, error)
+	// WriteFile writes the content to the file at path on the host.
+	WriteFile(ctx context.Context, content string, path string, shortCmdDesc string) (string, error)
+	// CopyFile copies the local file to the hostPath on the host.
+	CopyFile(ctx context.Context, localPath string, hostPath string, shortCmdDesc string) (string, error)
+}
 
+// Host is the esxi host the resources are managed on. With the ssh transport
+// the remote operations go through its Executor, with the api transport the
+// resources are managed through the api client instead.
 type Host struct {
-	ClientConfig *ssh.ClientConfig
-	Connection   *ConnectionInfo
+	Connection *ConnectionInfo
+	// Info is the release of the host and the features it supports, detected
+	// when connecting to the host, it is nil when the host was not detected.
+	Info *HostInfo
+
+	executor Executor
+	api      *apiClient
+	// bastion tunnels the connections to the host when it is not reachable directly.
+	bastion *bastion
+	// sleep waits between the power state checks of the virtual machines.
+	sleep func(ctx context.Context, duration time.Duration) error
+	// redactor scrubs the secrets from the logged commands and the errors.
+	redactor *redactor
+	// recorder appends the remote operations to the record file, when one is configured.
+	recorder *recorder
+	// sessions bounds the remote operations running at once on the host.
+	sessions semaphore
+	// topology and vmLocks serialize the read-modify-write sequences of the
+	// resource operations, see lockTopology and lockVirtualMachine.
+	topology semaphore
+	vmLocks  keyedLocks
 }
 
-func NewHost(host, sshPort, sslPort, user, pass string) (*Host, error) {
-	connection := ConnectionInfo{
-		Host:     host,
-		SSHPort:  sshPort,
-		SslPort:  sslPort,
-		UserName: user,
-		Password: pass,
+var _ Executor = (*Host)(nil)
+
+// errNoExecutor is returned for the remote commands of the hosts managed with the api transport.
+var errNoExecutor = errors.New("remote commands are not available with the api transport")
+
+// NewHost connects to the esxi host with the configured transport and validates the credentials.
+func NewHost(ctx context.Context, connection ConnectionInfo) (*Host, error) {
+	redactor := newRedactor(connection.connectionSecrets()...)
+	instance, err := newHost(ctx, connection)
+	if err != nil {
+		return nil, redactor.redactError(err)
 	}
-	clientConfig := &ssh.ClientConfig{
-		User: connection.UserName,
-		Auth: []ssh.AuthMethod{
-			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
-				// Reply password to all questions
-				answers := make([]string, len(questions))
-				for i := range answers {
-					answers[i] = connection.Password
-				}
+	instance.redactor = redactor
+	return instance, nil
+}
 
-				return answers, nil
-			}),
-		},
+func newHost(ctx context.Context, connection ConnectionInfo) (*Host, error) {
+	if transport := connection.Transport; transport != "" && transport != TransportSSH && transport != TransportAPI {
+		return nil, fmt.Errorf("unknown transport '%s', expected '%s' or '%s'", transport, TransportSSH, TransportAPI)
 	}
-	clientConfig.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
-		return nil
+
+	var tunnel *bastion
+	if connection.hasBastion() {
+		var err error
+		if tunnel, err = newBastion(&connection); err != nil {
+			return nil, err
+		}
 	}
 
-	instance := &Host{
-		Connection:   &connection,
-		ClientConfig: clientConfig,
+	if connection.Transport == TransportAPI {
+		if connection.DryRun {
+			return nil, fmt.Errorf("dryRun requires the '%s' transport, the api calls are not logged", TransportSSH)
+		}
+		instance := NewHostWithExecutor(connection, nil)
+		api, err := newAPIClient(ctx, instance.Connection, tunnel, instance.sessions)
+		if err != nil {
+			if tunnel != nil {
+				tunnel.Close()
+			}
+			return nil, err
+		}
+		instance.api = api
+		instance.bastion = tunnel
+		instance.Info = api.hostInfo(ctx)
+		return instance, nil
 	}
 
-	err := instance.validateCreds()
+	executor, err := newSSHExecutor(&connection, tunnel)
 	if err != nil {
+		if tunnel != nil {
+			tunnel.Close()
+		}
 		return nil, err
 	}
 
+	instance := NewHostWithExecutor(connection, executor)
+	instance.bastion = tunnel
+	if len(connection.RecordFile) > 0 {
+		if instance.recorder, err = newRecorder(connection.RecordFile); err != nil {
+			instance.Close()
+			return nil, err
+		}
+	}
+	version, err := instance.validateCreds(ctx)
+	if err != nil {
+		instance.Close()
+		return nil, err
+	}
+	instance.Info = instance.detectHostInfo(ctx, version)
+
 	return instance, nil
 }
 
-func (esxi *Host) validateCreds() error {
+// NewHostWithExecutor returns a host running the remote operations with the given executor.
+func NewHostWithExecutor(connection ConnectionInfo, executor Executor) *Host {
+	return &Host{
+		Connection: &connection,
+		executor:   executor,
+		sleep:      sleepContext,
+		redactor:   newRedactor(connection.connectionSecrets()...),
+		sessions:   newSemaphore(connection.maxConcurrentSessions()),
+		topology:   newSemaphore(1),
+	}
+}
+
+// validateCreds checks the connection to the host and returns its vmware version.
+func (esxi *Host) validateCreds(ctx context.Context) (string, error) {
 	var remoteCmd string
 	var err error
 
 	remoteCmd = "vmware --version"
-	_, err = esxi.Execute(remoteCmd, "Connectivity test, get vmware version")
+	version, err := esxi.Execute(ctx, remoteCmd, "Connectivity test, get vmware version")
+	var mismatchErr *HostKeyMismatchError
+	if errors.As(err, &mismatchErr) {
+		return "", fmt.Errorf("failed to connect to esxi host, the ssh host key did not match: %w", mismatchErr)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to esxi host: %w", err)
+		return "", fmt.Errorf("failed to connect to esxi host: %w", err)
 	}
 
-	mkdir, err := esxi.Execute("mkdir -p ~", "Create home directory if missing")
+	if esxi.Connection.ReadOnly || esxi.Connection.DryRun {
+		return version, nil
+	}
+
+	mkdir, err := esxi.ExecuteWithRetry(ctx, "mkdir -p ~", "Create home directory if missing")
 	logging.V(logLevel).Infof("ValidateCreds: Create home! %s %s", mkdir, err)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return version, nil
 }
 
-// Connect to esxi host using ssh
-func (esxi *Host) connect(attempt int) (*ssh.Client, *ssh.Session, error) {
-	for attempt > 0 {
-		client, err := ssh.Dial("tcp", esxi.Connection.getSSHConnection(), esxi.ClientConfig)
-		if err != nil {
-			logging.V(logLevel).Infof("Connect: Retry attempt %d", attempt)
-			attempt -= 1
-			time.Sleep(1 * time.Second)
-		} else {
-			session, err := client.NewSession()
-			if err != nil {
-				closeErr := client.Close()
-				if closeErr != nil {
-					return nil, nil, fmt.Errorf("session connection error. (closing client error: %w)", closeErr)
-				}
-				return nil, nil, fmt.Errorf("session connection error")
-			}
-
-			return client, session, nil
-		}
+// Close releases the resources of the executor, e.g. the ssh connections to
+// the esxi host, or logs out of the api, then closes the bastion connection.
+func (esxi *Host) Close() {
+	if esxi.api != nil {
+		esxi.api.close()
+	} else if closer, ok := esxi.executor.(interface{ Close() }); ok {
+		closer.Close()
+	}
+	if esxi.bastion != nil {
+		esxi.bastion.Close()
+	}
+	if esxi.recorder != nil {
+		esxi.recorder.close()
 	}
-	return nil, nil, fmt.Errorf("client connection error")
 }
 
-func (esxi *Host) Execute(command string, shortCmdDesc string) (string, error) {
-	logging.V(logLevel).Infof("Execute: %s", shortCmdDesc)
-
-	var attempt int
-
-	if command == "vmware --version" {
-		attempt = 3
-	} else {
-		attempt = 10
+func (esxi *Host) Execute(ctx context.Context, command string, shortCmdDesc string) (string, error) {
+	if esxi.executor == nil {
+		return "", errNoExecutor
+	}
+	if !isReadOnlyCommand(command) {
+		if skip, err := esxi.skipMutation(command, shortCmdDesc); skip {
+			return "", err
+		}
 	}
-	client, session, err := esxi.connect(attempt)
+	release, err := esxi.sessions.acquire(ctx)
 	if err != nil {
-		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
 		return failedToConnect, err
 	}
-
-	stdoutRaw, err := session.CombinedOutput(command)
-	stdout := strings.TrimSpace(string(stdoutRaw))
-
-	if stdout == "<unset>" {
-		return "failed to connect to esxi host or Management Agent has been restarted", err
-	}
+	start := time.Now()
+	stdout, err := esxi.executor.Execute(ctx, command, shortCmdDesc)
+	release()
+	esxi.record(Recording{Operation: RecordExecute, Command: command, Stdout: stdout}, start, err)
 
 	logMessage := fmt.Sprintf("Execute: cmd => %s", command)
 	if len(stdout) > 0 {
@@ -136,60 +203,77 @@ func (esxi *Host) Execute(command string, shortCmdDesc string) (string, error) {
 	if err != nil {
 		logMessage = fmt.Sprintf("%s\tstderr => %s\n", logMessage, err)
 	}
-	logging.V(logLevel).Infof(logMessage)
-	if closeErr := client.Close(); closeErr != nil {
-		logging.V(logLevel).Infof("Failed closing the client connection to host! %s", closeErr)
-	}
+	logging.V(logLevel).Infof("%s", esxi.redact(logMessage))
 
 	return stdout, err
 }
 
-func (esxi *Host) WriteFile(content string, path string, shortCmdDesc string) (string, error) {
-	logging.V(logLevel).Infof("WriteFile: %s", shortCmdDesc)
+// ExecuteWithRetry runs the command like Execute, and runs it again when it
+// fails with an error of the retried classes, the transient errors by default.
+// The resource operations only use it for the commands safe to run several
+// times, e.g. the reads and the commands setting a value.
+func (esxi *Host) ExecuteWithRetry(ctx context.Context, command string, shortCmdDesc string, retried ...ErrorClass) (string, error) {
+	return retry(ctx, esxi.Connection.Retry, esxi.sleep, shortCmdDesc, func() (string, string, error) {
+		stdout, err := esxi.Execute(ctx, command, shortCmdDesc)
+		return stdout, stdout, err
+	}, retried...)
+}
 
-	f, _ := os.CreateTemp("", "")
-	_, err := fmt.Fprintln(f, content)
-	if err != nil {
-		return "", err
+// redact replaces the secrets of the connection and of the resource inputs in the text.
+func (esxi *Host) redact(text string) string {
+	if esxi == nil {
+		return text
 	}
-	fCloseErr := f.Close()
-	if fCloseErr != nil {
-		return "", fCloseErr
+	return esxi.redactor.redact(text)
+}
+
+// redactError replaces the secrets in the message of the error.
+func (esxi *Host) redactError(err error) error {
+	if esxi == nil {
+		return err
 	}
-	defer RemoveFile(f)
+	return esxi.redactor.redactError(err)
+}
 
-	client, session, err := esxi.connect(attempts)
-	if err != nil {
-		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
-		return failedToConnect, err
+// addSecretInputs registers the secret inputs of a resource for redaction.
+func (esxi *Host) addSecretInputs(inputs resource.PropertyMap) {
+	if esxi != nil {
+		esxi.redactor.addInputs(inputs)
 	}
-	err = scp.CopyPath(f.Name(), path, session)
+}
+
+func (esxi *Host) WriteFile(ctx context.Context, content string, path string, shortCmdDesc string) (string, error) {
+	if esxi.executor == nil {
+		return "", errNoExecutor
+	}
+	if skip, err := esxi.skipMutation("write "+path, shortCmdDesc); skip {
+		return "", err
+	}
+	release, err := esxi.sessions.acquire(ctx)
 	if err != nil {
-		logging.V(logLevel).Infof("WriteFile: Failed copying the file! %s", err)
 		return failedToConnect, err
 	}
-	if closeErr := client.Close(); closeErr != nil {
-		logging.V(logLevel).Infof("Failed closing the client connection to host! %s", closeErr)
-	}
-
-	return content, err
+	start := time.Now()
+	stdout, err := esxi.executor.WriteFile(ctx, content, path, shortCmdDesc)
+	release()
+	esxi.record(Recording{Operation: RecordWriteFile, Path: path, Content: content, Stdout: stdout}, start, err)
+	return stdout, err
 }
 
-func (esxi *Host) CopyFile(localPath string, hostPath string, shortCmdDesc string) (string, error) {
-	logging.V(logLevel).Infof("CopyFile: %s", shortCmdDesc)
-
-	client, session, err := esxi.connect(attempts)
-	if err != nil {
-		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
-		return failedToConnect, err
+func (esxi *Host) CopyFile(ctx context.Context, localPath string, hostPath string, shortCmdDesc string) (string, error) {
+	if esxi.executor == nil {
+		return "", errNoExecutor
 	}
-	err = scp.CopyPath(localPath, hostPath, session)
-	if err != nil {
-		return "Failed to copy file to esxi host!", err
+	if skip, err := esxi.skipMutation("copy "+localPath+" to "+hostPath, shortCmdDesc); skip {
+		return "", err
 	}
-	if closeErr := client.Close(); closeErr != nil {
-		logging.V(logLevel).Infof("Failed closing the client connection to host! %s", closeErr)
+	release, err := esxi.sessions.acquire(ctx)
+	if err != nil {
+		return failedToConnect, err
 	}
-
-	return "", nil
+	start := time.Now()
+	stdout, err := esxi.executor.CopyFile(ctx, localPath, hostPath, shortCmdDesc)
+	release()
+	esxi.record(Recording{Operation: RecordCopyFile, Path: hostPath, Stdout: stdout}, start, err)
+	return stdout, err
 }
@@ -0,0 +1,213 @@
+package esxi
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jszwec/csvutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+const (
+	// vmSecureBootVirtualHWVer is the first virtual hardware version with the
+	// UEFI secure boot, introduced with ESXi 6.5.
+	vmSecureBootVirtualHWVer = 13
+	vmFirmwareEfi            = "efi"
+)
+
+// nicTypeVirtualHWVer is the first virtual hardware version of the nic types
+// not supported by all of them.
+var nicTypeVirtualHWVer = map[string]int{
+	"vmxnet3": 7,
+	"e1000e":  8,
+}
+
+// HostInfo describes the ESXi release of a host and the features it supports.
+// It is detected once, when connecting to the host, the features which could
+// not be detected are left empty and aren't checked.
+type HostInfo struct {
+	// Version and Build are the ones of the ESXi release, e.g. 7.0.3 and 21930508.
+	Version string
+	Build   string
+	// VirtualHWVersions are the virtual hardware versions the virtual machines
+	// can be created with, in ascending order.
+	VirtualHWVersions []int
+	// DefaultVirtualHWVer is the virtual hardware version the host creates the virtual machines with.
+	DefaultVirtualHWVer int
+	// GuestOSs are the api guest ids supported by the host, e.g. centos7_64Guest,
+	// the os of the virtual machines is checked against them.
+	GuestOSs []string
+	// HasOvftool is true when ovftool is on the PATH of the provider, to build
+	// the virtual machines from an OVF/OVA or to clone them.
+	HasOvftool bool
+	// HasEsxcliFormatter is true when esxcli supports the --formatter option.
+	HasEsxcliFormatter bool
+}
+
+type esxcliVersion struct {
+	Product string `csv:"Product"`
+	Version string `csv:"Version"`
+	Build   string `csv:"Build"`
+}
+
+var (
+	vmwareVersionPattern       = regexp.MustCompile(`ESXi ([0-9][0-9.]*)(?: build-([0-9]+))?`)
+	configOptionKeyPattern     = regexp.MustCompile(`key = "vmx-([0-9]+)"`)
+	configOptionFlagPattern    = regexp.MustCompile(`(createSupported|defaultConfigOption) = (true|false)`)
+	guestOSDescriptorIdPattern = regexp.MustCompile(`^\s*id = "([A-Za-z0-9_]+Guest[A-Za-z0-9_]*)"`)
+)
+
+// detectHostInfo detects the release and the features of the host, version
+// is the output of the vmware --version connectivity test.
+func (esxi *Host) detectHostInfo(ctx context.Context, version string) *HostInfo {
+	info := &HostInfo{HasOvftool: hasOvftool()}
+	if matches := vmwareVersionPattern.FindStringSubmatch(version); matches != nil {
+		info.Version, info.Build = matches[1], matches[2]
+	}
+
+	stdout, err := esxi.Execute(ctx, "esxcli --formatter=csv system version get", "get esxi version")
+	var versions []esxcliVersion
+	if err == nil && csvutil.Unmarshal([]byte(stdout), &versions) == nil && len(versions) == 1 {
+		info.HasEsxcliFormatter = true
+		info.Version = versions[0].Version
+		info.Build = strings.TrimPrefix(versions[0].Build, "Releasebuild-")
+	}
+
+	stdout, err = esxi.ExecuteWithRetry(ctx, "vim-cmd solo/querycfgoptdesc", "get virtual hardware versions")
+	if err == nil {
+		info.VirtualHWVersions, info.DefaultVirtualHWVer = parseConfigOptionDescriptors(stdout)
+	}
+
+	if info.DefaultVirtualHWVer > 0 {
+		command := shellf("vim-cmd solo/querycfgopt %s", hardwareVersion(info.DefaultVirtualHWVer))
+		stdout, err = esxi.ExecuteWithRetry(ctx, command, "get guest os ids")
+		if err == nil {
+			info.GuestOSs = parseGuestOSDescriptors(stdout)
+		}
+	}
+
+	logging.V(logLevel).Infof("detectHostInfo: ESXi %s build %s, virtual hardware versions %v, %d guest os ids, ovftool %t, esxcli formatter %t",
+		info.Version, info.Build, info.VirtualHWVersions, len(info.GuestOSs), info.HasOvftool, info.HasEsxcliFormatter)
+	return info
+}
+
+func hasOvftool() bool {
+	_, err := exec.LookPath("ovftool")
+	return err == nil
+}
+
+// parseConfigOptionDescriptors returns the virtual hardware versions the
+// virtual machines can be created with and the default one, the highest one
+// when the host has no default.
+func parseConfigOptionDescriptors(stdout string) ([]int, int) {
+	var versions []int
+	defaultVersion, current, create := 0, 0, false
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		if matches := configOptionKeyPattern.FindStringSubmatch(scanner.Text()); matches != nil {
+			current, _ = strconv.Atoi(matches[1])
+			create = false
+			continue
+		}
+		matches := configOptionFlagPattern.FindStringSubmatch(scanner.Text())
+		if matches == nil || matches[2] != trueValue || current == 0 {
+			continue
+		}
+		if matches[1] == "createSupported" && !create {
+			create = true
+			versions = append(versions, current)
+		} else if matches[1] == "defaultConfigOption" {
+			defaultVersion = current
+		}
+	}
+
+	sort.Ints(versions)
+	if defaultVersion == 0 && len(versions) > 0 {
+		defaultVersion = versions[len(versions)-1]
+	}
+	return versions, defaultVersion
+}
+
+// parseGuestOSDescriptors returns the guest ids of the config option of a virtual hardware version.
+func parseGuestOSDescriptors(stdout string) []string {
+	var ids []string
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		if matches := guestOSDescriptorIdPattern.FindStringSubmatch(scanner.Text()); matches != nil {
+			ids = append(ids, matches[1])
+		}
+	}
+	return ids
+}
+
+// defaultVirtualHWVer returns the virtual hardware version of the virtual
+// machines which don't set one: vmDefaultVirtualHWVer when the host supports
+// it, the default one of the host otherwise.
+func (info *HostInfo) defaultVirtualHWVer() int {
+	if info == nil || len(info.VirtualHWVersions) == 0 || Contains(info.VirtualHWVersions, vmDefaultVirtualHWVer) {
+		return vmDefaultVirtualHWVer
+	}
+	return info.DefaultVirtualHWVer
+}
+
+// release returns the name of the ESXi release in the error messages.
+func (info *HostInfo) release() string {
+	if len(info.Version) == 0 {
+		return "the ESXi host"
+	}
+	return "ESXi " + info.Version
+}
+
+// checkVirtualMachine returns an error for the settings of the virtual machine
+// not supported by the host, or by its virtual hardware version.
+func (info *HostInfo) checkVirtualMachine(vm VirtualMachine) error {
+	for _, ni := range vm.NetworkInterfaces {
+		if version, ok := nicTypeVirtualHWVer[ni.NicType]; ok && vm.VirtualHWVer < version {
+			return fmt.Errorf("nicType '%s' requires virtualHWVer %d or later, the virtual machine has %d",
+				ni.NicType, version, vm.VirtualHWVer)
+		}
+	}
+	if vm.EfiSecureBoot {
+		if vm.BootFirmware != vmFirmwareEfi {
+			return fmt.Errorf("efiSecureBoot requires bootFirmware '%s'", vmFirmwareEfi)
+		}
+		if vm.VirtualHWVer < vmSecureBootVirtualHWVer {
+			return fmt.Errorf("efiSecureBoot requires virtualHWVer %d or later, the virtual machine has %d",
+				vmSecureBootVirtualHWVer, vm.VirtualHWVer)
+		}
+	}
+	if info == nil {
+		return nil
+	}
+
+	if versions := info.VirtualHWVersions; len(versions) > 0 && !Contains(versions, vm.VirtualHWVer) {
+		return fmt.Errorf("virtualHWVer %d is not supported by %s, the supported versions are %d to %d",
+			vm.VirtualHWVer, info.release(), versions[0], versions[len(versions)-1])
+	}
+	if len(vm.Os) > 0 && !info.supportsGuestOS(vm.Os) {
+		return fmt.Errorf("os '%s' is not supported by %s, its guest id %s is not one of the guest ids of the host",
+			vm.Os, info.release(), guestId(strings.ToLower(vm.Os)))
+	}
+	return nil
+}
+
+// supportsGuestOS returns true when the guest id of the vmx guestOS is one of
+// the host, or when the guest ids of the host are unknown.
+func (info *HostInfo) supportsGuestOS(os string) bool {
+	if len(info.GuestOSs) == 0 {
+		return true
+	}
+	id := guestId(strings.ToLower(os))
+	for _, guest := range info.GuestOSs {
+		if strings.EqualFold(guest, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectHostInfo(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	info := esxi.detectHostInfo(ctx, fakeVersion)
+	require.Equal(t, "7.0.3", info.Version)
+	require.Equal(t, "21930508", info.Build)
+	require.True(t, info.HasEsxcliFormatter)
+	require.Equal(t, fakeVirtualHWVer, info.DefaultVirtualHWVer)
+	require.Len(t, info.VirtualHWVersions, fakeVirtualHWVer-fakeMinVirtualHWVer+1)
+	require.Equal(t, fakeMinVirtualHWVer, info.VirtualHWVersions[0])
+	require.Equal(t, fakeGuestIds, info.GuestOSs)
+
+	// The older esxcli without --formatter still get the version of vmware --version.
+	fake.Handle(`^esxcli --formatter`, func([]string) (string, error) {
+		return "Error: Invalid option --formatter", &FakeExitError{Status: fakeStatusFailure}
+	})
+	info = esxi.detectHostInfo(ctx, "VMware ESXi 6.0.0 build-3620759")
+	require.False(t, info.HasEsxcliFormatter)
+	require.Equal(t, "6.0.0", info.Version)
+	require.Equal(t, "3620759", info.Build)
+}
+
+func TestParseConfigOptionDescriptors(t *testing.T) {
+	versions, defaultVersion := parseConfigOptionDescriptors(fakeConfigOptionDescriptors())
+	require.Equal(t, fakeMinVirtualHWVer, versions[0])
+	require.Equal(t, fakeVirtualHWVer, versions[len(versions)-1])
+	require.Equal(t, fakeVirtualHWVer, defaultVersion)
+
+	// Without a default, the highest version is the default one.
+	versions, defaultVersion = parseConfigOptionDescriptors(`(vim.vm.ConfigOptionDescriptor) [
+   (vim.vm.ConfigOptionDescriptor) {
+      key = "vmx-11",
+      createSupported = true,
+      defaultConfigOption = false,
+   },
+   (vim.vm.ConfigOptionDescriptor) {
+      key = "vmx-10",
+      createSupported = true,
+      defaultConfigOption = false,
+   }
+]`)
+	require.Equal(t, []int{10, 11}, versions)
+	require.Equal(t, 11, defaultVersion)
+}
+
+func TestDefaultVirtualHWVer(t *testing.T) {
+	var info *HostInfo
+	require.Equal(t, vmDefaultVirtualHWVer, info.defaultVirtualHWVer())
+
+	info = &HostInfo{VirtualHWVersions: []int{11, 13, 19}, DefaultVirtualHWVer: 19}
+	require.Equal(t, vmDefaultVirtualHWVer, info.defaultVirtualHWVer())
+
+	info = &HostInfo{VirtualHWVersions: []int{4, 7, 8, 9, 10, 11}, DefaultVirtualHWVer: 11}
+	require.Equal(t, 11, info.defaultVirtualHWVer())
+}
+
+func TestCheckVirtualMachine(t *testing.T) {
+	info := &HostInfo{Version: "6.0.0", VirtualHWVersions: []int{4, 7, 8, 9, 10, 11}, DefaultVirtualHWVer: 11}
+
+	tests := []struct {
+		name string
+		vm   VirtualMachine
+		err  string
+	}{
+		{name: "supported", vm: VirtualMachine{VirtualHWVer: 11, BootFirmware: "bios"}},
+		{
+			name: "unsupported virtual hardware",
+			vm:   VirtualMachine{VirtualHWVer: 13},
+			err:  "virtualHWVer 13 is not supported by ESXi 6.0.0, the supported versions are 4 to 11",
+		},
+		{
+			name: "nic type",
+			vm:   VirtualMachine{VirtualHWVer: 7, NetworkInterfaces: []NetworkInterface{{VirtualNetwork: "VM Network", NicType: "e1000e"}}},
+			err:  "nicType 'e1000e' requires virtualHWVer 8 or later",
+		},
+		{
+			name: "secure boot without efi",
+			vm:   VirtualMachine{VirtualHWVer: 11, BootFirmware: "bios", EfiSecureBoot: true},
+			err:  "efiSecureBoot requires bootFirmware 'efi'",
+		},
+		{
+			name: "secure boot virtual hardware",
+			vm:   VirtualMachine{VirtualHWVer: 11, BootFirmware: "efi", EfiSecureBoot: true},
+			err:  "efiSecureBoot requires virtualHWVer 13 or later",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := info.checkVirtualMachine(test.vm)
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+
+	info = &HostInfo{Version: "7.0.3", VirtualHWVersions: []int{19}, DefaultVirtualHWVer: 19, GuestOSs: fakeGuestIds}
+	require.NoError(t, info.checkVirtualMachine(VirtualMachine{VirtualHWVer: 19, Os: "centos7-64"}))
+	require.NoError(t, info.checkVirtualMachine(VirtualMachine{VirtualHWVer: 19, Os: "windows2019srv-64"}))
+	require.NoError(t, info.checkVirtualMachine(VirtualMachine{VirtualHWVer: 19, Os: "otherLinux-64"}))
+	require.ErrorContains(t, info.checkVirtualMachine(VirtualMachine{VirtualHWVer: 19, Os: "darwin-64"}),
+		"os 'darwin-64' is not supported by ESXi 7.0.3, its guest id darwin64Guest is not one of the guest ids of the host")
+
+	// Without the host info, only the virtual machine settings are checked.
+	info = nil
+	require.NoError(t, info.checkVirtualMachine(VirtualMachine{VirtualHWVer: 21}))
+}
+
+func TestVirtualMachineOnOlderHost(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	esxi.Info = &HostInfo{Version: "6.0.0", VirtualHWVersions: []int{4, 7, 8, 9, 10, 11}, DefaultVirtualHWVer: 11, HasOvftool: true}
+	ctx := context.Background()
+
+	inputs := getBaseVMInputs()
+	inputs["diskStore"] = resource.NewStringProperty("datastore1")
+	inputs["power"] = resource.NewStringProperty("off")
+	inputs["virtualHWVer"] = resource.NewNumberProperty(13)
+	_, _, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "virtualHWVer 13 is not supported by ESXi 6.0.0")
+
+	// Without a virtual hardware version, the host default is used.
+	delete(inputs, "virtualHWVer")
+	_, _, err = VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	vmx, ok := fake.ReadFile("/vmfs/volumes/datastore1/vm-test-9967a16/vm-test-9967a16.vmx")
+	require.True(t, ok)
+	require.Contains(t, vmx, `virtualHW.version = "11"`)
+}
+
+func TestVirtualMachineSecureBoot(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+	esxi.Info = esxi.detectHostInfo(ctx, fakeVersion)
+	vmxPath := "/vmfs/volumes/datastore1/vm-test-9967a16/vm-test-9967a16.vmx"
+
+	inputs := getBaseVMInputs()
+	inputs["diskStore"] = resource.NewStringProperty("datastore1")
+	inputs["power"] = resource.NewStringProperty("off")
+	inputs["bootFirmware"] = resource.NewStringProperty("efi")
+	inputs["efiSecureBoot"] = resource.NewBoolProperty(true)
+	id, result, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.True(t, result["efiSecureBoot"].BoolValue())
+	vmx, ok := fake.ReadFile(vmxPath)
+	require.True(t, ok)
+	require.Contains(t, vmx, `uefi.secureBoot.enabled = "TRUE"`)
+
+	inputs["efiSecureBoot"] = resource.NewBoolProperty(false)
+	_, result, err = VirtualMachineUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	require.False(t, result["efiSecureBoot"].BoolValue())
+	vmx, _ = fake.ReadFile(vmxPath)
+	require.NotContains(t, vmx, `uefi.secureBoot.enabled = "TRUE"`)
+}
+
+func TestPortGroupSecurityPolicyWithoutFormatter(t *testing.T) {
+	esxi, _ := newFakeHost(t)
+	esxi.Info = &HostInfo{Version: "6.0.0"}
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":            resource.NewStringProperty("pg-test"),
+		"vSwitch":         resource.NewStringProperty("vSwitch0"),
+		"promiscuousMode": resource.NewStringProperty("true"),
+	}
+	_, result, err := PortGroupCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "true", result["promiscuousMode"].StringValue())
+	require.Equal(t, "false", result["macChanges"].StringValue())
+}
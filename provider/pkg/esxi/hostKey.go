@@ -0,0 +1,228 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const sha256FingerprintPrefix = "SHA256:"
+
+// HostKeyMismatchError is returned when the host key presented by the ESXi host
+// doesn't match the configured fingerprint or the known hosts file entry.
+type HostKeyMismatchError struct {
+	Host        string
+	Fingerprint string
+	Expected    string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("host key verification failed for %s: the presented key %s does not match %s, "+
+		"the host was reinstalled or the connection is being intercepted", e.Host, e.Fingerprint, e.Expected)
+}
+
+// errNoHostKeyVerification is returned when the host key of a connection
+// isn't verified, the insecure mode accepting any key is an explicit opt-in.
+var errNoHostKeyVerification = errors.New("no host key verification configured, set hostKeyFingerprint, " +
+	"knownHostsFile or trustOnFirstUse, or insecureSkipHostKeyVerification to accept any host key")
+
+// getHostKeyCallback builds the host key verification for the connection.
+// The fingerprint takes precedence over the known hosts file, then over the
+// keys recorded by trust on first use. Without any of them the connection
+// fails, unless the verification is explicitly skipped.
+func (c *ConnectionInfo) getHostKeyCallback() (ssh.HostKeyCallback, error) {
+	if len(c.HostKeyFingerprint) > 0 {
+		return fingerprintCallback(c.HostKeyFingerprint, "the configured fingerprint"), nil
+	}
+
+	if len(c.KnownHostsFile) > 0 {
+		return newKnownHostsCallback(c.KnownHostsFile, c.TrustOnFirstUse)
+	}
+
+	if c.TrustOnFirstUse {
+		address := c.getSSHConnection()
+		fingerprint, ok := c.TrustedHostKeys[address]
+		if !ok {
+			return nil, fmt.Errorf("the host key of %s isn't recorded in trustedHostKeys, "+
+				"trustOnFirstUse records it when the provider config is checked", address)
+		}
+		return fingerprintCallback(fingerprint, "the fingerprint recorded on first use"), nil
+	}
+
+	if c.InsecureSkipHostKeyVerification {
+		logging.Warningf("host key verification is disabled for %s by insecureSkipHostKeyVerification, "+
+			"the connection can be intercepted", c.Host)
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // explicitly opted into
+	}
+
+	return nil, fmt.Errorf("%s: %w", c.Host, errNoHostKeyVerification)
+}
+
+// recordsOnFirstUse returns true when the host key of the connection is
+// verified against the key recorded by trust on first use.
+func (c *ConnectionInfo) recordsOnFirstUse() bool {
+	return c.TrustOnFirstUse && len(c.HostKeyFingerprint) == 0 && len(c.KnownHostsFile) == 0
+}
+
+// fingerprintCallback verifies the host key against the SHA256 fingerprint.
+func fingerprintCallback(fingerprint string, source string) ssh.HostKeyCallback {
+	expected := fingerprint
+	if !strings.HasPrefix(expected, sha256FingerprintPrefix) {
+		expected = sha256FingerprintPrefix + expected
+	}
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		if presented := ssh.FingerprintSHA256(key); presented != expected {
+			return &HostKeyMismatchError{Host: hostname, Fingerprint: presented, Expected: fmt.Sprintf("%s %s", source, expected)}
+		}
+		return nil
+	}
+}
+
+// RecordHostKeys records in the TrustedHostKeys of the connection the
+// fingerprints of the host keys of the host and of its bastion, which trust on
+// first use verifies and hasn't recorded yet. The keys are read without
+// authenticating, the bastion being connected to once its key is recorded.
+func RecordHostKeys(ctx context.Context, connection *ConnectionInfo) error {
+	if connection.TrustedHostKeys == nil {
+		connection.TrustedHostKeys = map[string]string{}
+	}
+	record := func(address string, dial func() (net.Conn, error)) error {
+		if _, ok := connection.TrustedHostKeys[address]; ok {
+			return nil
+		}
+		conn, err := dial()
+		if err != nil {
+			return fmt.Errorf("unable to record the host key of %s: %w", address, err)
+		}
+		fingerprint, err := scanHostKey(conn, address)
+		if err != nil {
+			return err
+		}
+		logging.V(logLevel).Infof("RecordHostKeys: trusting host key %s of %s on first use", fingerprint, address)
+		connection.TrustedHostKeys[address] = fingerprint
+		return nil
+	}
+	dialer := &net.Dialer{Timeout: sshDialTimeout}
+
+	var tunnel *bastion
+	if connection.hasBastion() {
+		bastionConnection := connection.bastionConnection()
+		if bastionConnection.recordsOnFirstUse() {
+			address := bastionConnection.getSSHConnection()
+			if err := record(address, func() (net.Conn, error) { return dialer.DialContext(ctx, "tcp", address) }); err != nil {
+				return err
+			}
+		}
+		var err error
+		if tunnel, err = newBastion(connection); err != nil {
+			return err
+		}
+		defer tunnel.Close()
+	}
+
+	// The api transport doesn't connect to the ssh port of the host.
+	if connection.Transport == TransportAPI || !connection.recordsOnFirstUse() {
+		return nil
+	}
+	address := connection.getSSHConnection()
+	return record(address, func() (net.Conn, error) {
+		if tunnel != nil {
+			return tunnel.DialContext(ctx, "tcp", address)
+		}
+		return dialer.DialContext(ctx, "tcp", address)
+	})
+}
+
+// errHostKeyScanned ends the handshake of scanHostKey once the key is read.
+var errHostKeyScanned = errors.New("host key scanned")
+
+// scanHostKey returns the fingerprint of the host key presented on the
+// connection, the handshake being stopped before the authentication.
+func scanHostKey(conn net.Conn, address string) (string, error) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(sshDialTimeout))
+
+	var fingerprint string
+	config := &ssh.ClientConfig{
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintSHA256(key)
+			return errHostKeyScanned
+		},
+	}
+	if _, _, _, err := ssh.NewClientConn(conn, address, config); len(fingerprint) == 0 {
+		return "", fmt.Errorf("unable to read the host key of %s: %w", address, err)
+	}
+	return fingerprint, nil
+}
+
+// newKnownHostsCallback verifies host keys against an OpenSSH known_hosts file.
+// With trustOnFirstUse the key of an unknown host is appended to the file, a
+// changed key is always rejected.
+func newKnownHostsCallback(path string, trustOnFirstUse bool) (ssh.HostKeyCallback, error) {
+	if trustOnFirstUse {
+		const dirPermissions, filePermissions = 0o700, 0o600
+		if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+			return nil, fmt.Errorf("unable to create known hosts directory: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, filePermissions)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create known hosts file '%s': %w", path, err)
+		}
+		CloseFile(f)
+	}
+
+	var mutex sync.Mutex
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		// The file is re-read on every handshake so keys recorded by trust on first use are honoured.
+		callback, err := knownhosts.New(path)
+		if err != nil {
+			return fmt.Errorf("unable to read known hosts file '%s': %w", path, err)
+		}
+
+		err = callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) {
+			return err
+		}
+
+		if len(keyErr.Want) > 0 {
+			return &HostKeyMismatchError{
+				Host:        hostname,
+				Fingerprint: ssh.FingerprintSHA256(key),
+				Expected:    fmt.Sprintf("the key recorded at %s:%d", keyErr.Want[0].Filename, keyErr.Want[0].Line),
+			}
+		}
+
+		if !trustOnFirstUse {
+			return fmt.Errorf("host key verification failed for %s: no entry in known hosts file '%s' (key %s)",
+				hostname, path, ssh.FingerprintSHA256(key))
+		}
+
+		const filePermissions = 0o600
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, filePermissions)
+		if err != nil {
+			return fmt.Errorf("unable to record host key in '%s': %w", path, err)
+		}
+		defer CloseFile(f)
+		if _, err = fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)); err != nil {
+			return fmt.Errorf("unable to record host key in '%s': %w", path, err)
+		}
+		logging.V(logLevel).Infof("trusting host key %s for %s on first use, recorded in %s",
+			ssh.FingerprintSHA256(key), hostname, path)
+
+		return nil
+	}, nil
+}
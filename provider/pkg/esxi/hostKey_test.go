@@ -0,0 +1,135 @@
+package esxi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestHostKeyCallback(t *testing.T) {
+	key := newTestPublicKey(t)
+	otherKey := newTestPublicKey(t)
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.1.10"), Port: 22}
+	hostname := "esxi.local:22"
+
+	t.Run("Fingerprint", func(t *testing.T) {
+		connection := ConnectionInfo{HostKeyFingerprint: ssh.FingerprintSHA256(key)}
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+
+		require.NoError(t, callback(hostname, remote, key))
+
+		var mismatchErr *HostKeyMismatchError
+		require.True(t, errors.As(callback(hostname, remote, otherKey), &mismatchErr))
+	})
+
+	t.Run("Fingerprint without prefix", func(t *testing.T) {
+		fingerprint := ssh.FingerprintSHA256(key)[len(sha256FingerprintPrefix):]
+		connection := ConnectionInfo{HostKeyFingerprint: fingerprint}
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+
+		require.NoError(t, callback(hostname, remote, key))
+	})
+
+	t.Run("Unknown host without trust on first use", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+		require.NoError(t, os.WriteFile(knownHosts, nil, 0o600))
+
+		connection := ConnectionInfo{KnownHostsFile: knownHosts}
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+
+		require.ErrorContains(t, callback(hostname, remote, key), "no entry in known hosts file")
+	})
+
+	t.Run("Trust on first use", func(t *testing.T) {
+		knownHosts := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+
+		connection := ConnectionInfo{KnownHostsFile: knownHosts, TrustOnFirstUse: true}
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+
+		// First connection records the key, the next ones verify against it.
+		require.NoError(t, callback(hostname, remote, key))
+		require.NoError(t, callback(hostname, remote, key))
+
+		var mismatchErr *HostKeyMismatchError
+		require.True(t, errors.As(callback(hostname, remote, otherKey), &mismatchErr))
+		require.Contains(t, mismatchErr.Error(), knownHosts)
+	})
+
+	t.Run("Trust on first use without known hosts file", func(t *testing.T) {
+		connection := ConnectionInfo{Host: "esxi.local", SSHPort: "22", TrustOnFirstUse: true}
+		_, err := connection.getHostKeyCallback()
+		require.ErrorContains(t, err, "the host key of esxi.local:22 isn't recorded in trustedHostKeys")
+
+		connection.TrustedHostKeys = map[string]string{"esxi.local:22": ssh.FingerprintSHA256(key)}
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+		require.NoError(t, callback(hostname, remote, key))
+
+		var mismatchErr *HostKeyMismatchError
+		require.True(t, errors.As(callback(hostname, remote, otherKey), &mismatchErr))
+		require.Contains(t, mismatchErr.Error(), "the fingerprint recorded on first use")
+	})
+
+	t.Run("No verification", func(t *testing.T) {
+		connection := ConnectionInfo{Host: "esxi.local"}
+		_, err := connection.getHostKeyCallback()
+		require.ErrorIs(t, err, errNoHostKeyVerification)
+
+		connection.InsecureSkipHostKeyVerification = true
+		callback, err := connection.getHostKeyCallback()
+		require.NoError(t, err)
+		require.NoError(t, callback(hostname, remote, key))
+		require.NoError(t, callback(hostname, remote, otherKey))
+	})
+}
+
+func TestRecordHostKeys(t *testing.T) {
+	server := newTestSSHServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	connection := ConnectionInfo{Host: host, SSHPort: port, UserName: "root", Password: "secret", TrustOnFirstUse: true}
+	require.NoError(t, RecordHostKeys(ctx, &connection))
+	require.Equal(t, map[string]string{server.listener.Addr().String(): server.fingerprint}, connection.TrustedHostKeys)
+	require.EqualValues(t, 1, server.dials.Load())
+
+	// The recorded keys aren't read again, and verify the connections.
+	require.NoError(t, RecordHostKeys(ctx, &connection))
+	require.EqualValues(t, 1, server.dials.Load())
+	esxi, err := NewHost(ctx, connection)
+	require.NoError(t, err)
+	esxi.Close()
+
+	// A changed key is rejected.
+	connection.TrustedHostKeys[server.listener.Addr().String()] = ssh.FingerprintSHA256(newTestPublicKey(t))
+	_, err = NewHost(ctx, connection)
+	var mismatchErr *HostKeyMismatchError
+	require.True(t, errors.As(err, &mismatchErr), "%v", err)
+
+	// The keys of the hosts verified otherwise aren't recorded.
+	connection = ConnectionInfo{Host: host, SSHPort: port, HostKeyFingerprint: server.fingerprint, TrustOnFirstUse: true}
+	require.NoError(t, RecordHostKeys(ctx, &connection))
+	require.Empty(t, connection.TrustedHostKeys)
+}
+
+func newTestPublicKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(public)
+	require.NoError(t, err)
+	return key
+}
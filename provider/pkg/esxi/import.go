@@ -0,0 +1,51 @@
+package esxi
+
+import (
+	"strconv"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// importedInputs returns the outputs read on import which are inputs of the
+// resource, without the empty ones, the other outputs are computed by the host
+// or are settings of the provider.
+func importedInputs(outputs resource.PropertyMap, keys ...string) resource.PropertyMap {
+	inputs := resource.PropertyMap{}
+	for _, key := range keys {
+		value, has := outputs[resource.PropertyKey(key)]
+		if !has || value.IsNull() || (value.IsString() && value.StringValue() == "") {
+			continue
+		}
+		inputs[resource.PropertyKey(key)] = value
+	}
+	return inputs
+}
+
+// importedObjects returns the objects of the array with only the keys which
+// are inputs.
+func importedObjects(value resource.PropertyValue, keys ...string) resource.PropertyValue {
+	if !value.IsArray() {
+		return value
+	}
+	objects := make([]resource.PropertyValue, 0, len(value.ArrayValue()))
+	for _, element := range value.ArrayValue() {
+		if element.IsObject() {
+			element = resource.NewObjectProperty(importedInputs(element.ObjectValue(), keys...))
+		}
+		objects = append(objects, element)
+	}
+	return resource.NewArrayProperty(objects)
+}
+
+// importedBools converts the booleans read as strings to booleans.
+func importedBools(inputs resource.PropertyMap, keys ...string) {
+	for _, key := range keys {
+		value, has := inputs[resource.PropertyKey(key)]
+		if !has || !value.IsString() {
+			continue
+		}
+		if parsed, err := strconv.ParseBool(value.StringValue()); err == nil {
+			inputs[resource.PropertyKey(key)] = resource.NewBoolProperty(parsed)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package esxi
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxConcurrentSessions stays below the default MaxSessions of the ESXi sshd,
+// and keeps hostd responsive while Pulumi runs many resource operations in parallel.
+const DefaultMaxConcurrentSessions = 8
+
+// semaphore bounds the number of concurrent holders, the waiters give up when
+// their context is done. A nil semaphore doesn't bound anything.
+type semaphore chan struct{}
+
+func newSemaphore(size int) semaphore {
+	return make(semaphore, size)
+}
+
+// acquire waits for a slot and returns the function releasing it.
+func (s semaphore) acquire(ctx context.Context) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// keyedLocks holds a lock per key, e.g. per virtual machine id, the locks
+// nobody holds or waits for are dropped.
+type keyedLocks struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	semaphore semaphore
+	users     int
+}
+
+// lock waits for the lock of the key and returns the function unlocking it.
+func (k *keyedLocks) lock(ctx context.Context, key string) (func(), error) {
+	k.mutex.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*keyedLock{}
+	}
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedLock{semaphore: newSemaphore(1)}
+		k.locks[key] = entry
+	}
+	entry.users++
+	k.mutex.Unlock()
+
+	release, err := entry.semaphore.acquire(ctx)
+	if err != nil {
+		k.done(key, entry)
+		return nil, err
+	}
+	return func() {
+		release()
+		k.done(key, entry)
+	}, nil
+}
+
+func (k *keyedLocks) done(key string, entry *keyedLock) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if entry.users--; entry.users == 0 {
+		delete(k.locks, key)
+	}
+}
+
+// lockVirtualMachine serializes the edits of the vmx file of the virtual machine,
+// read, modified, written back and reloaded as a whole.
+func (esxi *Host) lockVirtualMachine(ctx context.Context, id string) (func(), error) {
+	return esxi.vmLocks.lock(ctx, id)
+}
+
+// lockTopology serializes the changes of the resource pools, the virtual switches
+// and the port groups of the host, whose configuration files hostd rewrites as a whole.
+func (esxi *Host) lockTopology(ctx context.Context) (func(), error) {
+	return esxi.topology.acquire(ctx)
+}
@@ -0,0 +1,174 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyExecutor measures the remote operations running at once, each
+// of them taking a little while so that the concurrent ones overlap.
+type concurrencyExecutor struct {
+	Executor
+	running atomic.Int32
+	max     atomic.Int32
+}
+
+func (executor *concurrencyExecutor) track() func() {
+	running := executor.running.Add(1)
+	for {
+		peak := executor.max.Load()
+		if running <= peak || executor.max.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return func() { executor.running.Add(-1) }
+}
+
+func (executor *concurrencyExecutor) Execute(ctx context.Context, command string, shortCmdDesc string) (string, error) {
+	defer executor.track()()
+	return executor.Executor.Execute(ctx, command, shortCmdDesc)
+}
+
+func (executor *concurrencyExecutor) WriteFile(ctx context.Context, content string, path string, shortCmdDesc string) (string, error) {
+	defer executor.track()()
+	return executor.Executor.WriteFile(ctx, content, path, shortCmdDesc)
+}
+
+// parallel runs fn n times at once and returns the first error.
+func parallel(n int, fn func(i int) error) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- fn(i)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSemaphore(t *testing.T) {
+	s := newSemaphore(2)
+	ctx := context.Background()
+
+	first, err := s.acquire(ctx)
+	require.NoError(t, err)
+	_, err = s.acquire(ctx)
+	require.NoError(t, err)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = s.acquire(timeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	first()
+	_, err = s.acquire(ctx)
+	require.NoError(t, err)
+
+	var unbounded semaphore
+	_, err = unbounded.acquire(ctx)
+	require.NoError(t, err)
+}
+
+func TestKeyedLocks(t *testing.T) {
+	var locks keyedLocks
+	ctx := context.Background()
+
+	unlock1, err := locks.lock(ctx, "1")
+	require.NoError(t, err)
+	unlock2, err := locks.lock(ctx, "2")
+	require.NoError(t, err)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = locks.lock(timeout, "1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock1()
+	unlock1, err = locks.lock(ctx, "1")
+	require.NoError(t, err)
+	unlock1()
+	unlock2()
+	require.Empty(t, locks.locks)
+}
+
+func TestMaxConcurrentSessions(t *testing.T) {
+	executor := &concurrencyExecutor{Executor: NewFakeExecutor()}
+	esxi := NewHostWithExecutor(ConnectionInfo{Host: "fake", MaxConcurrentSessions: 2}, executor)
+
+	err := parallel(8, func(int) error {
+		_, err := esxi.Execute(context.Background(), "vmware --version", "version")
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), executor.max.Load())
+
+	require.Equal(t, DefaultMaxConcurrentSessions, (&ConnectionInfo{}).maxConcurrentSessions())
+}
+
+func TestTopologyChangesAreSerialized(t *testing.T) {
+	executor := &concurrencyExecutor{Executor: NewFakeExecutor()}
+	esxi := NewHostWithExecutor(ConnectionInfo{Host: "fake"}, executor)
+	ctx := context.Background()
+
+	err := parallel(4, func(i int) error {
+		_, _, err := VirtualSwitchCreate(ctx, resource.PropertyMap{"name": resource.NewStringProperty(fmt.Sprintf("vSwitch%d", i+1))}, esxi)
+		return err
+	})
+	require.NoError(t, err)
+	err = parallel(4, func(i int) error {
+		_, _, err := ResourcePoolCreate(ctx, resource.PropertyMap{"name": resource.NewStringProperty(fmt.Sprintf("pool%d", i+1))}, esxi)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), executor.max.Load())
+}
+
+func TestVmxEditsAreSerialized(t *testing.T) {
+	fake := NewFakeExecutor()
+	executor := &concurrencyExecutor{Executor: fake}
+	esxi := NewHostWithExecutor(ConnectionInfo{Host: "fake"}, executor)
+	esxi.sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+	ctx := context.Background()
+
+	inputs := getBaseVMInputs()
+	inputs["diskStore"] = resource.NewStringProperty("datastore1")
+	inputs["power"] = resource.NewStringProperty("off")
+	id, _, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	vm := parseVirtualMachine(id, inputs, esxi.Connection)
+
+	executor.max.Store(0)
+	err = parallel(4, func(i int) error {
+		edit := vm
+		edit.Info = []KeyValuePair{{Key: fmt.Sprintf("edit%d", i), Value: "done"}}
+		return esxi.updateVmxContents(ctx, false, edit)
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), executor.max.Load())
+
+	// Every edit read the vmx written by the previous one.
+	vmx, ok := fake.ReadFile("/vmfs/volumes/datastore1/vm-test-9967a16/vm-test-9967a16.vmx")
+	require.True(t, ok)
+	for i := 0; i < 4; i++ {
+		require.Contains(t, vmx, fmt.Sprintf(`guestinfo.edit%d = "done"`, i))
+	}
+}
@@ -0,0 +1,40 @@
+package esxi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotFoundError is returned by the reads of the resources missing on the host,
+// e.g. deleted out of band in the ESXi UI.
+type NotFoundError struct {
+	// Kind is the kind of the resource, e.g. virtual machine.
+	Kind string
+	Id   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s '%s' not found", e.Kind, e.Id)
+}
+
+// IsNotFound returns true when the error reports a resource missing on the host.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// notFoundOrError returns the NotFoundError of the resource when the failed
+// command reports a missing object, else the error.
+func notFoundOrError(kind, id, output string, err error) error {
+	if ClassifyError(output, err) == ErrorNotFound {
+		return &NotFoundError{Kind: kind, Id: id}
+	}
+	return err
+}
+
+// exitedWith returns true when the command failed with the exit status, e.g.
+// grep and test exit with 1 when there is no match.
+func exitedWith(err error, status int) bool {
+	var exitErr interface{ ExitStatus() int }
+	return errors.As(err, &exitErr) && exitErr.ExitStatus() == status
+}
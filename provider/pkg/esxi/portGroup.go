@@ -1,6 +1,7 @@
 package esxi
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -8,59 +9,127 @@ import (
 
 	"github.com/jszwec/csvutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
-func PortGroupCreate(inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+const portGroupToken = "esxi-native:index:PortGroup"
+
+func init() {
+	registerResource(portGroupToken, portGroupResource{resourceRules{
+		diff: DiffRules{
+			Replaces: []string{"name", "vSwitch"},
+			Names:    []string{"name"},
+		},
+	}})
+}
+
+// portGroupResource implements the PortGroup resource with the functions
+// below.
+type portGroupResource struct {
+	resourceRules
+}
+
+func (portGroupResource) Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure {
+	return schema.ValidatePortGroup(portGroupToken, inputs)
+}
+
+func (portGroupResource) Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return PortGroupCreate(ctx, inputs, esxi)
+}
+
+func (portGroupResource) Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return PortGroupRead(ctx, id, inputs, esxi)
+}
+
+func (portGroupResource) Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	return PortGroupImport(ctx, id, esxi)
+}
+
+func (portGroupResource) Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	return PortGroupUpdate(ctx, update, esxi)
+}
+
+func (portGroupResource) Delete(ctx context.Context, id string, esxi *Host) error {
+	return PortGroupDelete(ctx, id, esxi)
+}
+
+func PortGroupCreate(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	var pg PortGroup
 	if parsed, err := parsePortGroup("", inputs); err == nil {
 		pg = parsed
 	} else {
 		return "", nil, err
 	}
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.createPortGroup(ctx, pg)
+	}
 
-	command := fmt.Sprintf("esxcli network vswitch standard portgroup add -v \"%s\" -p \"%s\"",
+	command := shellf("esxcli network vswitch standard portgroup add -v %s -p %s",
 		pg.VSwitch, pg.Name)
 
-	stdout, err := esxi.Execute(command, "create port group")
+	stdout, err := esxi.Execute(ctx, command, "create port group")
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create port group: %s err:%w", stdout, err)
 	}
 
-	err = esxi.updatePortGroup(pg)
+	err = esxi.updatePortGroup(ctx, pg, newResourceUpdate("", inputs))
 	if err != nil {
 		return "", nil, err
 	}
 
-	return esxi.readPortGroup(pg)
+	return esxi.readPortGroup(ctx, pg)
 }
 
-func PortGroupUpdate(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func PortGroupUpdate(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
 	var pg PortGroup
-	if parsed, err := parsePortGroup(id, inputs); err == nil {
+	if parsed, err := parsePortGroup(update.Id, update.NewInputs); err == nil {
 		pg = parsed
 	} else {
 		return "", nil, err
 	}
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.updatePortGroup(ctx, pg)
+	}
 
-	err := esxi.updatePortGroup(pg)
+	err = esxi.updatePortGroup(ctx, pg, update)
 	if err != nil {
 		return "", nil, err
 	}
 
-	return esxi.readPortGroup(pg)
+	return esxi.readPortGroup(ctx, pg)
 }
 
-func PortGroupDelete(id string, esxi *Host) error {
-	var command string
+func PortGroupDelete(ctx context.Context, id string, esxi *Host) error {
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
+	var command string
 	if name, vSwitch, err := extractId(id); err == nil {
-		command = fmt.Sprintf("esxcli network vswitch standard portgroup remove -v \"%s\" -p \"%s\"",
+		if esxi.api != nil {
+			return esxi.api.deletePortGroup(ctx, name)
+		}
+		command = shellf("esxcli network vswitch standard portgroup remove -v %s -p %s",
 			vSwitch, name)
 	} else {
 		return err
 	}
 
-	stdout, err := esxi.Execute(command, "delete port group")
+	stdout, err := esxi.Execute(ctx, command, "delete port group")
 	if err != nil {
 		return fmt.Errorf("failed to delete port group: %s err:%w", stdout, err)
 	}
@@ -68,7 +137,7 @@ func PortGroupDelete(id string, esxi *Host) error {
 	return nil
 }
 
-func PortGroupRead(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func PortGroupRead(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	var pg PortGroup
 	if parsed, err := parsePortGroup(id, inputs); err == nil {
 		pg = parsed
@@ -76,7 +145,21 @@ func PortGroupRead(id string, inputs resource.PropertyMap, esxi *Host) (string,
 		return "", nil, err
 	}
 
-	return esxi.readPortGroup(pg)
+	if esxi.api != nil {
+		return esxi.api.readPortGroup(ctx, pg)
+	}
+	return esxi.readPortGroup(ctx, pg)
+}
+
+// PortGroupImport reads the port group imported by its vswitch/name id.
+func PortGroupImport(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	id, outputs, err := PortGroupRead(ctx, id, nil, esxi)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	inputs := importedInputs(outputs, "name", "vSwitch", "vlan", "promiscuousMode", "macChanges", "forgedTransmits")
+	importedBools(inputs, "promiscuousMode", "macChanges", "forgedTransmits")
+	return id, inputs, outputs, nil
 }
 
 func extractId(id string) (string, string, error) {
@@ -114,49 +197,41 @@ func parsePortGroup(id string, inputs resource.PropertyMap) (PortGroup, error) {
 		pg.Vlan = 0
 	}
 
-	if property, has := inputs["promiscuousMode"]; has {
-		pg.PromiscuousMode = property.StringValue()
-	} else {
-		pg.PromiscuousMode = ""
-	}
-
-	if property, has := inputs["macChanges"]; has {
-		pg.MacChanges = property.StringValue()
-	} else {
-		pg.MacChanges = ""
-	}
-
-	if property, has := inputs["forgedTransmits"]; has {
-		pg.ForgedTransmits = property.StringValue()
-	} else {
-		pg.ForgedTransmits = ""
-	}
+	pg.PromiscuousMode = parsePolicy(inputs, "promiscuousMode")
+	pg.MacChanges = parsePolicy(inputs, "macChanges")
+	pg.ForgedTransmits = parsePolicy(inputs, "forgedTransmits")
 
 	return pg, nil
 }
 
-func (esxi *Host) updatePortGroup(pg PortGroup) error {
-	command := fmt.Sprintf("esxcli network vswitch standard portgroup set -v \"%d\" -p \"%s\"",
-		pg.Vlan, pg.Name)
+// updatePortGroup sets the vlan and the security policy of the port group, when they changed.
+func (esxi *Host) updatePortGroup(ctx context.Context, pg PortGroup, update ResourceUpdate) error {
+	if update.Changed("vlan") {
+		command := shellf("esxcli network vswitch standard portgroup set -v %d -p %s",
+			pg.Vlan, pg.Name)
 
-	stdout, err := esxi.Execute(command, "port group set vlan")
-	if err != nil {
-		return fmt.Errorf("failed to set port group vlan: %s err:%w", stdout, err)
+		stdout, err := esxi.ExecuteWithRetry(ctx, command, "port group set vlan")
+		if err != nil {
+			return fmt.Errorf("failed to set port group vlan: %s err:%w", stdout, err)
+		}
+	}
+	if !update.Changed("promiscuousMode", "forgedTransmits", "macChanges") {
+		return nil
 	}
 
-	command = fmt.Sprintf("esxcli network vswitch standard portgroup policy security set --use-vswitch --portgroup-name=\"%s\"", pg.Name)
+	command := shellf("esxcli network vswitch standard portgroup policy security set --use-vswitch --portgroup-name=%s", pg.Name)
 	// set the security policies.
 	if len(pg.PromiscuousMode) > 0 {
-		command = fmt.Sprintf("%s --allow-promiscuous=%s", command, pg.PromiscuousMode)
+		command += shellf(" --allow-promiscuous=%s", pg.PromiscuousMode)
 	}
 	if len(pg.ForgedTransmits) > 0 {
-		command = fmt.Sprintf("%s --allow-forged-transmits=%s", command, pg.ForgedTransmits)
+		command += shellf(" --allow-forged-transmits=%s", pg.ForgedTransmits)
 	}
 	if len(pg.MacChanges) > 0 {
-		command = fmt.Sprintf("%s --allow-mac-change=%s", command, pg.MacChanges)
+		command += shellf(" --allow-mac-change=%s", pg.MacChanges)
 	}
 
-	stdout, err = esxi.Execute(command, "port group set security policy")
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "port group set security policy")
 	if err != nil {
 		return fmt.Errorf("failed to set port group security policy: %s err:%w", stdout, err)
 	}
@@ -164,11 +239,29 @@ func (esxi *Host) updatePortGroup(pg PortGroup) error {
 	return nil
 }
 
-func (esxi *Host) readPortGroup(pg PortGroup) (string, resource.PropertyMap, error) {
+// parsePolicy returns the security policy of the port group input, a boolean
+// or its string, empty to inherit the policy of the virtual switch.
+func parsePolicy(inputs resource.PropertyMap, key resource.PropertyKey) string {
+	property, has := inputs[key]
+	switch {
+	case !has:
+		return ""
+	case property.IsBool():
+		return strconv.FormatBool(property.BoolValue())
+	default:
+		return property.StringValue()
+	}
+}
+
+func (esxi *Host) readPortGroup(ctx context.Context, pg PortGroup) (string, resource.PropertyMap, error) {
 	//  get port group info
-	command := fmt.Sprintf("esxcli network vswitch standard portgroup list | grep -m 1 \"^%s  \"", pg.Name)
+	command := shellf("esxcli network vswitch standard portgroup list | grep -m 1 %s", "^"+grepQuote(pg.Name)+"  ")
 
-	stdout, err := esxi.Execute(command, "port group list")
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "port group list")
+	// grep exits with 1 when the port group is not listed.
+	if stdout == "" && exitedWith(err, 1) {
+		return "", nil, &NotFoundError{Kind: "port group", Id: pg.Name}
+	}
 	if stdout == "" {
 		return "", nil, fmt.Errorf("failed to list port group: %s err: %w", stdout, err)
 	}
@@ -189,7 +282,7 @@ func (esxi *Host) readPortGroup(pg PortGroup) (string, resource.PropertyMap, err
 		pg.Vlan = 0
 	}
 
-	policy, err := esxi.readPortGroupSecurityPolicy(pg.Name)
+	policy, err := esxi.readPortGroupSecurityPolicy(ctx, pg.Name)
 	if err != nil {
 		return "", nil, err
 	}
@@ -202,9 +295,13 @@ func (esxi *Host) readPortGroup(pg PortGroup) (string, resource.PropertyMap, err
 	return pg.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func (esxi *Host) readPortGroupSecurityPolicy(name string) (*PortGroupSecurityPolicy, error) {
-	command := fmt.Sprintf("esxcli --formatter=csv network vswitch standard portgroup policy security get -p \"%s\"", name)
-	stdout, err := esxi.Execute(command, "port group security policy")
+func (esxi *Host) readPortGroupSecurityPolicy(ctx context.Context, name string) (*PortGroupSecurityPolicy, error) {
+	if esxi.Info != nil && !esxi.Info.HasEsxcliFormatter {
+		return esxi.readPortGroupSecurityPolicyText(ctx, name)
+	}
+
+	command := shellf("esxcli --formatter=csv network vswitch standard portgroup policy security get -p %s", name)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "port group security policy")
 	if stdout == "" {
 		return nil, fmt.Errorf("failed to get the port group security policy: %s err: %w", stdout, err)
 	}
@@ -217,6 +314,34 @@ func (esxi *Host) readPortGroupSecurityPolicy(name string) (*PortGroupSecurityPo
 	return &policies[0], nil
 }
 
+// readPortGroupSecurityPolicyText reads the security policy from the default
+// output of esxcli, for the hosts whose esxcli has no --formatter option.
+func (esxi *Host) readPortGroupSecurityPolicyText(ctx context.Context, name string) (*PortGroupSecurityPolicy, error) {
+	command := shellf("esxcli network vswitch standard portgroup policy security get -p %s", name)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "port group security policy")
+	if stdout == "" {
+		return nil, fmt.Errorf("failed to get the port group security policy: %s err: %w", stdout, err)
+	}
+
+	policy := &PortGroupSecurityPolicy{}
+	for _, field := range []struct {
+		name  string
+		value *bool
+	}{
+		{"Allow Promiscuous", &policy.AllowPromiscuous},
+		{"Allow MAC Address Change", &policy.AllowMACAddressChange},
+		{"Allow Forged Transmits", &policy.AllowForgedTransmits},
+	} {
+		matches := regexp.MustCompile(field.name + `: (true|false)`).FindStringSubmatch(stdout)
+		if matches == nil {
+			return nil, fmt.Errorf("failed to parse the port group security policy: %s", stdout)
+		}
+		*field.value = matches[1] == trueValue
+	}
+
+	return policy, nil
+}
+
 func (pg *PortGroup) toMap(keepId ...bool) map[string]interface{} {
 	outputs := structToMap(pg)
 	if len(keepId) != 0 && !keepId[0] {
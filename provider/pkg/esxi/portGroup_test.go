@@ -0,0 +1,47 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortGroupLifecycle(t *testing.T) {
+	esxi, _ := newFakeHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":            resource.NewStringProperty("pg-test"),
+		"vSwitch":         resource.NewStringProperty("vSwitch0"),
+		"vlan":            resource.NewNumberProperty(10),
+		"promiscuousMode": resource.NewStringProperty("true"),
+	}
+	id, result, err := PortGroupCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch0/pg-test", id)
+	require.Equal(t, "vSwitch0", result["vSwitch"].StringValue())
+	require.Equal(t, 10.0, result["vlan"].NumberValue())
+	require.Equal(t, "true", result["promiscuousMode"].StringValue())
+	require.Equal(t, "false", result["macChanges"].StringValue())
+
+	_, _, err = PortGroupCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "already exists")
+
+	// Without an override, the security policy is the one of the virtual switch.
+	update := resource.PropertyMap{"vlan": resource.NewNumberProperty(20)}
+	_, result, err = PortGroupUpdate(ctx, newResourceUpdate(id, update), esxi)
+	require.NoError(t, err)
+	require.Equal(t, 20.0, result["vlan"].NumberValue())
+	require.Equal(t, "false", result["promiscuousMode"].StringValue())
+
+	_, result, err = PortGroupRead(ctx, id, nil, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pg-test", result["name"].StringValue())
+	require.Equal(t, 20.0, result["vlan"].NumberValue())
+
+	require.NoError(t, PortGroupDelete(ctx, id, esxi))
+	_, _, err = PortGroupRead(ctx, id, nil, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
@@ -0,0 +1,168 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jszwec/csvutil"
+)
+
+// The checks of the preflight of a host.
+const (
+	PreflightConnect    = "connect"
+	PreflightEsxcli     = "esxcli"
+	PreflightVimCmd     = "vim-cmd"
+	PreflightVmkfstools = "vmkfstools"
+	PreflightPrivileges = "privileges"
+)
+
+// adminRole is the role of the users allowed to change the host.
+const adminRole = "Admin"
+
+// PreflightCheck is the outcome of a check of the preflight of a host.
+type PreflightCheck struct {
+	Name string
+	// Detail describes what the check found, e.g. the ESXi release of the host.
+	Detail string
+	// Err is the reason the check failed, nil when it passed.
+	Err error
+}
+
+type esxcliFilesystem struct {
+	VolumeName string `csv:"VolumeName"`
+	Mounted    string `csv:"Mounted"`
+	Type       string `csv:"Type"`
+}
+
+type esxcliPermission struct {
+	Principal string `csv:"Principal"`
+	IsGroup   string `csv:"IsGroup"`
+	Role      string `csv:"Role"`
+}
+
+// Preflight connects to the host and checks the tools the resources run on it
+// work, and that the user is privileged enough to manage them. A failure to
+// connect is reported as the connect check, the other checks are then not run.
+func Preflight(ctx context.Context, connection ConnectionInfo) []PreflightCheck {
+	esxi, err := NewHost(ctx, connection)
+	if err != nil {
+		return []PreflightCheck{{Name: PreflightConnect, Err: err}}
+	}
+	defer esxi.Close()
+	return esxi.preflight(ctx)
+}
+
+func (esxi *Host) preflight(ctx context.Context) []PreflightCheck {
+	connect := PreflightCheck{Name: PreflightConnect, Detail: "connected"}
+	if esxi.Info != nil {
+		connect.Detail = "connected to " + esxi.Info.release()
+		if len(esxi.Info.Build) > 0 {
+			connect.Detail += " build " + esxi.Info.Build
+		}
+	}
+	if esxi.api != nil {
+		connect.Detail += ", the api transport runs no remote command"
+		return []PreflightCheck{connect}
+	}
+
+	esxcli, diskStore := esxi.preflightEsxcli(ctx)
+	return []PreflightCheck{
+		connect,
+		esxcli,
+		esxi.preflightVimCmd(ctx),
+		esxi.preflightVmkfstools(ctx, diskStore),
+		esxi.preflightPrivileges(ctx),
+	}
+}
+
+// preflightEsxcli lists the file systems, and returns the first VMFS datastore for the vmkfstools check.
+func (esxi *Host) preflightEsxcli(ctx context.Context) (PreflightCheck, string) {
+	check := PreflightCheck{Name: PreflightEsxcli}
+	stdout, err := esxi.ExecuteWithRetry(ctx, "esxcli --formatter=csv storage filesystem list", "list file systems")
+	var filesystems []esxcliFilesystem
+	if err == nil {
+		err = csvutil.Unmarshal([]byte(stdout), &filesystems)
+	}
+	if err != nil {
+		check.Err = fmt.Errorf("failed to list the file systems: %s err: %w", stdout, err)
+		return check, ""
+	}
+
+	diskStore := ""
+	for _, filesystem := range filesystems {
+		if diskStore == "" && filesystem.Mounted == trueValue && strings.HasPrefix(filesystem.Type, "VMFS") {
+			diskStore = filesystem.VolumeName
+		}
+	}
+	check.Detail = fmt.Sprintf("%d file systems", len(filesystems))
+	return check, diskStore
+}
+
+func (esxi *Host) preflightVimCmd(ctx context.Context) PreflightCheck {
+	check := PreflightCheck{Name: PreflightVimCmd}
+	stdout, err := esxi.ExecuteWithRetry(ctx, "vim-cmd vmsvc/getallvms 2>/dev/null", "list virtual machines")
+	if err != nil {
+		check.Err = fmt.Errorf("failed to list the virtual machines: %s err: %w", stdout, err)
+		return check
+	}
+	// The first line is the header of the list.
+	check.Detail = fmt.Sprintf("%d virtual machines", len(strings.Split(stdout, "\n"))-1)
+	return check
+}
+
+func (esxi *Host) preflightVmkfstools(ctx context.Context, diskStore string) PreflightCheck {
+	check := PreflightCheck{Name: PreflightVmkfstools}
+	if diskStore == "" {
+		check.Detail = "no VMFS datastore to query, not checked"
+		return check
+	}
+	command := shellf("vmkfstools -P %s", "/vmfs/volumes/"+diskStore)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "query datastore")
+	if err != nil {
+		check.Err = fmt.Errorf("failed to query the datastore %s: %s err: %w", diskStore, stdout, err)
+		return check
+	}
+	check.Detail = fmt.Sprintf("queried the datastore %s", diskStore)
+	return check
+}
+
+// preflightPrivileges checks the user has the Admin role, or any role on a read-only host.
+func (esxi *Host) preflightPrivileges(ctx context.Context) PreflightCheck {
+	check := PreflightCheck{Name: PreflightPrivileges}
+	user := esxi.Connection.UserName
+	stdout, err := esxi.ExecuteWithRetry(ctx, "esxcli --formatter=csv system permission list", "list permissions")
+	var permissions []esxcliPermission
+	if err == nil {
+		err = csvutil.Unmarshal([]byte(stdout), &permissions)
+	}
+	if err != nil {
+		check.Err = fmt.Errorf("failed to list the permissions, the user '%s' may lack the %s role: %s err: %w",
+			user, adminRole, stdout, err)
+		return check
+	}
+
+	groups := false
+	for _, permission := range permissions {
+		if permission.IsGroup == trueValue {
+			groups = true
+			continue
+		}
+		if !strings.EqualFold(permission.Principal, user) {
+			continue
+		}
+		if permission.Role != adminRole && !esxi.Connection.ReadOnly {
+			check.Err = fmt.Errorf("the user '%s' has the %s role, the provider requires the %s role", user, permission.Role, adminRole)
+			return check
+		}
+		check.Detail = fmt.Sprintf("the user '%s' has the %s role", user, permission.Role)
+		return check
+	}
+
+	if groups {
+		check.Detail = fmt.Sprintf("the user '%s' has no role of its own, the roles of its groups are not checked", user)
+		return check
+	}
+	check.Err = fmt.Errorf("the user '%s' has no role on the host, the provider requires the %s role", user, adminRole)
+	return check
+}
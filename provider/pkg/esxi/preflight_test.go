@@ -0,0 +1,62 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+	esxi.Info = esxi.detectHostInfo(ctx, fakeVersion)
+
+	checks := esxi.preflight(ctx)
+	require.Len(t, checks, 5)
+	for _, check := range checks {
+		require.NoError(t, check.Err, check.Name)
+	}
+	require.Equal(t, "connected to ESXi 7.0.3 build 21930508", checks[0].Detail)
+	require.Equal(t, PreflightVmkfstools, checks[3].Name)
+	require.Equal(t, "queried the datastore datastore1", checks[3].Detail)
+	require.Equal(t, "the user 'root' has the Admin role", checks[4].Detail)
+
+	// The failed checks don't prevent the others from running.
+	fake.Handle(`^vim-cmd vmsvc/getallvms`, func([]string) (string, error) {
+		return "Failed to login: Permission denied", &FakeExitError{Status: fakeStatusFailure}
+	})
+	checks = esxi.preflight(ctx)
+	require.ErrorContains(t, checks[2].Err, "failed to list the virtual machines: Failed to login: Permission denied")
+	require.NoError(t, checks[3].Err)
+}
+
+func TestPreflightPrivileges(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+	fake.AddPermission("operator", false, "ReadOnly")
+
+	esxi.Connection.UserName = "operator"
+	require.EqualError(t, esxi.preflightPrivileges(ctx).Err, "the user 'operator' has the ReadOnly role, the provider requires the Admin role")
+
+	// A read-only host only reads.
+	esxi.Connection.ReadOnly = true
+	check := esxi.preflightPrivileges(ctx)
+	require.NoError(t, check.Err)
+	require.Equal(t, "the user 'operator' has the ReadOnly role", check.Detail)
+
+	esxi.Connection.UserName = "nobody"
+	require.EqualError(t, esxi.preflightPrivileges(ctx).Err, "the user 'nobody' has no role on the host, the provider requires the Admin role")
+
+	fake.AddPermission("admins", true, "Admin")
+	check = esxi.preflightPrivileges(ctx)
+	require.NoError(t, check.Err)
+	require.Contains(t, check.Detail, "the roles of its groups are not checked")
+}
+
+func TestPreflightConnectFailure(t *testing.T) {
+	checks := Preflight(context.Background(), ConnectionInfo{Transport: "telnet"})
+	require.Len(t, checks, 1)
+	require.Equal(t, PreflightConnect, checks[0].Name)
+	require.ErrorContains(t, checks[0].Err, "unknown transport 'telnet'")
+}
@@ -0,0 +1,305 @@
+package esxi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// errReadOnly is returned for the changes refused on the read-only hosts.
+var errReadOnly = errors.New("the host is read-only")
+
+// errDryRun fails the resource changes of the dry-run hosts, whose mutating
+// commands were logged and not run.
+var errDryRun = errors.New("dry run")
+
+// readOnlyCommands are the programs, with their arguments, a read-only host
+// runs. Every command of a pipeline or list must match one of them.
+var readOnlyCommands = []*regexp.Regexp{
+	regexp.MustCompile(`^vmware (-v|--version|-l)$`),
+	regexp.MustCompile(`^vim-cmd (vmsvc/(getallvms|get\.[a-z]+|power\.getstate|device\.getdevices)|solo/querycfgopt(desc)?|hostsvc/rsrc/pool_config_get)( |$)`),
+	regexp.MustCompile(`^esxcli( --formatter=[a-z]+)?( [a-z]+)+ (list|get)( |$)`),
+	regexp.MustCompile(`^(/bin/)?vmkfstools -(t0|P) `),
+	regexp.MustCompile(`^(ls|cat|grep|test|wc|sort|uniq|head|tail|cut|tr|echo|printf|true)( |$)`),
+	regexp.MustCompile(`^sed( |$)`),
+	regexp.MustCompile(`^awk( |$)`),
+}
+
+// readOnlyRedirection matches the redirections of the outputs to /dev/null or to
+// another output, which don't write any file.
+var readOnlyRedirection = regexp.MustCompile(`[0-9]?>(/dev/null|&[12])`)
+
+// isReadOnlyCommand returns true when the command only reads the state of the
+// host. The commands the provider doesn't know are not read-only, nor are the
+// ones with a redirection to a file or a command substitution.
+func isReadOnlyCommand(command string) bool {
+	commands, ok := shellCommands(readOnlyRedirection.ReplaceAllString(command, ""))
+	if !ok {
+		return false
+	}
+	for _, words := range commands {
+		line := strings.Join(words, " ")
+		matched := false
+		for _, pattern := range readOnlyCommands {
+			if pattern.MatchString(line) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+		if words[0] == "sed" && !isReadOnlySed(words[1:]) {
+			return false
+		}
+		for _, word := range words[1:] {
+			// awk runs commands with system or writes with print >.
+			if words[0] == "awk" && (strings.ContainsAny(word, ">|") || strings.Contains(word, "system(")) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readOnlySedOptions are the long options of sed which don't write any file.
+var readOnlySedOptions = map[string]bool{
+	"--quiet": true, "--silent": true, "--regexp-extended": true, "--posix": true,
+	"--separate": true, "--null-data": true, "--unbuffered": true, "--sandbox": true,
+}
+
+// isReadOnlySed returns true when sed only prints with the arguments: the
+// options editing the files in place (-i, --in-place) or reading the script
+// from a file are refused, as are the scripts writing files or running
+// commands.
+func isReadOnlySed(args []string) bool {
+	var scripts []string
+	expression := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case expression || (len(scripts) == 0 && !strings.HasPrefix(arg, "-")):
+			scripts = append(scripts, arg)
+			expression = false
+		case strings.HasPrefix(arg, "--expression="):
+			scripts = append(scripts, strings.TrimPrefix(arg, "--expression="))
+		case arg == "--expression":
+			expression = true
+		case strings.HasPrefix(arg, "--"):
+			if !readOnlySedOptions[arg] {
+				return false
+			}
+		case strings.HasPrefix(arg, "-"):
+			// A cluster of the short options, -e takes the rest of the
+			// argument or the next one as a script.
+			for j := 1; j < len(arg); j++ {
+				if arg[j] == 'e' {
+					if j+1 < len(arg) {
+						scripts = append(scripts, arg[j+1:])
+					} else {
+						expression = true
+					}
+					break
+				}
+				if strings.IndexByte("nErsuz", arg[j]) < 0 {
+					return false
+				}
+			}
+		}
+	}
+	if expression {
+		return false
+	}
+	for _, script := range scripts {
+		if !isReadOnlySedScript(script) {
+			return false
+		}
+	}
+	return true
+}
+
+// isReadOnlySedScript returns true when the sed script has no command writing
+// a file (w, W, the w flag of s) or running one (e, the e flag of s). The
+// commands and flags it doesn't know are not read-only.
+func isReadOnlySedScript(script string) bool {
+	i := 0
+	// delimited skips the text up to the unescaped delimiter, it returns
+	// false when the delimiter is missing.
+	delimited := func(delimiter byte) bool {
+		for ; i < len(script); i++ {
+			switch script[i] {
+			case '\\':
+				i++
+			case delimiter:
+				i++
+				return true
+			}
+		}
+		return false
+	}
+	// toEndOf skips the argument of a command up to one of the separators.
+	toEndOf := func(separators string) {
+		for i < len(script) && strings.IndexByte(separators, script[i]) < 0 {
+			i++
+		}
+	}
+
+	for i < len(script) {
+		c := script[i]
+		i++
+		switch {
+		case strings.IndexByte(" \t\n;{}!,0123456789$~", c) >= 0:
+			// The separators, the blocks and the addresses.
+		case c == '/':
+			if !delimited('/') {
+				return false
+			}
+		case c == '\\':
+			if i == len(script) {
+				return false
+			}
+			i++
+			if !delimited(script[i-1]) {
+				return false
+			}
+		case c == 's' || c == 'y':
+			if i == len(script) {
+				return false
+			}
+			i++
+			delimiter := script[i-1]
+			if !delimited(delimiter) || !delimited(delimiter) {
+				return false
+			}
+			if c == 's' {
+				for ; i < len(script) && strings.IndexByte(" \t\n;}", script[i]) < 0; i++ {
+					if strings.IndexByte("gpiImM0123456789", script[i]) < 0 {
+						return false
+					}
+				}
+			}
+		case c == 'a' || c == 'i' || c == 'c' || c == 'r' || c == 'R':
+			// The text or the file read ends the line.
+			toEndOf("\n")
+		case c == 'b' || c == 't' || c == 'T' || c == ':':
+			toEndOf(";\n")
+		case strings.IndexByte("=dDFgGhHlLnNpPqQxz", c) >= 0:
+		default:
+			// w and W write files, e runs commands.
+			return false
+		}
+	}
+	return true
+}
+
+// shellCommands splits the command line into the words of its commands, which
+// are separated by pipes, lists and new lines. It returns false for the
+// redirections, the command substitutions, the subshells and the background
+// commands, which aren't split.
+func shellCommands(line string) ([][]string, bool) {
+	var commands [][]string
+	var words []string
+	var word strings.Builder
+	inWord := false
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, words)
+			words = nil
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, false
+			}
+			word.WriteString(line[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '"':
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '$' || line[i] == '`' {
+					return nil, false
+				}
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+				}
+				word.WriteByte(line[i])
+			}
+			if i == len(line) {
+				return nil, false
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(line):
+			i++
+			word.WriteByte(line[i])
+			inWord = true
+		case c == ' ' || c == '\t':
+			endWord()
+		case c == '|' || c == ';' || c == '\n' || (c == '&' && i+1 < len(line) && line[i+1] == '&'):
+			if c == '&' || (c == '|' && i+1 < len(line) && line[i+1] == '|') {
+				i++
+			}
+			endCommand()
+		case strings.IndexByte("<>&()`$", c) >= 0:
+			return nil, false
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	endCommand()
+	return commands, true
+}
+
+// skipMutation refuses the mutating operation on a read-only host, or logs it
+// without running it on a dry-run host. It returns true when the operation
+// must not run, with the error to return for it.
+func (esxi *Host) skipMutation(operation string, shortCmdDesc string) (bool, error) {
+	switch {
+	case esxi.Connection.ReadOnly:
+		return true, fmt.Errorf("%w, refusing to run '%s': %s", errReadOnly, shortCmdDesc, esxi.redact(operation))
+	case esxi.Connection.DryRun:
+		logging.V(logLevel).Infof("dryRun: %s => %s", shortCmdDesc, esxi.redact(operation))
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// beginChange refuses the creation, the update or the deletion of a resource
+// on a read-only host, before any of its commands runs.
+func (esxi *Host) beginChange(token string) error {
+	if esxi.Connection.ReadOnly {
+		return fmt.Errorf("%w, refusing to run %s", errReadOnly, token)
+	}
+	return nil
+}
+
+// endChange fails the change of a resource on a dry-run host, so that the
+// resource isn't recorded as changed: its mutating commands were logged but
+// not run, and the ones reading their outcome may have failed.
+func (esxi *Host) endChange(token string, err error) error {
+	if !esxi.Connection.DryRun {
+		return err
+	}
+	if err != nil {
+		logging.V(logLevel).Infof("dryRun: %s failed without its mutating commands: %s", token, err)
+	}
+	return fmt.Errorf("%w, the commands of %s were logged and not run", errDryRun, token)
+}
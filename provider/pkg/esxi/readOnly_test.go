@@ -0,0 +1,162 @@
+package esxi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		readOnly bool
+	}{
+		{command: "vmware --version", readOnly: true},
+		{command: "esxcli --formatter=csv network vswitch standard portgroup policy security get -p 'VM Network'", readOnly: true},
+		{command: "esxcli network vswitch standard list -v vSwitch0", readOnly: true},
+		{command: "vim-cmd vmsvc/getallvms 2>/dev/null |sort -n | grep -m 1 -e '[0-9] * web .*web' |awk '{print $1}' ", readOnly: true},
+		{command: "grep -A1 -e '<name>web</name>' /etc/vmware/hostd/pools.xml | grep -m 1 -o objID.*objID", readOnly: true},
+		{command: `vim-cmd vmsvc/get.config 1 | grep vmPathName|grep -oE "\[.*\]"`, readOnly: true},
+		{command: `vim-cmd vmsvc/get.config 1 | grep vmPathName|awk '{print $NF}'|sed 's/["|,]//g'`, readOnly: true},
+		{command: "vmkfstools -t0 /vmfs/volumes/datastore1/disk.vmdk |grep -q 'VMFS Z- LVID:' && echo true", readOnly: true},
+		{command: "mkdir -p ~"},
+		{command: "vim-cmd vmsvc/power.on 1"},
+		{command: "vim-cmd vmsvc/destroy 1"},
+		{command: "vim-cmd solo/registervm /vmfs/volumes/datastore1/web/web.vmx web pool0"},
+		{command: "esxcli network vswitch standard add -P 128 -v vSwitch1"},
+		{command: "esxcli network vswitch standard portgroup set -v 10 -p web"},
+		{command: "esxcli storage filesystem rescan"},
+		{command: "/bin/vmkfstools -X 4G /vmfs/volumes/datastore1/disk.vmdk"},
+		{command: "cat /etc/vmware/hostd/pools.xml > /tmp/pools.xml"},
+		{command: "ls $(rm -fr /tmp/web)"},
+		{command: `echo "$(rm -fr /tmp/web)"`},
+		{command: "echo true && rm -fr /tmp/web"},
+		{command: "ls /tmp; rm -fr /tmp/web"},
+		{command: "vim-cmd vmsvc/get.guest 1 2>/dev/null |sed '1!G;h;$!d' |awk '/deviceConfigId = 4000/,/ipAddress/'", readOnly: true},
+		{command: "sed -n -e 's/a/b/p' -e '/web/=' /tmp/web.vmx", readOnly: true},
+		{command: "sed -i 's/a/b/' /tmp/web.vmx"},
+		{command: "sed --in-place 's/a/b/' /tmp/web.vmx"},
+		{command: "sed --in-place=.bak 's/a/b/' /tmp/web.vmx"},
+		{command: "sed -ni 's/a/b/p' /tmp/web.vmx"},
+		{command: "sed -Ei 's/a+/b/' /tmp/web.vmx"},
+		{command: "sed -f /tmp/script.sed /tmp/web.vmx"},
+		{command: "sed 'w /tmp/out' /tmp/web.vmx"},
+		{command: "sed -n '/web/W /tmp/out' /tmp/web.vmx"},
+		{command: "sed 's/a/b/w /tmp/out' /tmp/web.vmx"},
+		{command: "sed -e 's/a/b/gw /tmp/out' /tmp/web.vmx"},
+		{command: "sed --expression='1!G;w /tmp/out' /tmp/web.vmx"},
+		{command: "sed 's/.*/rm -fr \\/tmp\\/web/e' /tmp/web.vmx"},
+		{command: "sed '1e rm -fr /tmp/web' /tmp/web.vmx"},
+		{command: `awk '{print > "/tmp/out"}' /tmp/web.vmx`},
+		{command: "grep 'unterminated"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.readOnly, isReadOnlyCommand(test.command), test.command)
+	}
+}
+
+func TestReadOnlyHost(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	// The resources are created by a host which isn't read-only.
+	inputs := getBaseVMInputs()
+	inputs["diskStore"] = resource.NewStringProperty("datastore1")
+	inputs["power"] = resource.NewStringProperty("off")
+	vmId, _, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	disk := resource.PropertyMap{
+		"name":      resource.NewStringProperty("disk-test"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+		"size":      resource.NewNumberProperty(2),
+	}
+	diskId, _, err := VirtualDiskCreate(ctx, disk, esxi)
+	require.NoError(t, err)
+	pool := resource.PropertyMap{"name": resource.NewStringProperty("pool-test")}
+	poolId, _, err := ResourcePoolCreate(ctx, pool, esxi)
+	require.NoError(t, err)
+
+	readOnly := NewHostWithExecutor(ConnectionInfo{Host: "fake", ReadOnly: true}, fake)
+	readOnly.sleep = esxi.sleep
+	commands := len(fake.Commands())
+
+	// All the reads run.
+	service := NewResourceService()
+	for _, read := range []struct {
+		token, id string
+		inputs    resource.PropertyMap
+	}{
+		{"esxi-native:index:VirtualMachine", vmId, inputs},
+		{"esxi-native:index:VirtualDisk", diskId, disk},
+		{"esxi-native:index:ResourcePool", poolId, pool},
+		{"esxi-native:index:VirtualSwitch", "vSwitch0", resource.PropertyMap{}},
+		{"esxi-native:index:PortGroup", "vSwitch0/VM Network", resource.PropertyMap{}},
+	} {
+		_, _, err = service.Read(ctx, read.token, read.id, read.inputs, readOnly)
+		require.NoError(t, err, read.token)
+	}
+	require.Greater(t, len(fake.Commands()), commands)
+
+	// The changes are refused before running any command.
+	commands = len(fake.Commands())
+	_, _, err = service.Create(ctx, "esxi-native:index:VirtualSwitch", resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1")}, readOnly)
+	require.ErrorIs(t, err, errReadOnly)
+	_, err = service.Update(ctx, "esxi-native:index:VirtualMachine", newResourceUpdate(vmId, inputs), readOnly)
+	require.ErrorIs(t, err, errReadOnly)
+	err = service.Delete(ctx, "esxi-native:index:ResourcePool", poolId, readOnly)
+	require.ErrorIs(t, err, errReadOnly)
+	require.Len(t, fake.Commands(), commands)
+
+	// And so are the mutating commands and the file transfers.
+	_, err = readOnly.Execute(ctx, shellf("vim-cmd vmsvc/power.on %s", vmId), "power on vm")
+	require.ErrorIs(t, err, errReadOnly)
+	require.ErrorContains(t, err, "refusing to run 'power on vm': vim-cmd vmsvc/power.on")
+	_, err = readOnly.WriteFile(ctx, "content", "/tmp/file.txt", "write file")
+	require.ErrorIs(t, err, errReadOnly)
+	_, err = readOnly.CopyFile(ctx, "/tmp/local", "/tmp/file.txt", "copy file")
+	require.ErrorIs(t, err, errReadOnly)
+	require.Len(t, fake.Commands(), commands)
+	_, ok := fake.ReadFile("/tmp/file.txt")
+	require.False(t, ok)
+}
+
+func TestDryRunHost(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	esxi.Connection.DryRun = true
+	ctx := context.Background()
+	service := NewResourceService()
+
+	_, _, err := service.Create(ctx, "esxi-native:index:VirtualSwitch", resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1")}, esxi)
+	require.ErrorIs(t, err, errDryRun)
+	err = service.Delete(ctx, "esxi-native:index:PortGroup", "vSwitch0/VM Network", esxi)
+	require.ErrorIs(t, err, errDryRun)
+
+	// The reads ran, the changes were only logged.
+	for _, command := range fake.Commands() {
+		require.True(t, isReadOnlyCommand(command), command)
+	}
+	require.NotEmpty(t, fake.Commands())
+
+	_, err = esxi.WriteFile(ctx, "content", "/tmp/file.txt", "write file")
+	require.NoError(t, err)
+	_, ok := fake.ReadFile("/tmp/file.txt")
+	require.False(t, ok)
+
+	esxi.Connection.DryRun = false
+	stdout, err := esxi.Execute(ctx, "esxcli network vswitch standard list", "list virtual switches")
+	require.NoError(t, err)
+	require.NotContains(t, stdout, "vSwitch1")
+	_, _, err = PortGroupRead(ctx, "vSwitch0/VM Network", nil, esxi)
+	require.NoError(t, err)
+}
+
+func TestDryRunRequiresSSH(t *testing.T) {
+	_, err := NewHost(context.Background(), ConnectionInfo{Transport: TransportAPI, Host: "127.0.0.1", Password: "secret", DryRun: true})
+	require.ErrorContains(t, err, "dryRun requires the 'ssh' transport")
+	require.False(t, strings.Contains(err.Error(), "secret"))
+}
@@ -0,0 +1,228 @@
+package esxi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// The operations of the recordings.
+const (
+	RecordExecute   = "execute"
+	RecordWriteFile = "writeFile"
+	RecordCopyFile  = "copyFile"
+)
+
+// Recording is a line of a record file: a remote operation run on a host and
+// its outcome, with the secrets redacted.
+type Recording struct {
+	Time      time.Time `json:"time"`
+	Host      string    `json:"host"`
+	Operation string    `json:"operation"`
+	// Command is the command of an execute operation.
+	Command string `json:"command,omitempty"`
+	// Path and Content are the remote path and the content of a file operation.
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+	Stdout  string `json:"stdout"`
+	// Error is the message of the error of the operation, ExitStatus the exit status of a failed command.
+	Error      string `json:"error,omitempty"`
+	ExitStatus int    `json:"exitStatus,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// recorder appends the remote operations of a host to a JSON lines record file.
+type recorder struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func newRecorder(path string) (*recorder, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the record file: %w", err)
+	}
+	return &recorder{file: file}, nil
+}
+
+// record appends the recording, a failure to record is logged but doesn't fail the operation.
+func (r *recorder) record(recording Recording) {
+	line, err := json.Marshal(recording)
+	if err == nil {
+		r.mutex.Lock()
+		_, err = r.file.Write(append(line, '\n'))
+		r.mutex.Unlock()
+	}
+	if err != nil {
+		logging.V(logLevel).Infof("record: failed to record the %s operation: %s", recording.Operation, err)
+	}
+}
+
+func (r *recorder) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_ = r.file.Close()
+}
+
+// record records the operation, when the host has a record file.
+func (esxi *Host) record(recording Recording, start time.Time, err error) {
+	if esxi.recorder == nil {
+		return
+	}
+	recording.Time = start.UTC()
+	recording.Host = esxi.Connection.Host
+	recording.Command = esxi.redact(recording.Command)
+	recording.Content = esxi.redact(recording.Content)
+	recording.Stdout = esxi.redact(recording.Stdout)
+	recording.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		recording.Error = esxi.redact(err.Error())
+		var exitErr interface{ ExitStatus() int }
+		if errors.As(err, &exitErr) {
+			recording.ExitStatus = exitErr.ExitStatus()
+		}
+	}
+	esxi.recorder.record(recording)
+}
+
+// ReplayExecutor serves back the outcomes of the operations of a record file.
+// The commands are answered with the recordings of the same command in the
+// order they were recorded, the last one being repeated once they are all
+// served. The secrets redacted in the recorded commands match any text.
+type ReplayExecutor struct {
+	mutex      sync.Mutex
+	recordings []replayed
+}
+
+type replayed struct {
+	Recording
+	command *regexp.Regexp
+	served  bool
+}
+
+var _ Executor = (*ReplayExecutor)(nil)
+
+// errNotRecorded is returned for the operations missing from the record file.
+var errNotRecorded = errors.New("replay: the operation was not recorded")
+
+// NewReplayExecutor returns an executor replaying the record file.
+func NewReplayExecutor(path string) (*ReplayExecutor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the record file: %w", err)
+	}
+	defer file.Close()
+
+	replay := &ReplayExecutor{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024) //nolint:gomnd // the vmx contents fit
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var recording Recording
+		if err = json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			return nil, fmt.Errorf("invalid record file %s, line %d: %w", path, line, err)
+		}
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(recording.Command), regexp.QuoteMeta(redacted), ".*")
+		replay.recordings = append(replay.recordings, replayed{
+			Recording: recording,
+			command:   regexp.MustCompile("(?s)^" + pattern + "$"),
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the record file %s: %w", path, err)
+	}
+	return replay, nil
+}
+
+// Unserved returns the recordings never served, e.g. to check a replay ran all the recorded commands.
+func (replay *ReplayExecutor) Unserved() []Recording {
+	replay.mutex.Lock()
+	defer replay.mutex.Unlock()
+
+	var unserved []Recording
+	for _, recording := range replay.recordings {
+		if !recording.served {
+			unserved = append(unserved, recording.Recording)
+		}
+	}
+	return unserved
+}
+
+// serve returns the next recording of the operation matching the command or the path.
+func (replay *ReplayExecutor) serve(operation string, matches func(*replayed) bool) (Recording, bool) {
+	replay.mutex.Lock()
+	defer replay.mutex.Unlock()
+
+	last := -1
+	for i := range replay.recordings {
+		recording := &replay.recordings[i]
+		if recording.Operation != operation || !matches(recording) {
+			continue
+		}
+		if !recording.served {
+			recording.served = true
+			return recording.Recording, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Recording{}, false
+	}
+	return replay.recordings[last].Recording, true
+}
+
+func (recording Recording) outcome() (string, error) {
+	switch {
+	case recording.ExitStatus != 0:
+		return recording.Stdout, &FakeExitError{Status: recording.ExitStatus}
+	case len(recording.Error) > 0:
+		return recording.Stdout, errors.New(recording.Error)
+	default:
+		return recording.Stdout, nil
+	}
+}
+
+func (replay *ReplayExecutor) Execute(ctx context.Context, command string, _ string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return failedToConnect, err
+	}
+	recording, ok := replay.serve(RecordExecute, func(recording *replayed) bool {
+		return recording.command.MatchString(command)
+	})
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errNotRecorded, command)
+	}
+	return recording.outcome()
+}
+
+func (replay *ReplayExecutor) WriteFile(ctx context.Context, _ string, path string, _ string) (string, error) {
+	return replay.transfer(ctx, RecordWriteFile, path)
+}
+
+func (replay *ReplayExecutor) CopyFile(ctx context.Context, _ string, hostPath string, _ string) (string, error) {
+	return replay.transfer(ctx, RecordCopyFile, hostPath)
+}
+
+func (replay *ReplayExecutor) transfer(ctx context.Context, operation string, path string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return failedToConnect, err
+	}
+	recording, ok := replay.serve(operation, func(recording *replayed) bool {
+		return recording.Path == path
+	})
+	if !ok {
+		return failedToConnect, fmt.Errorf("%w: %s %s", errNotRecorded, operation, path)
+	}
+	return recording.outcome()
+}
@@ -0,0 +1,153 @@
+package esxi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newReplayHost returns a host replaying the record file of testdata/replay.
+func newReplayHost(t *testing.T, name string) (*Host, *ReplayExecutor) {
+	t.Helper()
+	replay, err := NewReplayExecutor(filepath.Join("testdata", "replay", name))
+	require.NoError(t, err)
+	return NewHostWithExecutor(ConnectionInfo{Host: "esxi1.lab", SSHPort: "22", SslPort: "443", UserName: "root"}, replay), replay
+}
+
+func readRecordings(t *testing.T, path string) []Recording {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var recordings []Recording
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var recording Recording
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &recording))
+		recordings = append(recordings, recording)
+	}
+	require.NoError(t, scanner.Err())
+	return recordings
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "record.jsonl")
+	esxi, fake := newFakeHost(t)
+	esxi.Connection.Password = "hunter22"
+	esxi.redactor = newRedactor(esxi.Connection.connectionSecrets()...)
+	var err error
+	esxi.recorder, err = newRecorder(path)
+	require.NoError(t, err)
+	fake.Handle(`^login `, func([]string) (string, error) {
+		return "logged in", nil
+	})
+	ctx := context.Background()
+
+	_, err = esxi.WriteFile(ctx, "key = \"value\"", "/tmp/file.txt", "write")
+	require.NoError(t, err)
+	recorded, err := esxi.Execute(ctx, "cat /tmp/file.txt", "read")
+	require.NoError(t, err)
+	_, err = esxi.Execute(ctx, "login root hunter22", "login")
+	require.NoError(t, err)
+	_, err = esxi.Execute(ctx, "grep -q missing /tmp/file.txt", "grep")
+	require.Error(t, err)
+	esxi.Close()
+
+	recordings := readRecordings(t, path)
+	require.Len(t, recordings, 4)
+	require.Equal(t, RecordWriteFile, recordings[0].Operation)
+	require.Equal(t, "/tmp/file.txt", recordings[0].Path)
+	require.Equal(t, "fake", recordings[1].Host)
+	require.Equal(t, "login root [secret]", recordings[2].Command)
+	require.Equal(t, fakeStatusFailure, recordings[3].ExitStatus)
+	for _, recording := range recordings {
+		require.False(t, recording.Time.IsZero())
+	}
+
+	// The replay serves the recorded outcomes back, whatever the secrets are.
+	replay, err := NewReplayExecutor(path)
+	require.NoError(t, err)
+	replayed := NewHostWithExecutor(ConnectionInfo{Host: "fake", Password: "other"}, replay)
+	_, err = replayed.WriteFile(ctx, "key = \"value\"", "/tmp/file.txt", "write")
+	require.NoError(t, err)
+	stdout, err := replayed.Execute(ctx, "cat /tmp/file.txt", "read")
+	require.NoError(t, err)
+	require.Equal(t, recorded, stdout)
+	stdout, err = replayed.Execute(ctx, "login root other", "login")
+	require.NoError(t, err)
+	require.Equal(t, "logged in", stdout)
+	_, err = replayed.Execute(ctx, "grep -q missing /tmp/file.txt", "grep")
+	var exitErr *FakeExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, fakeStatusFailure, exitErr.Status)
+	require.Empty(t, replay.Unserved())
+
+	// The last recording of a command is repeated, the commands not recorded fail.
+	stdout, err = replayed.Execute(ctx, "cat /tmp/file.txt", "read")
+	require.NoError(t, err)
+	require.Equal(t, recorded, stdout)
+	_, err = replayed.Execute(ctx, "cat /tmp/other.txt", "read")
+	require.ErrorIs(t, err, errNotRecorded)
+	_, err = replayed.CopyFile(ctx, "/tmp/local", "/tmp/other.txt", "copy")
+	require.ErrorIs(t, err, errNotRecorded)
+}
+
+func TestReplayVirtualSwitch(t *testing.T) {
+	esxi, replay := newReplayHost(t, "virtual-switch.jsonl")
+
+	vswitch, err := esxi.getVirtualSwitch(context.Background(), "vSwitch0")
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch0", vswitch.Name)
+	require.Equal(t, 128, vswitch.Ports)
+	require.Equal(t, 1500, vswitch.Mtu)
+	require.Equal(t, "listen", vswitch.LinkDiscoveryMode)
+	require.Equal(t, []Uplink{{Name: "vmnic0"}}, vswitch.Uplinks)
+	require.False(t, vswitch.PromiscuousMode)
+	require.Empty(t, replay.Unserved())
+}
+
+func TestReplayResourcePool(t *testing.T) {
+	esxi, replay := newReplayHost(t, "resource-pool.jsonl")
+
+	id, err := esxi.getResourcePoolId(context.Background(), "web")
+	require.NoError(t, err)
+	pool, err := esxi.getResourcePoolDetails(context.Background(), ResourcePool{Id: id})
+	require.NoError(t, err)
+	require.Equal(t, "pool0", pool.Id)
+	require.Equal(t, "web", pool.Name)
+	require.Equal(t, 100, pool.CpuMin)
+	require.Equal(t, "true", pool.CpuMinExpandable)
+	require.Equal(t, "normal", pool.CpuShares)
+	require.Equal(t, 200, pool.MemMin)
+	require.Equal(t, "high", pool.MemShares)
+	require.Empty(t, replay.Unserved())
+}
+
+func TestReplayVirtualMachine(t *testing.T) {
+	esxi, replay := newReplayHost(t, "virtual-machine.jsonl")
+
+	id, err := esxi.getVirtualMachineId(context.Background(), "web-1")
+	require.NoError(t, err)
+	vm, err := esxi.readVirtualMachine(context.Background(), VirtualMachine{Id: id})
+	require.NoError(t, err)
+	require.Equal(t, "1", vm.Id)
+	require.Equal(t, "web-1", vm.Name)
+	require.Equal(t, "datastore1", vm.DiskStore)
+	require.Equal(t, "web", vm.ResourcePoolName)
+	require.Equal(t, 512, vm.MemSize)
+	require.Equal(t, 1, vm.NumVCpus)
+	require.Equal(t, "centos", vm.Os)
+	require.Equal(t, 13, vm.VirtualHWVer)
+	require.Equal(t, 16, vm.BootDiskSize)
+	require.Equal(t, "thin", vm.BootDiskType)
+	require.Equal(t, "off", vm.Power)
+	require.Equal(t, []NetworkInterface{{VirtualNetwork: "VM Network", NicType: "e1000"}}, vm.NetworkInterfaces)
+	require.Equal(t, []KeyValuePair{{Key: "role", Value: "web"}}, vm.Info)
+	require.Empty(t, replay.Unserved())
+}
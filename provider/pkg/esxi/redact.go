@@ -0,0 +1,136 @@
+package esxi
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// redacted replaces the secrets in the logs and the error messages.
+const redacted = "[secret]"
+
+// redactor scrubs the secrets, the credentials of the connection and the
+// secret inputs of the resources, from the commands and outputs logged and
+// from the error messages returned to the engine.
+type redactor struct {
+	mutex   sync.RWMutex
+	secrets map[string]struct{}
+	// sorted are the secrets from the longest, so a secret containing another
+	// one is replaced as a whole.
+	sorted []string
+}
+
+func newRedactor(secrets ...string) *redactor {
+	r := &redactor{secrets: map[string]struct{}{}}
+	r.add(secrets...)
+	return r
+}
+
+// connectionSecrets returns the credentials of the connection.
+func (c *ConnectionInfo) connectionSecrets() []string {
+	return []string{c.Password, c.PrivateKey, c.PrivateKeyPassphrase, c.BastionPassword, c.BastionPrivateKey}
+}
+
+// add registers the secrets, along with the forms they are escaped to in the
+// commands, the urls and the shell words.
+func (r *redactor) add(secrets ...string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	changed := false
+	for _, secret := range secrets {
+		if len(secret) == 0 {
+			continue
+		}
+		for _, form := range []string{secret, url.QueryEscape(secret), url.PathEscape(secret), shellQuote(secret)} {
+			if _, has := r.secrets[form]; !has {
+				r.secrets[form] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return
+	}
+
+	r.sorted = make([]string, 0, len(r.secrets))
+	for secret := range r.secrets {
+		r.sorted = append(r.sorted, secret)
+	}
+	sort.Slice(r.sorted, func(i, j int) bool {
+		return len(r.sorted[i]) > len(r.sorted[j])
+	})
+}
+
+// addInputs registers the string values of the secret inputs.
+func (r *redactor) addInputs(inputs resource.PropertyMap) {
+	r.add(secretStrings(resource.NewObjectProperty(inputs), false)...)
+}
+
+func secretStrings(value resource.PropertyValue, secret bool) []string {
+	var result []string
+	switch {
+	case value.IsSecret():
+		result = secretStrings(value.SecretValue().Element, true)
+	case value.IsOutput():
+		output := value.OutputValue()
+		result = secretStrings(output.Element, secret || output.Secret)
+	case value.IsString() && secret:
+		result = []string{value.StringValue()}
+	case value.IsArray():
+		for _, element := range value.ArrayValue() {
+			result = append(result, secretStrings(element, secret)...)
+		}
+	case value.IsObject():
+		for _, element := range value.ObjectValue() {
+			result = append(result, secretStrings(element, secret)...)
+		}
+	}
+	return result
+}
+
+// redact replaces the secrets in the text.
+func (r *redactor) redact(text string) string {
+	if r == nil || len(text) == 0 {
+		return text
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, secret := range r.sorted {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	return text
+}
+
+// redactError returns the error with the secrets replaced in its message. The
+// original error is still unwrapped, so errors.Is and errors.As see through it.
+func (r *redactor) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	if redactedMessage := r.redact(message); redactedMessage != message {
+		return &redactedError{err: err, message: redactedMessage}
+	}
+	return err
+}
+
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
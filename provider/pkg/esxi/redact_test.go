@@ -0,0 +1,121 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor("", "p@ss word", "p@ss")
+	require.Equal(t, "login [secret] failed", r.redact("login p@ss word failed"))
+	require.Equal(t, "vi://root:[secret]@esxi/", r.redact("vi://root:p%40ss+word@esxi/"))
+	require.Equal(t, "ovftool [secret]", r.redact("ovftool 'p@ss word'"))
+	require.Equal(t, "nothing to hide", r.redact("nothing to hide"))
+
+	r.addInputs(resource.PropertyMap{
+		"name": resource.NewStringProperty("vm"),
+		"ovfProperties": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"key":   resource.NewStringProperty("password"),
+				"value": resource.MakeSecret(resource.NewStringProperty("guest-secret")),
+			}),
+		}),
+		"info": resource.MakeSecret(resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"key":   resource.NewStringProperty("token"),
+				"value": resource.NewStringProperty("info-secret"),
+			}),
+		})),
+		"notes": resource.NewOutputProperty(resource.Output{Element: resource.NewStringProperty("output-secret"), Known: true, Secret: true}),
+	})
+	require.Equal(t, "vm [secret] [secret] [secret] [secret] password",
+		r.redact("vm guest-secret info-secret token output-secret password"))
+
+	var nilRedactor *redactor
+	require.Equal(t, "p@ss", nilRedactor.redact("p@ss"))
+}
+
+func TestRedactError(t *testing.T) {
+	r := newRedactor("hunter22")
+	err := fmt.Errorf("failed with hunter22: %w", context.Canceled)
+
+	redactedErr := r.redactError(err)
+	require.EqualError(t, redactedErr, "failed with [secret]: context canceled")
+	require.ErrorIs(t, redactedErr, context.Canceled)
+
+	plain := errors.New("failed")
+	require.Same(t, plain, r.redactError(plain))
+	require.NoError(t, r.redactError(nil))
+}
+
+func TestResourceErrorsAreRedacted(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	esxi.Connection.Password = "hunter22"
+	esxi.redactor = newRedactor(esxi.Connection.connectionSecrets()...)
+	fake.Handle(`^esxcli network vswitch standard portgroup add`, func([]string) (string, error) {
+		return "login hunter22 rejected the port group, token input-secret", &FakeExitError{Status: 1}
+	})
+
+	_, _, err := NewResourceService().Create(context.Background(), "esxi-native:index:PortGroup", resource.PropertyMap{
+		"name":    resource.NewStringProperty("pg"),
+		"vSwitch": resource.NewStringProperty("vSwitch0"),
+		"token":   resource.MakeSecret(resource.NewStringProperty("input-secret")),
+	}, esxi)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "hunter22")
+	require.NotContains(t, err.Error(), "input-secret")
+	require.Contains(t, err.Error(), "login [secret] rejected the port group, token [secret]")
+}
+
+func TestOvftoolPasswordOffCommandLine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ovftool is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+printf '%s\n' "$@" > "` + dir + `/args"
+cat > "` + dir + `/stdin"
+echo "Error: login failed"
+exit 1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ovftool"), []byte(script), 0o755)) //nolint:gosec // the fake ovftool is executed
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	esxi, _ := newFakeHost(t)
+	esxi.Connection.Password = "hunter22"
+	esxi.redactor = newRedactor(esxi.Connection.connectionSecrets()...)
+	esxi.redactor.addInputs(resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("guest-{{ .Name }}")),
+	})
+
+	source := filepath.Join(dir, "image.ova")
+	require.NoError(t, os.WriteFile(source, nil, 0o600))
+	err := esxi.buildVirtualMachineFromSource(context.Background(), VirtualMachine{
+		Name:             "vm",
+		SourcePath:       source,
+		BootDiskType:     vdThin,
+		DiskStore:        "datastore1",
+		ResourcePoolName: "/",
+		OvfProperties:    []KeyValuePair{{Key: "password", Value: "guest-{{ .Name }}"}},
+	})
+	require.ErrorContains(t, err, "ovftool error")
+	require.NotContains(t, err.Error(), "hunter22")
+	require.NotContains(t, err.Error(), "guest-vm")
+	require.Contains(t, err.Error(), "--prop:password=[secret]")
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	require.NotContains(t, string(args), "hunter22")
+	require.Contains(t, string(args), "vi://root@fake:443/")
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	require.NoError(t, err)
+	require.Equal(t, "hunter22\n", string(stdin))
+}
@@ -0,0 +1,92 @@
+package esxi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// RefreshRules are how the live values read from the host are written to the
+// inputs of a resource on refresh.
+type RefreshRules struct {
+	// Unread are the inputs which are settings of the provider, not read from
+	// the host, they are kept as they are.
+	Unread []string
+	// Equivalents tell whether the live value is the input written differently,
+	// e.g. with a suffix added by the host.
+	Equivalents map[string]func(input, live string) bool
+}
+
+// refreshInputs returns the inputs of the resource with the live values of its
+// outputs, so a refresh shows the changes made out of band, e.g. the memory of
+// a virtual machine changed in the ESXi UI. The inputs which are not outputs,
+// e.g. the source of a virtual machine, are kept.
+func refreshInputs(rules RefreshRules, inputs, outputs resource.PropertyMap) resource.PropertyMap {
+	refreshed := inputs.Copy()
+	for key, input := range inputs {
+		live, has := outputs[key]
+		if !has || Contains(rules.Unread, string(key)) {
+			continue
+		}
+		refreshed[key] = liveValue(input, live, rules.Equivalents[string(key)])
+	}
+	return refreshed
+}
+
+// liveValue returns the live value in place of the input, the objects keep the
+// keys of the input and the secrets stay secret. The input is kept when the
+// live value is unknown, or of another type.
+func liveValue(input, live resource.PropertyValue, equivalent func(input, live string) bool) resource.PropertyValue {
+	if input.IsSecret() {
+		return resource.MakeSecret(liveValue(input.SecretValue().Element, live, equivalent))
+	}
+	if live.IsSecret() {
+		live = live.SecretValue().Element
+	}
+
+	switch {
+	case live.IsNull() || live.ContainsUnknowns():
+		return input
+	case input.IsObject() && live.IsObject():
+		object := input.ObjectValue().Copy()
+		for key, value := range input.ObjectValue() {
+			if liveElement, has := live.ObjectValue()[key]; has {
+				object[key] = liveValue(value, liveElement, nil)
+			}
+		}
+		return resource.NewObjectProperty(object)
+	case input.IsArray() && live.IsArray():
+		elements := make([]resource.PropertyValue, len(live.ArrayValue()))
+		for i, element := range live.ArrayValue() {
+			if i < len(input.ArrayValue()) {
+				element = liveValue(input.ArrayValue()[i], element, nil)
+			}
+			elements[i] = element
+		}
+		return resource.NewArrayProperty(elements)
+	case input.IsString() && live.IsString():
+		if live.StringValue() == esxiUnknown || (equivalent != nil && equivalent(input.StringValue(), live.StringValue())) {
+			return input
+		}
+		return live
+	case input.IsBool() && live.IsString():
+		// Some booleans are read as strings.
+		if value, err := strconv.ParseBool(live.StringValue()); err == nil {
+			return resource.NewBoolProperty(value)
+		}
+		return input
+	case input.TypeString() != live.TypeString():
+		return input
+	}
+	return live
+}
+
+// samePoolName returns true for the names of the same resource pool, with or
+// without the leading slash.
+func samePoolName(input, live string) bool {
+	if input == "/" || live == "/" {
+		return input == live
+	}
+	return strings.TrimPrefix(input, "/") == strings.TrimPrefix(live, "/")
+}
@@ -0,0 +1,89 @@
+package esxi
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		inputs   resource.PropertyMap
+		outputs  resource.PropertyMap
+		expected resource.PropertyMap
+	}{
+		{
+			name:  "live values",
+			token: "esxi-native:index:VirtualMachine",
+			inputs: resource.PropertyMap{
+				"memSize":        resource.NewNumberProperty(512),
+				"ovfSource":      resource.NewStringProperty("/images/web.ova"),
+				"startupTimeout": resource.NewNumberProperty(60),
+			},
+			outputs: resource.PropertyMap{
+				"memSize":        resource.NewNumberProperty(1024),
+				"startupTimeout": resource.NewNumberProperty(vmDefaultStartupTimeout),
+				"ipAddress":      resource.NewStringProperty("192.168.20.101"),
+			},
+			expected: resource.PropertyMap{
+				"memSize":        resource.NewNumberProperty(1024),
+				"ovfSource":      resource.NewStringProperty("/images/web.ova"),
+				"startupTimeout": resource.NewNumberProperty(60),
+			},
+		},
+		{
+			name:  "objects keep the keys of the inputs",
+			token: "esxi-native:index:VirtualMachine",
+			inputs: resource.PropertyMap{
+				"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("VM Network")}),
+				}),
+			},
+			outputs: resource.PropertyMap{
+				"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{
+						"virtualNetwork": resource.NewStringProperty("Lab Network"),
+						"macAddress":     resource.NewStringProperty(""),
+					}),
+					resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("VM Network")}),
+				}),
+			},
+			expected: resource.PropertyMap{
+				"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("Lab Network")}),
+					resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("VM Network")}),
+				}),
+			},
+		},
+		{
+			name:     "equivalent names",
+			token:    "esxi-native:index:VirtualDisk",
+			inputs:   resource.PropertyMap{"name": resource.NewStringProperty("data"), "diskType": resource.NewStringProperty("thin")},
+			outputs:  resource.PropertyMap{"name": resource.NewStringProperty("data.vmdk"), "diskType": resource.NewStringProperty(esxiUnknown)},
+			expected: resource.PropertyMap{"name": resource.NewStringProperty("data"), "diskType": resource.NewStringProperty("thin")},
+		},
+		{
+			name:     "booleans read as strings",
+			token:    "esxi-native:index:PortGroup",
+			inputs:   resource.PropertyMap{"promiscuousMode": resource.NewBoolProperty(false)},
+			outputs:  resource.PropertyMap{"promiscuousMode": resource.NewStringProperty("true")},
+			expected: resource.PropertyMap{"promiscuousMode": resource.NewBoolProperty(true)},
+		},
+		{
+			name:     "secrets",
+			token:    "esxi-native:index:ResourcePool",
+			inputs:   resource.PropertyMap{"name": resource.MakeSecret(resource.NewStringProperty("/web"))},
+			outputs:  resource.PropertyMap{"name": resource.NewStringProperty("web"), "cpuMin": resource.NewNumberProperty(100)},
+			expected: resource.PropertyMap{"name": resource.MakeSecret(resource.NewStringProperty("/web"))},
+		},
+	}
+	service := NewResourceService()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, service.RefreshInputs(test.token, test.inputs, test.outputs))
+		})
+	}
+}
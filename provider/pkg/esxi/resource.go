@@ -0,0 +1,100 @@
+package esxi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// Resource is a resource type of the provider, the file implementing it
+// registers it with its type token.
+type Resource interface {
+	// Check applies the defaults of the provider config to the inputs which
+	// aren't set, and validates the inputs.
+	Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure
+	// Diff returns the changes of the inputs, and whether they replace the resource.
+	Diff(olds, news resource.PropertyMap) *pulumirpc.DiffResponse
+	// Create creates the resource and returns its id and its outputs.
+	Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error)
+	// Read returns the outputs of the resource, or a NotFoundError when it was
+	// deleted out of band.
+	Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error)
+	// Import reads the resource imported by its import id, and returns its id,
+	// its inputs and its outputs.
+	Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error)
+	// Refresh returns the inputs with the live values of the outputs.
+	Refresh(inputs, outputs resource.PropertyMap) resource.PropertyMap
+	// Update applies the changes of the inputs to the resource.
+	Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error)
+	// Delete deletes the resource.
+	Delete(ctx context.Context, id string, esxi *Host) error
+}
+
+// Function is a function of the provider, invoked with its type token.
+type Function interface {
+	Invoke(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error)
+}
+
+// FunctionFunc implements a Function with a function.
+type FunctionFunc func(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error)
+
+func (f FunctionFunc) Invoke(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
+	return f(ctx, inputs, esxi)
+}
+
+// Defaults are the inputs of the resources defaulted by the provider config,
+// the inputs set on a resource take priority over them.
+type Defaults struct {
+	DiskStore       string
+	ResourcePool    string
+	Network         string
+	Os              string
+	HardwareVersion int
+}
+
+// setDefault sets the input when it isn't set and the default is configured.
+func setDefault(inputs resource.PropertyMap, key resource.PropertyKey, value resource.PropertyValue, configured bool) {
+	if _, has := inputs[key]; !has && configured {
+		inputs[key] = value
+	}
+}
+
+var (
+	resources = map[string]Resource{}
+	functions = map[string]Function{}
+)
+
+// registerResource registers the resource type, it is called by the init
+// functions of the resource files.
+func registerResource(token string, r Resource) {
+	if _, ok := resources[token]; ok {
+		panic(fmt.Sprintf("resource '%s' registered twice", token))
+	}
+	resources[token] = r
+}
+
+// registerFunction registers the function, it is called by the init functions
+// of the resource files.
+func registerFunction(token string, f Function) {
+	if _, ok := functions[token]; ok {
+		panic(fmt.Sprintf("function '%s' registered twice", token))
+	}
+	functions[token] = f
+}
+
+// resourceRules implements the diff and the refresh of a resource from its
+// rules, the resources embed it.
+type resourceRules struct {
+	diff    DiffRules
+	refresh RefreshRules
+}
+
+func (r resourceRules) Diff(olds, news resource.PropertyMap) *pulumirpc.DiffResponse {
+	return diffInputs(r.diff, olds, news)
+}
+
+func (r resourceRules) Refresh(inputs, outputs resource.PropertyMap) resource.PropertyMap {
+	return refreshInputs(r.refresh, inputs, outputs)
+}
@@ -2,6 +2,7 @@ package esxi
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -9,8 +10,55 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
+const resourcePoolToken = "esxi-native:index:ResourcePool"
+
+func init() {
+	registerResource(resourcePoolToken, resourcePoolResource{resourceRules{
+		diff: DiffRules{
+			Replaces: []string{"name"},
+			Names:    []string{"name"},
+		},
+		refresh: RefreshRules{
+			Equivalents: map[string]func(input, live string) bool{"name": samePoolName},
+		},
+	}})
+}
+
+// resourcePoolResource implements the ResourcePool resource with the functions
+// below.
+type resourcePoolResource struct {
+	resourceRules
+}
+
+func (resourcePoolResource) Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure {
+	return schema.ValidateResourcePool(resourcePoolToken, inputs)
+}
+
+func (resourcePoolResource) Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return ResourcePoolCreate(ctx, inputs, esxi)
+}
+
+func (resourcePoolResource) Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return ResourcePoolRead(ctx, id, inputs, esxi)
+}
+
+func (resourcePoolResource) Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	return ResourcePoolImport(ctx, id, esxi)
+}
+
+func (resourcePoolResource) Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	return ResourcePoolUpdate(ctx, update, esxi)
+}
+
+func (resourcePoolResource) Delete(ctx context.Context, id string, esxi *Host) error {
+	return ResourcePoolDelete(ctx, id, esxi)
+}
+
 const (
 	rootPool       = "ha-root-pool"
 	basePool       = "Resources"
@@ -18,7 +66,7 @@ const (
 	uDigitsPattern = "[0-9]+"
 )
 
-func ResourcePoolCreate(inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func ResourcePoolCreate(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	var command string
 	rp := parseResourcePool("", inputs)
 	parentPool := basePool
@@ -28,120 +76,148 @@ func ResourcePoolCreate(inputs resource.PropertyMap, esxi *Host) (string, resour
 		parentPool = rp.Name[:i]
 		rp.Name = rp.Name[i+1:]
 	}
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.createResourcePool(ctx, parentPool, rp)
+	}
 
 	//  Check if already exists
-	stdout, _ := esxi.getResourcePoolId(rp.Name)
+	stdout, _ := esxi.getResourcePoolId(ctx, rp.Name)
 	if stdout != "" {
 		rp.Id = stdout
-		return esxi.readResourcePool(rp)
+		return esxi.readResourcePool(ctx, rp)
 	}
 
-	command = fmt.Sprintf("--cpu-min=%d", rp.CpuMin)
-	command = fmt.Sprintf("%s --cpu-min-expandable=%s", command, rp.CpuMinExpandable)
+	command = shellf("--cpu-min=%d", rp.CpuMin)
+	command += shellf(" --cpu-min-expandable=%s", rp.CpuMinExpandable)
 	if rp.CpuMax > 0 {
-		command = fmt.Sprintf("%s --cpu-max=%d", command, rp.CpuMax)
+		command += shellf(" --cpu-max=%d", rp.CpuMax)
 	}
 	if Contains([]string{"low", "normal", "high"}, rp.CpuShares) {
-		command = fmt.Sprintf("%s --cpu-shares=%s", command, rp.CpuShares)
+		command += shellf(" --cpu-shares=%s", rp.CpuShares)
 	} else {
 		shares, _ := strconv.Atoi(rp.CpuShares)
-		command = fmt.Sprintf("%s --cpu-shares=%d", command, shares)
+		command += shellf(" --cpu-shares=%d", shares)
 	}
-	command = fmt.Sprintf("%s --mem-min=%d", command, rp.MemMin)
-	command = fmt.Sprintf("%s --mem-min-expandable=%s", command, rp.MemMinExpandable)
+	command += shellf(" --mem-min=%d", rp.MemMin)
+	command += shellf(" --mem-min-expandable=%s", rp.MemMinExpandable)
 	if rp.MemMax > 0 {
-		command = fmt.Sprintf("%s --mem-max=%d", command, rp.MemMax)
+		command += shellf(" --mem-max=%d", rp.MemMax)
 	}
 	if Contains([]string{"low", "normal", "high"}, rp.MemShares) {
-		command = fmt.Sprintf("%s --mem-shares=%s", command, rp.MemShares)
+		command += shellf(" --mem-shares=%s", rp.MemShares)
 	} else {
 		shares, _ := strconv.Atoi(rp.MemShares)
-		command = fmt.Sprintf("%s --mem-shares=%d", command, shares)
+		command += shellf(" --mem-shares=%d", shares)
 	}
 
-	parentPoolId, err := esxi.getResourcePoolId(parentPool)
+	parentPoolId, err := esxi.getResourcePoolId(ctx, parentPool)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get parent pool id: %w", err)
 	}
 
-	command = fmt.Sprintf("%s %s %s", command, parentPoolId, rp.Name)
-	command = fmt.Sprintf("vim-cmd hostsvc/rsrc/create %s", command)
+	command = shellf("vim-cmd hostsvc/rsrc/create %s %s %s", shellRaw(command), parentPoolId, rp.Name)
 
-	stdout, err = esxi.Execute(command, "create resource pool")
+	stdout, err = esxi.Execute(ctx, command, "create resource pool")
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create resource pool %s: %w", stdout, err)
 	}
 
-	id, err := esxi.getResourcePoolId(rp.Name)
+	id, err := esxi.getResourcePoolId(ctx, rp.Name)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get resource pool %s: %w", id, err)
 	}
 
 	rp.Id = id
-	return esxi.readResourcePool(rp)
+	return esxi.readResourcePool(ctx, rp)
 }
 
-func ResourcePoolUpdate(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func ResourcePoolUpdate(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
 	var command string
-	rp := parseResourcePool(id, inputs)
+	rp := parseResourcePool(update.Id, update.NewInputs)
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.updateResourcePool(ctx, rp)
+	}
 
-	stdout, err := esxi.getResourcePoolName(rp.Id)
+	stdout, err := esxi.getResourcePoolName(ctx, rp.Id)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get resource pool name: %w", err)
 	}
 	if stdout != rp.Name {
-		command = fmt.Sprintf("vim-cmd hostsvc/rsrc/rename %s %s", rp.Id, rp.Name)
-		_, err = esxi.Execute(command, "update resource pool")
+		command = shellf("vim-cmd hostsvc/rsrc/rename %s %s", rp.Id, rp.Name)
+		_, err = esxi.ExecuteWithRetry(ctx, command, "update resource pool")
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to update resource pool: %w", err)
 		}
 	}
 
+	// The pool config is only set when one of its values changed.
+	if !update.Changed("cpuMin", "cpuMinExpandable", "cpuMax", "cpuShares", "memMin", "memMinExpandable", "memMax", "memShares") {
+		return esxi.readResourcePool(ctx, rp)
+	}
+
 	command = ""
 	if rp.CpuMin > 0 {
-		command = fmt.Sprintf("--cpu-min=%d", rp.CpuMin)
+		command = shellf("--cpu-min=%d", rp.CpuMin)
 	}
-	command = fmt.Sprintf("%s --cpu-min-expandable=%s", command, rp.CpuMinExpandable)
+	command += shellf(" --cpu-min-expandable=%s", rp.CpuMinExpandable)
 	if rp.CpuMax > 0 {
-		command = fmt.Sprintf("%s --cpu-max=%d", command, rp.CpuMax)
+		command += shellf(" --cpu-max=%d", rp.CpuMax)
 	}
 	if Contains([]string{"low", "normal", "high"}, rp.CpuShares) {
-		command = fmt.Sprintf("%s --cpu-shares=%s", command, rp.CpuShares)
+		command += shellf(" --cpu-shares=%s", rp.CpuShares)
 	} else {
 		shares, _ := strconv.Atoi(rp.CpuShares)
-		command = fmt.Sprintf("%s --cpu-shares=%d", command, shares)
+		command += shellf(" --cpu-shares=%d", shares)
 	}
 	if rp.MemMin > 0 {
-		command = fmt.Sprintf("%s --mem-min=%d", command, rp.MemMin)
+		command += shellf(" --mem-min=%d", rp.MemMin)
 	}
-	command = fmt.Sprintf("%s --mem-min-expandable=%s", command, rp.MemMinExpandable)
+	command += shellf(" --mem-min-expandable=%s", rp.MemMinExpandable)
 	if rp.MemMax > 0 {
-		command = fmt.Sprintf("%s --mem-max=%d", command, rp.MemMax)
+		command += shellf(" --mem-max=%d", rp.MemMax)
 	}
 	if Contains([]string{"low", "normal", "high"}, rp.MemShares) {
-		command = fmt.Sprintf("%s --mem-shares=%s", command, rp.MemShares)
+		command += shellf(" --mem-shares=%s", rp.MemShares)
 	} else {
 		shares, _ := strconv.Atoi(rp.MemShares)
-		command = fmt.Sprintf("%s --mem-shares=%d", command, shares)
+		command += shellf(" --mem-shares=%d", shares)
 	}
 
-	command = fmt.Sprintf("%s %s", command, rp.Id)
-	command = fmt.Sprintf("vim-cmd hostsvc/rsrc/pool_config_set %s", command)
+	command = shellf("vim-cmd hostsvc/rsrc/pool_config_set %s %s", shellRaw(command), rp.Id)
 
-	stdout, err = esxi.Execute(command, "update resource pool")
+	stdout, err = esxi.ExecuteWithRetry(ctx, command, "update resource pool")
 	r := strings.NewReplacer("'vim.ResourcePool:", "", "'", "")
 	stdout = r.Replace(stdout)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to update resource pool %s: %w", stdout, err)
 	}
 
-	return esxi.readResourcePool(rp)
+	return esxi.readResourcePool(ctx, rp)
 }
 
-func ResourcePoolDelete(id string, esxi *Host) error {
-	command := fmt.Sprintf("vim-cmd hostsvc/rsrc/destroy %s", id)
+func ResourcePoolDelete(ctx context.Context, id string, esxi *Host) error {
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.deleteResourcePool(ctx, id)
+	}
 
-	stdout, err := esxi.Execute(command, "delete resource pool")
+	command := shellf("vim-cmd hostsvc/rsrc/destroy %s", id)
+
+	stdout, err := esxi.Execute(ctx, command, "delete resource pool")
 	if err != nil {
 		return fmt.Errorf("failed to delete resource pool: %s err: %w", stdout, err)
 	}
@@ -149,9 +225,43 @@ func ResourcePoolDelete(id string, esxi *Host) error {
 	return nil
 }
 
-func ResourcePoolRead(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func ResourcePoolRead(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	rp := parseResourcePool(id, inputs)
-	return esxi.readResourcePool(rp)
+	if esxi.api != nil {
+		return esxi.api.readResourcePool(ctx, rp)
+	}
+	return esxi.readResourcePool(ctx, rp)
+}
+
+// ResourcePoolImport reads the resource pool imported by its path, e.g.
+// parent/child.
+func ResourcePoolImport(ctx context.Context, path string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	var id string
+	var err error
+	if esxi.api != nil {
+		id, err = esxi.api.resourcePoolId(ctx, path)
+		err = notFoundOrError("resource pool", path, "", err)
+	} else {
+		id, err = esxi.getResourcePoolId(ctx, path)
+		if exitedWith(err, 1) || (err == nil && id == "") {
+			err = &NotFoundError{Kind: "resource pool", Id: path}
+		}
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	id, outputs, err := ResourcePoolRead(ctx, id, resource.PropertyMap{"name": resource.NewStringProperty(path)}, esxi)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	// The pools are looked up by their names, the path tells apart the pools of the same name.
+	if !samePoolName(path, outputs["name"].StringValue()) {
+		return "", nil, nil, &NotFoundError{Kind: "resource pool", Id: path}
+	}
+	inputs := importedInputs(outputs, "name", "cpuMin", "cpuMinExpandable", "cpuMax", "cpuShares",
+		"memMin", "memMinExpandable", "memMax", "memShares")
+	return id, inputs, outputs, nil
 }
 
 func parseResourcePool(id string, inputs resource.PropertyMap) ResourcePool {
@@ -213,8 +323,8 @@ func parseResourcePool(id string, inputs resource.PropertyMap) ResourcePool {
 	return rp
 }
 
-func (esxi *Host) readResourcePool(rp ResourcePool) (string, resource.PropertyMap, error) {
-	rp, err := esxi.getResourcePoolDetails(rp)
+func (esxi *Host) readResourcePool(ctx context.Context, rp ResourcePool) (string, resource.PropertyMap, error) {
+	rp, err := esxi.getResourcePoolDetails(ctx, rp)
 	if err != nil {
 		return "", nil, err
 	}
@@ -223,7 +333,7 @@ func (esxi *Host) readResourcePool(rp ResourcePool) (string, resource.PropertyMa
 	return rp.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func (esxi *Host) getResourcePoolId(name string) (string, error) {
+func (esxi *Host) getResourcePoolId(ctx context.Context, name string) (string, error) {
 	if name == "/" || name == basePool {
 		return rootPool, nil
 	}
@@ -232,8 +342,8 @@ func (esxi *Host) getResourcePoolId(name string) (string, error) {
 	name = result[len(result)-1]
 
 	r := strings.NewReplacer("objID>", "", "</objID", "")
-	command := fmt.Sprintf("grep -A1 '<name>%s</name>' /etc/vmware/hostd/pools.xml | grep -m 1 -o objID.*objID", name)
-	stdout, err := esxi.Execute(command, "get existing resource pool id")
+	command := shellf("grep -A1 -e %s /etc/vmware/hostd/pools.xml | grep -m 1 -o objID.*objID", "<name>"+grepQuote(name)+"</name>")
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "get existing resource pool id")
 	if err != nil {
 		logging.V(logLevel).Infof("getResourcePoolName: Failed get existing resource pool id => %s", stdout)
 		return "", fmt.Errorf("failed to get existing resource pool id: %w", err)
@@ -243,7 +353,7 @@ func (esxi *Host) getResourcePoolId(name string) (string, error) {
 	}
 }
 
-func (esxi *Host) getResourcePoolName(id string) (string, error) {
+func (esxi *Host) getResourcePoolName(ctx context.Context, id string) (string, error) {
 	var resourcePoolName, fullResourcePoolName string
 
 	fullResourcePoolName = ""
@@ -253,8 +363,8 @@ func (esxi *Host) getResourcePoolName(id string) (string, error) {
 	}
 
 	// Get full Resource Pool Path
-	command := fmt.Sprintf("grep -A1 '<objID>%s</objID>' /etc/vmware/hostd/pools.xml | grep '<path>'", id)
-	stdout, err := esxi.Execute(command, "get resource pool path")
+	command := shellf("grep -A1 -e %s /etc/vmware/hostd/pools.xml | grep '<path>'", "<objID>"+grepQuote(id)+"</objID>")
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "get resource pool path")
 	if err != nil {
 		logging.V(logLevel).Infof("getResourcePoolName: Failed get resource pool PATH => %s", stdout)
 		return "", fmt.Errorf("failed to get pool path: %w", err)
@@ -266,8 +376,8 @@ func (esxi *Host) getResourcePoolName(id string) (string, error) {
 	for i := range result {
 		if result[i] != "path" && result[i] != "host" && result[i] != "user" && result[i] != "" {
 			r := strings.NewReplacer("name>", "", "</name", "")
-			command = fmt.Sprintf("grep -B1 '<objID>%s</objID>' /etc/vmware/hostd/pools.xml | grep -o name.*name", result[i])
-			stdout, _ = esxi.Execute(command, "get resource pool name")
+			command = shellf("grep -B1 -e %s /etc/vmware/hostd/pools.xml | grep -o name.*name", "<objID>"+grepQuote(result[i])+"</objID>")
+			stdout, _ = esxi.ExecuteWithRetry(ctx, command, "get resource pool name")
 			resourcePoolName = r.Replace(stdout)
 
 			if resourcePoolName != "" {
@@ -283,12 +393,12 @@ func (esxi *Host) getResourcePoolName(id string) (string, error) {
 	return fullResourcePoolName, nil
 }
 
-func (esxi *Host) getResourcePoolDetails(rp ResourcePool) (ResourcePool, error) {
+func (esxi *Host) getResourcePoolDetails(ctx context.Context, rp ResourcePool) (ResourcePool, error) {
 	// Get full Resource Pool Path
-	command := fmt.Sprintf("vim-cmd hostsvc/rsrc/pool_config_get %s", rp.Id)
-	stdout, err := esxi.Execute(command, "get resource pool config")
+	command := shellf("vim-cmd hostsvc/rsrc/pool_config_get %s", rp.Id)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "get resource pool config")
 	if strings.Contains(stdout, "deleted") {
-		return rp, err
+		return rp, &NotFoundError{Kind: "resource pool", Id: rp.Id}
 	}
 	if err != nil {
 		return rp, fmt.Errorf("failed to get resource pool config: %w", err)
@@ -351,7 +461,7 @@ func (esxi *Host) getResourcePoolDetails(rp ResourcePool) (ResourcePool, error)
 		}
 	}
 
-	rp.Name, err = esxi.getResourcePoolName(rp.Id)
+	rp.Name, err = esxi.getResourcePoolName(ctx, rp.Id)
 	if err != nil {
 		return rp, fmt.Errorf("failed to get pool name: %w", err)
 	}
@@ -0,0 +1,58 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcePoolLifecycle(t *testing.T) {
+	esxi, _ := newFakeHost(t)
+	ctx := context.Background()
+
+	parentId, result, err := ResourcePoolCreate(ctx, resource.PropertyMap{
+		"name":      resource.NewStringProperty("pool-parent"),
+		"cpuShares": resource.NewStringProperty("high"),
+		"memMax":    resource.NewNumberProperty(4096),
+	}, esxi)
+	require.NoError(t, err)
+	require.NotEmpty(t, parentId)
+	require.Equal(t, "pool-parent", result["name"].StringValue())
+	require.Equal(t, "high", result["cpuShares"].StringValue())
+	require.Equal(t, 100.0, result["cpuMin"].NumberValue())
+	require.Equal(t, 4096.0, result["memMax"].NumberValue())
+
+	childInputs := resource.PropertyMap{
+		"name":      resource.NewStringProperty("pool-parent/pool-child"),
+		"memShares": resource.NewStringProperty("1000"),
+	}
+	childId, result, err := ResourcePoolCreate(ctx, childInputs, esxi)
+	require.NoError(t, err)
+	require.NotEqual(t, parentId, childId)
+	require.Equal(t, "pool-parent/pool-child", result["name"].StringValue())
+	require.Equal(t, "1000", result["memShares"].StringValue())
+
+	childInputs["cpuMin"] = resource.NewNumberProperty(500)
+	_, result, err = ResourcePoolUpdate(ctx, newResourceUpdate(childId, childInputs), esxi)
+	require.NoError(t, err)
+	require.Equal(t, 500.0, result["cpuMin"].NumberValue())
+
+	_, result, err = ResourcePoolUpdate(ctx, newResourceUpdate(parentId, resource.PropertyMap{
+		"name": resource.NewStringProperty("pool-renamed"),
+	}), esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pool-renamed", result["name"].StringValue())
+	require.Equal(t, "normal", result["cpuShares"].StringValue())
+
+	_, result, err = ResourcePoolRead(ctx, childId, childInputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "pool-renamed/pool-child", result["name"].StringValue())
+
+	require.NoError(t, ResourcePoolDelete(ctx, childId, esxi))
+	require.NoError(t, ResourcePoolDelete(ctx, parentId, esxi))
+	require.Error(t, ResourcePoolDelete(ctx, parentId, esxi))
+	_, _, err = ResourcePoolRead(ctx, childId, childInputs, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
@@ -1,145 +1,153 @@
 package esxi
 
 import (
+	"context"
 	"fmt"
-	"reflect"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
-
-	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
-type functionsMapper map[string]interface{}
-
+// ResourceService dispatches the operations of the provider to the resources
+// and functions registered with their type token.
 type ResourceService struct {
-	functions functionsMapper
+	resources map[string]Resource
+	functions map[string]Function
 }
 
 func NewResourceService() *ResourceService {
-	return &ResourceService{
-		functionsMapper{
-			"esxi-native:index:PortGroup:Create":        PortGroupCreate,
-			"esxi-native:index:PortGroup:Update":        PortGroupUpdate,
-			"esxi-native:index:PortGroup:Delete":        PortGroupDelete,
-			"esxi-native:index:PortGroup:Read":          PortGroupRead,
-			"esxi-native:index:ResourcePool:Create":     ResourcePoolCreate,
-			"esxi-native:index:ResourcePool:Update":     ResourcePoolUpdate,
-			"esxi-native:index:ResourcePool:Delete":     ResourcePoolDelete,
-			"esxi-native:index:ResourcePool:Read":       ResourcePoolRead,
-			"esxi-native:index:VirtualDisk:Create":      VirtualDiskCreate,
-			"esxi-native:index:VirtualDisk:Update":      VirtualDiskUpdate,
-			"esxi-native:index:VirtualDisk:Delete":      VirtualDiskDelete,
-			"esxi-native:index:VirtualDisk:Read":        VirtualDiskRead,
-			"esxi-native:index:VirtualMachine:Create":   VirtualMachineCreate,
-			"esxi-native:index:VirtualMachine:Update":   VirtualMachineUpdate,
-			"esxi-native:index:VirtualMachine:Delete":   VirtualMachineDelete,
-			"esxi-native:index:VirtualMachine:Read":     VirtualMachineRead,
-			"esxi-native:index:getVirtualMachine":       VirtualMachineGet,
-			"esxi-native:index:getVirtualMachineById":   VirtualMachineGet,
-			"esxi-native:index:VirtualSwitch:Create":    VirtualSwitchCreate,
-			"esxi-native:index:VirtualSwitch:Update":    VirtualSwitchUpdate,
-			"esxi-native:index:VirtualSwitch:Delete":    VirtualSwitchDelete,
-			"esxi-native:index:VirtualSwitch:Read":      VirtualSwitchRead,
-			"esxi-native:index:PortGroup:Validate":      schema.ValidatePortGroup,
-			"esxi-native:index:ResourcePool:Validate":   schema.ValidateResourcePool,
-			"esxi-native:index:VirtualDisk:Validate":    schema.ValidateVirtualDisk,
-			"esxi-native:index:VirtualMachine:Validate": schema.ValidateVirtualMachine,
-			"esxi-native:index:VirtualSwitch:Validate":  schema.ValidateVirtualSwitch,
-		},
-	}
+	return &ResourceService{resources: resources, functions: functions}
 }
 
-func (receiver *ResourceService) Validate(token string, inputs resource.PropertyMap) ([]*pulumirpc.CheckFailure, error) {
-	handler, ok := receiver.functions[fmt.Sprintf("%s:Validate", token)]
+func (receiver *ResourceService) resource(token string) (Resource, error) {
+	r, ok := receiver.resources[token]
 	if !ok {
 		return nil, fmt.Errorf("unknown operation '%s'", token)
 	}
-	params := []reflect.Value{reflect.ValueOf(token), reflect.ValueOf(inputs)}
+	return r, nil
+}
 
-	functionHandler := reflect.ValueOf(handler)
-	functionResult := functionHandler.Call(params)
-	result := functionResult[0].Interface().([]*pulumirpc.CheckFailure)
-	return result, nil
+// Check applies the defaults of the provider config to the inputs of the
+// resource, and validates them.
+func (receiver *ResourceService) Check(token string, inputs resource.PropertyMap, defaults Defaults) ([]*pulumirpc.CheckFailure, error) {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return nil, err
+	}
+	return r.Check(inputs, defaults), nil
+}
+
+// Diff returns the changes of the inputs of the resource, with the detailed
+// diff of every changed input and whether it replaces the resource.
+func (receiver *ResourceService) Diff(token string, olds, news resource.PropertyMap) (*pulumirpc.DiffResponse, error) {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return nil, err
+	}
+	return r.Diff(olds, news), nil
 }
 
-func (receiver *ResourceService) Invoke(token string, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
-	handler, ok := receiver.functions[token]
+// RefreshInputs returns the inputs of the resource with the live values of its
+// outputs, the inputs of an unknown resource are kept.
+func (receiver *ResourceService) RefreshInputs(token string, inputs, outputs resource.PropertyMap) resource.PropertyMap {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return inputs
+	}
+	return r.Refresh(inputs, outputs)
+}
+
+func (receiver *ResourceService) Invoke(ctx context.Context, token string, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
+	f, ok := receiver.functions[token]
 	if !ok {
 		return nil, fmt.Errorf("unknown function '%s'", token)
 	}
-	params := []reflect.Value{
-		reflect.ValueOf(inputs),
-		reflect.ValueOf(esxi),
-	}
 
-	functionHandler := reflect.ValueOf(handler)
-	functionResult := functionHandler.Call(params)
-	result := functionResult[0].Interface().(resource.PropertyMap)
-	err := functionResult[1].Interface()
+	esxi.addSecretInputs(inputs)
+	result, err := f.Invoke(ctx, inputs, esxi)
 	if err != nil {
-		return result, err.(error)
+		return result, esxi.redactError(err)
 	}
 	return result, nil
 }
 
-func (receiver *ResourceService) Create(token string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	token = fmt.Sprintf("%s:Create", token)
-	return receiver.call(token, "", inputs, esxi)
-}
-
-func (receiver *ResourceService) Update(token string, id string, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
-	token = fmt.Sprintf("%s:Update", token)
-	_, outputs, err := receiver.call(token, id, inputs, esxi)
-	return outputs, err
-}
+func (receiver *ResourceService) Create(ctx context.Context, token string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return "", nil, err
+	}
+	operation := fmt.Sprintf("%s:Create", token)
+	if err = esxi.beginChange(operation); err != nil {
+		return "", nil, err
+	}
 
-func (receiver *ResourceService) Read(token string, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	token = fmt.Sprintf("%s:Read", token)
-	return receiver.call(token, id, inputs, esxi)
+	esxi.addSecretInputs(inputs)
+	id, outputs, err := r.Create(ctx, inputs, esxi)
+	if err = esxi.endChange(operation, esxi.redactError(err)); err != nil {
+		return "", nil, err
+	}
+	return id, outputs, nil
 }
 
-func (receiver *ResourceService) Delete(token string, id string, esxi *Host) error {
-	token = fmt.Sprintf("%s:Delete", token)
-	handler, ok := receiver.functions[token]
-	if !ok {
-		return fmt.Errorf("unknown operation '%s'", token)
+// Update applies the update to the resource, which only changes the inputs
+// differing from the old ones.
+func (receiver *ResourceService) Update(ctx context.Context, token string, update ResourceUpdate, esxi *Host) (resource.PropertyMap, error) {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return nil, err
+	}
+	operation := fmt.Sprintf("%s:Update", token)
+	if err = esxi.beginChange(operation); err != nil {
+		return nil, err
 	}
 
-	params := []reflect.Value{
-		reflect.ValueOf(id), reflect.ValueOf(esxi),
+	esxi.addSecretInputs(update.NewInputs)
+	_, outputs, err := r.Update(ctx, update, esxi)
+	if err = esxi.endChange(operation, esxi.redactError(err)); err != nil {
+		return nil, err
 	}
+	return outputs, nil
+}
 
-	functionHandler := reflect.ValueOf(handler)
-	functionResult := functionHandler.Call(params)
-	err := functionResult[0].Interface()
+func (receiver *ResourceService) Read(ctx context.Context, token string, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	r, err := receiver.resource(token)
 	if err != nil {
-		return err.(error)
+		return "", nil, err
 	}
 
-	return nil
+	esxi.addSecretInputs(inputs)
+	resourceId, outputs, err := r.Read(ctx, id, inputs, esxi)
+	if err != nil {
+		return resourceId, outputs, esxi.redactError(err)
+	}
+	return resourceId, outputs, nil
 }
 
-func (receiver *ResourceService) call(token string, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	handler, ok := receiver.functions[token]
-	if !ok {
-		return "", nil, fmt.Errorf("unknown operation '%s'", token)
+func (receiver *ResourceService) Delete(ctx context.Context, token string, id string, esxi *Host) error {
+	r, err := receiver.resource(token)
+	if err != nil {
+		return err
 	}
-	var params []reflect.Value
-	if len(id) > 0 {
-		params = []reflect.Value{reflect.ValueOf(id), reflect.ValueOf(inputs), reflect.ValueOf(esxi)}
-	} else {
-		params = []reflect.Value{reflect.ValueOf(inputs), reflect.ValueOf(esxi)}
+	operation := fmt.Sprintf("%s:Delete", token)
+	if err = esxi.beginChange(operation); err != nil {
+		return err
 	}
 
-	functionHandler := reflect.ValueOf(handler)
-	functionResult := functionHandler.Call(params)
-	resourceId := functionResult[0].Interface().(string)
-	resourceData := functionResult[1].Interface().(resource.PropertyMap)
-	err := functionResult[2].Interface()
+	err = r.Delete(ctx, id, esxi)
+	return esxi.endChange(operation, esxi.redactError(err))
+}
+
+// Import reads the resource imported by its id, with its inputs read from the
+// host in place of the inputs of a program.
+func (receiver *ResourceService) Import(ctx context.Context, token string, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	r, err := receiver.resource(token)
 	if err != nil {
-		return resourceId, resourceData, err.(error)
+		return "", nil, nil, err
 	}
 
-	return resourceId, resourceData, nil
+	resourceId, inputs, outputs, err := r.Import(ctx, id, esxi)
+	if err != nil {
+		return resourceId, inputs, outputs, esxi.redactError(err)
+	}
+	return resourceId, inputs, outputs, nil
 }
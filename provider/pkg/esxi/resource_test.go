@@ -0,0 +1,58 @@
+package esxi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryMatchesSchema(t *testing.T) {
+	data, err := os.ReadFile("../../cmd/pulumi-resource-esxi-native/schema.json")
+	require.NoError(t, err)
+	var spec struct {
+		Resources map[string]json.RawMessage `json:"resources"`
+		Functions map[string]json.RawMessage `json:"functions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &spec))
+
+	tokens := func(m map[string]json.RawMessage) []string {
+		var keys []string
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	var registered []string
+	for token := range resources {
+		registered = append(registered, token)
+	}
+	sort.Strings(registered)
+	require.Equal(t, tokens(spec.Resources), registered)
+
+	registered = nil
+	for token := range functions {
+		registered = append(registered, token)
+	}
+	sort.Strings(registered)
+	require.Equal(t, tokens(spec.Functions), registered)
+}
+
+func TestResourceServiceUnknownToken(t *testing.T) {
+	service := NewResourceService()
+	ctx := context.Background()
+	inputs := resource.PropertyMap{"name": resource.NewStringProperty("web")}
+
+	_, err := service.Check("esxi-native:index:Unknown", inputs, Defaults{})
+	require.ErrorContains(t, err, "unknown operation 'esxi-native:index:Unknown'")
+	_, _, err = service.Create(ctx, "esxi-native:index:Unknown", inputs, nil)
+	require.ErrorContains(t, err, "unknown operation 'esxi-native:index:Unknown'")
+	_, err = service.Invoke(ctx, "esxi-native:index:getUnknown", inputs, nil)
+	require.ErrorContains(t, err, "unknown function 'esxi-native:index:getUnknown'")
+	require.Equal(t, inputs, service.RefreshInputs("esxi-native:index:Unknown", inputs, resource.PropertyMap{}))
+}
@@ -0,0 +1,194 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// ErrorClass tells how a failed remote operation is handled.
+type ErrorClass int
+
+const (
+	// ErrorFatal failures are returned as is, running the operation again won't help.
+	ErrorFatal ErrorClass = iota
+	// ErrorTransient failures are expected to go away, e.g. hostd is busy or restarting.
+	ErrorTransient
+	// ErrorNotFound failures report a missing object.
+	ErrorNotFound
+	// ErrorAlreadyExists failures report an object created already.
+	ErrorAlreadyExists
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorTransient:
+		return "transient"
+	case ErrorNotFound:
+		return "not found"
+	case ErrorAlreadyExists:
+		return "already exists"
+	default:
+		return "fatal"
+	}
+}
+
+var errManagementAgentRestarted = errors.New("failed to connect to esxi host or Management Agent has been restarted")
+
+// The outputs of the esxi tools, in lower case, by class of failure.
+var (
+	alreadyExistsOutputs = []string{"already exists", "already exist", "duplicate"}
+	notFoundOutputs      = []string{"not found", "does not exist", "doesn't exist", "no such file", "unable to find", "invalid vmid"}
+	transientOutputs     = []string{
+		"management agent has been restarted",
+		"hostd is busy",
+		"resource busy",
+		"temporarily unavailable",
+		"failed to lock",
+		"it is locked",
+		"lock was not free",
+		"another task is already in progress",
+		"connection reset",
+		"connection refused",
+		"broken pipe",
+	}
+)
+
+// ClassifyError classifies the failure of a remote operation from its error
+// and its output. The cancellations, the authentication and host key errors
+// and the exhausted connection attempts are fatal, the network errors are transient.
+func ClassifyError(output string, err error) ErrorClass {
+	if err == nil {
+		return ErrorFatal
+	}
+
+	var mismatchErr *HostKeyMismatchError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrorFatal
+	case errors.As(err, &mismatchErr), errors.Is(err, errPoolClosed), errors.Is(err, errBastionClosed), errors.Is(err, errNoExecutor):
+		return ErrorFatal
+	case errors.Is(err, errConnection):
+		// The connection attempts were retried already.
+		return ErrorFatal
+	}
+
+	message := strings.ToLower(output + "\n" + err.Error())
+	switch {
+	case strings.Contains(message, "unable to authenticate"):
+		return ErrorFatal
+	case containsAny(message, alreadyExistsOutputs):
+		return ErrorAlreadyExists
+	case containsAny(message, notFoundOutputs):
+		return ErrorNotFound
+	case containsAny(message, transientOutputs), errors.Is(err, errManagementAgentRestarted), errors.Is(err, io.EOF):
+		return ErrorTransient
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorTransient
+	}
+	return ErrorFatal
+}
+
+func containsAny(message string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryPolicy runs the failed operations again with an exponential backoff and jitter.
+type RetryPolicy struct {
+	// Attempts is the number of runs of the operation, including the first one.
+	Attempts int
+	// InitialDelay is the delay before the first retry, it doubles on every retry.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between two retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries for about a minute, which lets hostd restart.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:     6,
+	InitialDelay: time.Second,
+	MaxDelay:     30 * time.Second,
+}
+
+// withDefaults returns the policy with the default values in place of the unset ones.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Attempts <= 0 {
+		p.Attempts = DefaultRetryPolicy.Attempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultRetryPolicy.InitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if p.MaxDelay < p.InitialDelay {
+		p.MaxDelay = p.InitialDelay
+	}
+	return p
+}
+
+// delay returns the backoff before the retry, in [d/2, d] where d doubles on
+// every retry, so concurrent operations don't retry in lockstep.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.InitialDelay
+	for i := 0; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2 //nolint:gomnd // half of the delay is jittered
+
+	return half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec // no need for a secure random number
+}
+
+// retry runs fn until it succeeds, its failure is not of one of the retried
+// classes, or the attempts of the policy are exhausted.
+func retry[T any](ctx context.Context, policy RetryPolicy, sleep func(context.Context, time.Duration) error,
+	desc string, fn func() (T, string, error), retried ...ErrorClass) (T, error) {
+	policy = policy.withDefaults()
+	if len(retried) == 0 {
+		retried = []ErrorClass{ErrorTransient}
+	}
+
+	for attempt := 1; ; attempt++ {
+		value, output, err := fn()
+		if err == nil {
+			return value, nil
+		}
+		class := ClassifyError(output, err)
+		if attempt >= policy.Attempts || !containsClass(retried, class) {
+			return value, err
+		}
+
+		delay := policy.delay(attempt - 1)
+		logging.V(logLevel).Infof("retry: %s failed with a %s error, attempt %d of %d in %s: %s",
+			desc, class, attempt+1, policy.Attempts, delay, err)
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return value, err
+		}
+	}
+}
+
+func containsClass(classes []ErrorClass, class ErrorClass) bool {
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	exitErr := &FakeExitError{Status: 1}
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		class  ErrorClass
+	}{
+		{name: "success"},
+		{name: "exit status", err: exitErr, class: ErrorFatal},
+		{name: "hostd busy", output: "hostd is busy", err: exitErr, class: ErrorTransient},
+		{name: "agent restarted", output: errManagementAgentRestarted.Error(), err: errManagementAgentRestarted, class: ErrorTransient},
+		{name: "locked file", output: "Failed to lock the file", err: exitErr, class: ErrorTransient},
+		{name: "eof", err: io.EOF, class: ErrorTransient},
+		{name: "network", err: &net.OpError{Op: "dial", Err: errors.New("i/o timeout")}, class: ErrorTransient},
+		{name: "not found", output: "Unable to find a VM corresponding to \"42\"", err: exitErr, class: ErrorNotFound},
+		{name: "missing file", output: "ls: /vmfs/volumes/missing: No such file or directory", err: exitErr, class: ErrorNotFound},
+		{name: "already exists", output: "A portgroup with the name pg already exists", err: exitErr, class: ErrorAlreadyExists},
+		{name: "authentication", err: errors.New("ssh: handshake failed: ssh: unable to authenticate"), class: ErrorFatal},
+		{name: "host key", err: &HostKeyMismatchError{Host: "esxi"}, class: ErrorFatal},
+		{name: "cancelled", output: "hostd is busy", err: context.Canceled, class: ErrorFatal},
+		{name: "connection retried", err: fmt.Errorf("%w: %w", errConnection, io.EOF), class: ErrorFatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.class, ClassifyError(tt.output, tt.err))
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Attempts: 5, InitialDelay: time.Second, MaxDelay: 8 * time.Second}.withDefaults()
+	for retry, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		for i := 0; i < 20; i++ {
+			delay := policy.delay(retry)
+			require.GreaterOrEqual(t, delay, expected/2)
+			require.LessOrEqual(t, delay, expected)
+		}
+	}
+
+	require.Equal(t, DefaultRetryPolicy, RetryPolicy{}.withDefaults())
+}
+
+func TestExecuteWithRetry(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	esxi.Connection.Retry = RetryPolicy{Attempts: 3}
+	var delays []time.Duration
+	esxi.sleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return ctx.Err()
+	}
+	ctx := context.Background()
+
+	failures := 2
+	fake.Handle(`^esxcli network vswitch standard list`, func([]string) (string, error) {
+		if failures > 0 {
+			failures--
+			return "hostd is busy", &FakeExitError{Status: 1}
+		}
+		return "Name: vSwitch0", nil
+	})
+	stdout, err := esxi.ExecuteWithRetry(ctx, "esxcli network vswitch standard list", "vswitch list")
+	require.NoError(t, err)
+	require.Equal(t, "Name: vSwitch0", stdout)
+	require.Len(t, delays, 2)
+
+	// The attempts are exhausted.
+	failures = 5
+	delays = nil
+	_, err = esxi.ExecuteWithRetry(ctx, "esxcli network vswitch standard list", "vswitch list")
+	require.Error(t, err)
+	require.Len(t, delays, 2)
+
+	// The fatal errors are not retried, the not found ones only when asked.
+	delays = nil
+	_, err = esxi.ExecuteWithRetry(ctx, `ls -d "/vmfs/volumes/missing"`, "missing directory")
+	require.Error(t, err)
+	require.Empty(t, delays)
+	_, err = esxi.ExecuteWithRetry(ctx, `ls -d "/vmfs/volumes/missing"`, "missing directory", ErrorNotFound)
+	require.Error(t, err)
+	require.Len(t, delays, 2)
+}
+
+func TestCreateIsNotRetried(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	esxi.sleep = func(context.Context, time.Duration) error {
+		t.Fatal("the create command was retried")
+		return nil
+	}
+	fake.Handle(`^esxcli network vswitch standard add`, func([]string) (string, error) {
+		return "hostd is busy", &FakeExitError{Status: 1}
+	})
+
+	_, _, err := VirtualSwitchCreate(context.Background(), resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1")}, esxi)
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package esxi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// shellRaw is a trusted fragment of a command line, e.g. options built by the
+// provider, which shellf inserts without quoting.
+type shellRaw string
+
+// shellf formats a command line for the shell of the esxi host. Every string
+// argument is quoted as a single shell word, so names and paths from the
+// resource inputs are passed as is to the program and can't change the
+// command. The format must not quote the %s verbs itself, the shellRaw
+// arguments are the only ones inserted as is.
+func shellf(format string, args ...interface{}) string {
+	quoted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case shellRaw:
+			quoted[i] = string(value)
+		case string:
+			quoted[i] = shellQuote(value)
+		case fmt.Stringer:
+			quoted[i] = shellQuote(value.String())
+		default:
+			quoted[i] = arg
+		}
+	}
+	return fmt.Sprintf(format, quoted...)
+}
+
+// shellQuote returns the value as a single word of the POSIX shell. The values
+// made of safe characters only are returned as is, the others are single
+// quoted, in which the shell interprets no character but the single quote.
+func shellQuote(value string) string {
+	if len(value) == 0 {
+		return "''"
+	}
+	if strings.IndexFunc(value, isUnsafeShellRune) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// shellJoin returns the command line running the program with the arguments.
+func shellJoin(program string, args ...string) string {
+	words := []string{shellQuote(program)}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+func isUnsafeShellRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return false
+	case strings.ContainsRune("-_./:,=@%+", r):
+		return false
+	default:
+		return true
+	}
+}
+
+// grepQuote escapes the regular expression metacharacters of the value, for
+// grep to match it literally within a basic regular expression pattern.
+func grepQuote(value string) string {
+	return escapeRunes(value, `\.[*^$`)
+}
+
+// grepExtendedQuote escapes the value for the extended regular expressions of grep -E.
+func grepExtendedQuote(value string) string {
+	return escapeRunes(value, `\.[*^$+?(){|`)
+}
+
+func escapeRunes(value string, special string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(special, r) {
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,134 @@
+package esxi
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+var hostileNames = []string{
+	"vm",
+	"",
+	"my vm",
+	`a"; rm -rf / #`,
+	"$(reboot)",
+	"`id`",
+	"it's",
+	`'\''`,
+	"-v",
+	"a\nb",
+	"~",
+	"2>/dev/null",
+	"a|b&&c;d",
+	`\.[]*^$+?(){}|`,
+	"vm\\",
+	"日本語",
+}
+
+func TestShellQuote(t *testing.T) {
+	require.Equal(t, "vSwitch0", shellQuote("vSwitch0"))
+	require.Equal(t, "/vmfs/volumes/datastore1/vm-1.vmx", shellQuote("/vmfs/volumes/datastore1/vm-1.vmx"))
+	require.Equal(t, "''", shellQuote(""))
+	require.Equal(t, "'my vm'", shellQuote("my vm"))
+	require.Equal(t, `'it'\''s'`, shellQuote("it's"))
+
+	require.Equal(t, "ls -l /vmfs/volumes/'data store'/'$(id)'", shellf("ls -l /vmfs/volumes/%s/%s", "data store", "$(id)"))
+	require.Equal(t, "vim-cmd hostsvc/rsrc/create --cpu-min=0 pool '; reboot'", shellf("vim-cmd hostsvc/rsrc/create %s %s %s", shellRaw("--cpu-min=0"), "pool", "; reboot"))
+	require.Equal(t, "vmkfstools -c 10G -d thin disk.vmdk", shellf("vmkfstools -c %dG -d %s %s", 10, "thin", "disk.vmdk"))
+}
+
+// requireSingleArgument checks that the name is passed as is, as the single
+// argument of the command line built by shellf.
+func requireSingleArgument(t *testing.T, name string) {
+	command := shellf("mkdir -p %s", name)
+
+	pipelines, err := parseFakeCommandLine(command)
+	require.NoError(t, err, command)
+	require.Len(t, pipelines, 1, command)
+	require.Len(t, pipelines[0].stages, 1, command)
+	require.Equal(t, []string{"mkdir", "-p", name}, pipelines[0].stages[0].args, command)
+
+	words, err := shellquote.Split(command)
+	require.NoError(t, err, command)
+	require.Equal(t, []string{"mkdir", "-p", name}, words, command)
+}
+
+func TestShellfHostileNames(t *testing.T) {
+	for _, name := range hostileNames {
+		requireSingleArgument(t, name)
+	}
+}
+
+func TestShellQuoteWithShell(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no shell available")
+	}
+	for _, name := range hostileNames {
+		out, err := exec.Command(sh, "-c", shellf("printf %s %s", "%s", name)).Output() //nolint:gosec // the command is the one under test
+		require.NoError(t, err, name)
+		require.Equal(t, name, string(out))
+	}
+}
+
+func FuzzShellf(f *testing.F) {
+	for _, name := range hostileNames {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		if strings.ContainsRune(name, 0) {
+			// The arguments of a command can't contain a nul byte.
+			t.Skip()
+		}
+		requireSingleArgument(t, name)
+	})
+}
+
+func FuzzGrepQuote(f *testing.F) {
+	for _, name := range hostileNames {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		if !utf8.ValidString(name) || strings.ContainsAny(name, "\x00\n") {
+			// grep matches lines of text.
+			t.Skip()
+		}
+
+		basic, err := regexp.Compile("^" + fakeBasicRegexp(grepQuote(name)) + "$")
+		require.NoError(t, err)
+		require.True(t, basic.MatchString(name), name)
+		require.False(t, basic.MatchString(name+"x"), name)
+
+		extended, err := regexp.Compile("^" + grepExtendedQuote(name) + "$")
+		require.NoError(t, err)
+		require.True(t, extended.MatchString(name), name)
+	})
+}
+
+func TestPortGroupHostileName(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+	name := `pg"; reboot #$(id)`
+
+	id, result, err := PortGroupCreate(ctx, resource.PropertyMap{
+		"name":    resource.NewStringProperty(name),
+		"vSwitch": resource.NewStringProperty("vSwitch0"),
+	}, esxi)
+	require.NoError(t, err)
+	require.Equal(t, name, result["name"].StringValue())
+	require.Equal(t, "vSwitch0/"+name, id)
+
+	for _, command := range fake.Commands() {
+		pipelines, err := parseFakeCommandLine(command)
+		require.NoError(t, err, command)
+		require.Len(t, pipelines, 1, command)
+	}
+	require.NoError(t, PortGroupDelete(ctx, id, esxi))
+}
@@ -0,0 +1,253 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/tmc/scp"
+	"golang.org/x/crypto/ssh"
+)
+
+// errConnection is returned once the connection attempts of the retry policy are exhausted.
+var errConnection = errors.New("client connection error")
+
+const (
+	failedToConnect = "failed to connect to esxi host"
+
+	sshDialTimeout = 30 * time.Second
+)
+
+// sshExecutor runs the commands on the esxi host over pooled ssh connections.
+type sshExecutor struct {
+	clientConfig *ssh.ClientConfig
+	connection   *ConnectionInfo
+	// bastion tunnels the connections to the host, they are dialed directly when nil.
+	bastion *bastion
+	// agentConn is the connection to the ssh-agent, nil when it isn't used.
+	agentConn io.Closer
+
+	pool *sshPool
+}
+
+var _ Executor = (*sshExecutor)(nil)
+
+func newSSHExecutor(connection *ConnectionInfo, tunnel *bastion) (*sshExecutor, error) {
+	authMethods, agentConn, err := connection.getAuthMethods()
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := connection.getHostKeyCallback()
+	if err != nil {
+		if agentConn != nil {
+			_ = agentConn.Close()
+		}
+		return nil, err
+	}
+
+	executor := &sshExecutor{
+		connection: connection,
+		bastion:    tunnel,
+		agentConn:  agentConn,
+		clientConfig: &ssh.ClientConfig{
+			User:            connection.UserName,
+			Auth:            authMethods,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sshDialTimeout,
+		},
+	}
+	executor.pool = newSSHPool(executor.dial)
+
+	return executor, nil
+}
+
+// dial opens a new ssh client connection, through the bastion when one is
+// configured. The dial and the handshake are aborted when the context is done.
+// Host key verification errors are returned as is, as the handshake flattens
+// them into a plain handshake error.
+func (executor *sshExecutor) dial(ctx context.Context) (*ssh.Client, error) {
+	var hostKeyErr error
+	config := *executor.clientConfig
+	config.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		hostKeyErr = executor.clientConfig.HostKeyCallback(hostname, remote, key)
+		return hostKeyErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sshDialTimeout)
+	defer cancel()
+
+	address := executor.connection.getSSHConnection()
+	var conn net.Conn
+	var err error
+	if executor.bastion == nil {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = executor.bastion.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	handshakeDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-handshakeDone:
+		}
+	}()
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, address, &config)
+	close(handshakeDone)
+	if hostKeyErr != nil {
+		conn.Close()
+		return nil, hostKeyErr
+	}
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+// connect opens a session on the pooled ssh connections to the esxi host,
+// dialing a new connection when required. The transient connection failures
+// are retried with the retry policy of the connection.
+func (executor *sshExecutor) connect(ctx context.Context) (*ssh.Session, func(), error) {
+	type pooledSession struct {
+		session *ssh.Session
+		release func()
+	}
+	result, err := retry(ctx, executor.connection.Retry, sleepContext, "connect", func() (pooledSession, string, error) {
+		if ctx.Err() != nil {
+			return pooledSession{}, "", ctx.Err()
+		}
+		session, release, err := executor.pool.newSession(ctx)
+		return pooledSession{session, release}, "", err
+	})
+	if err != nil {
+		var mismatchErr *HostKeyMismatchError
+		if errors.As(err, &mismatchErr) || errors.Is(err, errPoolClosed) || ctx.Err() != nil {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%w: %w", errConnection, err)
+	}
+	return result.session, result.release, nil
+}
+
+// Close closes the ssh connections to the esxi host.
+func (executor *sshExecutor) Close() {
+	executor.pool.Close()
+	if executor.agentConn != nil {
+		_ = executor.agentConn.Close()
+	}
+}
+
+func (executor *sshExecutor) Execute(ctx context.Context, command string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("Execute: %s", shortCmdDesc)
+
+	session, release, err := executor.connect(ctx)
+	if err != nil {
+		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
+		return failedToConnect, err
+	}
+	defer release()
+
+	stdoutRaw, err := runSession(ctx, session, func() ([]byte, error) {
+		return session.CombinedOutput(command)
+	})
+	stdout := strings.TrimSpace(string(stdoutRaw))
+
+	if stdout == "<unset>" {
+		if err == nil {
+			err = errManagementAgentRestarted
+		}
+		return errManagementAgentRestarted.Error(), err
+	}
+
+	return stdout, err
+}
+
+func (executor *sshExecutor) WriteFile(ctx context.Context, content string, path string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("WriteFile: %s", shortCmdDesc)
+
+	f, _ := os.CreateTemp("", "")
+	_, err := fmt.Fprintln(f, content)
+	if err != nil {
+		return "", err
+	}
+	fCloseErr := f.Close()
+	if fCloseErr != nil {
+		return "", fCloseErr
+	}
+	defer RemoveFile(f)
+
+	session, release, err := executor.connect(ctx)
+	if err != nil {
+		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
+		return failedToConnect, err
+	}
+	defer release()
+	_, err = runSession(ctx, session, func() (any, error) {
+		return nil, scp.CopyPath(f.Name(), path, session)
+	})
+	if err != nil {
+		logging.V(logLevel).Infof("WriteFile: Failed copying the file! %s", err)
+		return failedToConnect, err
+	}
+	return content, err
+}
+
+func (executor *sshExecutor) CopyFile(ctx context.Context, localPath string, hostPath string, shortCmdDesc string) (string, error) {
+	logging.V(logLevel).Infof("CopyFile: %s", shortCmdDesc)
+
+	session, release, err := executor.connect(ctx)
+	if err != nil {
+		logging.V(logLevel).Infof("Execute: Failed connecting to host! %s", err)
+		return failedToConnect, err
+	}
+	defer release()
+	_, err = runSession(ctx, session, func() (any, error) {
+		return nil, scp.CopyPath(localPath, hostPath, session)
+	})
+	if err != nil {
+		return "Failed to copy file to esxi host!", err
+	}
+	return "", nil
+}
+
+// runSession runs fn on the session. When the context is done before fn
+// returns, the remote command is signalled and the session closed, so
+// cancellations and timeouts end remote commands promptly.
+func runSession[T any](ctx context.Context, session *ssh.Session, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		logging.V(logLevel).Infof("runSession: aborting remote command: %s", ctx.Err())
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		<-done
+		var zero T
+		return zero, ctx.Err()
+	}
+}
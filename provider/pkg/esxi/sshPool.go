@@ -0,0 +1,259 @@
+package esxi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshMaxClients is the number of tcp connections kept open to the host.
+	sshMaxClients = 4
+	// sshMaxSessionsPerClient stays below the default MaxSessions of the ESXi sshd.
+	sshMaxSessionsPerClient = 8
+
+	sshKeepAliveInterval = 30 * time.Second
+	sshKeepAliveTimeout  = 15 * time.Second
+)
+
+var errPoolClosed = fmt.Errorf("the ssh connection pool is closed")
+
+// pooledClient is a long-lived ssh connection shared by several sessions.
+type pooledClient struct {
+	client      *ssh.Client
+	sessions    int
+	maxSessions int
+	done        chan struct{}
+}
+
+// sshPool keeps a small bounded set of ssh connections open to the host and
+// opens sessions on them. Broken connections are detected by keepalives or
+// when opening a session fails, and are replaced transparently.
+type sshPool struct {
+	dial func(ctx context.Context) (*ssh.Client, error)
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	clients []*pooledClient
+	dialing int
+	closed  bool
+}
+
+func newSSHPool(dial func(ctx context.Context) (*ssh.Client, error)) *sshPool {
+	pool := &sshPool{dial: dial}
+	pool.cond = sync.NewCond(&pool.mutex)
+	return pool
+}
+
+// newSession opens a session on a pooled connection, dialing a new connection
+// when all of them are busy. The returned release function must be called once
+// the session is done.
+func (pool *sshPool) newSession(ctx context.Context) (*ssh.Session, func(), error) {
+	rejected, broken := 0, 0
+	for {
+		pc, err := pool.acquire(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		session, err := pc.client.NewSession()
+		if err == nil {
+			return session, func() {
+				if closeErr := session.Close(); closeErr != nil {
+					logging.V(logLevel).Infof("newSession: closing session: %s", closeErr)
+				}
+				pool.release(pc)
+			}, nil
+		}
+
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) {
+			// The host refuses more sessions on this connection, lower its limit
+			// and wait for another slot.
+			logging.V(logLevel).Infof("newSession: session rejected by host: %s", err)
+			pool.saturate(pc)
+			if rejected++; rejected > sshMaxSessionsPerClient {
+				return nil, nil, fmt.Errorf("session connection error: %w", err)
+			}
+			continue
+		}
+
+		// The connection is gone (e.g. the host or sshd restarted), drop it and
+		// retry on another one.
+		logging.V(logLevel).Infof("newSession: dropping broken ssh connection: %s", err)
+		pool.release(pc)
+		pool.discard(pc)
+		if broken++; broken > sshMaxClients {
+			return nil, nil, fmt.Errorf("session connection error: %w", err)
+		}
+	}
+}
+
+// acquire reserves a session slot on the least loaded connection, waiting for
+// one until the context is done.
+func (pool *sshPool) acquire(ctx context.Context) (*pooledClient, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	var stop chan struct{}
+	for {
+		if pool.closed {
+			return nil, errPoolClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var candidate *pooledClient
+		for _, pc := range pool.clients {
+			if pc.sessions < pc.maxSessions && (candidate == nil || pc.sessions < candidate.sessions) {
+				candidate = pc
+			}
+		}
+		// Prefer an idle existing connection, then a new one, then a busy one.
+		if candidate != nil && (candidate.sessions == 0 || len(pool.clients)+pool.dialing >= sshMaxClients) {
+			candidate.sessions++
+			return candidate, nil
+		}
+
+		if len(pool.clients)+pool.dialing < sshMaxClients {
+			return pool.connect(ctx)
+		}
+
+		if stop == nil {
+			// Wake up the waiting sessions when the context is done, as cond.Wait
+			// doesn't take a context.
+			stop = make(chan struct{})
+			defer close(stop)
+			go func() {
+				select {
+				case <-ctx.Done():
+					pool.mutex.Lock()
+					pool.cond.Broadcast()
+					pool.mutex.Unlock()
+				case <-stop:
+				}
+			}()
+		}
+		pool.cond.Wait()
+	}
+}
+
+// connect dials a new connection. It's called with the mutex held, which is
+// released while dialing so other sessions can progress.
+func (pool *sshPool) connect(ctx context.Context) (*pooledClient, error) {
+	pool.dialing++
+	pool.mutex.Unlock()
+	client, err := pool.dial(ctx)
+	pool.mutex.Lock()
+	pool.dialing--
+	defer pool.cond.Broadcast()
+
+	if err != nil {
+		return nil, err
+	}
+	if pool.closed {
+		_ = client.Close()
+		return nil, errPoolClosed
+	}
+
+	pc := &pooledClient{client: client, sessions: 1, maxSessions: sshMaxSessionsPerClient, done: make(chan struct{})}
+	pool.clients = append(pool.clients, pc)
+	go pool.keepAlive(pc)
+
+	return pc, nil
+}
+
+func (pool *sshPool) release(pc *pooledClient) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pc.sessions--
+	pool.cond.Broadcast()
+}
+
+// saturate releases the slot and caps the connection to its remaining sessions.
+func (pool *sshPool) saturate(pc *pooledClient) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pc.sessions--
+	if pc.maxSessions = pc.sessions; pc.maxSessions < 1 {
+		pc.maxSessions = 1
+	}
+	pool.cond.Broadcast()
+}
+
+// discard removes the connection from the pool and closes it.
+func (pool *sshPool) discard(pc *pooledClient) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.remove(pc)
+}
+
+// remove is called with the mutex held.
+func (pool *sshPool) remove(pc *pooledClient) {
+	for i, item := range pool.clients {
+		if item == pc {
+			pool.clients = append(pool.clients[:i], pool.clients[i+1:]...)
+			close(pc.done)
+			if err := pc.client.Close(); err != nil {
+				logging.V(logLevel).Infof("remove: closing ssh connection: %s", err)
+			}
+			pool.cond.Broadcast()
+			return
+		}
+	}
+}
+
+// keepAlive sends periodic keepalive requests, so idle connections aren't
+// dropped by the host and dead ones are detected before they are used.
+func (pool *sshPool) keepAlive(pc *pooledClient) {
+	ticker := time.NewTicker(sshKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pc.done:
+			return
+		case <-ticker.C:
+			reply := make(chan error, 1)
+			go func() {
+				_, _, err := pc.client.SendRequest("keepalive@openssh.com", true, nil)
+				reply <- err
+			}()
+
+			var err error
+			select {
+			case err = <-reply:
+			case <-time.After(sshKeepAliveTimeout):
+				err = fmt.Errorf("no keepalive reply within %s", sshKeepAliveTimeout)
+			case <-pc.done:
+				return
+			}
+			if err != nil {
+				logging.V(logLevel).Infof("keepAlive: dropping ssh connection: %s", err)
+				pool.discard(pc)
+				return
+			}
+		}
+	}
+}
+
+// Close closes all the pooled connections, sessions still in use are aborted.
+func (pool *sshPool) Close() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.closed = true
+	for len(pool.clients) > 0 {
+		pool.remove(pool.clients[0])
+	}
+	pool.cond.Broadcast()
+}
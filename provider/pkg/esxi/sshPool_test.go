@@ -0,0 +1,284 @@
+package esxi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHPoolReusesConnections(t *testing.T) {
+	server := newTestSSHServer(t)
+	esxi := server.newHost(t)
+
+	for i := 0; i < 20; i++ {
+		stdout, err := esxi.Execute(context.Background(), fmt.Sprintf("echo %d", i), "echo")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("echo %d", i), stdout)
+	}
+	require.EqualValues(t, 1, server.dials.Load())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := esxi.Execute(context.Background(), "echo", "echo")
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.LessOrEqual(t, server.dials.Load(), int64(sshMaxClients))
+}
+
+func TestSSHPoolReconnects(t *testing.T) {
+	server := newTestSSHServer(t)
+	esxi := server.newHost(t)
+
+	_, err := esxi.Execute(context.Background(), "echo", "echo")
+	require.NoError(t, err)
+
+	// Simulate the host dropping all connections, e.g. after a restart of sshd.
+	server.dropConnections()
+
+	stdout, err := esxi.Execute(context.Background(), "echo again", "echo")
+	require.NoError(t, err)
+	require.Equal(t, "echo again", stdout)
+	require.EqualValues(t, 2, server.dials.Load())
+}
+
+func TestSSHPoolClose(t *testing.T) {
+	server := newTestSSHServer(t)
+	esxi := server.newHost(t)
+
+	_, err := esxi.Execute(context.Background(), "echo", "echo")
+	require.NoError(t, err)
+
+	esxi.Close()
+	_, err = esxi.Execute(context.Background(), "echo", "echo")
+	require.ErrorIs(t, err, errPoolClosed)
+}
+
+func TestExecuteCancelled(t *testing.T) {
+	server := newTestSSHServer(t)
+	esxi := server.newHost(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := esxi.Execute(ctx, "hang", "hang")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// The connection is still usable after the aborted session.
+	stdout, err := esxi.Execute(context.Background(), "echo", "echo")
+	require.NoError(t, err)
+	require.Equal(t, "echo", stdout)
+}
+
+func TestSSHPoolAcquireCancelled(t *testing.T) {
+	pool := newSSHPool(func(context.Context) (*ssh.Client, error) {
+		return nil, fmt.Errorf("unexpected dial")
+	})
+	for i := 0; i < sshMaxClients; i++ {
+		pool.clients = append(pool.clients, &pooledClient{sessions: sshMaxSessionsPerClient, maxSessions: sshMaxSessionsPerClient})
+	}
+
+	// All the slots are busy, the session waits until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err := pool.newSession(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSSHDialCancelled(t *testing.T) {
+	// The listener accepts the connections but never completes the handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	executor, err := newSSHExecutor(&ConnectionInfo{
+		Host: host, SSHPort: port, UserName: "root", Password: "secret", HostKeyFingerprint: "SHA256:abc",
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(executor.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err = executor.pool.newSession(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSSHPoolBrokenConnections(t *testing.T) {
+	server := newTestSSHServer(t)
+	server.closeAfterHandshake.Store(true)
+	executor, err := newSSHExecutor(server.connection(t), nil)
+	require.NoError(t, err)
+	t.Cleanup(executor.Close)
+
+	// Every new connection is dropped by the host, the retries are capped.
+	_, _, err = executor.pool.newSession(context.Background())
+	require.ErrorContains(t, err, "session connection error")
+	require.EqualValues(t, sshMaxClients+1, server.dials.Load())
+}
+
+// testSSHServer is a minimal ssh server echoing the executed commands,
+// except for "hang" which never completes.
+type testSSHServer struct {
+	listener    net.Listener
+	config      *ssh.ServerConfig
+	fingerprint string
+	dials       atomic.Int64
+	// closeAfterHandshake drops the connections once they are established.
+	closeAfterHandshake atomic.Bool
+
+	mutex sync.Mutex
+	conns []net.Conn
+}
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) != "secret" {
+				return nil, fmt.Errorf("invalid password")
+			}
+			return &ssh.Permissions{}, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &testSSHServer{listener: listener, config: config, fingerprint: ssh.FingerprintSHA256(signer.PublicKey())}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		server.dropConnections()
+	})
+	go server.serve()
+
+	return server
+}
+
+func (server *testSSHServer) connection(t *testing.T) *ConnectionInfo {
+	t.Helper()
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+
+	return &ConnectionInfo{
+		Host: host, SSHPort: port, UserName: "root", Password: "secret", HostKeyFingerprint: server.fingerprint,
+	}
+}
+
+func (server *testSSHServer) newHost(t *testing.T) *Host {
+	t.Helper()
+	esxi, err := NewHost(context.Background(), *server.connection(t))
+	require.NoError(t, err)
+	t.Cleanup(esxi.Close)
+
+	return esxi
+}
+
+func (server *testSSHServer) dropConnections() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for _, conn := range server.conns {
+		_ = conn.Close()
+	}
+	server.conns = nil
+}
+
+func (server *testSSHServer) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		server.dials.Add(1)
+		server.mutex.Lock()
+		server.conns = append(server.conns, conn)
+		server.mutex.Unlock()
+
+		go server.handle(conn)
+	}
+}
+
+func (server *testSSHServer) handle(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, server.config)
+	if err != nil {
+		return
+	}
+	if server.closeAfterHandshake.Load() {
+		_ = conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		if newChannel.ChannelType() == "direct-tcpip" {
+			// The forwarded connections are echoed back, as if the destination was an echo server.
+			go ssh.DiscardRequests(channelRequests)
+			go func() {
+				defer channel.Close()
+				_, _ = io.Copy(channel, channel)
+			}()
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range channelRequests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				_ = req.Reply(true, nil)
+				// The payload is the command as an ssh string.
+				command := string(req.Payload[4:])
+				if command == "hang" {
+					// Block until the client closes the channel.
+					for range channelRequests {
+					}
+					return
+				}
+				_, _ = channel.Write([]byte(command))
+				status := make([]byte, 4)
+				binary.BigEndian.PutUint32(status, 0)
+				_, _ = channel.SendRequest("exit-status", false, status)
+				return
+			}
+		}()
+	}
+}
@@ -119,6 +119,8 @@ type VirtualMachine struct {
 	BootFirmware string
 	// esxi DiskStore for boot disk.
 	DiskStore string
+	// Enable the UEFI secure boot, the boot firmware must be efi.
+	EfiSecureBoot bool
 	// pass data to VM
 	Id string
 	// pass data to VM
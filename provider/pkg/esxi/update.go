@@ -0,0 +1,36 @@
+package esxi
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ResourceUpdate is the update of a resource, from its old inputs and outputs
+// to its new inputs.
+type ResourceUpdate struct {
+	Id         string
+	OldInputs  resource.PropertyMap
+	NewInputs  resource.PropertyMap
+	OldOutputs resource.PropertyMap
+}
+
+// newResourceUpdate returns the update applying all the inputs, used by the
+// creations and by the updates of the resources without old inputs.
+func newResourceUpdate(id string, inputs resource.PropertyMap) ResourceUpdate {
+	return ResourceUpdate{Id: id, NewInputs: inputs}
+}
+
+// Changed returns true when one of the inputs differs between the old and the
+// new inputs, or when the old inputs are not known.
+func (update ResourceUpdate) Changed(keys ...string) bool {
+	if update.OldInputs == nil {
+		return true
+	}
+	for _, key := range keys {
+		oldValue, hasOld := update.OldInputs[resource.PropertyKey(key)]
+		newValue, hasNew := update.NewInputs[resource.PropertyKey(key)]
+		if hasOld != hasNew || (hasOld && !oldValue.DeepEquals(newValue)) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package esxi
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"os"
@@ -107,6 +108,20 @@ func ParseTemplate(text string, data any) (string, error) {
 	return output, nil
 }
 
+// sleepContext pauses for the given duration, it returns the context error
+// early when the context is done.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func CloseFile(file *os.File) {
 	if e := file.Close(); e != nil {
 		logging.V(logLevel).Info(e)
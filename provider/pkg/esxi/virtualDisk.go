@@ -1,31 +1,88 @@
 package esxi
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
-func VirtualDiskCreate(inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+const virtualDiskToken = "esxi-native:index:VirtualDisk"
+
+func init() {
+	registerResource(virtualDiskToken, virtualDiskResource{resourceRules{
+		diff: DiffRules{
+			Replaces: []string{"diskStore", "directory", "name", "diskType"},
+			GrowOnly: map[string]float64{"size": 1},
+			Names:    []string{"diskStore", "directory", "name"},
+		},
+		refresh: RefreshRules{
+			Equivalents: map[string]func(input, live string) bool{
+				"name": func(input, live string) bool {
+					return strings.TrimSuffix(input, ".vmdk") == strings.TrimSuffix(live, ".vmdk")
+				},
+			},
+		},
+	}})
+}
+
+// virtualDiskResource implements the VirtualDisk resource with the functions
+// below.
+type virtualDiskResource struct {
+	resourceRules
+}
+
+func (virtualDiskResource) Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure {
+	setDefault(inputs, "diskStore", resource.NewStringProperty(defaults.DiskStore), len(defaults.DiskStore) > 0)
+	return schema.ValidateVirtualDisk(virtualDiskToken, inputs)
+}
+
+func (virtualDiskResource) Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualDiskCreate(ctx, inputs, esxi)
+}
+
+func (virtualDiskResource) Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualDiskRead(ctx, id, inputs, esxi)
+}
+
+func (virtualDiskResource) Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	return VirtualDiskImport(ctx, id, esxi)
+}
+
+func (virtualDiskResource) Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualDiskUpdate(ctx, update, esxi)
+}
+
+func (virtualDiskResource) Delete(ctx context.Context, id string, esxi *Host) error {
+	return VirtualDiskDelete(ctx, id, esxi)
+}
+
+func VirtualDiskCreate(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	vd := parseVirtualDisk("", inputs)
+	if esxi.api != nil {
+		return esxi.api.createVirtualDisk(ctx, vd)
+	}
 	// create vd
 	var id, command string
 	var err error
 
-	err = esxi.validateDiskStore(vd.DiskStore)
+	err = esxi.validateDiskStore(ctx, vd.DiskStore)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to validate disk store: %w", err)
 	}
 
 	// Create dir if required
-	command = fmt.Sprintf("mkdir -p \"/vmfs/volumes/%s/%s\"", vd.DiskStore, vd.Directory)
-	_, _ = esxi.Execute(command, "create virtual disk dir")
+	command = shellf("mkdir -p /vmfs/volumes/%s/%s", vd.DiskStore, vd.Directory)
+	_, _ = esxi.Execute(ctx, command, "create virtual disk dir")
 
-	command = fmt.Sprintf("ls -d \"/vmfs/volumes/%s/%s\"", vd.DiskStore, vd.Directory)
-	_, err = esxi.Execute(command, "validate dir exists")
+	command = shellf("ls -d /vmfs/volumes/%s/%s", vd.DiskStore, vd.Directory)
+	_, err = esxi.ExecuteWithRetry(ctx, command, "validate dir exists")
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create virtual disk directory: %w", err)
 	}
@@ -33,47 +90,62 @@ func VirtualDiskCreate(inputs resource.PropertyMap, esxi *Host) (string, resourc
 	// id is just the full path name
 	id = fmt.Sprintf("/vmfs/volumes/%s/%s/%s", vd.DiskStore, vd.Directory, vd.Name)
 
-	// validate if it exists already
-	command = fmt.Sprintf("ls -l \"%s\"", id)
-	_, err = esxi.Execute(command, "validate disk store exists")
+	// validate if it exists already, an existing disk is imported instead
+	command = shellf("ls -l %s", id)
+	_, err = esxi.ExecuteWithRetry(ctx, command, "validate disk store exists")
 	if err == nil {
-		return "", nil, err
+		return "", nil, fmt.Errorf("virtual disk %s already exists, import it instead", id)
 	}
 
-	command = fmt.Sprintf("/bin/vmkfstools -c %dG -d %s \"%s\"", vd.Size, vd.DiskType, id)
-	_, err = esxi.Execute(command, "Create virtual disk")
+	command = shellf("/bin/vmkfstools -c %dG -d %s %s", vd.Size, vd.DiskType, id)
+	_, err = esxi.Execute(ctx, command, "Create virtual disk")
 	if err != nil {
 		return "", nil, fmt.Errorf("unable to create virtual disk")
 	}
 
 	// read vd
 	if err == nil {
-		return esxi.readVirtualDisk(id)
+		return esxi.readVirtualDisk(ctx, id)
 	} else {
 		return "", nil, fmt.Errorf("failed to create virtual disk: %s err: %w", vd.Name, err)
 	}
 }
 
-func VirtualDiskUpdate(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func VirtualDiskUpdate(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	id, inputs := update.Id, update.NewInputs
 	vd := parseVirtualDisk(id, inputs)
+	// The size is the only input updated in place, the outputs are kept when it didn't change.
+	if !update.Changed("size") && update.OldOutputs != nil {
+		return id, update.OldOutputs.Copy(), nil
+	}
+	if esxi.api != nil {
+		if err := esxi.api.updateVirtualDisk(ctx, vd); err != nil {
+			return "", nil, fmt.Errorf("failed to grow virtual disk: %w", err)
+		}
+		return esxi.api.readVirtualDisk(ctx, id)
+	}
 
-	changed, err := esxi.growVirtualDisk(vd.Id, vd.Size)
+	changed, err := esxi.growVirtualDisk(ctx, vd.Id, vd.Size)
 	if err != nil && !changed {
 		return "", nil, fmt.Errorf("failed to grow virtual disk: %w", err)
 	}
 
-	return id, inputs, nil
+	return esxi.readVirtualDisk(ctx, id)
 }
 
-func VirtualDiskDelete(id string, esxi *Host) error {
-	vd, err := esxi.getVirtualDisk(id)
+func VirtualDiskDelete(ctx context.Context, id string, esxi *Host) error {
+	if esxi.api != nil {
+		return esxi.api.deleteVirtualDisk(ctx, id)
+	}
+
+	vd, err := esxi.getVirtualDisk(ctx, id)
 	if err != nil && strings.Contains(err.Error(), "invalid virtual disk id") {
 		return err
 	}
 
 	//  Destroy virtual disk.
-	command := fmt.Sprintf("/bin/vmkfstools -U \"%s\"", id)
-	stdout, err := esxi.Execute(command, "destroy virtual disk")
+	command := shellf("/bin/vmkfstools -U %s", id)
+	stdout, err := esxi.Execute(ctx, command, "destroy virtual disk")
 	if err != nil {
 		if strings.Contains(err.Error(), "Process exited with status 255") {
 			logging.V(logLevel).Infof("already deleted:%s", id)
@@ -83,22 +155,39 @@ func VirtualDiskDelete(id string, esxi *Host) error {
 		}
 	}
 
-	command = fmt.Sprintf("ls -al \"/vmfs/volumes/%s/%s/\" |wc -l", vd.DiskStore, vd.Directory)
+	command = shellf("ls -al /vmfs/volumes/%s/%s/ |wc -l", vd.DiskStore, vd.Directory)
 
-	stdout, _ = esxi.Execute(command, "check if storage dir is empty")
+	stdout, _ = esxi.ExecuteWithRetry(ctx, command, "check if storage dir is empty")
 	if stdout == "3" {
 		{
 			//  Delete empty dir.  Ignore stdout and errors.
-			command = fmt.Sprintf("rmdir \"/vmfs/volumes/%s/%s\"", vd.DiskStore, vd.Directory)
-			_, _ = esxi.Execute(command, "rmdir empty storage dir")
+			command = shellf("rmdir /vmfs/volumes/%s/%s", vd.DiskStore, vd.Directory)
+			_, _ = esxi.Execute(ctx, command, "rmdir empty storage dir")
 		}
 	}
 
 	return nil
 }
 
-func VirtualDiskRead(id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	return esxi.readVirtualDisk(id)
+func VirtualDiskRead(ctx context.Context, id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	if esxi.api != nil {
+		return esxi.api.readVirtualDisk(ctx, id)
+	}
+	return esxi.readVirtualDisk(ctx, id)
+}
+
+// VirtualDiskImport reads the virtual disk imported by its path, e.g.
+// /vmfs/volumes/datastore1/disks/data.vmdk.
+func VirtualDiskImport(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	id, outputs, err := VirtualDiskRead(ctx, id, nil, esxi)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	inputs := importedInputs(outputs, "diskStore", "directory", "name", "size", "diskType")
+	if name, has := inputs["name"]; has {
+		inputs["name"] = resource.NewStringProperty(strings.TrimSuffix(name.StringValue(), ".vmdk"))
+	}
+	return id, inputs, outputs, nil
 }
 
 func parseVirtualDisk(id string, inputs resource.PropertyMap) VirtualDisk {
@@ -125,9 +214,9 @@ func parseVirtualDisk(id string, inputs resource.PropertyMap) VirtualDisk {
 	return vd
 }
 
-func (esxi *Host) readVirtualDisk(id string) (string, resource.PropertyMap, error) {
-	vd, err := esxi.getVirtualDisk(id)
-	if err != nil && strings.Contains(err.Error(), "invalid virtual disk id") {
+func (esxi *Host) readVirtualDisk(ctx context.Context, id string) (string, resource.PropertyMap, error) {
+	vd, err := esxi.getVirtualDisk(ctx, id)
+	if err != nil {
 		return "", nil, err
 	}
 
@@ -135,22 +224,22 @@ func (esxi *Host) readVirtualDisk(id string) (string, resource.PropertyMap, erro
 	return vd.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func (esxi *Host) validateDiskStore(diskStore string) error {
+func (esxi *Host) validateDiskStore(ctx context.Context, diskStore string) error {
 	var command, stdout string
 	var err error
 
 	command = "esxcli storage filesystem list | grep '/vmfs/volumes/.*[VMFS|NFS]' |awk '{for(i=2;i<=NF-5;++i)printf $i\" \" ; printf \"\\n\"}'"
-	stdout, err = esxi.Execute(command, "get list of disk stores")
+	stdout, err = esxi.ExecuteWithRetry(ctx, command, "get list of disk stores")
 	if err != nil {
 		return fmt.Errorf("unable to get list of disk stores: %w", err)
 	}
 
 	if !strings.Contains(stdout, diskStore) {
 		command = "esxcli storage filesystem rescan"
-		_, _ = esxi.Execute(command, "refresh filesystems")
+		_, _ = esxi.Execute(ctx, command, "refresh filesystems")
 
 		command = "esxcli storage filesystem list | grep '/vmfs/volumes/.*[VMFS|NFS]' |awk '{for(i=2;i<=NF-5;++i)printf $i\" \" ; printf \"\\n\"}'"
-		stdout, err = esxi.Execute(command, "get list of disk stores")
+		stdout, err = esxi.ExecuteWithRetry(ctx, command, "get list of disk stores")
 		if err != nil {
 			return fmt.Errorf("unable to get list of disk stores: %w", err)
 		}
@@ -161,10 +250,10 @@ func (esxi *Host) validateDiskStore(diskStore string) error {
 	return nil
 }
 
-func (esxi *Host) growVirtualDisk(id string, size int) (bool, error) {
+func (esxi *Host) growVirtualDisk(ctx context.Context, id string, size int) (bool, error) {
 	var didGrowDisk bool
 
-	current, err := esxi.getVirtualDisk(id)
+	current, err := esxi.getVirtualDisk(ctx, id)
 
 	if current.Size == size {
 		return true, nil
@@ -175,8 +264,8 @@ func (esxi *Host) growVirtualDisk(id string, size int) (bool, error) {
 	}
 
 	if current.Size < size {
-		command := fmt.Sprintf("/bin/vmkfstools -X %dG \"%s\"", size, id)
-		stdout, err := esxi.Execute(command, "grow disk")
+		command := shellf("/bin/vmkfstools -X %dG %s", size, id)
+		stdout, err := esxi.Execute(ctx, command, "grow disk")
 		if err != nil {
 			return false, fmt.Errorf("%s err: %w", stdout, err)
 		}
@@ -186,7 +275,7 @@ func (esxi *Host) growVirtualDisk(id string, size int) (bool, error) {
 	return didGrowDisk, err
 }
 
-func (esxi *Host) getVirtualDisk(id string) (VirtualDisk, error) {
+func (esxi *Host) getVirtualDisk(ctx context.Context, id string) (VirtualDisk, error) {
 	var diskStore, diskDir, diskName, diskType, flatSize string
 	var diskSize int
 	var flatSizeI64 int64
@@ -211,8 +300,12 @@ func (esxi *Host) getVirtualDisk(id string) (VirtualDisk, error) {
 	}
 
 	// Test if virtual disk exists
-	command := fmt.Sprintf("test -s \"%s\"", id)
-	stdout, err := esxi.Execute(command, "test if virtual disk exists")
+	command := shellf("test -s %s", id)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "test if virtual disk exists")
+	// test exits with 1 when the disk is missing.
+	if exitedWith(err, 1) {
+		return VirtualDisk{}, &NotFoundError{Kind: "virtual disk", Id: id}
+	}
 	if err != nil {
 		return VirtualDisk{}, fmt.Errorf("virtual disk %s doesn't exist, err: %s %w", id, stdout, err)
 	}
@@ -225,9 +318,9 @@ func (esxi *Host) getVirtualDisk(id string) (VirtualDisk, error) {
 	}
 	diskNameFlat := fmt.Sprintf("%s-flat.%s", s[0], s[1])
 
-	command = fmt.Sprintf("ls -l \"/vmfs/volumes/%s/%s/%s\" | awk '{print $5}'",
+	command = shellf("ls -l /vmfs/volumes/%s/%s/%s | awk '{print $5}'",
 		diskStore, diskDir, diskNameFlat)
-	flatSize, err = esxi.Execute(command, "Get size")
+	flatSize, err = esxi.ExecuteWithRetry(ctx, command, "Get size")
 	if err != nil {
 		return VirtualDisk{}, fmt.Errorf("failed to read virtual disk %s size, err: %s %w", id, flatSize, err)
 	}
@@ -236,14 +329,14 @@ func (esxi *Host) getVirtualDisk(id string) (VirtualDisk, error) {
 	diskSize = int(flatSizeI64 / bytesSize / bytesSize / bytesSize)
 
 	// Determine virtual disk type  (only works if Guest is powered off)
-	command = fmt.Sprintf("vmkfstools -t0 \"%s\" |grep -q 'VMFS Z- LVID:' && echo true", id)
-	isZeroedThick, _ := esxi.Execute(command, "Get disk type.  Is zeroedthick.")
+	command = shellf("vmkfstools -t0 %s |grep -q 'VMFS Z- LVID:' && echo true", id)
+	isZeroedThick, _ := esxi.ExecuteWithRetry(ctx, command, "Get disk type.  Is zeroedthick.")
 
-	command = fmt.Sprintf("vmkfstools -t0 \"%s\" |grep -q 'VMFS -- LVID:' && echo true", id)
-	isEagerZeroedThick, _ := esxi.Execute(command, "Get disk type.  Is eagerzeroedthick.")
+	command = shellf("vmkfstools -t0 %s |grep -q 'VMFS -- LVID:' && echo true", id)
+	isEagerZeroedThick, _ := esxi.ExecuteWithRetry(ctx, command, "Get disk type.  Is eagerzeroedthick.")
 
-	command = fmt.Sprintf("vmkfstools -t0 \"%s\" |grep -q 'NOMP -- :' && echo true", id)
-	isThin, _ := esxi.Execute(command, "Get disk type.  Is thin.")
+	command = shellf("vmkfstools -t0 %s |grep -q 'NOMP -- :' && echo true", id)
+	isThin, _ := esxi.ExecuteWithRetry(ctx, command, "Get disk type.  Is thin.")
 
 	switch {
 	case isThin == trueValue:
@@ -0,0 +1,60 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualDiskLifecycle(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":      resource.NewStringProperty("disk-test"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+		"size":      resource.NewNumberProperty(2),
+	}
+	id, result, err := VirtualDiskCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "/vmfs/volumes/datastore1/disks/disk-test.vmdk", id)
+	require.Equal(t, "thin", result["diskType"].StringValue())
+	require.Equal(t, 2.0, result["size"].NumberValue())
+	require.Equal(t, "disks", result["directory"].StringValue())
+
+	_, _, err = VirtualDiskCreate(ctx, inputs, esxi)
+	require.EqualError(t, err, "virtual disk /vmfs/volumes/datastore1/disks/disk-test.vmdk already exists, import it instead")
+
+	inputs["size"] = resource.NewNumberProperty(4)
+	_, _, err = VirtualDiskUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	_, result, err = VirtualDiskRead(ctx, id, nil, esxi)
+	require.NoError(t, err)
+	require.Equal(t, 4.0, result["size"].NumberValue())
+
+	inputs["size"] = resource.NewNumberProperty(1)
+	_, _, err = VirtualDiskUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.ErrorContains(t, err, "not able to shrink virtual disk")
+
+	_, _, err = VirtualDiskCreate(ctx, resource.PropertyMap{
+		"name":      resource.NewStringProperty("disk-test"),
+		"diskStore": resource.NewStringProperty("missing"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+	}, esxi)
+	require.ErrorContains(t, err, "disk store missing does not exist")
+
+	// Deleting the last disk of the directory removes the directory.
+	require.NoError(t, VirtualDiskDelete(ctx, id, esxi))
+	_, ok := fake.ReadFile(id)
+	require.False(t, ok)
+	stdout, err := esxi.Execute(ctx, `ls -d "/vmfs/volumes/datastore1/disks"`, "check directory")
+	require.Error(t, err)
+	require.Contains(t, stdout, "No such file or directory")
+	_, _, err = VirtualDiskRead(ctx, id, nil, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
@@ -2,6 +2,7 @@ package esxi
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -11,13 +12,77 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
-func VirtualMachineGet(inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
+const virtualMachineToken = "esxi-native:index:VirtualMachine"
+
+func init() {
+	registerResource(virtualMachineToken, virtualMachineResource{resourceRules{
+		diff: DiffRules{
+			// The boot disk type and the ovf properties are only applied when
+			// the virtual machine is created.
+			Replaces: []string{
+				"name", "cloneFromVirtualMachine", "ovfSource", "diskStore", "resourcePoolName",
+				"bootDiskType", "ovfProperties", "ovfPropertiesTimer",
+			},
+			GrowOnly: map[string]float64{"bootDiskSize": vmDefaultBootDiskSize},
+			Names:    []string{"name"},
+		},
+		refresh: RefreshRules{
+			Unread:      []string{"startupTimeout", "shutdownTimeout"},
+			Equivalents: map[string]func(input, live string) bool{"resourcePoolName": samePoolName},
+		},
+	}})
+	registerFunction("esxi-native:index:getVirtualMachine", FunctionFunc(VirtualMachineGet))
+	registerFunction("esxi-native:index:getVirtualMachineById", FunctionFunc(VirtualMachineGet))
+}
+
+// virtualMachineResource implements the VirtualMachine resource with the functions
+// below.
+type virtualMachineResource struct {
+	resourceRules
+}
+
+func (virtualMachineResource) Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure {
+	setDefault(inputs, "diskStore", resource.NewStringProperty(defaults.DiskStore), len(defaults.DiskStore) > 0)
+	setDefault(inputs, "resourcePoolName", resource.NewStringProperty(defaults.ResourcePool), len(defaults.ResourcePool) > 0)
+	setDefault(inputs, "os", resource.NewStringProperty(defaults.Os), len(defaults.Os) > 0)
+	setDefault(inputs, "virtualHWVer", resource.NewNumberProperty(float64(defaults.HardwareVersion)), defaults.HardwareVersion > 0)
+	// An empty list of interfaces is kept as is.
+	setDefault(inputs, "networkInterfaces", resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty(defaults.Network)}),
+	}), len(defaults.Network) > 0)
+	return schema.ValidateVirtualMachine(virtualMachineToken, inputs)
+}
+
+func (virtualMachineResource) Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualMachineCreate(ctx, inputs, esxi)
+}
+
+func (virtualMachineResource) Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualMachineRead(ctx, id, inputs, esxi)
+}
+
+func (virtualMachineResource) Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	return VirtualMachineImport(ctx, id, esxi)
+}
+
+func (virtualMachineResource) Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualMachineUpdate(ctx, update, esxi)
+}
+
+func (virtualMachineResource) Delete(ctx context.Context, id string, esxi *Host) error {
+	return VirtualMachineDelete(ctx, id, esxi)
+}
+
+func VirtualMachineGet(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (resource.PropertyMap, error) {
 	var id string
 	if nameProp, has := inputs["name"]; has {
 		var err error
-		id, err = esxi.getVirtualMachineId(nameProp.StringValue())
+		id, err = esxi.getVirtualMachineId(ctx, nameProp.StringValue())
 		if err != nil {
 			return nil, err
 		}
@@ -25,44 +90,73 @@ func VirtualMachineGet(inputs resource.PropertyMap, esxi *Host) (resource.Proper
 		id = idProp.StringValue()
 	}
 
-	vm := esxi.readVirtualMachine(VirtualMachine{
+	vm, err := esxi.readVirtualMachine(ctx, VirtualMachine{
 		Id:             id,
 		StartupTimeout: vmDefaultStartupTimeout,
 	})
-
-	if len(vm.Name) == 0 {
-		return nil, fmt.Errorf("unable to find a virtual machine corresponding to the id '%s'", id)
+	if err != nil {
+		return nil, err
 	}
 
 	result := vm.toMap(true)
 	return resource.NewPropertyMapFromMap(result), nil
 }
 
-func VirtualMachineRead(id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+func VirtualMachineRead(ctx context.Context, id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	// read vm
-	vm := esxi.readVirtualMachine(VirtualMachine{
+	vm, err := esxi.readVirtualMachine(ctx, VirtualMachine{
 		Id:             id,
 		StartupTimeout: vmDefaultStartupTimeout,
 	})
-
-	if len(vm.Name) == 0 {
-		return "", nil, fmt.Errorf("unable to find a virtual machine corresponding to the id '%s'", id)
+	if err != nil {
+		return "", nil, err
 	}
 
 	result := vm.toMap()
 	return vm.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func VirtualMachineCreate(inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	vm := parseVirtualMachine("", inputs, esxi.Connection)
+// VirtualMachineImport reads the virtual machine imported by its vmid or by
+// its name.
+func VirtualMachineImport(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		name := id
+		id, err = esxi.getVirtualMachineId(ctx, name)
+		if err == nil && id == "" {
+			err = &NotFoundError{Kind: "virtual machine", Id: name}
+		}
+		if err != nil {
+			return "", nil, nil, notFoundOrError("virtual machine", name, "", err)
+		}
+	}
+
+	id, outputs, err := VirtualMachineRead(ctx, id, nil, esxi)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	inputs := importedInputs(outputs, "name", "diskStore", "resourcePoolName", "bootDiskSize", "bootDiskType",
+		"memSize", "numVCpus", "virtualHWVer", "os", "bootFirmware", "efiSecureBoot", "networkInterfaces",
+		"virtualDisks", "power", "notes", "info")
+	// The mac addresses are generated by the host.
+	if interfaces, has := inputs["networkInterfaces"]; has {
+		inputs["networkInterfaces"] = importedObjects(interfaces, "virtualNetwork", "nicType")
+	}
+	return id, inputs, outputs, nil
+}
+
+func VirtualMachineCreate(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	vm, err := esxi.prepareVirtualMachine("", inputs)
+	if err != nil {
+		return "", nil, err
+	}
 
 	powerOn := vm.Power == vmTurnedOn || vm.Power == ""
-	vm, err := esxi.createVirtualMachine(vm)
+	vm, err = esxi.createVirtualMachine(ctx, vm)
 	if err != nil {
 		return "", nil, err
 	}
 	if powerOn {
-		err = esxi.powerOnVirtualMachine(vm.Id)
+		err = esxi.powerOnVirtualMachine(ctx, vm.Id)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to power on the virtual machine")
 		}
@@ -70,64 +164,110 @@ func VirtualMachineCreate(inputs resource.PropertyMap, esxi *Host) (string, reso
 	}
 
 	// read vm
-	vm = esxi.readVirtualMachine(vm)
+	vm, err = esxi.readVirtualMachine(ctx, vm)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read the created virtual machine: %w", err)
+	}
 
 	result := vm.toMap()
 	return vm.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func VirtualMachineUpdate(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	vm := parseVirtualMachine(id, inputs, esxi.Connection)
+// vmxInputs are the inputs of the virtual machine written to its vmx file.
+var vmxInputs = []string{
+	"memSize", "numVCpus", "virtualHWVer", "os", "bootFirmware", "efiSecureBoot",
+	"notes", "info", "virtualDisks", "networkInterfaces",
+}
 
-	currentPowerState := esxi.getVirtualMachinePowerState(vm.Id)
-	if currentPowerState == vmTurnedOn || currentPowerState == vmTurnedSuspended {
-		esxi.powerOffVirtualMachine(vm.Id, vm.ShutdownTimeout)
+func VirtualMachineUpdate(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	id := update.Id
+	vm, err := esxi.prepareVirtualMachine(id, update.NewInputs)
+	if err != nil {
+		return id, nil, err
+	}
+	if esxi.api != nil {
+		if err := esxi.api.updateVirtualMachine(ctx, vm, esxi.sleep); err != nil {
+			return id, nil, err
+		}
+		return esxi.readUpdatedVirtualMachine(ctx, vm)
 	}
 
-	// make updates to vmx file
-	err := esxi.updateVmxContents(true, vm)
-	if err != nil {
-		return id, nil, fmt.Errorf("failed to update vmx contents: %w", err)
+	// The virtual machine is only powered off to change its vmx file or its
+	// boot disk, or when it is to be powered off.
+	reconfigure := update.Changed(vmxInputs...)
+	growBootDisk := update.Changed("bootDiskSize")
+	currentPowerState := esxi.getVirtualMachinePowerState(ctx, vm.Id)
+	if (reconfigure || growBootDisk || vm.Power != vmTurnedOn) &&
+		(currentPowerState == vmTurnedOn || currentPowerState == vmTurnedSuspended) {
+		esxi.powerOffVirtualMachine(ctx, vm.Id, vm.ShutdownTimeout)
+	}
+
+	// make updates to vmx file, the network interfaces are only rewritten when
+	// they changed, keeping the mac addresses generated by the host else.
+	if reconfigure {
+		err = esxi.updateVmxContents(ctx, update.Changed("networkInterfaces"), vm)
+		if err != nil {
+			return id, nil, fmt.Errorf("failed to update vmx contents: %w", err)
+		}
 	}
 
 	// Grow boot disk
-	bootDiskVmdkPath, _ := esxi.getBootDiskPath(vm.Id)
+	if growBootDisk {
+		bootDiskVmdkPath, _ := esxi.getBootDiskPath(ctx, vm.Id)
 
-	didGrow, err := esxi.growVirtualDisk(bootDiskVmdkPath, vm.BootDiskSize)
-	if err != nil {
-		return id, nil, fmt.Errorf("failed to grow boot disk: %w", err)
-	}
-	if didGrow {
-		_ = esxi.reloadVirtualMachine(id)
+		didGrow, err := esxi.growVirtualDisk(ctx, bootDiskVmdkPath, vm.BootDiskSize)
+		if err != nil {
+			return id, nil, fmt.Errorf("failed to grow boot disk: %w", err)
+		}
+		if didGrow {
+			_ = esxi.reloadVirtualMachine(ctx, id)
+		}
 	}
 	//  power on
 	if vm.Power == vmTurnedOn {
-		err = esxi.powerOnVirtualMachine(id)
+		err = esxi.powerOnVirtualMachine(ctx, id)
 		if err != nil {
 			return id, nil, fmt.Errorf("failed to power on: %w", err)
 		}
 	}
 
-	result := vm.toMap()
-	return vm.Id, resource.NewPropertyMapFromMap(result), nil
+	return esxi.readUpdatedVirtualMachine(ctx, vm)
 }
 
-func VirtualMachineDelete(id string, esxi *Host) error {
+// readUpdatedVirtualMachine returns the outputs of the updated virtual machine,
+// read back from the host like the created ones.
+func (esxi *Host) readUpdatedVirtualMachine(ctx context.Context, vm VirtualMachine) (string, resource.PropertyMap, error) {
+	updated, err := esxi.readVirtualMachine(ctx, vm)
+	if err != nil {
+		return vm.Id, nil, fmt.Errorf("failed to read the updated virtual machine: %w", err)
+	}
+
+	result := updated.toMap()
+	return updated.Id, resource.NewPropertyMapFromMap(result), nil
+}
+
+func VirtualMachineDelete(ctx context.Context, id string, esxi *Host) error {
+	if esxi.api != nil {
+		return esxi.api.deleteVirtualMachine(ctx, id, esxi.sleep)
+	}
+
 	var command, stdout string
 	var err error
 
-	esxi.powerOffVirtualMachine(id, vmDefaultShutdownTimeout)
+	esxi.powerOffVirtualMachine(ctx, id, vmDefaultShutdownTimeout)
 
 	// remove storage from vmx so it doesn't get deleted by the vim-cmd destroy
-	err = esxi.cleanStorageFromVmx(id)
+	err = esxi.cleanStorageFromVmx(ctx, id)
 	if err != nil {
 		logging.V(logLevel).Infof("VirtualMachineDelete: failed clean storage from id: %s (to be deleted)", id)
 	}
 
 	const waitTime = 5
-	time.Sleep(waitTime * time.Second)
-	command = fmt.Sprintf("vim-cmd vmsvc/destroy %s", id)
-	stdout, err = esxi.Execute(command, "vmsvc/destroy")
+	if err = esxi.sleep(ctx, waitTime*time.Second); err != nil {
+		return err
+	}
+	command = shellf("vim-cmd vmsvc/destroy %s", id)
+	stdout, err = esxi.Execute(ctx, command, "vmsvc/destroy")
 	if err != nil {
 		logging.V(logLevel).Infof("VirtualMachineDelete: failed to destroy vm: %s", stdout)
 		return fmt.Errorf("failed to destroy vm: %w", err)
@@ -147,6 +287,9 @@ func parseVirtualMachine(id string, inputs resource.PropertyMap, connection *Con
 	vm.SourcePath = parseSourcePath(inputs, connection)
 	vm.BootFirmware = parseStringProperty(inputs, "bootFirmware", "bios")
 	vm.DiskStore = inputs["diskStore"].StringValue()
+	if property, has := inputs["efiSecureBoot"]; has {
+		vm.EfiSecureBoot = property.BoolValue()
+	}
 	vm.ResourcePoolName = parseStringProperty(inputs, "resourcePoolName", "/")
 	if vm.ResourcePoolName == rootPool {
 		vm.ResourcePoolName = "/"
@@ -170,10 +313,24 @@ func parseVirtualMachine(id string, inputs resource.PropertyMap, connection *Con
 	return vm
 }
 
+// prepareVirtualMachine parses the virtual machine, with the defaults of the
+// host, and checks the host supports its settings.
+func (esxi *Host) prepareVirtualMachine(id string, inputs resource.PropertyMap) (VirtualMachine, error) {
+	vm := parseVirtualMachine(id, inputs, esxi.Connection)
+	if _, has := inputs["virtualHWVer"]; !has {
+		vm.VirtualHWVer = esxi.Info.defaultVirtualHWVer()
+	}
+	if err := esxi.Info.checkVirtualMachine(vm); err != nil {
+		return vm, err
+	}
+	return vm, nil
+}
+
 func parseSourcePath(inputs resource.PropertyMap, connection *ConnectionInfo) string {
 	if property, has := inputs["cloneFromVirtualMachine"]; has {
-		password := url.QueryEscape(connection.Password)
-		return fmt.Sprintf("vi://%s:%s@%s:%s/%s", connection.UserName, password, connection.Host, connection.SslPort, property.StringValue())
+		// The password is left out of the locator, ovftool prompts for it.
+		username := url.QueryEscape(connection.UserName)
+		return fmt.Sprintf("vi://%s@%s:%s/%s", username, connection.Host, connection.SslPort, property.StringValue())
 	}
 	if property, has := inputs["ovfSource"]; has {
 		return property.StringValue()
@@ -247,70 +404,76 @@ func parseKeyValuePairsProperty(inputs resource.PropertyMap, key string) []KeyVa
 	return []KeyValuePair{}
 }
 
-func (esxi *Host) readVirtualMachine(vm VirtualMachine) VirtualMachine {
-	var err error
-	command := fmt.Sprintf("vim-cmd  vmsvc/get.summary %s", vm.Id)
-	stdout, _ := esxi.Execute(command, "Get Guest summary")
+// readVirtualMachine returns the virtual machine read from the host, or a
+// NotFoundError when there is no virtual machine with its id.
+func (esxi *Host) readVirtualMachine(ctx context.Context, vm VirtualMachine) (VirtualMachine, error) {
+	if esxi.api != nil {
+		return esxi.api.readVirtualMachine(ctx, vm, esxi.sleep)
+	}
 
+	command := shellf("vim-cmd  vmsvc/get.summary %s", vm.Id)
+	stdout, err := esxi.ExecuteWithRetry(ctx, command, "Get Guest summary")
 	if strings.Contains(stdout, "Unable to find a VM corresponding") {
-		vm = VirtualMachine{Name: ""}
-		return vm
+		return VirtualMachine{}, &NotFoundError{Kind: "virtual machine", Id: vm.Id}
+	}
+	if err != nil {
+		return VirtualMachine{}, fmt.Errorf("failed to get the virtual machine %s summary: %s err: %w", vm.Id, stdout, err)
 	}
 
 	vm.patchWithSummary(stdout)
 
 	//  Get resource pool that this VM is located
-	vmResourcePoolId := esxi.getVMResourcePoolId(vm)
-	vm.ResourcePoolName, err = esxi.getResourcePoolName(vmResourcePoolId)
+	vmResourcePoolId := esxi.getVMResourcePoolId(ctx, vm)
+	vm.ResourcePoolName, err = esxi.getResourcePoolName(ctx, vmResourcePoolId)
 	logging.V(logLevel).Infof("readVirtualMachine: resource_pool_name|%s| scanner.Text() => |%s|", vmResourcePoolId, err)
 
-	vmxContents := esxi.readVMXContents(vm)
+	vmxContents := esxi.readVMXContents(ctx, vm)
 
 	vm.patchWithVMXContents(vmxContents)
 
 	//  Get power state
-	vm.Power = esxi.getVirtualMachinePowerState(vm.Id)
+	vm.Power = esxi.getVirtualMachinePowerState(ctx, vm.Id)
 	logging.V(logLevel).Infof("readVirtualMachine: Power => %s", vm.Power)
 
 	// Get IP address (need vmware tools installed)
 	if vm.Power == vmTurnedOn {
-		vm.IpAddress = esxi.getVirtualMachineIpAddress(vm.Id, vm.StartupTimeout)
+		vm.IpAddress = esxi.getVirtualMachineIpAddress(ctx, vm.Id, vm.StartupTimeout)
 		logging.V(logLevel).Infof("readVirtualMachine: IpAddress found => %s", vm.IpAddress)
 	} else {
 		vm.IpAddress = ""
 	}
 
 	// Get boot disk size
-	bootDiskPath, _ := esxi.getBootDiskPath(vm.Id)
-	vd, _ := esxi.getVirtualDisk(bootDiskPath)
+	bootDiskPath, _ := esxi.getBootDiskPath(ctx, vm.Id)
+	vd, _ := esxi.getVirtualDisk(ctx, bootDiskPath)
 	vm.BootDiskSize = vd.Size
 	vm.BootDiskType = vd.DiskType
 
 	// Get Info
 	vm.Info = extractGuestInfo(vmxContents)
 
-	return vm
+	return vm, nil
 }
 
-func (esxi *Host) getVMResourcePoolId(vm VirtualMachine) string {
-	command := fmt.Sprintf(`grep -A2 'objID>%s</objID' /etc/vmware/hostd/pools.xml | grep -o resourcePool.*resourcePool`, vm.Id)
-	stdout, _ := esxi.Execute(command, "check if guest is in resource pool")
+func (esxi *Host) getVMResourcePoolId(ctx context.Context, vm VirtualMachine) string {
+	command := shellf("grep -A2 -e %s /etc/vmware/hostd/pools.xml | grep -o resourcePool.*resourcePool", "objID>"+grepQuote(vm.Id)+"</objID")
+	stdout, _ := esxi.ExecuteWithRetry(ctx, command, "check if guest is in resource pool")
 	nr := strings.NewReplacer("resourcePool>", "", "</resourcePool", "")
 	vmResourcePoolId := nr.Replace(stdout)
 	logging.V(logLevel).Infof("readVirtualMachine: resource_pool_name|%s| scanner.Text() => |%s|", vmResourcePoolId, stdout)
 	return vmResourcePoolId
 }
 
-func (esxi *Host) readVMXContents(vm VirtualMachine) string {
+func (esxi *Host) readVMXContents(ctx context.Context, vm VirtualMachine) string {
 	// Implement reading VMX contents from the ESXi host
-	command := fmt.Sprintf("vim-cmd vmsvc/get.config %s | grep vmPathName|grep -oE \"\\[.*\\]\"", vm.Id)
-	stdout, _ := esxi.Execute(command, "get dst_vmx_ds")
+	command := shellf("vim-cmd vmsvc/get.config %s | grep vmPathName|grep -oE \"\\[.*\\]\"", vm.Id)
+	stdout, _ := esxi.ExecuteWithRetry(ctx, command, "get dst_vmx_ds")
 	dstVmxDs := stdout
 	dstVmxDs = strings.Trim(dstVmxDs, "[")
 	dstVmxDs = strings.Trim(dstVmxDs, "]")
 
-	command = fmt.Sprintf("vim-cmd vmsvc/get.config %s | grep vmPathName|awk '{print $NF}'|sed 's/[\"|,]//g'", vm.Id)
-	stdout, _ = esxi.Execute(command, "get dst_vmx")
+	command = shellf("vim-cmd vmsvc/get.config %s | grep vmPathName|awk '{print $NF}'|sed 's/[\"|,]//g'", vm.Id)
+	stdout, _ = esxi.ExecuteWithRetry(ctx, command, "get dst_vmx")
 	dstVmx := stdout
 
 	dstVmxFile := fmt.Sprintf("/vmfs/volumes/%s/%s", dstVmxDs, dstVmx)
@@ -318,8 +481,8 @@ func (esxi *Host) readVMXContents(vm VirtualMachine) string {
 	logging.V(logLevel).Infof("readVirtualMachine: dstVmxFile => %s", dstVmxFile)
 	logging.V(logLevel).Infof("readVirtualMachine: vm.DiskStore => %s  dstVmxDs => %s", vm.DiskStore, dstVmxDs)
 
-	command = fmt.Sprintf("cat \"%s\"", dstVmxFile)
-	vmxContents, _ := esxi.Execute(command, "read guest_name.vmx file")
+	command = shellf("cat %s", dstVmxFile)
+	vmxContents, _ := esxi.ExecuteWithRetry(ctx, command, "read guest_name.vmx file")
 	return vmxContents
 }
 
@@ -436,6 +599,11 @@ func (vm *VirtualMachine) patchWithVMXContents(vmxContents string) {
 				logging.V(logLevel).Infof("readVirtualMachine: %s => %s", results[0], results[3])
 			}
 
+		case strings.Contains(scanner.Text(), "uefi.secureBoot.enabled = "):
+			stdout := r.FindString(scanner.Text())
+			vm.EfiSecureBoot = strings.EqualFold(strings.ReplaceAll(stdout, `"`, ""), trueValue)
+			logging.V(logLevel).Infof("readVirtualMachine: EfiSecureBoot found => %t", vm.EfiSecureBoot)
+
 		case strings.Contains(scanner.Text(), "firmware = "):
 			stdout := r.FindString(scanner.Text())
 			vm.BootFirmware = strings.ReplaceAll(stdout, `"`, "")
@@ -2,13 +2,18 @@ package esxi
 
 import (
 	"bufio"
+	"context"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVirtualMachineCreate(t *testing.T) {
@@ -19,7 +24,7 @@ func TestVirtualMachineCreate(t *testing.T) {
 	inputs := getBaseVMInputs()
 
 	esxi := getESXi(t)
-	id, result, err := VirtualMachineCreate(inputs, esxi)
+	id, result, err := VirtualMachineCreate(context.Background(), inputs, esxi)
 	if err != nil {
 		t.Skipf("Test failed with err: %s", err)
 	}
@@ -33,6 +38,107 @@ func TestVirtualMachineCreate(t *testing.T) {
 	}
 }
 
+func TestVirtualMachineLifecycle(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	poolId, _, err := ResourcePoolCreate(ctx, resource.PropertyMap{"name": resource.NewStringProperty("pool-vm")}, esxi)
+	require.NoError(t, err)
+	diskId, _, err := VirtualDiskCreate(ctx, resource.PropertyMap{
+		"name":      resource.NewStringProperty("data"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("zeroedthick"),
+	}, esxi)
+	require.NoError(t, err)
+
+	inputs := getBaseVMInputs()
+	inputs["diskStore"] = resource.NewStringProperty("datastore1")
+	inputs["resourcePoolName"] = resource.NewStringProperty("pool-vm")
+	inputs["networkInterfaces"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("VM Network")}),
+	})
+	inputs["virtualDisks"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{
+			"virtualDiskId": resource.NewStringProperty(diskId),
+			"slot":          resource.NewStringProperty("0:1"),
+		}),
+	})
+
+	id, result, err := VirtualMachineCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Equal(t, "vm-test-9967a16", result["name"].StringValue())
+	require.Equal(t, "pool-vm", result["resourcePoolName"].StringValue())
+	require.Equal(t, 16.0, result["bootDiskSize"].NumberValue())
+	require.Equal(t, "on", result["power"].StringValue())
+	require.Equal(t, "192.168.20.101", result["ipAddress"].StringValue())
+
+	vmx, ok := fake.ReadFile("/vmfs/volumes/datastore1/vm-test-9967a16/vm-test-9967a16.vmx")
+	require.True(t, ok)
+	require.Contains(t, vmx, `ethernet0.networkName = "VM Network"`)
+	require.Contains(t, vmx, `scsi0:1.fileName = "`+diskId+`"`)
+
+	// The mac address generated by the host is kept when the network
+	// interfaces don't change, and the outputs are read back from the host.
+	vmxPath := "/vmfs/volumes/datastore1/vm-test-9967a16/vm-test-9967a16.vmx"
+	_, err = esxi.WriteFile(ctx, vmx+"ethernet0.generatedAddress = \"00:0c:29:12:34:56\"\n", vmxPath, "write vmx")
+	require.NoError(t, err)
+	olds := inputs.Copy()
+	inputs["memSize"] = resource.NewNumberProperty(1024)
+	inputs["bootDiskSize"] = resource.NewNumberProperty(20)
+	_, result, err = VirtualMachineUpdate(ctx, ResourceUpdate{Id: id, OldInputs: olds, NewInputs: inputs}, esxi)
+	require.NoError(t, err)
+	require.Equal(t, 1024.0, result["memSize"].NumberValue())
+	require.Equal(t, "192.168.20.101", result["ipAddress"].StringValue())
+	vmx, _ = fake.ReadFile(vmxPath)
+	require.Contains(t, vmx, `ethernet0.generatedAddress = "00:0c:29:12:34:56"`)
+	_, result, err = VirtualMachineRead(ctx, id, nil, esxi)
+	require.NoError(t, err)
+	require.Equal(t, 1024.0, result["memSize"].NumberValue())
+	require.Equal(t, 20.0, result["bootDiskSize"].NumberValue())
+
+	// The network interfaces are rewritten when they change.
+	olds = inputs.Copy()
+	inputs["networkInterfaces"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("Lab Network")}),
+	})
+	_, _, err = VirtualMachineUpdate(ctx, ResourceUpdate{Id: id, OldInputs: olds, NewInputs: inputs}, esxi)
+	require.NoError(t, err)
+	vmx, _ = fake.ReadFile(vmxPath)
+	require.Contains(t, vmx, `ethernet0.networkName = "Lab Network"`)
+	require.NotContains(t, vmx, "generatedAddress")
+
+	// The attached disks are detached before destroying the virtual machine.
+	require.NoError(t, VirtualMachineDelete(ctx, id, esxi))
+	_, _, err = VirtualMachineRead(ctx, id, nil, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+	_, ok = fake.ReadFile(diskId)
+	require.True(t, ok)
+	require.NoError(t, ResourcePoolDelete(ctx, poolId, esxi))
+}
+
+func TestBuildVirtualMachineFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.ova" {
+			http.NotFound(w, r)
+			return
+		}
+		// Hang until the client gives up.
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	esxi, _ := newFakeHost(t)
+	err := esxi.buildVirtualMachineFromSource(context.Background(), VirtualMachine{SourcePath: server.URL + "/missing.ova"})
+	require.ErrorContains(t, err, "404 Not Found")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = esxi.buildVirtualMachineFromSource(ctx, VirtualMachine{SourcePath: server.URL + "/image.ova"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func getBaseVMInputs() resource.PropertyMap {
 	inputs := resource.PropertyMap{
 		"bootDiskSize": {V: float64(16)},
@@ -72,7 +178,7 @@ func getESXi(t *testing.T) *Host {
 		}
 	}(file)
 
-	var host, sshPort, sslPort, user, pass string
+	var connection ConnectionInfo
 
 	// Read the file line by line
 	scanner := bufio.NewScanner(file)
@@ -95,15 +201,21 @@ func getESXi(t *testing.T) *Host {
 
 		switch key {
 		case "ESXI_HOST":
-			host = value
+			connection.Host = value
 		case "ESXI_USERNAME":
-			user = value
+			connection.UserName = value
 		case "ESXI_PASSWORD":
-			pass = value
+			connection.Password = value
 		case "ESXI_SSH_PORT":
-			sshPort = value
+			connection.SSHPort = value
 		case "ESXI_SSL_PORT":
-			sslPort = value
+			connection.SslPort = value
+		case "ESXI_PRIVATE_KEY_PATH":
+			connection.PrivateKeyPath = value
+		case "ESXI_PRIVATE_KEY_PASSPHRASE":
+			connection.PrivateKeyPassphrase = value
+		case "ESXI_USE_SSH_AGENT":
+			connection.UseSSHAgent = value == trueValue
 		}
 	}
 
@@ -112,7 +224,7 @@ func getESXi(t *testing.T) *Host {
 		return nil
 	}
 
-	esxiHost, err := NewHost(host, sshPort, sslPort, user, pass)
+	esxiHost, err := NewHost(context.Background(), connection)
 	if err != nil {
 		t.Skipf("Skipping test due failure on building ESXi host! Err: %s", err)
 	}
@@ -2,6 +2,7 @@ package esxi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,7 +10,6 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -17,21 +17,21 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 )
 
-func (esxi *Host) createPlainVirtualMachine(vm VirtualMachine) (VirtualMachine, error) {
+func (esxi *Host) createPlainVirtualMachine(ctx context.Context, vm VirtualMachine) (VirtualMachine, error) {
 	// check if path already exists.
 	fullPATH := fmt.Sprintf("/vmfs/volumes/%s/%s", vm.DiskStore, vm.Name)
-	bootDiskVmdkPath := fmt.Sprintf("\"/vmfs/volumes/%s/%s/%s.vmdk\"", vm.DiskStore, vm.Name, vm.Name)
-	command := fmt.Sprintf("ls -d %s", bootDiskVmdkPath)
-	stdout, _ := esxi.Execute(command, "check if guest path already exists.")
+	bootDiskVmdkPath := fmt.Sprintf("%s/%s.vmdk", fullPATH, vm.Name)
+	command := shellf("ls -d %s", bootDiskVmdkPath)
+	stdout, _ := esxi.ExecuteWithRetry(ctx, command, "check if guest path already exists.")
 	if !strings.Contains(stdout, "No such file or directory") {
 		return VirtualMachine{}, fmt.Errorf("virtual machine may already exists. vmdkPATH:%s", bootDiskVmdkPath)
 	}
 
-	command = fmt.Sprintf("ls -d \"%s\"", fullPATH)
-	stdout, _ = esxi.Execute(command, "check if guest path already exists.")
+	command = shellf("ls -d %s", fullPATH)
+	stdout, _ = esxi.ExecuteWithRetry(ctx, command, "check if guest path already exists.")
 	if strings.Contains(stdout, "No such file or directory") {
-		command = fmt.Sprintf("mkdir \"%s\"", fullPATH)
-		_, err := esxi.Execute(command, "create guest path")
+		command = shellf("mkdir %s", fullPATH)
+		_, err := esxi.Execute(ctx, command, "create guest path")
 		if err != nil {
 			return VirtualMachine{}, fmt.Errorf("failed to create guest path. fullPATH: %s", fullPATH)
 		}
@@ -70,8 +70,11 @@ scsi0:0.fileName = "%s.vmdk"
 scsi0:0.deviceType = "scsi-hardDisk"
 nvram = "%s.nvram"`, vm.VirtualHWVer, vm.Name, vm.NumVCpus, vm.MemSize, vm.Os, vm.Notes, vm.Name, vm.Name)
 
-	if vm.BootFirmware == "efi" {
+	if vm.BootFirmware == vmFirmwareEfi {
 		vmxContents += "\nfirmware = \"efi\""
+		if vm.EfiSecureBoot {
+			vmxContents += "\nuefi.secureBoot.enabled = \"TRUE\""
+		}
 	} else if vm.BootFirmware == "bios" {
 		vmxContents += "\nfirmware = \"bios\""
 	}
@@ -94,29 +97,29 @@ ide1:0.clientDevice = "TRUE"`
 	// Write vmx file to esxi host
 	dstVmxFile := fmt.Sprintf("%s/%s.vmx", fullPATH, vm.Name)
 
-	_, err := esxi.WriteFile(vmxContents, dstVmxFile, "write vmx file")
+	_, err := esxi.WriteFile(ctx, vmxContents, dstVmxFile, "write vmx file")
 	if err != nil {
 		return VirtualMachine{}, fmt.Errorf("failed to write vmx file %w", err)
 	}
 
 	// Create boot disk (vmdk)
-	command = fmt.Sprintf("vmkfstools -c %dG -d %s \"%s/%s.vmdk\"", vm.BootDiskSize, vm.BootDiskType, fullPATH, vm.Name)
-	_, err = esxi.Execute(command, "vmkfstools (make boot disk)")
+	command = shellf("vmkfstools -c %dG -d %s %s", vm.BootDiskSize, vm.BootDiskType, bootDiskVmdkPath)
+	_, err = esxi.Execute(ctx, command, "vmkfstools (make boot disk)")
 	if err != nil {
-		command = fmt.Sprintf("rm -fr \"%s\"", fullPATH)
-		_, _ = esxi.Execute(command, "cleanup guest path because of failed events")
+		command = shellf("rm -fr %s", fullPATH)
+		_, _ = esxi.Execute(ctx, command, "cleanup guest path because of failed events")
 		return VirtualMachine{}, fmt.Errorf("failed to vmkfstools (make boot disk) err:%w", err)
 	}
 
-	poolID, err := esxi.getResourcePoolId(vm.ResourcePoolName)
+	poolID, err := esxi.getResourcePoolId(ctx, vm.ResourcePoolName)
 	if err != nil {
 		return VirtualMachine{}, fmt.Errorf("failed to use Resource Pool ID:%s", poolID)
 	}
-	command = fmt.Sprintf("vim-cmd solo/registervm \"%s\" %s %s", dstVmxFile, vm.Name, poolID)
-	id, err := esxi.Execute(command, "solo/registervm")
+	command = shellf("vim-cmd solo/registervm %s %s %s", dstVmxFile, vm.Name, poolID)
+	id, err := esxi.Execute(ctx, command, "solo/registervm")
 	if err != nil {
-		command = fmt.Sprintf("rm -fr \"%s\"", fullPATH)
-		_, _ = esxi.Execute(command, "cleanup guest path because of failed events")
+		command = shellf("rm -fr %s", fullPATH)
+		_, _ = esxi.Execute(ctx, command, "cleanup guest path because of failed events")
 		return VirtualMachine{}, fmt.Errorf("failed to register guest err:%w", err)
 	}
 
@@ -125,33 +128,37 @@ ide1:0.clientDevice = "TRUE"`
 	return vm, nil
 }
 
-func (esxi *Host) createVirtualMachine(vm VirtualMachine) (VirtualMachine, error) {
+func (esxi *Host) createVirtualMachine(ctx context.Context, vm VirtualMachine) (VirtualMachine, error) {
+	if esxi.api != nil {
+		return esxi.api.createVirtualMachine(ctx, vm, esxi)
+	}
+
 	// Step 1: Check if Disk Store already exists
-	err := esxi.validateDiskStore(vm.DiskStore)
+	err := esxi.validateDiskStore(ctx, vm.DiskStore)
 	if err != nil {
 		return VirtualMachine{}, fmt.Errorf("failed to validate disk store: %w", err)
 	}
 
 	// Step 2: Check if guest already exists
-	vm, err = esxi.getOrCreateVirtualMachine(vm)
+	vm, err = esxi.getOrCreateVirtualMachine(ctx, vm)
 	if err != nil {
 		return VirtualMachine{}, err
 	}
 
 	// Step 3: Handle OVF properties, if present
-	err = esxi.handleOvfProperties(vm)
+	err = esxi.handleOvfProperties(ctx, vm)
 	if err != nil {
 		return VirtualMachine{}, err
 	}
 
 	// Step 4: Grow boot disk to boot_disk_size
-	err = esxi.growBootDisk(vm.Id, vm.BootDiskSize)
+	err = esxi.growBootDisk(ctx, vm.Id, vm.BootDiskSize)
 	if err != nil {
 		return VirtualMachine{}, err
 	}
 
 	// Step 5: Make updates to the vmx file
-	err = esxi.updateVmxContents(true, vm)
+	err = esxi.updateVmxContents(ctx, true, vm)
 	if err != nil {
 		return VirtualMachine{}, fmt.Errorf("failed to update vmx contents: %w", err)
 	}
@@ -160,8 +167,8 @@ func (esxi *Host) createVirtualMachine(vm VirtualMachine) (VirtualMachine, error
 }
 
 // getOrCreateVirtualMachine checks if the virtual machine already exists or creates it if not.
-func (esxi *Host) getOrCreateVirtualMachine(vm VirtualMachine) (VirtualMachine, error) {
-	id, err := esxi.getVirtualMachineId(vm.Name)
+func (esxi *Host) getOrCreateVirtualMachine(ctx context.Context, vm VirtualMachine) (VirtualMachine, error) {
+	id, err := esxi.getVirtualMachineId(ctx, vm.Name)
 	if err != nil {
 		return VirtualMachine{}, fmt.Errorf("failed to get VM ID: %w", err)
 	}
@@ -169,25 +176,25 @@ func (esxi *Host) getOrCreateVirtualMachine(vm VirtualMachine) (VirtualMachine,
 	switch {
 	case id != "":
 		// VM already exists, power off guest if it's powered on or suspended
-		currentPowerState := esxi.getVirtualMachinePowerState(id)
+		currentPowerState := esxi.getVirtualMachinePowerState(ctx, id)
 		if currentPowerState == vmTurnedOn || currentPowerState == vmTurnedSuspended {
-			esxi.powerOffVirtualMachine(id, vm.ShutdownTimeout)
+			esxi.powerOffVirtualMachine(ctx, id, vm.ShutdownTimeout)
 		}
 	case vm.SourcePath == "none":
 		// Create a plain virtual machine
-		vm, err = esxi.createPlainVirtualMachine(vm)
+		vm, err = esxi.createPlainVirtualMachine(ctx, vm)
 		if err != nil {
 			return VirtualMachine{}, err
 		}
 	default:
 		// Build VM with ovftool or copy from local source
-		err = esxi.buildVirtualMachineFromSource(vm)
+		err = esxi.buildVirtualMachineFromSource(ctx, vm)
 		if err != nil {
 			return VirtualMachine{}, err
 		}
 
 		// Retrieve the VM ID after building the virtual machine
-		id, err = esxi.getVirtualMachineId(vm.Name)
+		id, err = esxi.getVirtualMachineId(ctx, vm.Name)
 		if err != nil {
 			return VirtualMachine{}, fmt.Errorf("failed to get VM ID: %w", err)
 		}
@@ -198,25 +205,27 @@ func (esxi *Host) getOrCreateVirtualMachine(vm VirtualMachine) (VirtualMachine,
 }
 
 // handleOvfProperties handles OVF properties injection and power off if necessary.
-func (esxi *Host) handleOvfProperties(vm VirtualMachine) error {
+func (esxi *Host) handleOvfProperties(ctx context.Context, vm VirtualMachine) error {
 	if len(vm.OvfProperties) > 0 {
-		currentPowerState := esxi.getVirtualMachinePowerState(vm.Id)
+		currentPowerState := esxi.getVirtualMachinePowerState(ctx, vm.Id)
 		if currentPowerState != vmTurnedOn {
 			return fmt.Errorf("failed to power on after ovfProperties injection")
 		}
 
 		// Allow cloud-init to process.
 		duration := time.Duration(vm.OvfPropertiesTimer) * time.Second
-		time.Sleep(duration)
-		esxi.powerOffVirtualMachine(vm.Id, vm.ShutdownTimeout)
+		if err := esxi.sleep(ctx, duration); err != nil {
+			return err
+		}
+		esxi.powerOffVirtualMachine(ctx, vm.Id, vm.ShutdownTimeout)
 	}
 	return nil
 }
 
 // growBootDisk grows the boot disk to the specified size.
-func (esxi *Host) growBootDisk(id string, bootDiskSize int) error {
-	bootDiskVmdkPath, _ := esxi.getBootDiskPath(id)
-	_, err := esxi.growVirtualDisk(bootDiskVmdkPath, bootDiskSize)
+func (esxi *Host) growBootDisk(ctx context.Context, id string, bootDiskSize int) error {
+	bootDiskVmdkPath, _ := esxi.getBootDiskPath(ctx, id)
+	_, err := esxi.growVirtualDisk(ctx, bootDiskVmdkPath, bootDiskSize)
 	if err != nil {
 		return fmt.Errorf("failed to grow boot disk: %w", err)
 	}
@@ -224,19 +233,26 @@ func (esxi *Host) growBootDisk(id string, bootDiskSize int) error {
 }
 
 // buildVirtualMachineFromSource builds the virtual machine using ovftool or copies from a local source.
-func (esxi *Host) buildVirtualMachineFromSource(vm VirtualMachine) error {
+func (esxi *Host) buildVirtualMachineFromSource(ctx context.Context, vm VirtualMachine) error {
 	switch {
 	case strings.HasPrefix(vm.SourcePath, "http://") || strings.HasPrefix(vm.SourcePath, "https://"):
 		// If the source is a remote URL, check its accessibility
-		resp, err := http.Get(vm.SourcePath)
-		if err != nil || resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("URL not accessible: %s", vm.SourcePath)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, vm.SourcePath, nil)
+		if err != nil {
+			return fmt.Errorf("invalid URL %s: %w", vm.SourcePath, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("URL not accessible: %s: %w", vm.SourcePath, err)
 		}
 		defer func(Body io.ReadCloser) {
 			if e := Body.Close(); e != nil {
 				logging.V(logLevel).Info(e)
 			}
 		}(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("URL not accessible: %s: %s", vm.SourcePath, resp.Status)
+		}
 	case strings.HasPrefix(vm.SourcePath, "vi://"):
 		logging.V(logLevel).Infof("Source is Guest VM (vi).\n")
 	default:
@@ -246,105 +262,100 @@ func (esxi *Host) buildVirtualMachineFromSource(vm VirtualMachine) error {
 		}
 	}
 
+	if esxi.Info != nil && !esxi.Info.HasOvftool {
+		return fmt.Errorf("building a virtual machine from '%s' requires ovftool, it was not found on the PATH of the provider", vm.SourcePath)
+	}
+
+	// ovftool authenticates against the host API, ssh keys are of no use there.
+	if len(esxi.Connection.Password) == 0 {
+		return fmt.Errorf("building a virtual machine from '%s' requires the password to be configured, ovftool does not support key authentication", vm.SourcePath)
+	}
+
 	// Set params for packer
 	if vm.BootDiskType == vdZeroedThick {
 		vm.BootDiskType = "thick"
 	}
 
+	hostAddress := fmt.Sprintf("%s:%s", esxi.Connection.Host, esxi.Connection.SslPort)
+	if esxi.bastion != nil {
+		// ovftool can't go through the bastion itself, it connects to a local port forwarded to the host.
+		localAddress, stop, err := esxi.bastion.forward(ctx, hostAddress)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		// The virtual machines are cloned from the same host.
+		vm.SourcePath = strings.Replace(vm.SourcePath, "@"+hostAddress+"/", "@"+localAddress+"/", 1)
+		hostAddress = localAddress
+	}
+
+	// The password is kept off the command line, visible to the other processes,
+	// ovftool prompts for the password of each locator without one and reads it
+	// from its standard input: the source one first when cloning, then the target.
 	username := url.QueryEscape(esxi.Connection.UserName)
-	password := url.QueryEscape(esxi.Connection.Password)
-	dstPath := fmt.Sprintf("vi://%s:%s@%s:%s/", username, password, esxi.Connection.Host, esxi.Connection.SslPort)
+	dstPath := fmt.Sprintf("vi://%s@%s/", username, hostAddress)
+	passwords := esxi.Connection.Password + "\n"
+	if strings.HasPrefix(vm.SourcePath, "vi://"+username+"@"+hostAddress+"/") {
+		passwords += esxi.Connection.Password + "\n"
+	}
 	if vm.ResourcePoolName != "/" {
 		dstPath = fmt.Sprintf("%s/%s", dstPath, vm.ResourcePoolName)
 	}
 
-	netParam := ""
-	if (strings.HasSuffix(vm.SourcePath, ".ova") || strings.HasSuffix(vm.SourcePath, ".ovf")) && len(vm.NetworkInterfaces) > 0 && vm.NetworkInterfaces[0].VirtualNetwork != "" {
-		netParam = fmt.Sprintf(" --network='%s'", vm.NetworkInterfaces[0].VirtualNetwork)
-	}
-
-	extraParams := "--X:logToConsole --X:logLevel=info"
-	if len(vm.OvfProperties) > 0 && (strings.HasSuffix(vm.SourcePath, ".ova") || strings.HasSuffix(vm.SourcePath, ".ovf")) {
+	// ovftool is run without a shell, its arguments are passed as is.
+	args := []string{"--acceptAllEulas", "--noSSLVerify", "--X:useMacNaming=false", "--X:logToConsole", "--X:logLevel=info"}
+	isOvf := strings.HasSuffix(vm.SourcePath, ".ova") || strings.HasSuffix(vm.SourcePath, ".ovf")
+	if len(vm.OvfProperties) > 0 && isOvf {
 		// Inject OVF properties if available
-		extraParams = fmt.Sprintf("%s --X:injectOvfEnv --allowExtraConfig --powerOn", extraParams)
+		args = append(args, "--X:injectOvfEnv", "--allowExtraConfig", "--powerOn")
 
 		for _, prop := range vm.OvfProperties {
 			value, err := ParseTemplate(prop.Value, vm)
 			if err != nil {
 				return fmt.Errorf("unable to parse templated ovfProperty '%s', err: %w", prop.Key, err)
 			}
-			extraParams = fmt.Sprintf("%s --prop:%s='%s'", extraParams, prop.Key, value)
+			if esxi.redact(prop.Value) != prop.Value {
+				// The rendered value of a secret property is a secret as well.
+				esxi.redactor.add(value)
+			}
+			args = append(args, fmt.Sprintf("--prop:%s=%s", prop.Key, value))
 		}
 	}
 
-	ovfCmd := fmt.Sprintf("ovftool --acceptAllEulas --noSSLVerify --X:useMacNaming=false %s -dm=%s --name='%s' --overwrite -ds='%s'%s '%s' '%s'",
-		extraParams, vm.BootDiskType, vm.Name, vm.DiskStore, netParam, vm.SourcePath, dstPath)
-
-	osShellCmd := "/bin/bash"
-	osShellCmdOpt := "-c"
-
-	var ovfBat *os.File
-	if runtime.GOOS == "windows" {
-		// For Windows, create a batch file and execute it
-		ovfCmd = strings.ReplaceAll(ovfCmd, "'", "\"")
-
-		var err error
-		ovfBat, err = os.CreateTemp("", "ovfCmd*.bat")
-		if err != nil {
-			return fmt.Errorf("unable to create temporary batch file: %w", err)
-		}
-		defer RemoveFile(ovfBat)
-
-		// Write the ovftool command to the batch file
-		file, err := os.Create(ovfBat.Name())
-		if err != nil {
-			return fmt.Errorf("unable to create batch file: %w", err)
-		}
-		defer CloseFile(file)
-
-		_, err = file.WriteString(strings.ReplaceAll(ovfCmd, "%", "%%"))
-		if err != nil {
-			return fmt.Errorf("unable to write to batch file: %w", err)
-		}
-
-		err = file.Close()
-		if err != nil {
-			return fmt.Errorf("unable to close batch file: %w", err)
-		}
-
-		ovfCmd = ovfBat.Name()
-		osShellCmd = "cmd.exe"
-		osShellCmdOpt = "/c"
+	args = append(args, "-dm="+vm.BootDiskType, "--name="+vm.Name, "--overwrite", "-ds="+vm.DiskStore)
+	if isOvf && len(vm.NetworkInterfaces) > 0 && vm.NetworkInterfaces[0].VirtualNetwork != "" {
+		args = append(args, "--network="+vm.NetworkInterfaces[0].VirtualNetwork)
 	}
+	args = append(args, vm.SourcePath, dstPath)
 
 	// Execute ovftool command
-	cmd := exec.Command(osShellCmd, osShellCmdOpt, ovfCmd)
+	cmd := exec.CommandContext(ctx, "ovftool", args...)
+	cmd.Stdin = strings.NewReader(passwords)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	err := cmd.Run()
 
-	// Clean up temporary batch file for Windows
-	if runtime.GOOS == "windows" {
-		_ = cmd.Wait()
-		_ = os.Remove(ovfBat.Name())
-	}
-
 	// Check for errors during ovftool execution
 	if err != nil {
-		return fmt.Errorf("ovftool error: %w; command: %s; stdout: %s", err, ovfCmd, out.String())
+		return fmt.Errorf("ovftool error: %w; command: %s; stdout: %s",
+			err, esxi.redact(shellJoin("ovftool", args...)), esxi.redact(out.String()))
 	}
 
 	return nil
 }
 
-func (esxi *Host) getVirtualMachineId(name string) (string, error) {
+func (esxi *Host) getVirtualMachineId(ctx context.Context, name string) (string, error) {
+	if esxi.api != nil {
+		return esxi.api.virtualMachineId(ctx, name)
+	}
+
 	var command, id string
 	var err error
 
-	command = fmt.Sprintf("vim-cmd vmsvc/getallvms 2>/dev/null |sort -n | "+
-		"grep -m 1 \"[0-9] * %s .*%s\" |awk '{print $1}' ", name, name)
+	command = shellf("vim-cmd vmsvc/getallvms 2>/dev/null |sort -n | "+
+		"grep -m 1 -e %s |awk '{print $1}' ", "[0-9] * "+grepQuote(name)+" .*"+grepQuote(name))
 
-	id, err = esxi.Execute(command, "get vm Id")
+	id, err = esxi.ExecuteWithRetry(ctx, command, "get vm Id")
 	logging.V(logLevel).Infof("getVirtualMachineId: result => %s", id)
 	if err != nil {
 		logging.V(logLevel).Infof("getVirtualMachineId: Failed get vm id => %s", err)
@@ -355,14 +366,14 @@ func (esxi *Host) getVirtualMachineId(name string) (string, error) {
 }
 
 /*
-func (esxi *Host) validateVirtualMachineId(id string) (string, error) {
+func (esxi *Host) validateVirtualMachineId(ctx context.Context, id string) (string, error) {
 	var command string
 	var err error
 
-	command = fmt.Sprintf("vim-cmd vmsvc/getallvms 2>/dev/null | awk '{print $1}' | "+
-		"grep '^%s$'", id)
+	command = shellf("vim-cmd vmsvc/getallvms 2>/dev/null | awk '{print $1}' | "+
+		"grep -e %s", "^"+grepQuote(id)+"$")
 
-	id, err = esxi.Execute(command, "validate vm id exists")
+	id, err = esxi.ExecuteWithRetry(ctx, command, "validate vm id exists")
 	logging.V(logLevel).Infof("validateVirtualMachineId: result => %s", id)
 	if err != nil {
 		logging.V(logLevel).Infof("validateVirtualMachineId: Failed get vm by id => %s", err)
@@ -373,12 +384,12 @@ func (esxi *Host) validateVirtualMachineId(id string) (string, error) {
 }
 */
 
-func (esxi *Host) getBootDiskPath(id string) (string, error) {
+func (esxi *Host) getBootDiskPath(ctx context.Context, id string) (string, error) {
 	var command, stdout string
 	var err error
 
-	command = fmt.Sprintf("vim-cmd vmsvc/device.getdevices %s | grep -A10 -e 'key = 2000' -e 'key = 3000' -e 'key = 16000'|grep -m 1 fileName", id)
-	stdout, err = esxi.Execute(command, "get boot disk")
+	command = shellf("vim-cmd vmsvc/device.getdevices %s | grep -A10 -e 'key = 2000' -e 'key = 3000' -e 'key = 16000'|grep -m 1 fileName", id)
+	stdout, err = esxi.ExecuteWithRetry(ctx, command, "get boot disk")
 	if err != nil {
 		logging.V(logLevel).Infof("getBootDiskPath: Failed get boot disk path => %s", stdout)
 		return "Failed get boot disk path:", err
@@ -387,31 +398,37 @@ func (esxi *Host) getBootDiskPath(id string) (string, error) {
 	return r.Replace(stdout), err
 }
 
-func (esxi *Host) getDstVmxFile(id string) (string, error) {
+func (esxi *Host) getDstVmxFile(ctx context.Context, id string) (string, error) {
 	// Get location of vmx file on esxi host
-	command := fmt.Sprintf("vim-cmd vmsvc/get.config %s | grep vmPathName|grep -oE \"\\[.*\\]\"", id)
-	dstVmxDs, _ := esxi.Execute(command, "get dstVmxDs")
+	command := shellf("vim-cmd vmsvc/get.config %s | grep vmPathName|grep -oE \"\\[.*\\]\"", id)
+	dstVmxDs, _ := esxi.ExecuteWithRetry(ctx, command, "get dstVmxDs")
 	dstVmxDs = strings.Trim(dstVmxDs, "[")
 	dstVmxDs = strings.Trim(dstVmxDs, "]")
 
-	command = fmt.Sprintf("vim-cmd vmsvc/get.config %s | grep vmPathName|awk '{print $NF}'|sed 's/[\"|,]//g'", id)
-	dstVmx, err := esxi.Execute(command, "get dstVmx")
+	command = shellf("vim-cmd vmsvc/get.config %s | grep vmPathName|awk '{print $NF}'|sed 's/[\"|,]//g'", id)
+	dstVmx, err := esxi.ExecuteWithRetry(ctx, command, "get dstVmx")
 
 	dstVmxFile := fmt.Sprintf("/vmfs/volumes/%s/%s", dstVmxDs, dstVmx)
 	return dstVmxFile, err
 }
 
-func (esxi *Host) readVmxContents(id string) (string, error) {
-	dstVmxFile, _ := esxi.getDstVmxFile(id)
-	command := fmt.Sprintf("cat \"%s\"", dstVmxFile)
-	vmxContents, err := esxi.Execute(command, "read vmx file")
+func (esxi *Host) readVmxContents(ctx context.Context, id string) (string, error) {
+	dstVmxFile, _ := esxi.getDstVmxFile(ctx, id)
+	command := shellf("cat %s", dstVmxFile)
+	vmxContents, err := esxi.ExecuteWithRetry(ctx, command, "read vmx file")
 
 	return vmxContents, err
 }
 
-func (esxi *Host) updateVmxContents(isNew bool, vm VirtualMachine) error {
+func (esxi *Host) updateVmxContents(ctx context.Context, isNew bool, vm VirtualMachine) error {
+	unlock, err := esxi.lockVirtualMachine(ctx, vm.Id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Read existing vmxContents
-	vmxContents, err := esxi.readVmxContents(vm.Id)
+	vmxContents, err := esxi.readVmxContents(ctx, vm.Id)
 	if err != nil {
 		return fmt.Errorf("failed to get vmx contents: %w", err)
 	}
@@ -438,6 +455,7 @@ func (esxi *Host) updateVmxContents(isNew bool, vm VirtualMachine) error {
 	}
 
 	vmxContents = replaceVMXSetting("firmware", vm.BootFirmware, vmxContents)
+	vmxContents = setVMXSecureBoot(vm.EfiSecureBoot, vmxContents)
 
 	// Modify annotation
 	if vm.Notes != "" {
@@ -471,20 +489,30 @@ func (esxi *Host) updateVmxContents(isNew bool, vm VirtualMachine) error {
 	}
 
 	// Write updated vmxContents back to ESXi host
-	dstVmxFile, err := esxi.getDstVmxFile(vm.Id)
+	dstVmxFile, err := esxi.getDstVmxFile(ctx, vm.Id)
 	if err != nil {
 		return fmt.Errorf("failed to get destination vmx file: %w", err)
 	}
 
-	_, err = esxi.WriteFile(strings.ReplaceAll(vmxContents, "\\\"", "\""), dstVmxFile, "write vmx file")
+	_, err = esxi.WriteFile(ctx, strings.ReplaceAll(vmxContents, "\\\"", "\""), dstVmxFile, "write vmx file")
 	if err != nil {
 		return fmt.Errorf("failed to write vmx file: %w", err)
 	}
 
-	err = esxi.reloadVirtualMachine(vm.Id)
+	err = esxi.reloadVirtualMachine(ctx, vm.Id)
 	return err
 }
 
+// setVMXSecureBoot enables or disables the UEFI secure boot.
+func setVMXSecureBoot(enabled bool, vmxContents string) string {
+	re := regexp.MustCompile(`(?m)^uefi\.secureBoot\.enabled = ".*"\n?`)
+	vmxContents = re.ReplaceAllString(vmxContents, "")
+	if enabled {
+		vmxContents = strings.TrimSuffix(vmxContents, "\n") + "\nuefi.secureBoot.enabled = \"TRUE\""
+	}
+	return vmxContents
+}
+
 // replaceVMXSetting replaces or adds the given VMX setting in the vmxContents.
 func replaceVMXSetting(settingName string, value interface{}, vmxContents string) string {
 	re := regexp.MustCompile(settingName + ` = ".*"`)
@@ -572,8 +600,14 @@ ethernet%d.present = "TRUE"
 	return vmxContents
 }
 
-func (esxi *Host) cleanStorageFromVmx(id string) error {
-	vmxContents, err := esxi.readVmxContents(id)
+func (esxi *Host) cleanStorageFromVmx(ctx context.Context, id string) error {
+	unlock, err := esxi.lockVirtualMachine(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	vmxContents, err := esxi.readVmxContents(ctx, id)
 	if err != nil {
 		logging.V(logLevel).Infof("cleanStorageFromVmx: Failed get vmx contents => %s", err)
 		return fmt.Errorf("failed to get vmx contents: %w", err)
@@ -590,34 +624,40 @@ func (esxi *Host) cleanStorageFromVmx(id string) error {
 	}
 
 	// Write vmx file to esxi host
-	dstVmxFile, _ := esxi.getDstVmxFile(id)
-	_, err = esxi.CopyFile(strings.ReplaceAll(vmxContents, "\\\"", "\""), dstVmxFile, "write vmx file")
+	dstVmxFile, _ := esxi.getDstVmxFile(ctx, id)
+	_, err = esxi.WriteFile(ctx, strings.ReplaceAll(vmxContents, "\\\"", "\""), dstVmxFile, "write vmx file")
 	if err != nil {
 		return fmt.Errorf("failed to write vmx file %w", err)
 	}
 
-	err = esxi.reloadVirtualMachine(id)
+	err = esxi.reloadVirtualMachine(ctx, id)
 	return err
 }
 
-func (esxi *Host) reloadVirtualMachine(id string) error {
-	command := fmt.Sprintf("vim-cmd vmsvc/reload %s", id)
-	_, err := esxi.Execute(command, "vmsvc/reload")
+func (esxi *Host) reloadVirtualMachine(ctx context.Context, id string) error {
+	command := shellf("vim-cmd vmsvc/reload %s", id)
+	_, err := esxi.ExecuteWithRetry(ctx, command, "vmsvc/reload")
 
 	return err
 }
 
-func (esxi *Host) powerOnVirtualMachine(id string) error {
-	if esxi.getVirtualMachinePowerState(id) == vmTurnedOn {
+func (esxi *Host) powerOnVirtualMachine(ctx context.Context, id string) error {
+	if esxi.api != nil {
+		return esxi.api.powerOnVirtualMachine(ctx, id)
+	}
+
+	if esxi.getVirtualMachinePowerState(ctx, id) == vmTurnedOn {
 		return nil
 	}
 
-	command := fmt.Sprintf("vim-cmd vmsvc/power.on %s", id)
-	_, err := esxi.Execute(command, "vmsvc/power.on")
+	command := shellf("vim-cmd vmsvc/power.on %s", id)
+	_, err := esxi.Execute(ctx, command, "vmsvc/power.on")
 
-	time.Sleep(vmSleepBetweenPowerStateChecks * time.Second)
+	if sleepErr := esxi.sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second); sleepErr != nil {
+		return sleepErr
+	}
 
-	if esxi.getVirtualMachinePowerState(id) == vmTurnedOn {
+	if esxi.getVirtualMachinePowerState(ctx, id) == vmTurnedOn {
 		return nil
 	}
 
@@ -629,8 +669,8 @@ func (esxi *Host) powerOnVirtualMachine(id string) error {
 // If the virtual machine is turned on, it tries to gracefully shut it down before powering off.
 // The shutdownTimeout parameter specifies the maximum time (in seconds) to wait for the VM to shut down.
 // If shutdownTimeout is 0, the VM will be powered off immediately without attempting a graceful shutdown.
-func (esxi *Host) powerOffVirtualMachine(id string, shutdownTimeout int) {
-	savedPowerState := esxi.getVirtualMachinePowerState(id)
+func (esxi *Host) powerOffVirtualMachine(ctx context.Context, id string, shutdownTimeout int) {
+	savedPowerState := esxi.getVirtualMachinePowerState(ctx, id)
 
 	if savedPowerState == vmTurnedOff {
 		// VM is already turned off, no need to do anything.
@@ -640,36 +680,40 @@ func (esxi *Host) powerOffVirtualMachine(id string, shutdownTimeout int) {
 	if savedPowerState == vmTurnedOn {
 		if shutdownTimeout > 0 {
 			// Try to gracefully shut down the VM first.
-			command := fmt.Sprintf("vim-cmd vmsvc/power.shutdown %s", id)
-			_, _ = esxi.Execute(command, "vmsvc/power.shutdown")
-			time.Sleep(vmSleepBetweenPowerStateChecks * time.Second)
+			command := shellf("vim-cmd vmsvc/power.shutdown %s", id)
+			_, _ = esxi.Execute(ctx, command, "vmsvc/power.shutdown")
+			if esxi.sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second) != nil {
+				return
+			}
 
 			for i := 0; i < (shutdownTimeout / vmSleepBetweenPowerStateChecks); i++ {
-				if esxi.getVirtualMachinePowerState(id) == vmTurnedOff {
+				if esxi.getVirtualMachinePowerState(ctx, id) == vmTurnedOff {
 					// VM is successfully shut down.
 					return
 				}
-				time.Sleep(vmSleepBetweenPowerStateChecks * time.Second)
+				if esxi.sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second) != nil {
+					return
+				}
 			}
 		}
 
 		// VM is either still running after the timeout or no graceful shutdown attempted.
 		// Power off the VM forcefully.
-		command := fmt.Sprintf("vim-cmd vmsvc/power.off %s", id)
-		_, _ = esxi.Execute(command, "vmsvc/power.off")
-		time.Sleep(1 * time.Second)
+		command := shellf("vim-cmd vmsvc/power.off %s", id)
+		_, _ = esxi.Execute(ctx, command, "vmsvc/power.off")
+		_ = esxi.sleep(ctx, 1*time.Second)
 
 		return
 	}
 
 	// VM power state is unknown, just power it off forcefully.
-	command := fmt.Sprintf("vim-cmd vmsvc/power.off %s", id)
-	_, _ = esxi.Execute(command, "vmsvc/power.off")
+	command := shellf("vim-cmd vmsvc/power.off %s", id)
+	_, _ = esxi.Execute(ctx, command, "vmsvc/power.off")
 }
 
-func (esxi *Host) getVirtualMachinePowerState(id string) string {
-	command := fmt.Sprintf("vim-cmd vmsvc/power.getstate %s", id)
-	stdout, _ := esxi.Execute(command, "vmsvc/power.getstate")
+func (esxi *Host) getVirtualMachinePowerState(ctx context.Context, id string) string {
+	command := shellf("vim-cmd vmsvc/power.getstate %s", id)
+	stdout, _ := esxi.ExecuteWithRetry(ctx, command, "vmsvc/power.getstate")
 	if strings.Contains(stdout, "Unable to find a VM corresponding") {
 		return esxiUnknown
 	}
@@ -686,12 +730,12 @@ func (esxi *Host) getVirtualMachinePowerState(id string) string {
 	}
 }
 
-func (esxi *Host) getVirtualMachineIpAddress(id string, startupTimeout int) string {
+func (esxi *Host) getVirtualMachineIpAddress(ctx context.Context, id string, startupTimeout int) string {
 	var command, stdout, ipAddress, ipAddress2 string
 	var uptime int
 
 	// Check if powered off
-	if esxi.getVirtualMachinePowerState(id) != vmTurnedOn {
+	if esxi.getVirtualMachinePowerState(ctx, id) != vmTurnedOn {
 		return ""
 	}
 
@@ -699,18 +743,20 @@ func (esxi *Host) getVirtualMachineIpAddress(id string, startupTimeout int) stri
 	uptime = 0
 	for uptime < startupTimeout {
 		// Primary method to get IP
-		command = fmt.Sprintf("vim-cmd vmsvc/get.guest %s 2>/dev/null |sed '1!G;h;$!d' |awk '/deviceConfigId = 4000/,/ipAddress/' |grep -m 1 -oE '((1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\\.){3}(1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])'", id)
-		stdout, _ = esxi.Execute(command, "get ip_address method 1")
+		command = shellf("vim-cmd vmsvc/get.guest %s 2>/dev/null |sed '1!G;h;$!d' |awk '/deviceConfigId = 4000/,/ipAddress/' |grep -m 1 -oE '((1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\\.){3}(1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])'", id)
+		stdout, _ = esxi.Execute(ctx, command, "get ip_address method 1")
 		ipAddress = stdout
 		if ipAddress != "" {
 			return ipAddress
 		}
 
-		time.Sleep(vmSleepBetweenPowerStateChecks * time.Second)
+		if esxi.sleep(ctx, vmSleepBetweenPowerStateChecks*time.Second) != nil {
+			return ""
+		}
 
 		// Get uptime if above failed.
-		command = fmt.Sprintf("vim-cmd vmsvc/get.summary %s 2>/dev/null | grep 'uptimeSeconds ='|sed 's/^.*= //g'|sed s/,//g", id)
-		stdout, err := esxi.Execute(command, "get uptime")
+		command = shellf("vim-cmd vmsvc/get.summary %s 2>/dev/null | grep 'uptimeSeconds ='|sed 's/^.*= //g'|sed s/,//g", id)
+		stdout, err := esxi.Execute(ctx, command, "get uptime")
 		if err != nil {
 			return ""
 		}
@@ -718,8 +764,8 @@ func (esxi *Host) getVirtualMachineIpAddress(id string, startupTimeout int) stri
 	}
 
 	// Alternate method to get IP
-	command = fmt.Sprintf("vim-cmd vmsvc/get.guest %s 2>/dev/null | grep -m 1 '^   ipAddress = ' | grep -oE '((1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\\.){3}(1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])'", id)
-	stdout, _ = esxi.Execute(command, "get ip_address method 2")
+	command = shellf("vim-cmd vmsvc/get.guest %s 2>/dev/null | grep -m 1 '^   ipAddress = ' | grep -oE '((1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])\\.){3}(1?[0-9][0-9]?|2[0-4][0-9]|25[0-5])'", id)
+	stdout, _ = esxi.Execute(ctx, command, "get ip_address method 2")
 	ipAddress2 = stdout
 	if ipAddress2 != "" {
 		return ipAddress2
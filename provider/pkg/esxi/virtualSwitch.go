@@ -1,6 +1,7 @@
 package esxi
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"regexp"
@@ -8,14 +9,66 @@ import (
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/schema"
 )
 
-func VirtualSwitchCreate(inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+const virtualSwitchToken = "esxi-native:index:VirtualSwitch"
+
+func init() {
+	registerResource(virtualSwitchToken, virtualSwitchResource{resourceRules{
+		diff: DiffRules{
+			Replaces: []string{"name"},
+			Names:    []string{"name"},
+		},
+	}})
+}
+
+// virtualSwitchResource implements the VirtualSwitch resource with the functions
+// below.
+type virtualSwitchResource struct {
+	resourceRules
+}
+
+func (virtualSwitchResource) Check(inputs resource.PropertyMap, defaults Defaults) []*pulumirpc.CheckFailure {
+	return schema.ValidateVirtualSwitch(virtualSwitchToken, inputs)
+}
+
+func (virtualSwitchResource) Create(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualSwitchCreate(ctx, inputs, esxi)
+}
+
+func (virtualSwitchResource) Read(ctx context.Context, id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualSwitchRead(ctx, id, inputs, esxi)
+}
+
+func (virtualSwitchResource) Import(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	return VirtualSwitchImport(ctx, id, esxi)
+}
+
+func (virtualSwitchResource) Update(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	return VirtualSwitchUpdate(ctx, update, esxi)
+}
+
+func (virtualSwitchResource) Delete(ctx context.Context, id string, esxi *Host) error {
+	return VirtualSwitchDelete(ctx, id, esxi)
+}
+
+func VirtualSwitchCreate(ctx context.Context, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
 	vs := parseVirtualSwitch("", inputs)
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.createVirtualSwitch(ctx, vs)
+	}
 
 	//  Create vswitch
-	command := fmt.Sprintf("esxcli network vswitch standard add -P %d -v \"%s\"", vs.Ports, vs.Name)
-	stdout, err := esxi.Execute(command, "create vswitch")
+	command := shellf("esxcli network vswitch standard add -P %d -v %s", vs.Ports, vs.Name)
+	stdout, err := esxi.Execute(ctx, command, "create vswitch")
 	if strings.Contains(stdout, "this name already exists") {
 		return "", nil, fmt.Errorf("failed to create vswitch: %s, it already exists", vs.Name)
 	}
@@ -24,13 +77,13 @@ func VirtualSwitchCreate(inputs resource.PropertyMap, esxi *Host) (string, resou
 	}
 
 	var somethingWentWrong string
-	err = esxi.updateVirtualSwitch(vs)
+	err = esxi.updateVirtualSwitch(ctx, vs, newResourceUpdate("", inputs))
 	if err != nil {
 		somethingWentWrong = fmt.Sprintf("failed to update vswitch: %s", err)
 	}
 
 	// Refresh
-	id, result, err := esxi.readVirtualSwitch(vs.Name)
+	id, result, err := esxi.readVirtualSwitch(ctx, vs.Name)
 	if err != nil {
 		return "", nil, err
 	}
@@ -42,21 +95,38 @@ func VirtualSwitchCreate(inputs resource.PropertyMap, esxi *Host) (string, resou
 	return id, result, nil
 }
 
-func VirtualSwitchUpdate(id string, inputs resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	vs := parseVirtualSwitch(id, inputs)
+func VirtualSwitchUpdate(ctx context.Context, update ResourceUpdate, esxi *Host) (string, resource.PropertyMap, error) {
+	vs := parseVirtualSwitch(update.Id, update.NewInputs)
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.updateVirtualSwitch(ctx, vs)
+	}
 
-	err := esxi.updateVirtualSwitch(vs)
+	err = esxi.updateVirtualSwitch(ctx, vs, update)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to update vswitch: %w", err)
 	}
 
-	return esxi.readVirtualSwitch(vs.Name)
+	return esxi.readVirtualSwitch(ctx, vs.Name)
 }
 
-func VirtualSwitchDelete(id string, esxi *Host) error {
-	command := fmt.Sprintf("esxcli network vswitch standard remove -v \"%s\"", id)
+func VirtualSwitchDelete(ctx context.Context, id string, esxi *Host) error {
+	unlock, err := esxi.lockTopology(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if esxi.api != nil {
+		return esxi.api.deleteVirtualSwitch(ctx, id)
+	}
+
+	command := shellf("esxcli network vswitch standard remove -v %s", id)
 
-	stdout, err := esxi.Execute(command, "delete vswitch")
+	stdout, err := esxi.Execute(ctx, command, "delete vswitch")
 	if err != nil {
 		return fmt.Errorf("failed to delete vswitch: %s err: %w", stdout, err)
 	}
@@ -64,8 +134,22 @@ func VirtualSwitchDelete(id string, esxi *Host) error {
 	return nil
 }
 
-func VirtualSwitchRead(id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
-	return esxi.readVirtualSwitch(id)
+func VirtualSwitchRead(ctx context.Context, id string, _ resource.PropertyMap, esxi *Host) (string, resource.PropertyMap, error) {
+	if esxi.api != nil {
+		return esxi.api.readVirtualSwitch(ctx, id)
+	}
+	return esxi.readVirtualSwitch(ctx, id)
+}
+
+// VirtualSwitchImport reads the virtual switch imported by its name.
+func VirtualSwitchImport(ctx context.Context, id string, esxi *Host) (string, resource.PropertyMap, resource.PropertyMap, error) {
+	id, outputs, err := VirtualSwitchRead(ctx, id, nil, esxi)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	inputs := importedInputs(outputs, "name", "ports", "mtu", "linkDiscoveryMode",
+		"promiscuousMode", "macChanges", "forgedTransmits", "uplinks")
+	return id, inputs, outputs, nil
 }
 
 func parseVirtualSwitch(id string, inputs resource.PropertyMap) VirtualSwitch {
@@ -125,8 +209,8 @@ func parseVirtualSwitch(id string, inputs resource.PropertyMap) VirtualSwitch {
 	return vs
 }
 
-func (esxi *Host) readVirtualSwitch(name string) (string, resource.PropertyMap, error) {
-	vs, err := esxi.getVirtualSwitch(name)
+func (esxi *Host) readVirtualSwitch(ctx context.Context, name string) (string, resource.PropertyMap, error) {
+	vs, err := esxi.getVirtualSwitch(ctx, name)
 	if err != nil {
 		return "", nil, err
 	}
@@ -135,31 +219,40 @@ func (esxi *Host) readVirtualSwitch(name string) (string, resource.PropertyMap,
 	return vs.Id, resource.NewPropertyMapFromMap(result), nil
 }
 
-func (esxi *Host) updateVirtualSwitch(vs VirtualSwitch) error {
+// updateVirtualSwitch sets the mtu and the link discovery mode, the security
+// policy and the uplinks of the virtual switch, when they changed.
+func (esxi *Host) updateVirtualSwitch(ctx context.Context, vs VirtualSwitch, update ResourceUpdate) error {
 	var command, stdout string
 	var err error
 
 	//  Set mtu and cdp
-	command = fmt.Sprintf("esxcli network vswitch standard set -m %d -c \"%s\" -v \"%s\"",
-		vs.Mtu, vs.LinkDiscoveryMode, vs.Name)
+	if update.Changed("mtu", "linkDiscoveryMode") {
+		command = shellf("esxcli network vswitch standard set -m %d -c %s -v %s",
+			vs.Mtu, vs.LinkDiscoveryMode, vs.Name)
 
-	stdout, err = esxi.Execute(command, "set vswitch mtu, link_discovery_mode")
-	if err != nil {
-		return fmt.Errorf("failed to set vswitch mtu: %s err: %w", stdout, err)
+		stdout, err = esxi.ExecuteWithRetry(ctx, command, "set vswitch mtu, link_discovery_mode")
+		if err != nil {
+			return fmt.Errorf("failed to set vswitch mtu: %s err: %w", stdout, err)
+		}
 	}
 
 	//  Set security
-	command = fmt.Sprintf("esxcli network vswitch standard policy security set -f %t -m %t -p %t -v \"%s\"",
-		vs.PromiscuousMode, vs.MacChanges, vs.ForgedTransmits, vs.Name)
+	if update.Changed("forgedTransmits", "macChanges", "promiscuousMode") {
+		command = shellf("esxcli network vswitch standard policy security set -f %t -m %t -p %t -v %s",
+			vs.ForgedTransmits, vs.MacChanges, vs.PromiscuousMode, vs.Name)
 
-	stdout, err = esxi.Execute(command, "set vswitch security")
-	if err != nil {
-		return fmt.Errorf("failed to set vswitch security: %s err: %w", stdout, err)
+		stdout, err = esxi.ExecuteWithRetry(ctx, command, "set vswitch security")
+		if err != nil {
+			return fmt.Errorf("failed to set vswitch security: %s err: %w", stdout, err)
+		}
 	}
 
 	//  Update uplinks
-	command = fmt.Sprintf("esxcli network vswitch standard list -v \"%s\"", vs.Name)
-	stdout, err = esxi.Execute(command, "vswitch list")
+	if !update.Changed("uplinks") {
+		return nil
+	}
+	command = shellf("esxcli network vswitch standard list -v %s", vs.Name)
+	stdout, err = esxi.ExecuteWithRetry(ctx, command, "vswitch list")
 
 	if err != nil {
 		return fmt.Errorf("failed to list vswitch: %s err: %w", stdout, err)
@@ -175,10 +268,10 @@ func (esxi *Host) updateVirtualSwitch(vs VirtualSwitch) error {
 	//  Add uplink if needed
 	for i := range vs.Uplinks {
 		if !Contains(foundUplinks, vs.Uplinks[i].Name) {
-			command = fmt.Sprintf("esxcli network vswitch standard uplink add -u \"%s\" -v \"%s\"",
+			command = shellf("esxcli network vswitch standard uplink add -u %s -v %s",
 				vs.Uplinks[i].Name, vs.Name)
 
-			stdout, err = esxi.Execute(command, "vswitch add uplink")
+			stdout, err = esxi.Execute(ctx, command, "vswitch add uplink")
 			if strings.Contains(stdout, "Not a valid pnic") {
 				return fmt.Errorf("uplink not found: %s", vs.Uplinks[i].Name)
 			}
@@ -193,10 +286,10 @@ func (esxi *Host) updateVirtualSwitch(vs VirtualSwitch) error {
 	for _, item := range foundUplinks {
 		if !ContainsValue(vs.Uplinks, selector, item) {
 			log.Printf("[vswitchUpdate] delete uplink (%s)\n", item)
-			command = fmt.Sprintf("esxcli network vswitch standard uplink remove -u \"%s\" -v \"%s\"",
+			command = shellf("esxcli network vswitch standard uplink remove -u %s -v %s",
 				item, vs.Name)
 
-			stdout, err = esxi.Execute(command, "vswitch remove uplink")
+			stdout, err = esxi.Execute(ctx, command, "vswitch remove uplink")
 			if err != nil {
 				return fmt.Errorf("failed to remove vswitch uplink: %s err: %w", stdout, err)
 			}
@@ -206,7 +299,7 @@ func (esxi *Host) updateVirtualSwitch(vs VirtualSwitch) error {
 	return nil
 }
 
-func (esxi *Host) getVirtualSwitch(name string) (VirtualSwitch, error) {
+func (esxi *Host) getVirtualSwitch(ctx context.Context, name string) (VirtualSwitch, error) {
 	vs := VirtualSwitch{
 		Id:   name,
 		Name: name,
@@ -214,11 +307,14 @@ func (esxi *Host) getVirtualSwitch(name string) (VirtualSwitch, error) {
 	var command, stdout string
 	var err error
 
-	command = fmt.Sprintf("esxcli network vswitch standard list -v \"%s\"", name)
-	stdout, _ = esxi.Execute(command, "vswitch list")
-
+	command = shellf("esxcli network vswitch standard list -v %s", name)
+	stdout, err = esxi.ExecuteWithRetry(ctx, command, "vswitch list")
+	if err != nil {
+		return VirtualSwitch{}, notFoundOrError("virtual switch", name, stdout,
+			fmt.Errorf("failed to list vswitch %s: %s err: %w", name, stdout, err))
+	}
 	if stdout == "" {
-		return VirtualSwitch{}, fmt.Errorf(stdout)
+		return VirtualSwitch{}, &NotFoundError{Kind: "virtual switch", Id: name}
 	}
 
 	re := regexp.MustCompile(`Configured Ports: ([0-9]*)`)
@@ -256,8 +352,8 @@ func (esxi *Host) getVirtualSwitch(name string) (VirtualSwitch, error) {
 		vs.Uplinks = vs.Uplinks[:0]
 	}
 
-	command = fmt.Sprintf("esxcli network vswitch standard policy security get -v \"%s\"", name)
-	stdout, _ = esxi.Execute(command, "vswitch policy security get")
+	command = shellf("esxcli network vswitch standard policy security get -v %s", name)
+	stdout, _ = esxi.ExecuteWithRetry(ctx, command, "vswitch policy security get")
 
 	if stdout == "" {
 		log.Printf("[vswitchRead] Failed to run %s: %s\n", "vswitch policy security get", err)
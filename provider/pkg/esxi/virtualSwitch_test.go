@@ -0,0 +1,54 @@
+package esxi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualSwitchLifecycle(t *testing.T) {
+	esxi, fake := newFakeHost(t)
+	ctx := context.Background()
+
+	inputs := resource.PropertyMap{
+		"name":            resource.NewStringProperty("vSwitch-test"),
+		"mtu":             resource.NewNumberProperty(9000),
+		"promiscuousMode": resource.NewBoolProperty(true),
+		"upLinks": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("vmnic1")}),
+		}),
+	}
+	id, result, err := VirtualSwitchCreate(ctx, inputs, esxi)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch-test", id)
+	require.Equal(t, 9000.0, result["mtu"].NumberValue())
+	require.Equal(t, 128.0, result["ports"].NumberValue())
+	require.True(t, result["promiscuousMode"].BoolValue())
+	require.False(t, result["macChanges"].BoolValue())
+	require.False(t, result["forgedTransmits"].BoolValue())
+	require.Equal(t, "vmnic1", result["uplinks"].ArrayValue()[0].ObjectValue()["name"].StringValue())
+
+	_, _, err = VirtualSwitchCreate(ctx, inputs, esxi)
+	require.ErrorContains(t, err, "it already exists")
+
+	inputs["forgedTransmits"] = resource.NewBoolProperty(true)
+	inputs["upLinks"] = resource.NewArrayProperty(nil)
+	_, result, err = VirtualSwitchUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.NoError(t, err)
+	require.True(t, result["forgedTransmits"].BoolValue())
+	require.NotContains(t, result, resource.PropertyKey("uplinks"))
+
+	inputs["upLinks"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("vmnic9")}),
+	})
+	_, _, err = VirtualSwitchUpdate(ctx, newResourceUpdate(id, inputs), esxi)
+	require.ErrorContains(t, err, "uplink not found: vmnic9")
+
+	require.NoError(t, VirtualSwitchDelete(ctx, id, esxi))
+	require.Contains(t, fake.Commands(), "esxcli network vswitch standard remove -v vSwitch-test")
+	require.Error(t, VirtualSwitchDelete(ctx, id, esxi))
+	_, _, err = VirtualSwitchRead(ctx, id, nil, esxi)
+	require.True(t, IsNotFound(err), "%v", err)
+}
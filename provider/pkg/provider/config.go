@@ -0,0 +1,571 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+const configPrefix = "esxi-native:config:"
+
+// configError is an invalid value of a config key, reported on that key by CheckConfig.
+type configError struct {
+	key string
+	err error
+}
+
+func (e *configError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+func invalidConfig(key string, format string, args ...interface{}) error {
+	return &configError{key: key, err: fmt.Errorf(format, args...)}
+}
+
+// hostnamePattern matches the RFC 1123 host names.
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*\.?$`)
+
+func isHostname(host string) bool {
+	const maxHostnameLength = 253
+	return net.ParseIP(host) != nil || (len(host) <= maxHostnameLength && hostnamePattern.MatchString(host))
+}
+
+// autoNamingPlaceholder matches the placeholders of the auto-naming pattern,
+// and autoNamingCharset the characters allowed in the random part of the names.
+var (
+	autoNamingPlaceholder = regexp.MustCompile(`\$\{([^}]*)\}`)
+	autoNamingCharset     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+func isPort(port string) bool {
+	const maxPort = 65535
+	value, err := strconv.Atoi(port)
+	return err == nil && value >= 1 && value <= maxPort
+}
+
+// getConnection reads the connection of the default host from the provider config,
+// the ports default to 22 for SSH and 443 for SSL. The connection is returned
+// along with the error of an invalid value, to check the other values.
+func getConnection(vars map[string]string) (esxi.ConnectionInfo, error) {
+	host, _ := getConfig(vars, "host", "ESXI_HOST")
+	user, _ := getConfig(vars, "username", "ESXI_USERNAME")
+	pass, _ := getConfig(vars, "password", "ESXI_PASSWORD")
+	sshPort, _ := getConfig(vars, "sshPort", "ESXI_SSH_PORT")
+	sslPort, _ := getConfig(vars, "sslPort", "ESXI_SSL_PORT")
+	if len(sshPort) == 0 {
+		sshPort = "22"
+	}
+	if len(sslPort) == 0 {
+		sslPort = "443"
+	}
+
+	privateKey, _ := getConfig(vars, "privateKey", "ESXI_PRIVATE_KEY")
+	privateKeyPath, _ := getConfig(vars, "privateKeyPath", "ESXI_PRIVATE_KEY_PATH")
+	privateKeyPassphrase, _ := getConfig(vars, "privateKeyPassphrase", "ESXI_PRIVATE_KEY_PASSPHRASE")
+	useSSHAgent, _ := getConfig(vars, "useSshAgent", "ESXI_USE_SSH_AGENT")
+	hostKeyFingerprint, _ := getConfig(vars, "hostKeyFingerprint", "ESXI_HOST_KEY_FINGERPRINT")
+	knownHostsFile, _ := getConfig(vars, "knownHostsFile", "ESXI_KNOWN_HOSTS_FILE")
+	trustOnFirstUse, _ := getConfig(vars, "trustOnFirstUse", "ESXI_TRUST_ON_FIRST_USE")
+	sslThumbprint, _ := getConfig(vars, "sslThumbprint", "ESXI_SSL_THUMBPRINT")
+	insecureSkipHostKeyVerification, _ := getConfig(vars, "insecureSkipHostKeyVerification", "ESXI_INSECURE_SKIP_HOST_KEY_VERIFICATION")
+	transport, _ := getConfig(vars, "transport", "ESXI_TRANSPORT")
+	bastionHost, _ := getConfig(vars, "bastionHost", "ESXI_BASTION_HOST")
+	bastionPort, _ := getConfig(vars, "bastionPort", "ESXI_BASTION_PORT")
+	bastionUser, _ := getConfig(vars, "bastionUser", "ESXI_BASTION_USER")
+	bastionPassword, _ := getConfig(vars, "bastionPassword", "ESXI_BASTION_PASSWORD")
+	bastionPrivateKey, _ := getConfig(vars, "bastionPrivateKey", "ESXI_BASTION_PRIVATE_KEY")
+	bastionPrivateKeyPath, _ := getConfig(vars, "bastionPrivateKeyPath", "ESXI_BASTION_PRIVATE_KEY_PATH")
+	recordFile, _ := getConfig(vars, "recordFile", "ESXI_RECORD_FILE")
+	readOnly, _ := getConfig(vars, "readOnly", "ESXI_READ_ONLY")
+	dryRun, _ := getConfig(vars, "dryRun", "ESXI_DRY_RUN")
+
+	connection := esxi.ConnectionInfo{
+		Transport:                       transport,
+		Host:                            host,
+		SSHPort:                         sshPort,
+		SslPort:                         sslPort,
+		UserName:                        user,
+		Password:                        pass,
+		PrivateKey:                      privateKey,
+		PrivateKeyPath:                  privateKeyPath,
+		PrivateKeyPassphrase:            privateKeyPassphrase,
+		UseSSHAgent:                     useSSHAgent == "true",
+		HostKeyFingerprint:              hostKeyFingerprint,
+		KnownHostsFile:                  knownHostsFile,
+		TrustOnFirstUse:                 trustOnFirstUse == "true",
+		SslThumbprint:                   sslThumbprint,
+		InsecureSkipHostKeyVerification: insecureSkipHostKeyVerification == "true",
+		BastionHost:                     bastionHost,
+		BastionPort:                     bastionPort,
+		BastionUser:                     bastionUser,
+		BastionPassword:                 bastionPassword,
+		BastionPrivateKey:               bastionPrivateKey,
+		BastionPrivateKeyPath:           bastionPrivateKeyPath,
+		RecordFile:                      recordFile,
+		ReadOnly:                        readOnly == "true",
+		DryRun:                          dryRun == "true",
+	}
+
+	var err error
+	if connection.TrustedHostKeys, err = getTrustedHostKeys(vars); err != nil {
+		return connection, err
+	}
+	if connection.Retry, err = getRetryPolicy(vars); err != nil {
+		return connection, err
+	}
+	if connection.MaxConcurrentSessions, err = getMaxConcurrentSessions(vars); err != nil {
+		return connection, err
+	}
+	return connection, nil
+}
+
+// configVars returns the provider config of CheckConfig as the variables of
+// Configure, along with the keys whose values are unknown during a preview.
+func configVars(news resource.PropertyMap) (map[string]string, []string) {
+	vars := map[string]string{}
+	var unknowns []string
+	for key, value := range news {
+		for value.IsSecret() {
+			value = value.SecretValue().Element
+		}
+		switch {
+		case value.ContainsUnknowns():
+			unknowns = append(unknowns, string(key))
+		case value.IsString():
+			vars[configPrefix+string(key)] = value.StringValue()
+		case value.IsBool():
+			vars[configPrefix+string(key)] = strconv.FormatBool(value.BoolValue())
+		case value.IsNumber():
+			vars[configPrefix+string(key)] = strconv.FormatFloat(value.NumberValue(), 'f', -1, 64)
+		case value.IsObject():
+			if encoded, err := json.Marshal(value.ObjectValue().Mappable()); err == nil {
+				vars[configPrefix+string(key)] = string(encoded)
+			}
+		}
+	}
+	sort.Strings(unknowns)
+	return vars, unknowns
+}
+
+// checkConfig validates the provider config: the host names, the ports, the
+// values of the options and the conflicting options. The keys in unknowns
+// are not checked.
+func checkConfig(vars map[string]string, unknowns []string) []*pulumirpc.CheckFailure {
+	var failures []*pulumirpc.CheckFailure
+	isUnknown := func(keys ...string) bool {
+		for _, unknown := range unknowns {
+			for _, key := range keys {
+				if unknown == key {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	fail := func(key string, format string, args ...interface{}) {
+		if !isUnknown(key) {
+			failures = append(failures, &pulumirpc.CheckFailure{Property: key, Reason: fmt.Sprintf(format, args...)})
+		}
+	}
+	failErr := func(err error) {
+		var configErr *configError
+		if errors.As(err, &configErr) {
+			fail(configErr.key, "%s", configErr.err)
+		} else {
+			fail("", "%s", err)
+		}
+	}
+
+	connection, err := getConnection(vars)
+	if err != nil {
+		failErr(err)
+	}
+	hostsConfig, err := getHostsConfig(vars)
+	if err != nil {
+		failErr(err)
+	}
+	if _, err = getResourceDefaults(vars); err != nil {
+		failErr(err)
+	}
+	if _, err = getAutoNamingConfig(vars); err != nil {
+		failErr(err)
+	}
+
+	// With named hosts, the default host is optional.
+	defaultHost := len(connection.Host) > 0 || (len(hostsConfig) == 0 && !isUnknown("hosts"))
+	if _, hostErr := getConfig(vars, "host", "ESXI_HOST"); len(connection.Host) == 0 && defaultHost {
+		fail("host", "%s", hostErr)
+	}
+	if _, userErr := getConfig(vars, "username", "ESXI_USERNAME"); len(connection.UserName) == 0 && defaultHost {
+		fail("username", "%s", userErr)
+	}
+	if defaultHost && !hasCredentials(connection) && !isUnknown("privateKey", "privateKeyPath", "useSshAgent", "transport") {
+		fail("password", "%s", missingCredentials(connection.Transport))
+	}
+
+	for _, host := range []struct{ key, value string }{{"host", connection.Host}, {"bastionHost", connection.BastionHost}} {
+		if len(host.value) > 0 && !isHostname(host.value) {
+			fail(host.key, "invalid %s '%s', expected a host name or an IP address", host.key, host.value)
+		}
+	}
+	for _, port := range []struct{ key, value string }{
+		{"sshPort", connection.SSHPort}, {"sslPort", connection.SslPort}, {"bastionPort", connection.BastionPort},
+	} {
+		if len(port.value) > 0 && !isPort(port.value) {
+			fail(port.key, "invalid %s '%s', expected a port number between 1 and 65535", port.key, port.value)
+		}
+	}
+	if len(connection.SslThumbprint) > 0 && !esxi.IsSslThumbprint(connection.SslThumbprint) {
+		fail("sslThumbprint", "invalid sslThumbprint '%s', expected the SHA-1 or SHA-256 thumbprint of the certificate in hex",
+			connection.SslThumbprint)
+	}
+	if transport := connection.Transport; transport != "" && transport != esxi.TransportSSH && transport != esxi.TransportAPI {
+		fail("transport", "invalid transport '%s', expected '%s' or '%s'", transport, esxi.TransportSSH, esxi.TransportAPI)
+	}
+	for _, key := range []string{
+		"useSshAgent", "trustOnFirstUse", "insecureSkipHostKeyVerification", "readOnly", "dryRun", "preflight", "autoNaming",
+	} {
+		if value, has := vars[configPrefix+key]; has && value != "true" && value != "false" {
+			fail(key, "invalid %s '%s', expected true or false", key, value)
+		}
+	}
+
+	// The conflicting options, of which only one would be used.
+	if len(connection.PrivateKey) > 0 && len(connection.PrivateKeyPath) > 0 {
+		fail("privateKeyPath", "privateKeyPath conflicts with privateKey, set only one of them")
+	}
+	if len(connection.PrivateKeyPassphrase) > 0 && len(connection.PrivateKey) == 0 && len(connection.PrivateKeyPath) == 0 {
+		fail("privateKeyPassphrase", "privateKeyPassphrase requires privateKey or privateKeyPath")
+	}
+	if len(connection.HostKeyFingerprint) > 0 && connection.TrustOnFirstUse {
+		fail("trustOnFirstUse", "trustOnFirstUse conflicts with hostKeyFingerprint, the host key is verified against the fingerprint")
+	}
+	if connection.InsecureSkipHostKeyVerification && verifiesHostKeys(connection) {
+		fail("insecureSkipHostKeyVerification", "insecureSkipHostKeyVerification conflicts with hostKeyFingerprint, "+
+			"knownHostsFile and trustOnFirstUse, set only one of them")
+	}
+	if defaultHost && !isUnknown("hostKeyFingerprint", "knownHostsFile", "trustOnFirstUse", "insecureSkipHostKeyVerification", "transport") {
+		if host := unverifiedHost(connection); len(host) > 0 {
+			fail("hostKeyFingerprint", "the ssh host key of '%s' isn't verified, set hostKeyFingerprint, knownHostsFile or "+
+				"trustOnFirstUse, or insecureSkipHostKeyVerification to accept any host key", host)
+		}
+	}
+	if defaultHost && !isUnknown("sslThumbprint", "insecureSkipHostKeyVerification", "transport") && unverifiedCertificate(connection) {
+		fail("sslThumbprint", "the certificate of '%s' isn't verified by the api transport, set sslThumbprint, "+
+			"or insecureSkipHostKeyVerification to accept any certificate", connection.Host)
+	}
+	if connection.ReadOnly && connection.DryRun {
+		fail("dryRun", "dryRun conflicts with readOnly, set only one of them")
+	}
+	if connection.DryRun && connection.Transport == esxi.TransportAPI {
+		fail("dryRun", "dryRun requires the '%s' transport", esxi.TransportSSH)
+	}
+
+	names := make([]string, 0, len(hostsConfig))
+	for name := range hostsConfig {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hostConfig := hostsConfig[name]
+		named := hostConfig.connection(connection)
+		switch {
+		case !isHostname(named.Host):
+			fail("hosts", "invalid host '%s' of the host '%s', expected a host name or an IP address", named.Host, name)
+		case !isPort(named.SSHPort) || !isPort(named.SslPort):
+			fail("hosts", "invalid sshPort '%s' or sslPort '%s' of the host '%s', expected port numbers between 1 and 65535",
+				named.SSHPort, named.SslPort, name)
+		case len(named.UserName) == 0 || !hasCredentials(named):
+			fail("hosts", "the host '%s' has no username or credentials, and none are inherited from the provider config", name)
+		case len(unverifiedHost(named)) > 0:
+			fail("hosts", "the ssh host key of the host '%s' isn't verified, set its hostKeyFingerprint, or knownHostsFile, "+
+				"trustOnFirstUse or insecureSkipHostKeyVerification in the provider config", name)
+		case len(named.SslThumbprint) > 0 && !esxi.IsSslThumbprint(named.SslThumbprint):
+			fail("hosts", "invalid sslThumbprint '%s' of the host '%s', expected the SHA-1 or SHA-256 thumbprint of the "+
+				"certificate in hex", named.SslThumbprint, name)
+		case unverifiedCertificate(named):
+			fail("hosts", "the certificate of the host '%s' isn't verified by the api transport, set its sslThumbprint, "+
+				"or insecureSkipHostKeyVerification in the provider config", name)
+		}
+	}
+
+	return failures
+}
+
+// verifiesHostKeys returns true when a host key verification is configured.
+func verifiesHostKeys(connection esxi.ConnectionInfo) bool {
+	return len(connection.HostKeyFingerprint) > 0 || len(connection.KnownHostsFile) > 0 || connection.TrustOnFirstUse
+}
+
+// unverifiedHost returns the host or the bastion whose ssh host key isn't
+// verified by the connection, empty when every key is verified or when the
+// verification is explicitly skipped. The fingerprint only applies to the host,
+// and the api transport doesn't connect to its ssh port.
+func unverifiedHost(connection esxi.ConnectionInfo) string {
+	shared := len(connection.KnownHostsFile) > 0 || connection.TrustOnFirstUse || connection.InsecureSkipHostKeyVerification
+	switch {
+	case shared:
+		return ""
+	case len(connection.BastionHost) > 0:
+		return connection.BastionHost
+	case connection.Transport != esxi.TransportAPI && len(connection.HostKeyFingerprint) == 0:
+		return connection.Host
+	}
+	return ""
+}
+
+// unverifiedCertificate returns true when the api transport would connect to
+// the host without verifying its certificate, and the verification isn't
+// explicitly skipped.
+func unverifiedCertificate(connection esxi.ConnectionInfo) bool {
+	return connection.Transport == esxi.TransportAPI && len(connection.SslThumbprint) == 0 &&
+		!connection.InsecureSkipHostKeyVerification
+}
+
+// getTrustedHostKeys reads the fingerprints of the host keys recorded by trust
+// on first use, a JSON object of the fingerprints by ssh address.
+func getTrustedHostKeys(vars map[string]string) (map[string]string, error) {
+	value, _ := getConfig(vars, "trustedHostKeys", "ESXI_TRUSTED_HOST_KEYS")
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var keys map[string]string
+	if err := json.Unmarshal([]byte(value), &keys); err != nil {
+		return nil, invalidConfig("trustedHostKeys", "invalid trustedHostKeys, expected an object of the fingerprints by host:port: %w", err)
+	}
+	return keys, nil
+}
+
+// missingCredentials is the reason the config has no credentials for the transport.
+func missingCredentials(transport string) string {
+	if transport == esxi.TransportAPI {
+		return "the config key 'esxi-native:config:password' or env var.: 'ESXI_PASSWORD' must be provided with the api transport"
+	}
+	return "one of the config keys 'esxi-native:config:password', 'esxi-native:config:privateKey', " +
+		"'esxi-native:config:privateKeyPath' or 'esxi-native:config:useSshAgent', or env var.: " +
+		"'ESXI_PASSWORD', 'ESXI_PRIVATE_KEY', 'ESXI_PRIVATE_KEY_PATH' or 'ESXI_USE_SSH_AGENT', must be provided"
+}
+
+// preflightConnections returns the connections of the default host and of the
+// named hosts, by name, the default host having no name.
+func preflightConnections(vars map[string]string) (map[string]esxi.ConnectionInfo, error) {
+	connection, err := getConnection(vars)
+	if err != nil {
+		return nil, err
+	}
+	hostsConfig, err := getHostsConfig(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := map[string]esxi.ConnectionInfo{}
+	if len(connection.Host) > 0 {
+		connections[""] = connection
+	}
+	for name, hostConfig := range hostsConfig {
+		connections[name] = hostConfig.connection(connection)
+	}
+	return connections, nil
+}
+
+func preflightLabel(name string) string {
+	if len(name) == 0 {
+		return "preflight of the default host"
+	}
+	return fmt.Sprintf("preflight of the host '%s'", name)
+}
+
+// preflightReport returns the report of the checks of a host, a line per check.
+func preflightReport(name string, checks []esxi.PreflightCheck) string {
+	var report strings.Builder
+	report.WriteString(preflightLabel(name) + ":")
+	for _, check := range checks {
+		if check.Err != nil {
+			fmt.Fprintf(&report, "\n  %-10s FAILED %s", check.Name, check.Err)
+		} else {
+			fmt.Fprintf(&report, "\n  %-10s ok     %s", check.Name, check.Detail)
+		}
+	}
+	return report.String()
+}
+
+// replaceConfigKeys are the config keys of the addresses of the hosts, whose
+// change points the provider to other machines: the resources are replaced,
+// their ids being those of the old hosts.
+var replaceConfigKeys = map[string]bool{"host": true, "sshPort": true, "sslPort": true, "hosts": true}
+
+// noopConfigKeys are the config keys of the credentials, of how the hosts are
+// connected to, and of the auto-naming, whose change leaves the resources as
+// they are: the resources keep the names generated before.
+var noopConfigKeys = map[string]bool{
+	"username":               true,
+	"password":               true,
+	"privateKey":             true,
+	"privateKeyPath":         true,
+	"privateKeyPassphrase":   true,
+	"useSshAgent":            true,
+	"hostKeyFingerprint":     true,
+	"knownHostsFile":         true,
+	"trustOnFirstUse":        true,
+	"sslThumbprint":          true,
+	"transport":              true,
+	"bastionHost":            true,
+	"bastionPort":            true,
+	"bastionUser":            true,
+	"bastionPassword":        true,
+	"bastionPrivateKey":      true,
+	"bastionPrivateKeyPath":  true,
+	"autoNaming":             true,
+	"autoNamingPattern":      true,
+	"autoNamingRandomLength": true,
+	"autoNamingCharset":      true,
+	"autoNamingCase":         true,
+}
+
+// diffConfig returns the changed config keys, and the ones among them which
+// replace the resources. The changes of the no-op keys aren't returned, nor
+// are the ones which keep the same hosts, e.g. an sshPort set to its default.
+func diffConfig(olds, news resource.PropertyMap) ([]string, []string) {
+	diff := olds.Diff(news)
+	if diff == nil {
+		return nil, nil
+	}
+
+	oldVars, _ := configVars(olds)
+	newVars, unknowns := configVars(news)
+	oldConnection, _ := getConnection(oldVars)
+	newConnection, _ := getConnection(newVars)
+
+	var diffs, replaces []string
+	for _, key := range diff.ChangedKeys() {
+		name := string(key)
+		switch {
+		case noopConfigKeys[name]:
+			continue
+		case !replaceConfigKeys[name]:
+			diffs = append(diffs, name)
+		case containsString(unknowns, name) || hostsChanged(name, oldVars, newVars, oldConnection, newConnection):
+			diffs = append(diffs, name)
+			replaces = append(replaces, name)
+		}
+	}
+	return diffs, replaces
+}
+
+// hostsChanged returns true when the change of the key points the default
+// host, or one of the named hosts kept in the config, to another address.
+func hostsChanged(key string, oldVars, newVars map[string]string, oldConnection, newConnection esxi.ConnectionInfo) bool {
+	switch key {
+	case "host":
+		return oldConnection.Host != newConnection.Host
+	case "sshPort":
+		return oldConnection.SSHPort != newConnection.SSHPort
+	case "sslPort":
+		return oldConnection.SslPort != newConnection.SslPort
+	}
+
+	oldHosts, _ := getHostsConfig(oldVars)
+	newHosts, err := getHostsConfig(newVars)
+	if err != nil {
+		return true
+	}
+	for name, oldHost := range oldHosts {
+		newHost, ok := newHosts[name]
+		if !ok {
+			// The resources of a removed host fail their next operation, they aren't replaced.
+			continue
+		}
+		oldNamed := oldHost.connection(oldConnection)
+		newNamed := newHost.connection(newConnection)
+		if oldNamed.Host != newNamed.Host || oldNamed.SSHPort != newNamed.SSHPort || oldNamed.SslPort != newNamed.SslPort {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// getResourceDefaults reads the inputs of the resources defaulted by the
+// provider config.
+func getResourceDefaults(vars map[string]string) (esxi.Defaults, error) {
+	diskStore, _ := getConfig(vars, "defaultDiskStore", "ESXI_DEFAULT_DISK_STORE")
+	resourcePool, _ := getConfig(vars, "defaultResourcePool", "ESXI_DEFAULT_RESOURCE_POOL")
+	network, _ := getConfig(vars, "defaultNetwork", "ESXI_DEFAULT_NETWORK")
+	os, _ := getConfig(vars, "defaultOs", "ESXI_DEFAULT_OS")
+	defaults := esxi.Defaults{DiskStore: diskStore, ResourcePool: resourcePool, Network: network, Os: os}
+
+	if value, _ := getConfig(vars, "defaultHardwareVersion", "ESXI_DEFAULT_HARDWARE_VERSION"); len(value) > 0 {
+		version, err := strconv.Atoi(value)
+		if err != nil || version < 1 {
+			return defaults, invalidConfig("defaultHardwareVersion",
+				"invalid defaultHardwareVersion '%s', expected a virtual hardware version such as 13", value)
+		}
+		defaults.HardwareVersion = version
+	}
+	return defaults, nil
+}
+
+// getAutoNamingConfig reads how the names of the resources without one are
+// generated, the values not configured keep their defaults.
+func getAutoNamingConfig(vars map[string]string) (esxi.AutoNamingConfig, error) {
+	var config esxi.AutoNamingConfig
+	if enabled, _ := getConfig(vars, "autoNaming", "ESXI_AUTO_NAMING"); enabled == "false" {
+		config.Disabled = true
+	}
+
+	config.Pattern, _ = getConfig(vars, "autoNamingPattern", "ESXI_AUTO_NAMING_PATTERN")
+	for _, placeholder := range autoNamingPlaceholder.FindAllStringSubmatch(config.Pattern, -1) {
+		if !containsString([]string{"name", "stack", "project", "random"}, placeholder[1]) {
+			return config, invalidConfig("autoNamingPattern",
+				"invalid autoNamingPattern '%s', unknown placeholder '%s', expected ${name}, ${stack}, ${project} or ${random}",
+				config.Pattern, placeholder[0])
+		}
+	}
+	if strings.Count(config.Pattern, "${random}") > 1 {
+		return config, invalidConfig("autoNamingPattern",
+			"invalid autoNamingPattern '%s', expected ${random} at most once", config.Pattern)
+	}
+
+	if value, _ := getConfig(vars, "autoNamingRandomLength", "ESXI_AUTO_NAMING_RANDOM_LENGTH"); len(value) > 0 {
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 1 {
+			return config, invalidConfig("autoNamingRandomLength",
+				"invalid autoNamingRandomLength '%s', expected a positive number", value)
+		}
+		config.RandomLength = length
+	}
+
+	config.Charset, _ = getConfig(vars, "autoNamingCharset", "ESXI_AUTO_NAMING_CHARSET")
+	if len(config.Charset) > 0 && !autoNamingCharset.MatchString(config.Charset) {
+		return config, invalidConfig("autoNamingCharset",
+			"invalid autoNamingCharset '%s', expected letters, digits, '-' or '_'", config.Charset)
+	}
+
+	config.Case, _ = getConfig(vars, "autoNamingCase", "ESXI_AUTO_NAMING_CASE")
+	if len(config.Case) > 0 && config.Case != "lower" && config.Case != "upper" {
+		return config, invalidConfig("autoNamingCase", "invalid autoNamingCase '%s', expected 'lower' or 'upper'", config.Case)
+	}
+	return config, nil
+}
@@ -0,0 +1,615 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+func TestGetConnection(t *testing.T) {
+	connection, err := getConnection(map[string]string{"esxi-native:config:host": "10.0.0.1"})
+	require.NoError(t, err)
+	require.Equal(t, "22", connection.SSHPort)
+	require.Equal(t, "443", connection.SslPort)
+
+	// The ports which are set are kept.
+	connection, err = getConnection(map[string]string{
+		"esxi-native:config:host":    "10.0.0.1",
+		"esxi-native:config:sshPort": "2222",
+		"esxi-native:config:sslPort": "8443",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2222", connection.SSHPort)
+	require.Equal(t, "8443", connection.SslPort)
+
+	// An invalid value is reported on its key, along with the connection.
+	connection, err = getConnection(map[string]string{
+		"esxi-native:config:host":          "10.0.0.1",
+		"esxi-native:config:retryAttempts": "none",
+	})
+	var configErr *configError
+	require.True(t, errors.As(err, &configErr))
+	require.Equal(t, "retryAttempts", configErr.key)
+	require.Equal(t, "10.0.0.1", connection.Host)
+}
+
+func TestConfigVars(t *testing.T) {
+	vars, unknowns := configVars(resource.PropertyMap{
+		"host":          resource.NewStringProperty("10.0.0.1"),
+		"password":      resource.MakeSecret(resource.NewStringProperty("secret")),
+		"readOnly":      resource.NewBoolProperty(true),
+		"retryAttempts": resource.NewNumberProperty(3),
+		"hosts": resource.NewObjectProperty(resource.PropertyMap{
+			"lab1": resource.NewObjectProperty(resource.PropertyMap{"host": resource.NewStringProperty("10.0.0.2")}),
+		}),
+		"username": resource.MakeComputed(resource.NewStringProperty("")),
+	})
+	require.Equal(t, map[string]string{
+		"esxi-native:config:host":          "10.0.0.1",
+		"esxi-native:config:password":      "secret",
+		"esxi-native:config:readOnly":      "true",
+		"esxi-native:config:retryAttempts": "3",
+		"esxi-native:config:hosts":         `{"lab1":{"host":"10.0.0.2"}}`,
+	}, vars)
+	require.Equal(t, []string{"username"}, unknowns)
+}
+
+func TestCheckConfig(t *testing.T) {
+	valid := map[string]string{
+		"host":     "esxi.example.com",
+		"username": "root",
+		"password": "secret",
+	}
+	tests := []struct {
+		name     string
+		config   map[string]string
+		unknowns []string
+		property string
+		reason   string
+	}{
+		{name: "valid", config: valid},
+		{
+			name:     "missing host",
+			config:   map[string]string{"username": "root", "password": "secret"},
+			property: "host",
+			reason:   "config key 'esxi-native:config:host', or env var.: 'ESXI_HOST', must be provided",
+		},
+		{
+			name:     "missing credentials",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root"},
+			property: "password",
+			reason:   "one of the config keys 'esxi-native:config:password'",
+		},
+		{
+			name:     "unknown credentials",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root"},
+			unknowns: []string{"privateKey"},
+		},
+		{
+			name:     "invalid host",
+			config:   map[string]string{"host": "esxi_01!", "username": "root", "password": "secret"},
+			property: "host",
+			reason:   "invalid host 'esxi_01!', expected a host name or an IP address",
+		},
+		{
+			name:     "ssh port out of range",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "sshPort": "70000"},
+			property: "sshPort",
+			reason:   "invalid sshPort '70000', expected a port number between 1 and 65535",
+		},
+		{
+			name:     "invalid bastion port",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "bastionPort": "ssh"},
+			property: "bastionPort",
+			reason:   "invalid bastionPort 'ssh'",
+		},
+		{
+			name:     "invalid transport",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "transport": "telnet"},
+			property: "transport",
+			reason:   "invalid transport 'telnet', expected 'ssh' or 'api'",
+		},
+		{
+			name:     "private key conflicts",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "privateKey": "key", "privateKeyPath": "/keys/esxi"},
+			property: "privateKeyPath",
+			reason:   "privateKeyPath conflicts with privateKey",
+		},
+		{
+			name:     "passphrase without key",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "privateKeyPassphrase": "phrase"},
+			property: "privateKeyPassphrase",
+			reason:   "privateKeyPassphrase requires privateKey or privateKeyPath",
+		},
+		{
+			name: "trust on first use conflicts",
+			config: map[string]string{
+				"host": "10.0.0.1", "username": "root", "password": "secret",
+				"hostKeyFingerprint": "SHA256:abc", "trustOnFirstUse": "true",
+			},
+			property: "trustOnFirstUse",
+			reason:   "trustOnFirstUse conflicts with hostKeyFingerprint",
+		},
+		{
+			name:     "unverified host key",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "insecureSkipHostKeyVerification": "false"},
+			property: "hostKeyFingerprint",
+			reason:   "the ssh host key of '10.0.0.1' isn't verified",
+		},
+		{
+			name:   "insecure host key verification",
+			config: map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "insecureSkipHostKeyVerification": "true"},
+		},
+		{
+			name: "insecure host key verification conflicts",
+			config: map[string]string{
+				"host": "10.0.0.1", "username": "root", "password": "secret",
+				"knownHostsFile": "/keys/known_hosts", "insecureSkipHostKeyVerification": "true",
+			},
+			property: "insecureSkipHostKeyVerification",
+			reason:   "insecureSkipHostKeyVerification conflicts with hostKeyFingerprint",
+		},
+		{
+			name: "unverified bastion host key",
+			config: map[string]string{
+				"host": "10.0.0.1", "username": "root", "password": "secret",
+				"hostKeyFingerprint": "SHA256:abc", "bastionHost": "jump.example.com",
+			},
+			property: "hostKeyFingerprint",
+			reason:   "the ssh host key of 'jump.example.com' isn't verified",
+		},
+		{
+			name: "api transport without host key verification",
+			config: map[string]string{
+				"host": "10.0.0.1", "username": "root", "password": "secret", "transport": "api", "insecureSkipHostKeyVerification": "false",
+				"sslThumbprint": "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01",
+			},
+		},
+		{
+			name:     "api transport without certificate verification",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "transport": "api"},
+			property: "sslThumbprint",
+			reason:   "the certificate of '10.0.0.1' isn't verified by the api transport",
+		},
+		{
+			name:   "api transport with insecure certificate verification",
+			config: map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "transport": "api", "insecureSkipHostKeyVerification": "true"},
+		},
+		{
+			name:     "invalid ssl thumbprint",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "transport": "api", "sslThumbprint": "AB:CD"},
+			property: "sslThumbprint",
+			reason:   "invalid sslThumbprint 'AB:CD'",
+		},
+		{
+			name:     "invalid trusted host keys",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "trustOnFirstUse": "true", "trustedHostKeys": "[]"},
+			property: "trustedHostKeys",
+			reason:   "invalid trustedHostKeys",
+		},
+		{
+			name:     "read only and dry run",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "readOnly": "true", "dryRun": "true"},
+			property: "dryRun",
+			reason:   "dryRun conflicts with readOnly",
+		},
+		{
+			name:     "invalid boolean",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "preflight": "yes"},
+			property: "preflight",
+			reason:   "invalid preflight 'yes', expected true or false",
+		},
+		{
+			name:     "invalid retry attempts",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "retryAttempts": "0"},
+			property: "retryAttempts",
+			reason:   "invalid retryAttempts '0', expected a positive number",
+		},
+		{
+			name:     "invalid default hardware version",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "defaultHardwareVersion": "vmx-13"},
+			property: "defaultHardwareVersion",
+			reason:   "invalid defaultHardwareVersion 'vmx-13'",
+		},
+		{
+			name:     "invalid auto-naming pattern",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "autoNamingPattern": "${name}-${region}"},
+			property: "autoNamingPattern",
+			reason:   "unknown placeholder '${region}'",
+		},
+		{
+			name:     "auto-naming pattern with two random parts",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "autoNamingPattern": "${random}-${name}-${random}"},
+			property: "autoNamingPattern",
+			reason:   "expected ${random} at most once",
+		},
+		{
+			name:     "invalid auto-naming charset",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "autoNamingCharset": "ab/c"},
+			property: "autoNamingCharset",
+			reason:   "invalid autoNamingCharset 'ab/c'",
+		},
+		{
+			name:     "invalid auto-naming case",
+			config:   map[string]string{"host": "10.0.0.1", "username": "root", "password": "secret", "autoNamingCase": "title"},
+			property: "autoNamingCase",
+			reason:   "invalid autoNamingCase 'title', expected 'lower' or 'upper'",
+		},
+		{
+			name:   "named hosts without default host",
+			config: map[string]string{"username": "root", "password": "secret", "hosts": `{"lab1": {"host": "10.0.0.1"}}`},
+		},
+		{
+			name:     "named host without credentials",
+			config:   map[string]string{"username": "root", "hosts": `{"lab1": {"host": "10.0.0.1"}}`},
+			property: "hosts",
+			reason:   "the host 'lab1' has no username or credentials",
+		},
+		{
+			name:     "named host with invalid port",
+			config:   map[string]string{"username": "root", "password": "secret", "hosts": `{"lab1": {"host": "10.0.0.1", "sshPort": "0"}}`},
+			property: "hosts",
+			reason:   "invalid sshPort '0' or sslPort '443' of the host 'lab1'",
+		},
+		{
+			name: "named host without host key verification",
+			config: map[string]string{
+				"username": "root", "password": "secret", "insecureSkipHostKeyVerification": "false",
+				"hosts": `{"lab1": {"host": "10.0.0.1"}, "lab2": {"host": "10.0.0.2", "hostKeyFingerprint": "SHA256:abc"}}`,
+			},
+			property: "hosts",
+			reason:   "the ssh host key of the host 'lab1' isn't verified",
+		},
+		{
+			name: "named api host without certificate verification",
+			config: map[string]string{
+				"username": "root", "password": "secret",
+				"hosts": `{"lab1": {"host": "10.0.0.1", "transport": "api"}, "lab2": {"host": "10.0.0.2", "transport": "api", "sslThumbprint": "` +
+					strings.Repeat("ab", 32) + `"}}`,
+			},
+			property: "hosts",
+			reason:   "the certificate of the host 'lab1' isn't verified by the api transport",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The host keys are verified with a fingerprint, unless the test sets
+			// the verification.
+			vars := map[string]string{configPrefix + "hostKeyFingerprint": "SHA256:abc"}
+			for _, key := range []string{"knownHostsFile", "trustOnFirstUse", "insecureSkipHostKeyVerification"} {
+				if _, has := test.config[key]; has {
+					delete(vars, configPrefix+"hostKeyFingerprint")
+				}
+			}
+			for key, value := range test.config {
+				vars[configPrefix+key] = value
+			}
+			failures := checkConfig(vars, test.unknowns)
+			if test.reason == "" {
+				require.Empty(t, failures)
+				return
+			}
+			require.Len(t, failures, 1)
+			require.Equal(t, test.property, failures[0].GetProperty())
+			require.Contains(t, failures[0].GetReason(), test.reason)
+		})
+	}
+}
+
+func TestCheckConfigPreflight(t *testing.T) {
+	var checked []string
+	p := &esxiProvider{
+		name:     "esxi-native",
+		canceler: makeCancellationContext(),
+		preflight: func(_ context.Context, connection esxi.ConnectionInfo) []esxi.PreflightCheck {
+			checked = append(checked, connection.Host)
+			return []esxi.PreflightCheck{
+				{Name: esxi.PreflightConnect, Detail: "connected to ESXi 7.0.3"},
+				{Name: esxi.PreflightVmkfstools, Err: errors.New("vmkfstools: not found")},
+			}
+		},
+	}
+	news, err := plugin.MarshalProperties(resource.PropertyMap{
+		"host":      resource.NewStringProperty("10.0.0.1"),
+		"username":  resource.NewStringProperty("root"),
+		"password":  resource.NewStringProperty("secret"),
+		"preflight": resource.NewBoolProperty(true),
+
+		"hostKeyFingerprint": resource.NewStringProperty("SHA256:abc"),
+	}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+
+	response, err := p.CheckConfig(context.Background(), &pulumirpc.CheckRequest{Urn: "urn:pulumi:dev::test::pulumi:providers:esxi-native::default", News: news})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1"}, checked)
+	require.Len(t, response.GetFailures(), 1)
+	require.Equal(t, "host", response.GetFailures()[0].GetProperty())
+	require.Equal(t, "preflight of the default host, check 'vmkfstools' failed: vmkfstools: not found", response.GetFailures()[0].GetReason())
+
+	// Without preflight, the hosts are not connected to.
+	checked = nil
+	delete(news.GetFields(), "preflight")
+	response, err = p.CheckConfig(context.Background(), &pulumirpc.CheckRequest{News: news})
+	require.NoError(t, err)
+	require.Empty(t, response.GetFailures())
+	require.Empty(t, checked)
+}
+
+func TestCheckConfigTrustOnFirstUse(t *testing.T) {
+	var recorded []string
+	p := &esxiProvider{
+		name:     "esxi-native",
+		canceler: makeCancellationContext(),
+		recordHostKeys: func(_ context.Context, connection *esxi.ConnectionInfo) error {
+			address := connection.Host + ":" + connection.SSHPort
+			if _, has := connection.TrustedHostKeys[address]; !has {
+				recorded = append(recorded, address)
+				connection.TrustedHostKeys[address] = "SHA256:" + connection.Host
+			}
+			return nil
+		},
+	}
+	config := resource.PropertyMap{
+		"username":        resource.NewStringProperty("root"),
+		"password":        resource.NewStringProperty("secret"),
+		"trustOnFirstUse": resource.NewBoolProperty(true),
+		"hosts": resource.NewObjectProperty(resource.PropertyMap{
+			"lab1": resource.NewObjectProperty(resource.PropertyMap{"host": resource.NewStringProperty("10.0.0.1")}),
+			"lab2": resource.NewObjectProperty(resource.PropertyMap{"host": resource.NewStringProperty("10.0.0.2")}),
+		}),
+	}
+	news, err := plugin.MarshalProperties(config, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	olds, err := plugin.MarshalProperties(resource.PropertyMap{
+		"trustedHostKeys": resource.NewStringProperty(`{"10.0.0.1:22":"SHA256:lab1"}`),
+	}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+
+	// The key recorded in the old config is kept, the other key is recorded in
+	// the inputs.
+	response, err := p.CheckConfig(context.Background(), &pulumirpc.CheckRequest{Olds: olds, News: news})
+	require.NoError(t, err)
+	require.Empty(t, response.GetFailures())
+	require.Equal(t, []string{"10.0.0.2:22"}, recorded)
+	inputs, err := plugin.UnmarshalProperties(response.GetInputs(), plugin.MarshalOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"10.0.0.1:22":"SHA256:lab1","10.0.0.2:22":"SHA256:10.0.0.2"}`, inputs["trustedHostKeys"].StringValue())
+
+	// Once every key is recorded, the inputs are kept.
+	recorded = nil
+	checked := response.GetInputs()
+	response, err = p.CheckConfig(context.Background(), &pulumirpc.CheckRequest{Olds: checked, News: checked})
+	require.NoError(t, err)
+	require.Empty(t, response.GetFailures())
+	require.Empty(t, recorded)
+	require.Same(t, checked, response.GetInputs())
+
+	// A host whose key can't be read fails the check.
+	p.recordHostKeys = func(context.Context, *esxi.ConnectionInfo) error { return errors.New("connection refused") }
+	response, err = p.CheckConfig(context.Background(), &pulumirpc.CheckRequest{News: news})
+	require.NoError(t, err)
+	require.Len(t, response.GetFailures(), 2)
+	require.Equal(t, "hosts", response.GetFailures()[0].GetProperty())
+	require.Equal(t, "connection refused", response.GetFailures()[0].GetReason())
+}
+
+func TestPreflightReport(t *testing.T) {
+	report := preflightReport("lab1", []esxi.PreflightCheck{
+		{Name: esxi.PreflightConnect, Detail: "connected to ESXi 7.0.3"},
+		{Name: esxi.PreflightPrivileges, Err: errors.New("the user 'ops' has the ReadOnly role")},
+	})
+	require.Equal(t, "preflight of the host 'lab1':\n"+
+		"  connect    ok     connected to ESXi 7.0.3\n"+
+		"  privileges FAILED the user 'ops' has the ReadOnly role", report)
+}
+
+func TestDiffConfig(t *testing.T) {
+	olds := resource.PropertyMap{
+		"host":     resource.NewStringProperty("10.0.0.1"),
+		"username": resource.NewStringProperty("root"),
+		"password": resource.MakeSecret(resource.NewStringProperty("secret")),
+		"hosts":    resource.NewStringProperty(`{"lab1": {"host": "10.0.0.2"}}`),
+	}
+	tests := []struct {
+		name     string
+		news     resource.PropertyMap
+		changes  pulumirpc.DiffResponse_DiffChanges
+		diffs    []string
+		replaces []string
+	}{
+		{name: "no change", news: resource.PropertyMap{}, changes: pulumirpc.DiffResponse_DIFF_NONE},
+		{
+			name:     "host",
+			news:     resource.PropertyMap{"host": resource.NewStringProperty("10.0.0.9")},
+			changes:  pulumirpc.DiffResponse_DIFF_SOME,
+			diffs:    []string{"host"},
+			replaces: []string{"host"},
+		},
+		{
+			name:     "unknown host",
+			news:     resource.PropertyMap{"host": resource.MakeComputed(resource.NewStringProperty(""))},
+			changes:  pulumirpc.DiffResponse_DIFF_SOME,
+			diffs:    []string{"host"},
+			replaces: []string{"host"},
+		},
+		{
+			name:     "ssh port",
+			news:     resource.PropertyMap{"sshPort": resource.NewStringProperty("2222")},
+			changes:  pulumirpc.DiffResponse_DIFF_SOME,
+			diffs:    []string{"sshPort"},
+			replaces: []string{"sshPort"},
+		},
+		{
+			name:    "default ssl port",
+			news:    resource.PropertyMap{"sslPort": resource.NewStringProperty("443")},
+			changes: pulumirpc.DiffResponse_DIFF_NONE,
+		},
+		{
+			name: "credentials",
+			news: resource.PropertyMap{
+				"username":       resource.NewStringProperty("admin"),
+				"password":       resource.MakeSecret(resource.NewStringProperty("rotated")),
+				"privateKeyPath": resource.NewStringProperty("/keys/esxi"),
+			},
+			changes: pulumirpc.DiffResponse_DIFF_NONE,
+		},
+		{
+			name:     "named host address",
+			news:     resource.PropertyMap{"hosts": resource.NewStringProperty(`{"lab1": {"host": "10.0.0.3"}}`)},
+			changes:  pulumirpc.DiffResponse_DIFF_SOME,
+			diffs:    []string{"hosts"},
+			replaces: []string{"hosts"},
+		},
+		{
+			name:    "named host credentials",
+			news:    resource.PropertyMap{"hosts": resource.NewStringProperty(`{"lab1": {"host": "10.0.0.2", "password": "other"}}`)},
+			changes: pulumirpc.DiffResponse_DIFF_NONE,
+		},
+		{
+			name:    "named host added",
+			news:    resource.PropertyMap{"hosts": resource.NewStringProperty(`{"lab1": {"host": "10.0.0.2"}, "lab2": {"host": "10.0.0.4"}}`)},
+			changes: pulumirpc.DiffResponse_DIFF_NONE,
+		},
+		{
+			name:    "other key",
+			news:    resource.PropertyMap{"retryAttempts": resource.NewStringProperty("3")},
+			changes: pulumirpc.DiffResponse_DIFF_SOME,
+			diffs:   []string{"retryAttempts"},
+		},
+	}
+	p := &esxiProvider{name: "esxi-native"}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			news := olds.Copy()
+			for key, value := range test.news {
+				news[key] = value
+			}
+			oldProperties, err := plugin.MarshalProperties(olds, plugin.MarshalOptions{KeepSecrets: true})
+			require.NoError(t, err)
+			newProperties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: true})
+			require.NoError(t, err)
+
+			response, err := p.DiffConfig(context.Background(), &pulumirpc.DiffRequest{Olds: oldProperties, News: newProperties})
+			require.NoError(t, err)
+			require.Equal(t, test.changes, response.GetChanges())
+			require.Equal(t, test.diffs, response.GetDiffs())
+			require.Equal(t, test.replaces, response.GetReplaces())
+		})
+	}
+}
+
+func TestCheckWithResourceDefaults(t *testing.T) {
+	defaults, err := getResourceDefaults(map[string]string{
+		"esxi-native:config:defaultDiskStore":       "datastore1",
+		"esxi-native:config:defaultResourcePool":    "pool-web",
+		"esxi-native:config:defaultNetwork":         "VM Network",
+		"esxi-native:config:defaultOs":              "ubuntu-64",
+		"esxi-native:config:defaultHardwareVersion": "19",
+	})
+	require.NoError(t, err)
+	p := &esxiProvider{
+		name:            "esxi-native",
+		defaults:        defaults,
+		namingService:   esxi.NewAutoNamingService(),
+		resourceService: esxi.NewResourceService(),
+	}
+	check := func(urn string, inputs resource.PropertyMap) *pulumirpc.CheckResponse {
+		news, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{})
+		require.NoError(t, err)
+		response, err := p.Check(context.Background(), &pulumirpc.CheckRequest{Urn: urn, News: news, RandomSeed: []byte("seed")})
+		require.NoError(t, err)
+		return response
+	}
+	checked := func(response *pulumirpc.CheckResponse) resource.PropertyMap {
+		require.Empty(t, response.GetFailures())
+		inputs, err := plugin.UnmarshalProperties(response.GetInputs(), plugin.MarshalOptions{})
+		require.NoError(t, err)
+		return inputs
+	}
+
+	vm := checked(check("urn:pulumi:dev::test::esxi-native:index:VirtualMachine::web", resource.PropertyMap{
+		"name":     resource.NewStringProperty("web"),
+		"memSize":  resource.NewNumberProperty(1024),
+		"numVCpus": resource.NewNumberProperty(2),
+	}))
+	require.Equal(t, "datastore1", vm["diskStore"].StringValue())
+	require.Equal(t, "pool-web", vm["resourcePoolName"].StringValue())
+	require.Equal(t, "ubuntu-64", vm["os"].StringValue())
+	require.Equal(t, float64(19), vm["virtualHWVer"].NumberValue())
+	require.Equal(t, "VM Network", vm["networkInterfaces"].ArrayValue()[0].ObjectValue()["virtualNetwork"].StringValue())
+
+	// The inputs of the resource take priority over the defaults.
+	vm = checked(check("urn:pulumi:dev::test::esxi-native:index:VirtualMachine::db", resource.PropertyMap{
+		"name":              resource.NewStringProperty("db"),
+		"memSize":           resource.NewNumberProperty(1024),
+		"numVCpus":          resource.NewNumberProperty(2),
+		"diskStore":         resource.NewStringProperty("nvme"),
+		"os":                resource.NewStringProperty("centos"),
+		"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{}),
+	}))
+	require.Equal(t, "nvme", vm["diskStore"].StringValue())
+	require.Equal(t, "centos", vm["os"].StringValue())
+	require.Empty(t, vm["networkInterfaces"].ArrayValue())
+
+	disk := resource.PropertyMap{
+		"name":      resource.NewStringProperty("data"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+	}
+	require.Equal(t, "datastore1", checked(check("urn:pulumi:dev::test::esxi-native:index:VirtualDisk::data", disk))["diskStore"].StringValue())
+
+	// Without a default, the disk store is still required.
+	p.defaults = esxi.Defaults{}
+	response := check("urn:pulumi:dev::test::esxi-native:index:VirtualDisk::data", disk)
+	require.Len(t, response.GetFailures(), 1)
+	require.Equal(t, "The property 'diskStore' is required!", response.GetFailures()[0].GetReason())
+}
+
+func TestCheckWithAutoNaming(t *testing.T) {
+	check := func(p *esxiProvider, urn string, inputs resource.PropertyMap) *pulumirpc.CheckResponse {
+		news, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{})
+		require.NoError(t, err)
+		response, err := p.Check(context.Background(), &pulumirpc.CheckRequest{Urn: urn, News: news, RandomSeed: []byte("seed")})
+		require.NoError(t, err)
+		return response
+	}
+	newProvider := func(vars map[string]string) *esxiProvider {
+		config, err := getAutoNamingConfig(vars)
+		require.NoError(t, err)
+		return &esxiProvider{
+			name:            "esxi-native",
+			namingService:   esxi.NewAutoNamingServiceWithConfig(config),
+			resourceService: esxi.NewResourceService(),
+		}
+	}
+
+	// The names fit the limits of the host, 31 characters for a virtual switch.
+	p := newProvider(map[string]string{
+		"esxi-native:config:autoNamingPattern":      "${stack}-${name}-${random}",
+		"esxi-native:config:autoNamingRandomLength": "6",
+		"esxi-native:config:autoNamingCase":         "lower",
+	})
+	response := check(p, "urn:pulumi:production::lab::esxi-native:index:VirtualSwitch::Storage Network", resource.PropertyMap{})
+	require.Empty(t, response.GetFailures())
+	inputs, err := plugin.UnmarshalProperties(response.GetInputs(), plugin.MarshalOptions{})
+	require.NoError(t, err)
+	require.Regexp(t, `^production-storage-network-[0-9a-f]{4}$`, inputs["name"].StringValue())
+
+	// Without auto-naming, the resources without a name fail their check.
+	p = newProvider(map[string]string{"esxi-native:config:autoNaming": "false"})
+	response = check(p, "urn:pulumi:dev::lab::esxi-native:index:VirtualSwitch::storage", resource.PropertyMap{})
+	require.Len(t, response.GetFailures(), 1)
+	require.Equal(t, "The property 'name' is required!", response.GetFailures()[0].GetReason())
+	response = check(p, "urn:pulumi:dev::lab::esxi-native:index:VirtualSwitch::storage", resource.PropertyMap{
+		"name": resource.NewStringProperty("vSwitch1"),
+	})
+	require.Empty(t, response.GetFailures())
+}
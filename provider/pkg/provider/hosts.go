@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+// hostIdSeparator separates the name of the host from the id of the resource
+// on that host, in the ids of the resources managed on the named hosts.
+const hostIdSeparator = "::"
+
+// hostConfig is an entry of the hosts config, its unset fields are inherited
+// from the provider config.
+type hostConfig struct {
+	Host                 string `json:"host"`
+	Username             string `json:"username"`
+	Password             string `json:"password"`
+	SSHPort              string `json:"sshPort"`
+	SslPort              string `json:"sslPort"`
+	PrivateKey           string `json:"privateKey"`
+	PrivateKeyPath       string `json:"privateKeyPath"`
+	PrivateKeyPassphrase string `json:"privateKeyPassphrase"`
+	HostKeyFingerprint   string `json:"hostKeyFingerprint"`
+	SslThumbprint        string `json:"sslThumbprint"`
+	Transport            string `json:"transport"`
+}
+
+// getHostsConfig reads the named hosts of the hosts config, a JSON object
+// mapping the names to the connection settings of the hosts.
+func getHostsConfig(vars map[string]string) (map[string]hostConfig, error) {
+	value, _ := getConfig(vars, "hosts", "ESXI_HOSTS")
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var hosts map[string]hostConfig
+	if err := json.Unmarshal([]byte(value), &hosts); err != nil {
+		return nil, invalidConfig("hosts", "invalid hosts, expected an object of the host connections by name: %w", err)
+	}
+	for name, host := range hosts {
+		if len(name) == 0 || strings.Contains(name, hostIdSeparator) {
+			return nil, invalidConfig("hosts", "invalid hosts, the host name '%s' is empty or contains '%s'", name, hostIdSeparator)
+		}
+		if len(host.Host) == 0 {
+			return nil, invalidConfig("hosts", "invalid hosts, the host '%s' has no 'host' address", name)
+		}
+	}
+	return hosts, nil
+}
+
+// connection returns the connection to the host, the defaults are the
+// connection settings of the provider config.
+func (c hostConfig) connection(defaults esxi.ConnectionInfo) esxi.ConnectionInfo {
+	connection := defaults
+	connection.Host = c.Host
+	for _, field := range []struct {
+		value  string
+		target *string
+	}{
+		{c.Username, &connection.UserName},
+		{c.SSHPort, &connection.SSHPort},
+		{c.SslPort, &connection.SslPort},
+		{c.HostKeyFingerprint, &connection.HostKeyFingerprint},
+		{c.SslThumbprint, &connection.SslThumbprint},
+		{c.Transport, &connection.Transport},
+	} {
+		if len(field.value) > 0 {
+			*field.target = field.value
+		}
+	}
+
+	// The credentials are inherited as a whole, a host with its own password
+	// doesn't try the private key of the provider config.
+	if len(c.Password) > 0 || len(c.PrivateKey) > 0 || len(c.PrivateKeyPath) > 0 {
+		connection.Password = c.Password
+		connection.PrivateKey = c.PrivateKey
+		connection.PrivateKeyPath = c.PrivateKeyPath
+		connection.PrivateKeyPassphrase = c.PrivateKeyPassphrase
+	}
+	return connection
+}
+
+// hasCredentials returns true when the connection can authenticate on the
+// host, the api only accepts the password.
+func hasCredentials(connection esxi.ConnectionInfo) bool {
+	if connection.Transport == esxi.TransportAPI {
+		return len(connection.Password) > 0
+	}
+	return len(connection.Password) > 0 || len(connection.PrivateKey) > 0 ||
+		len(connection.PrivateKeyPath) > 0 || connection.UseSSHAgent
+}
+
+// hostRegistry holds the esxi hosts the resources are managed on: the default
+// host of the provider config, connected to while configuring the provider,
+// and the named hosts of the hosts config, connected to on their first use.
+type hostRegistry struct {
+	defaultHost *esxi.Host
+	named       map[string]*namedHost
+	connect     func(ctx context.Context, connection esxi.ConnectionInfo) (*esxi.Host, error)
+}
+
+type namedHost struct {
+	mutex      sync.Mutex
+	connection esxi.ConnectionInfo
+	host       *esxi.Host
+}
+
+func newHostRegistry() *hostRegistry {
+	return &hostRegistry{
+		named:   map[string]*namedHost{},
+		connect: esxi.NewHost,
+	}
+}
+
+// has returns true when the name is the one of a named host, or empty with a default host.
+func (r *hostRegistry) has(name string) bool {
+	if len(name) == 0 {
+		return r.defaultHost != nil
+	}
+	_, ok := r.named[name]
+	return ok
+}
+
+// get returns the named host, or the default host when the name is empty.
+func (r *hostRegistry) get(ctx context.Context, name string) (*esxi.Host, error) {
+	if len(name) == 0 {
+		if r.defaultHost == nil {
+			return nil, fmt.Errorf("the provider config has no default host, the 'host' property must be one of the hosts config")
+		}
+		return r.defaultHost, nil
+	}
+
+	entry, ok := r.named[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown host '%s', it is not one of the hosts config", name)
+	}
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.host == nil {
+		host, err := r.connect(ctx, entry.connection)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the host '%s': %w", name, err)
+		}
+		entry.host = host
+	}
+	return entry.host, nil
+}
+
+// close releases the connections to the hosts.
+func (r *hostRegistry) close() {
+	if r.defaultHost != nil {
+		r.defaultHost.Close()
+	}
+	for _, entry := range r.named {
+		entry.mutex.Lock()
+		if entry.host != nil {
+			entry.host.Close()
+			entry.host = nil
+		}
+		entry.mutex.Unlock()
+	}
+}
+
+// hostName returns the host property of the inputs, empty for the default host.
+func hostName(inputs resource.PropertyMap) string {
+	if property, has := inputs["host"]; has && property.IsString() {
+		return property.StringValue()
+	}
+	return ""
+}
+
+// hostId returns the id of the resource managed on the named host, the ids of
+// the resources of the default host are left as is.
+func hostId(name string, id string) string {
+	if len(name) == 0 || len(id) == 0 {
+		return id
+	}
+	return name + hostIdSeparator + id
+}
+
+// parseHostId returns the name of the host and the id of the resource on that host.
+func parseHostId(id string) (string, string) {
+	if name, localId, found := strings.Cut(id, hostIdSeparator); found {
+		return name, localId
+	}
+	return "", id
+}
+
+// withHost sets the host property of the outputs of a resource managed on a named host.
+func withHost(name string, outputs resource.PropertyMap) resource.PropertyMap {
+	if len(name) > 0 && outputs != nil {
+		outputs["host"] = resource.NewStringProperty(name)
+	}
+	return outputs
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+func TestGetHostsConfig(t *testing.T) {
+	hosts, err := getHostsConfig(map[string]string{
+		"esxi-native:config:hosts": `{"lab1": {"host": "10.0.0.1"}, "lab2": {"host": "10.0.0.2", "username": "admin", "password": "other", "sshPort": "2222"}}`,
+	})
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+
+	defaults := esxi.ConnectionInfo{UserName: "root", PrivateKeyPath: "/keys/esxi", SSHPort: "22", SslPort: "443", Transport: esxi.TransportSSH}
+	lab1 := hosts["lab1"].connection(defaults)
+	require.Equal(t, "10.0.0.1", lab1.Host)
+	require.Equal(t, "root", lab1.UserName)
+	require.Equal(t, "/keys/esxi", lab1.PrivateKeyPath)
+	require.True(t, hasCredentials(lab1))
+
+	lab2 := hosts["lab2"].connection(defaults)
+	require.Equal(t, "10.0.0.2", lab2.Host)
+	require.Equal(t, "admin", lab2.UserName)
+	require.Equal(t, "other", lab2.Password)
+	require.Empty(t, lab2.PrivateKeyPath)
+	require.Equal(t, "2222", lab2.SSHPort)
+	require.Equal(t, "443", lab2.SslPort)
+
+	hosts, err = getHostsConfig(map[string]string{})
+	require.NoError(t, err)
+	require.Empty(t, hosts)
+
+	for _, invalid := range []string{`["10.0.0.1"]`, `{"lab1": {}}`, `{"lab::1": {"host": "10.0.0.1"}}`} {
+		_, err = getHostsConfig(map[string]string{"esxi-native:config:hosts": invalid})
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestHostId(t *testing.T) {
+	require.Equal(t, "vSwitch0/pg", hostId("", "vSwitch0/pg"))
+	require.Equal(t, "lab1::vSwitch0/pg", hostId("lab1", "vSwitch0/pg"))
+	require.Equal(t, "", hostId("lab1", ""))
+
+	name, id := parseHostId("lab1::vSwitch0/pg")
+	require.Equal(t, "lab1", name)
+	require.Equal(t, "vSwitch0/pg", id)
+	name, id = parseHostId("42")
+	require.Empty(t, name)
+	require.Equal(t, "42", id)
+}
+
+func TestHostRegistry(t *testing.T) {
+	hosts := newHostRegistry()
+	hosts.named["lab1"] = &namedHost{connection: esxi.ConnectionInfo{Host: "10.0.0.1"}}
+	var connected []string
+	hosts.connect = func(_ context.Context, connection esxi.ConnectionInfo) (*esxi.Host, error) {
+		connected = append(connected, connection.Host)
+		return esxi.NewHostWithExecutor(connection, esxi.NewFakeExecutor()), nil
+	}
+	ctx := context.Background()
+
+	require.False(t, hosts.has(""))
+	require.True(t, hosts.has("lab1"))
+	require.False(t, hosts.has("lab2"))
+	_, err := hosts.get(ctx, "")
+	require.ErrorContains(t, err, "no default host")
+	_, err = hosts.get(ctx, "lab2")
+	require.ErrorContains(t, err, "unknown host 'lab2'")
+	require.Empty(t, connected)
+
+	// The named hosts are connected to once, on their first use.
+	lab1, err := hosts.get(ctx, "lab1")
+	require.NoError(t, err)
+	again, err := hosts.get(ctx, "lab1")
+	require.NoError(t, err)
+	require.Same(t, lab1, again)
+	require.Equal(t, []string{"10.0.0.1"}, connected)
+	hosts.close()
+}
+
+func TestResourcesOnNamedHosts(t *testing.T) {
+	fakes := map[string]*esxi.FakeExecutor{"": esxi.NewFakeExecutor(), "lab1": esxi.NewFakeExecutor()}
+	hosts := newHostRegistry()
+	hosts.defaultHost = esxi.NewHostWithExecutor(esxi.ConnectionInfo{Host: "default"}, fakes[""])
+	hosts.named["lab1"] = &namedHost{connection: esxi.ConnectionInfo{Host: "lab1"}}
+	hosts.connect = func(_ context.Context, connection esxi.ConnectionInfo) (*esxi.Host, error) {
+		return esxi.NewHostWithExecutor(connection, fakes[connection.Host]), nil
+	}
+	p := &esxiProvider{
+		canceler:        makeCancellationContext(),
+		hosts:           hosts,
+		namingService:   esxi.NewAutoNamingService(),
+		resourceService: esxi.NewResourceService(),
+	}
+	ctx := context.Background()
+	urn := "urn:pulumi:dev::test::esxi-native:index:VirtualSwitch::vswitch"
+
+	properties, err := plugin.MarshalProperties(resource.PropertyMap{
+		"name": resource.NewStringProperty("vSwitch1"),
+		"host": resource.NewStringProperty("lab1"),
+	}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	created, err := p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+	require.NoError(t, err)
+	require.Equal(t, "lab1::vSwitch1", created.GetId())
+	require.Equal(t, "lab1", created.GetProperties().GetFields()["host"].GetStringValue())
+	require.Contains(t, fakes["lab1"].Commands(), "esxcli network vswitch standard add -P 128 -v vSwitch1")
+	require.Empty(t, fakes[""].Commands())
+
+	// The resource is read on its host, with its id on that host.
+	read, err := p.Read(ctx, &pulumirpc.ReadRequest{Urn: urn, Id: created.GetId(), Properties: created.GetProperties()})
+	require.NoError(t, err)
+	require.Equal(t, "lab1::vSwitch1", read.GetId())
+	require.Contains(t, fakes["lab1"].Commands(), "esxcli network vswitch standard list -v vSwitch1")
+	require.Empty(t, fakes[""].Commands())
+
+	_, err = p.Delete(ctx, &pulumirpc.DeleteRequest{Urn: urn, Id: created.GetId()})
+	require.NoError(t, err)
+	require.Contains(t, fakes["lab1"].Commands(), "esxcli network vswitch standard remove -v vSwitch1")
+
+	// The resources without host are managed on the default host.
+	properties, err = plugin.MarshalProperties(resource.PropertyMap{"name": resource.NewStringProperty("vSwitch2")}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	created, err = p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch2", created.GetId())
+	require.Contains(t, fakes[""].Commands(), "esxcli network vswitch standard add -P 128 -v vSwitch2")
+
+	// An unknown host fails the check.
+	properties, err = plugin.MarshalProperties(resource.PropertyMap{
+		"name": resource.NewStringProperty("vSwitch3"),
+		"host": resource.NewStringProperty("lab9"),
+	}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	checked, err := p.Check(ctx, &pulumirpc.CheckRequest{Urn: urn, News: properties})
+	require.NoError(t, err)
+	require.Len(t, checked.GetFailures(), 1)
+	require.Equal(t, "host", checked.GetFailures()[0].GetProperty())
+}
+
+func TestDiffHost(t *testing.T) {
+	p := &esxiProvider{resourceService: esxi.NewResourceService()}
+	urn := "urn:pulumi:dev::test::esxi-native:index:VirtualSwitch::vswitch"
+	inputs := resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1"), "host": resource.NewStringProperty("lab1")}
+	olds, err := plugin.MarshalProperties(checkpointObject(inputs, inputs.Copy()), plugin.MarshalOptions{KeepSecrets: true})
+	require.NoError(t, err)
+
+	diff := func(news resource.PropertyMap) *pulumirpc.DiffResponse {
+		properties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{})
+		require.NoError(t, err)
+		response, err := p.Diff(context.Background(), &pulumirpc.DiffRequest{Urn: urn, Olds: olds, News: properties})
+		require.NoError(t, err)
+		return response
+	}
+
+	response := diff(inputs)
+	require.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, response.GetChanges())
+
+	// The switch on the other host is created before the old one is deleted.
+	response = diff(resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1"), "host": resource.NewStringProperty("lab2")})
+	require.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, response.GetChanges())
+	require.Equal(t, []string{"host"}, response.GetReplaces())
+	require.Equal(t, pulumirpc.PropertyDiff_UPDATE_REPLACE, response.GetDetailedDiff()["host"].GetKind())
+	require.False(t, response.GetDeleteBeforeReplace())
+
+	// The update of the switch is diffed along.
+	response = diff(resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1"), "mtu": resource.NewNumberProperty(9000)})
+	require.Equal(t, []string{"host"}, response.GetReplaces())
+	require.Equal(t, pulumirpc.PropertyDiff_DELETE_REPLACE, response.GetDetailedDiff()["host"].GetKind())
+	require.Equal(t, pulumirpc.PropertyDiff_ADD, response.GetDetailedDiff()["mtu"].GetKind())
+}
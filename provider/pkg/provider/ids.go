@@ -16,6 +16,7 @@ package provider
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 
@@ -26,7 +27,8 @@ import (
 // or the equivalent in the old values. If neither is specified, it generates
 // a random name for a resource's name fields
 // based on its URN name, It ensures the name meets the length constraints, if known.
-// Defaults to the name followed by 7 random hex characters separated by a '-'.
+// Defaults to the name followed by 7 random hex characters separated by a '-',
+// the pattern, the random part and the case being set by the provider config.
 func getDefaultName(
 	randomSeed []byte,
 	urn resource.URN,
@@ -46,34 +48,79 @@ func getDefaultName(
 		return v, nil
 	}
 
+	pattern := autoNamingSpec.Pattern
+	if len(pattern) == 0 {
+		pattern = esxi.DefaultAutoNamingPattern
+	}
+	randLength := autoNamingSpec.RandomLength
+	if randLength <= 0 {
+		randLength = esxi.DefaultAutoNamingRandomLength
+	}
+	charset := autoNamingSpec.Charset
+	if len(charset) == 0 {
+		charset = esxi.DefaultAutoNamingCharset
+	}
+
+	// The parts of the pattern around the random part, with the placeholders
+	// replaced and the characters not allowed by the host replaced with '-'.
+	parts := strings.SplitN(pattern, "${random}", 2)
+	for i, part := range parts {
+		part = strings.NewReplacer(
+			"${name}", urn.Name().String(),
+			"${stack}", urn.Stack().String(),
+			"${project}", urn.Project().String(),
+		).Replace(part)
+		if autoNamingSpec.InvalidChars != nil {
+			part = autoNamingSpec.InvalidChars.ReplaceAllString(part, "-")
+		}
+		parts[i] = part
+	}
+	prefix := strings.Join(parts, "")
+
+	if len(parts) == 1 {
+		// Without a random part, the name is the pattern.
+		name := withCase(prefix, autoNamingSpec.Case)
+		if len(name) < autoNamingSpec.MinLength || (autoNamingSpec.MaxLength > 0 && len(name) > autoNamingSpec.MaxLength) {
+			return resource.PropertyValue{}, fmt.Errorf("failed to auto-generate value for %[1]q."+
+				" Name: %[2]q doesn't fit the length constraints of %[3]d to %[4]d. Please provide a value for %[1]q",
+				autoName, name, autoNamingSpec.MinLength, autoNamingSpec.MaxLength)
+		}
+		return resource.NewStringProperty(name), nil
+	}
+
 	// Generate random name that fits the length constraints.
-	name := urn.Name().String()
-	prefix := name + "-"
-	randLength := 7
 	if len(prefix)+randLength < autoNamingSpec.MinLength {
 		randLength = autoNamingSpec.MinLength - len(prefix)
 	}
 
-	maxLength := 0
 	if autoNamingSpec.MaxLength > 0 {
 		left := autoNamingSpec.MaxLength - len(prefix)
 
 		if left <= 0 {
 			return resource.PropertyValue{}, fmt.Errorf("failed to auto-generate value for %[1]q."+
 				" Prefix: %[2]q is too large to fix max length constraint of %[3]d. Please provide a value for %[1]q",
-				autoName, prefix, maxLength)
+				autoName, prefix, autoNamingSpec.MaxLength)
 		}
 		if left < randLength {
 			randLength = left
 		}
-		maxLength = len(prefix) + left
 	}
 
-	// Resource name is URN name + "-" + random suffix.
-	random, err := resource.NewUniqueName(randomSeed, prefix, randLength, maxLength, nil)
+	random, err := resource.NewUniqueName(randomSeed, "", randLength, 0, []rune(charset))
 	if err != nil {
 		return resource.PropertyValue{}, err
 	}
 
-	return resource.NewStringProperty(random), nil
+	return resource.NewStringProperty(withCase(parts[0]+random+parts[1], autoNamingSpec.Case)), nil
+}
+
+// withCase returns the name in the case of the auto-naming config.
+func withCase(name, nameCase string) string {
+	switch nameCase {
+	case "lower":
+		return strings.ToLower(name)
+	case "upper":
+		return strings.ToUpper(name)
+	}
+	return name
 }
@@ -4,6 +4,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -18,6 +19,7 @@ func Test_getDefaultName(t *testing.T) {
 		name       string
 		minLength  int
 		maxLength  int
+		config     esxi.AutoNamingConfig
 		olds       resource.PropertyMap
 		news       resource.PropertyMap
 		err        error
@@ -63,9 +65,38 @@ func Test_getDefaultName(t *testing.T) {
 			maxLength:  13,
 			comparison: within(13, 13),
 		},
+		{
+			name:       "Autoname with pattern",
+			config:     esxi.AutoNamingConfig{Pattern: "${project}-${stack}-${name}-${random}-vm", RandomLength: 4},
+			comparison: matches(`^test-dev-my-Name-[0-9a-f]{4}-vm$`),
+		},
+		{
+			name:       "Autoname with pattern on max length",
+			maxLength:  20,
+			config:     esxi.AutoNamingConfig{Pattern: "${stack}-${name}-${random}-vm"},
+			comparison: matches(`^dev-my-Name-[0-9a-f]{5}-vm$`),
+		},
+		{
+			name:       "Autoname with charset and case",
+			config:     esxi.AutoNamingConfig{RandomLength: 10, Charset: "xyz", Case: "upper"},
+			comparison: matches(`^MY-NAME-[XYZ]{10}$`),
+		},
+		{
+			name:       "Autoname without random part",
+			config:     esxi.AutoNamingConfig{Pattern: "${stack}-${name}", Case: "lower"},
+			comparison: equals(resource.NewStringProperty("dev-my-name")),
+		},
+		{
+			name:      "Autoname without random part too long",
+			maxLength: 8,
+			config:    esxi.AutoNamingConfig{Pattern: "${stack}-${name}"},
+			err:       fmt.Errorf("failed to auto-generate value for %[1]q. Name: \"dev-my-Name\" doesn't fit the length constraints of 0 to 8. Please provide a value for %[1]q", sdkName),
+		},
 	}
 
 	urn := resource.URN("urn:pulumi:dev::test::test-provider:testModule:TestResource::myName")
+	// The characters not allowed by the host are replaced in the generated names.
+	invalidChars := regexp.MustCompile(`[^A-Za-z0-9-]`)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -74,6 +105,12 @@ func Test_getDefaultName(t *testing.T) {
 				MinLength:    tt.minLength,
 				MaxLength:    tt.maxLength,
 			}
+			urn := urn
+			if tt.config != (esxi.AutoNamingConfig{}) {
+				autoNamingSpec.InvalidChars = invalidChars
+				autoNamingSpec.AutoNamingConfig = tt.config
+				urn = resource.URN("urn:pulumi:dev::test::test-provider:testModule:TestResource::my_Name")
+			}
 			got, err := getDefaultName(nil, urn, autoNamingSpec, tt.olds, tt.news)
 			if tt.err != nil {
 				require.EqualError(t, err, tt.err.Error())
@@ -95,6 +132,12 @@ func equals(expected resource.PropertyValue) func(resource.PropertyValue) bool {
 	}
 }
 
+func matches(pattern string) func(resource.PropertyValue) bool {
+	return func(actual resource.PropertyValue) bool {
+		return regexp.MustCompile(pattern).MatchString(actual.StringValue())
+	}
+}
+
 func within(min, max int) func(value resource.PropertyValue) bool {
 	return func(actual resource.PropertyValue) bool {
 		l := len(actual.V.(string))
@@ -16,13 +16,18 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"time"
 
 	pbempty "github.com/golang/protobuf/ptypes/empty"
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/pkg/errors"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
@@ -63,7 +68,13 @@ type esxiProvider struct {
 
 	pulumiSchema []byte
 
-	esxi            *esxi.Host
+	// preflight checks a host for the preflight config.
+	preflight func(ctx context.Context, connection esxi.ConnectionInfo) []esxi.PreflightCheck
+	// recordHostKeys records the host keys trusted on first use of a host.
+	recordHostKeys func(ctx context.Context, connection *esxi.ConnectionInfo) error
+
+	hosts           *hostRegistry
+	defaults        esxi.Defaults
 	namingService   *esxi.AutoNamingService
 	resourceService *esxi.ResourceService
 }
@@ -71,7 +82,7 @@ type esxiProvider struct {
 var _ pulumirpc.ResourceProviderServer = (*esxiProvider)(nil)
 
 func newESXiNativeProvider(host *provider.HostClient, name, version string, pulumiSchema []byte) (
-	pulumirpc.ResourceProviderServer, error,
+	*esxiProvider, error,
 ) {
 	return &esxiProvider{
 		host:         host,
@@ -79,6 +90,9 @@ func newESXiNativeProvider(host *provider.HostClient, name, version string, pulu
 		name:         name,
 		version:      version,
 		pulumiSchema: pulumiSchema,
+		preflight:    esxi.Preflight,
+
+		recordHostKeys: esxi.RecordHostKeys,
 	}, nil
 }
 
@@ -102,12 +116,151 @@ func (p *esxiProvider) Construct(_ context.Context, _ *pulumirpc.ConstructReques
 	return nil, status.Error(codes.Unimplemented, "construct is not yet implemented")
 }
 
-// CheckConfig validates the configuration for this provider.
-func (p *esxiProvider) CheckConfig(_ context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
-	return &pulumirpc.CheckResponse{Inputs: req.GetNews()}, nil
+// CheckConfig validates the configuration for this provider. With preflight
+// set, it then connects to the hosts and reports the checks they failed.
+func (p *esxiProvider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	urn := resource.URN(req.GetUrn())
+	label := fmt.Sprintf("%s.CheckConfig(%s)", p.name, urn)
+	logging.V(logLevel).Infof("%s executing", label)
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.news", label),
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+		SkipNulls:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vars, unknowns := configVars(news)
+	failures := checkConfig(vars, unknowns)
+	inputs := req.GetNews()
+	if len(failures) == 0 && len(unknowns) == 0 {
+		olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+			Label: fmt.Sprintf("%s.olds", label), KeepSecrets: true, SkipNulls: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var recorded bool
+		if failures, recorded = p.trustHostKeys(ctx, olds, news, vars); recorded {
+			if inputs, err = plugin.MarshalProperties(news, plugin.MarshalOptions{
+				Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true, KeepSecrets: true,
+			}); err != nil {
+				return nil, err
+			}
+			vars, _ = configVars(news)
+		}
+	}
+	if preflight, _ := getConfig(vars, "preflight", "ESXI_PREFLIGHT"); preflight == "true" && len(failures) == 0 && len(unknowns) == 0 {
+		failures = p.preflightHosts(ctx, urn, vars)
+	}
+
+	return &pulumirpc.CheckResponse{Inputs: inputs, Failures: failures}, nil
 }
 
-// DiffConfig diffs the configuration for this provider.
+// trustHostKeys records the host keys trusted on first use in the
+// trustedHostKeys of the config, which is kept in the provider state. The keys
+// recorded in the old config are kept, and the keys of the hosts not recorded
+// yet are read from the hosts. It returns whether the config was changed.
+func (p *esxiProvider) trustHostKeys(ctx context.Context, olds, news resource.PropertyMap, vars map[string]string) (
+	[]*pulumirpc.CheckFailure, bool,
+) {
+	connections, err := preflightConnections(vars)
+	if err != nil {
+		return []*pulumirpc.CheckFailure{{Reason: err.Error()}}, false
+	}
+	names := make([]string, 0, len(connections))
+	for name, connection := range connections {
+		if connection.TrustOnFirstUse {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	sort.Strings(names)
+
+	oldVars, _ := configVars(olds)
+	trusted, _ := getTrustedHostKeys(oldVars)
+	if trusted == nil {
+		trusted = map[string]string{}
+	}
+	configured, _ := getTrustedHostKeys(vars)
+	for address, fingerprint := range configured {
+		trusted[address] = fingerprint
+	}
+
+	var failures []*pulumirpc.CheckFailure
+	for _, name := range names {
+		connection := connections[name]
+		connection.TrustedHostKeys = trusted
+		recordCtx, cancel := p.operationContext(ctx, 0)
+		err := p.recordHostKeys(recordCtx, &connection)
+		cancel()
+		if err != nil {
+			property := "trustOnFirstUse"
+			if len(name) > 0 {
+				property = "hosts"
+			}
+			failures = append(failures, &pulumirpc.CheckFailure{Property: property, Reason: err.Error()})
+		}
+	}
+
+	if len(trusted) == len(configured) {
+		return failures, false
+	}
+	encoded, err := json.Marshal(trusted)
+	if err != nil {
+		return append(failures, &pulumirpc.CheckFailure{Property: "trustedHostKeys", Reason: err.Error()}), false
+	}
+	news["trustedHostKeys"] = resource.NewStringProperty(string(encoded))
+	return failures, true
+}
+
+// preflightHosts runs the preflight of the hosts of the config, reports the
+// checks of every host and returns the failed ones.
+func (p *esxiProvider) preflightHosts(ctx context.Context, urn resource.URN, vars map[string]string) []*pulumirpc.CheckFailure {
+	connections, err := preflightConnections(vars)
+	if err != nil {
+		return []*pulumirpc.CheckFailure{{Reason: err.Error()}}
+	}
+	names := make([]string, 0, len(connections))
+	for name := range connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []*pulumirpc.CheckFailure
+	for _, name := range names {
+		checkCtx, cancel := p.operationContext(ctx, 0)
+		checks := p.preflight(checkCtx, connections[name])
+		cancel()
+
+		report := preflightReport(name, checks)
+		logging.V(logLevel).Infof("%s", report)
+		if p.host != nil {
+			_ = p.host.Log(ctx, diag.Info, urn, report)
+		}
+		property := "host"
+		if len(name) > 0 {
+			property = "hosts"
+		}
+		for _, check := range checks {
+			if check.Err != nil {
+				failures = append(failures, &pulumirpc.CheckFailure{
+					Property: property,
+					Reason:   fmt.Sprintf("%s, check '%s' failed: %s", preflightLabel(name), check.Name, check.Err),
+				})
+			}
+		}
+	}
+	return failures
+}
+
+// DiffConfig diffs the configuration for this provider. A change of the hosts
+// replaces the resources, a change of the credentials is ignored.
 func (p *esxiProvider) DiffConfig(_ context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
 	urn := resource.URN(req.GetUrn())
 	label := fmt.Sprintf("%s.DiffConfig(%s)", p.name, urn)
@@ -129,17 +282,11 @@ func (p *esxiProvider) DiffConfig(_ context.Context, req *pulumirpc.DiffRequest)
 		return nil, errors.Wrapf(err, "diffConfig failed because of malformed resource inputs")
 	}
 
-	diff := olds.Diff(news)
-	if diff == nil {
+	diffs, replaces := diffConfig(olds, news)
+	if len(diffs) == 0 {
 		return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_NONE}, nil
 	}
 
-	diffs := make([]string, 0, len(diff.Keys()))
-	for _, k := range diff.Keys() {
-		diffs = append(diffs, string(k))
-	}
-
-	var replaces []string
 	return &pulumirpc.DiffResponse{
 		Changes:  pulumirpc.DiffResponse_DIFF_SOME,
 		Diffs:    diffs,
@@ -148,40 +295,76 @@ func (p *esxiProvider) DiffConfig(_ context.Context, req *pulumirpc.DiffRequest)
 }
 
 // Configure configures the resource provider with "globals" that control its behavior.
-func (p *esxiProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+func (p *esxiProvider) Configure(ctx context.Context, req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
 	vars := req.GetVariables()
 
-	host, hostErr := getConfig(vars, "host", "ESXI_HOST")
-	user, userErr := getConfig(vars, "username", "ESXI_USERNAME")
-	pass, passErr := getConfig(vars, "password", "ESXI_PASSWORD")
-	sshPort, sshPortErr := getConfig(vars, "sshPort", "ESXI_SSH_PORT")
-	sslPort, sslPortErr := getConfig(vars, "sslPort", "ESXI_SSL_PORT")
-	if len(sshPort) > 0 {
-		sshPort = "22"
+	_, hostErr := getConfig(vars, "host", "ESXI_HOST")
+	_, userErr := getConfig(vars, "username", "ESXI_USERNAME")
+	_, passErr := getConfig(vars, "password", "ESXI_PASSWORD")
+
+	connection, err := getConnection(vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
-	if len(sslPort) > 0 {
-		sslPort = "443"
+	hostsConfig, err := getHostsConfig(vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	defaults, err := getResourceDefaults(vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	autoNaming, err := getAutoNamingConfig(vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	if len(host) > 0 && len(user) > 0 && len(pass) > 0 {
-		// If all required values are not present/valid, the client will return an appropriate error.
-		esxiHost, err := esxi.NewHost(host, sshPort, sslPort, user, pass)
-		if err != nil {
-			return nil, err
+	// The named hosts are connected to on their first use.
+	hosts := newHostRegistry()
+	for name, hostConfig := range hostsConfig {
+		named := hostConfig.connection(connection)
+		if len(named.UserName) == 0 || !hasCredentials(named) {
+			return nil, fmt.Errorf("invalid config: the host '%s' has no username or credentials, "+
+				"and none are inherited from the provider config", name)
 		}
-		p.esxi = esxiHost
-	} else {
-		errorMessage := "Invalid config."
-		for _, errMsg := range []string{hostErr, userErr, passErr, sshPortErr, sslPortErr} {
-			if len(errMsg) > 0 {
-				errorMessage = fmt.Sprintf("%s\n%s", errorMessage, errMsg)
+		hosts.named[name] = &namedHost{connection: named}
+	}
+
+	// With named hosts, the default host is optional.
+	if len(connection.Host) > 0 || len(hostsConfig) == 0 {
+		if len(connection.Host) > 0 && len(connection.UserName) > 0 && hasCredentials(connection) {
+			// If all required values are not present/valid, the client will return an appropriate error.
+			connectCtx, cancel := p.operationContext(ctx, 0)
+			defer cancel()
+			esxiHost, err := esxi.NewHost(connectCtx, connection)
+			if err != nil {
+				return nil, err
+			}
+			hosts.defaultHost = esxiHost
+		} else {
+			passErr = ""
+			if !hasCredentials(connection) {
+				passErr = missingCredentials(connection.Transport)
+			}
+			errorMessage := "Invalid config."
+			for _, errMsg := range []string{hostErr, userErr, passErr} {
+				if len(errMsg) > 0 {
+					errorMessage = fmt.Sprintf("%s\n%s", errorMessage, errMsg)
+				}
 			}
-		}
 
-		return nil, fmt.Errorf(errorMessage)
+			return nil, fmt.Errorf(errorMessage)
+		}
 	}
+	if connection.InsecureSkipHostKeyVerification && p.host != nil {
+		_ = p.host.Log(ctx, diag.Warning, "", "insecureSkipHostKeyVerification is set, the ssh host keys and the api "+
+			"certificates aren't verified and the connections to the hosts can be intercepted")
+	}
+	p.close()
+	p.hosts = hosts
+	p.defaults = defaults
 
-	p.namingService = esxi.NewAutoNamingService()
+	p.namingService = esxi.NewAutoNamingServiceWithConfig(autoNaming)
 	p.resourceService = esxi.NewResourceService()
 
 	p.configured = true
@@ -192,7 +375,7 @@ func (p *esxiProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequ
 }
 
 // Invoke dynamically executes a built-in function in the provider.
-func (p *esxiProvider) Invoke(_ context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+func (p *esxiProvider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
 	// Unmarshal arguments.
 	token := req.GetTok()
 
@@ -205,11 +388,29 @@ func (p *esxiProvider) Invoke(_ context.Context, req *pulumirpc.InvokeRequest) (
 		return nil, err
 	}
 
+	// The virtual machines are looked up by id on the host the id encodes.
+	name := hostName(inputs)
+	if property, has := inputs["id"]; has && property.IsString() {
+		if idHost, id := parseHostId(property.StringValue()); len(idHost) > 0 {
+			name = idHost
+			inputs["id"] = resource.NewStringProperty(id)
+		}
+	}
+
 	// Process Invoke call.
-	result, err := p.resourceService.Invoke(token, inputs, p.esxi)
+	ctx, cancel := p.operationContext(ctx, 0)
+	defer cancel()
+	esxiHost, err := p.hosts.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.resourceService.Invoke(ctx, token, inputs, esxiHost)
 	if err != nil {
 		return nil, err
 	}
+	if property, has := result["id"]; has && property.IsString() {
+		result["id"] = resource.NewStringProperty(hostId(name, property.StringValue()))
+	}
 
 	res, err := plugin.MarshalProperties(result, plugin.MarshalOptions{
 		Label:        fmt.Sprintf("%s.Invoke(%s).outputs", p.name, token),
@@ -272,10 +473,19 @@ func (p *esxiProvider) Check(_ context.Context, req *pulumirpc.CheckRequest) (*p
 		}
 		newInputs[resource.PropertyKey(autoNamingSpec.PropertyName)] = val
 	}
-	checkFailures, err := p.resourceService.Validate(resourceToken, newInputs)
+	checkFailures, err := p.resourceService.Check(resourceToken, newInputs, p.defaults)
 	if err != nil {
 		return nil, err
 	}
+	if host, has := newInputs["host"]; p.hosts != nil && !(has && host.ContainsUnknowns()) {
+		if name := hostName(newInputs); !p.hosts.has(name) {
+			reason := fmt.Sprintf("The property 'host' is invalid! The host '%s' is not one of the hosts config", name)
+			if len(name) == 0 {
+				reason = "The property 'host' is required! The provider config has no default host"
+			}
+			checkFailures = append(checkFailures, &pulumirpc.CheckFailure{Property: "host", Reason: reason})
+		}
+	}
 	if len(checkFailures) == 0 {
 		inputs, err := plugin.MarshalProperties(newInputs, plugin.MarshalOptions{
 			Label:        fmt.Sprintf("%s.inputs", label),
@@ -296,22 +506,47 @@ func (p *esxiProvider) Diff(_ context.Context, req *pulumirpc.DiffRequest) (*pul
 	label := fmt.Sprintf("%s.Diff(%s)", p.name, urn)
 	logging.V(logLevel).Infof("%s executing", label)
 
-	diff, err := p.diffState(req.GetOlds(), req.GetNews(), label)
+	oldInputs, newInputs, err := p.diffInputs(req.GetOlds(), req.GetNews(), label)
 	if err != nil {
 		return nil, err
 	}
-	if diff == nil {
-		return &pulumirpc.DiffResponse{Changes: pulumirpc.DiffResponse_DIFF_NONE}, nil
+
+	// The host isn't an input of the resources on the host, its change is
+	// diffed apart from the others.
+	hostDiff := oldInputs.Diff(newInputs)
+	oldInputs, newInputs = oldInputs.Copy(), newInputs.Copy()
+	delete(oldInputs, "host")
+	delete(newInputs, "host")
+
+	diff, err := p.resourceService.Diff(string(urn.Type()), oldInputs, newInputs)
+	if err != nil {
+		return nil, err
 	}
 
-	return &pulumirpc.DiffResponse{
-		Changes:             pulumirpc.DiffResponse_DIFF_UNKNOWN,
-		DeleteBeforeReplace: true,
-	}, nil
+	// The resources are not moved from a host to another, they are replaced.
+	// The replacement on the new host is created before the resource is deleted.
+	if hostDiff != nil && hostDiff.Changed("host") {
+		kind := pulumirpc.PropertyDiff_UPDATE_REPLACE
+		if hostDiff.Added("host") {
+			kind = pulumirpc.PropertyDiff_ADD_REPLACE
+		} else if hostDiff.Deleted("host") {
+			kind = pulumirpc.PropertyDiff_DELETE_REPLACE
+		}
+		if diff.DetailedDiff == nil {
+			diff.DetailedDiff = map[string]*pulumirpc.PropertyDiff{}
+		}
+		diff.Changes = pulumirpc.DiffResponse_DIFF_SOME
+		diff.HasDetailedDiff = true
+		diff.DetailedDiff["host"] = &pulumirpc.PropertyDiff{Kind: kind, InputDiff: true}
+		diff.Diffs = append(diff.Diffs, "host")
+		diff.Replaces = append(diff.Replaces, "host")
+		diff.DeleteBeforeReplace = false
+	}
+	return diff, nil
 }
 
 // Create allocates a new instance of the provided resource and returns its unique ID afterward.
-func (p *esxiProvider) Create(_ context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+func (p *esxiProvider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
 	urn := resource.URN(req.GetUrn())
 	label := fmt.Sprintf("%s.Create(%s)", p.name, urn)
 	logging.V(logLevel).Infof("%s executing", label)
@@ -329,10 +564,18 @@ func (p *esxiProvider) Create(_ context.Context, req *pulumirpc.CreateRequest) (
 
 	resourceToken := string(urn.Type())
 	// Process Create call.
-	id, outputs, err := p.resourceService.Create(resourceToken, inputs, p.esxi)
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
+	name := hostName(inputs)
+	esxiHost, err := p.hosts.get(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	id, outputs, err := p.resourceService.Create(ctx, resourceToken, inputs, esxiHost)
+	if err != nil {
+		return nil, err
+	}
+	id, outputs = hostId(name, id), withHost(name, outputs)
 
 	// Store both outputs and inputs into the state.
 	checkpoint, err := plugin.MarshalProperties(
@@ -350,7 +593,7 @@ func (p *esxiProvider) Create(_ context.Context, req *pulumirpc.CreateRequest) (
 }
 
 // Read the current live state associated with a resource.
-func (p *esxiProvider) Read(_ context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+func (p *esxiProvider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
 	urn := resource.URN(req.GetUrn())
 	label := fmt.Sprintf("%s.Read(%s)", p.name, urn)
 	logging.V(logLevel).Infof("%s executing", label)
@@ -367,24 +610,39 @@ func (p *esxiProvider) Read(_ context.Context, req *pulumirpc.ReadRequest) (*pul
 	// Read the resource state from ESXi.
 	resourceToken := string(urn.Type())
 
-	var readInputs resource.PropertyMap
-
-	// Extract old inputs from the `__inputs` field of the old state.
+	// Extract old inputs from the `__inputs` field of the old state, there is
+	// no old state when the resource is imported.
 	inputs := parseCheckpointObject(oldState)
-	if inputs == nil {
-		readInputs = make(resource.PropertyMap)
-	} else {
-		readInputs = inputs
-	}
 
 	// Process Read call.
-	id, newState, err := p.resourceService.Read(id, resourceToken, readInputs, p.esxi)
+	ctx, cancel := p.operationContext(ctx, 0)
+	defer cancel()
+	name, id := parseHostId(id)
+	esxiHost, err := p.hosts.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var newState resource.PropertyMap
+	imported := inputs == nil
+	if imported {
+		id, inputs, newState, err = p.resourceService.Import(ctx, resourceToken, id, esxiHost)
+	} else {
+		id, newState, err = p.resourceService.Read(ctx, resourceToken, id, inputs, esxiHost)
+	}
+	if esxi.IsNotFound(err) {
+		// The resource was deleted out of band, the empty id removes it from the state.
+		logging.V(logLevel).Infof("%s: %s, removing it from the state", label, err)
+		return &pulumirpc.ReadResponse{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
+	id, newState = hostId(name, id), withHost(name, newState)
 
-	if inputs == nil {
-		inputs = newState
+	if imported {
+		inputs = withHost(name, inputs)
+	} else {
+		inputs = p.resourceService.RefreshInputs(resourceToken, inputs, newState)
 	}
 
 	// Store both outputs and inputs into the state checkpoint.
@@ -412,7 +670,7 @@ func (p *esxiProvider) Read(_ context.Context, req *pulumirpc.ReadRequest) (*pul
 }
 
 // Update updates an existing resource with new values.
-func (p *esxiProvider) Update(_ context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+func (p *esxiProvider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
 	urn := resource.URN(req.GetUrn())
 	label := fmt.Sprintf("%s.Update(%s)", p.name, urn)
 	logging.V(logLevel).Infof("%s executing", label)
@@ -420,6 +678,17 @@ func (p *esxiProvider) Update(_ context.Context, req *pulumirpc.UpdateRequest) (
 	id := req.GetId()
 	resourceToken := string(urn.Type())
 
+	// Retrieve the old inputs and outputs from the old state.
+	oldState, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.olds", label), KeepUnknowns: true, SkipNulls: true, KeepSecrets: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	oldInputs := parseCheckpointObject(oldState)
+	oldOutputs := oldState.Copy()
+	delete(oldOutputs, "__inputs")
+
 	// Read the inputs to persist them into state.
 	newInputs, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
 		Label:        fmt.Sprintf("%s.newInputs", label),
@@ -432,10 +701,23 @@ func (p *esxiProvider) Update(_ context.Context, req *pulumirpc.UpdateRequest) (
 	}
 
 	// Process Update call.
-	outputs, err := p.resourceService.Update(id, resourceToken, make(resource.PropertyMap), p.esxi)
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
+	name, id := parseHostId(id)
+	esxiHost, err := p.hosts.get(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	outputs, err := p.resourceService.Update(ctx, resourceToken, esxi.ResourceUpdate{
+		Id:         id,
+		OldInputs:  oldInputs,
+		NewInputs:  newInputs,
+		OldOutputs: oldOutputs,
+	}, esxiHost)
+	if err != nil {
+		return nil, err
+	}
+	outputs = withHost(name, outputs)
 
 	// Store both outputs and inputs into the state and return RPC checkpoint.
 	checkpoint, err := plugin.MarshalProperties(
@@ -451,7 +733,7 @@ func (p *esxiProvider) Update(_ context.Context, req *pulumirpc.UpdateRequest) (
 
 // Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed
 // to still exist.
-func (p *esxiProvider) Delete(_ context.Context, req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
+func (p *esxiProvider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
 	urn := resource.URN(req.GetUrn())
 	label := fmt.Sprintf("%s.Update(%s)", p.name, urn)
 	logging.V(logLevel).Infof("%s executing", label)
@@ -459,8 +741,15 @@ func (p *esxiProvider) Delete(_ context.Context, req *pulumirpc.DeleteRequest) (
 	resourceToken := string(urn.Type())
 	id := req.GetId()
 
-	// Process Read call.
-	err := p.resourceService.Delete(resourceToken, id, p.esxi)
+	// Process Delete call.
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
+	name, id := parseHostId(id)
+	esxiHost, err := p.hosts.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	err = p.resourceService.Delete(ctx, resourceToken, id, esxiHost)
 	if err != nil {
 		return nil, err
 	}
@@ -490,11 +779,41 @@ func (p *esxiProvider) GetSchema(_ context.Context, req *pulumirpc.GetSchemaRequ
 // hard-closing any gRPC connection.
 func (p *esxiProvider) Cancel(context.Context, *pbempty.Empty) (*pbempty.Empty, error) {
 	p.canceler.cancel()
+	p.close()
 	return &pbempty.Empty{}, nil
 }
 
-// diffState extracts old and new inputs and calculates a diff between them.
-func (p *esxiProvider) diffState(olds *structpb.Struct, news *structpb.Struct, label string) (*resource.ObjectDiff, error) {
+// close releases the connections to the ESXi hosts.
+func (p *esxiProvider) close() {
+	if p.hosts != nil {
+		p.hosts.close()
+	}
+}
+
+// operationContext derives the context of a resource operation from the request context.
+// It's done when the operation exceeds its custom timeout, in seconds, or the provider is
+// cancelled, which aborts the remote commands and ovftool runs of the operation.
+func (p *esxiProvider) operationContext(ctx context.Context, timeout float64) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Second)))
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	go func() {
+		select {
+		case <-p.canceler.context.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// diffInputs extracts the old inputs from the old state, and the new inputs.
+func (p *esxiProvider) diffInputs(olds *structpb.Struct, news *structpb.Struct, label string) (resource.PropertyMap, resource.PropertyMap, error) {
 	oldState, err := plugin.UnmarshalProperties(olds, plugin.MarshalOptions{
 		Label:        fmt.Sprintf("%s.oldState", label),
 		KeepUnknowns: true,
@@ -502,7 +821,7 @@ func (p *esxiProvider) diffState(olds *structpb.Struct, news *structpb.Struct, l
 		KeepSecrets:  true,
 	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "diff failed because malformed resource inputs")
+		return nil, nil, errors.Wrapf(err, "diff failed because malformed resource inputs")
 	}
 
 	// Extract old inputs from the `__inputs` field of the old state.
@@ -515,10 +834,10 @@ func (p *esxiProvider) diffState(olds *structpb.Struct, news *structpb.Struct, l
 		KeepSecrets:  true,
 	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "diff failed because malformed resource inputs")
+		return nil, nil, errors.Wrapf(err, "diff failed because malformed resource inputs")
 	}
 
-	return oldInputs.Diff(newInputs), nil
+	return oldInputs, newInputs, nil
 }
 
 // checkpointObject puts inputs in the `__inputs` field of the state.
@@ -583,6 +902,52 @@ func filterNullProperties(m resource.PropertyMap) resource.PropertyMap {
 	return result
 }
 
+// getRetryPolicy reads the retry policy of the connections and remote commands,
+// the values not configured keep their defaults.
+func getRetryPolicy(vars map[string]string) (esxi.RetryPolicy, error) {
+	var policy esxi.RetryPolicy
+	if attempts, _ := getConfig(vars, "retryAttempts", "ESXI_RETRY_ATTEMPTS"); len(attempts) > 0 {
+		value, err := strconv.Atoi(attempts)
+		if err != nil || value < 1 {
+			return policy, invalidConfig("retryAttempts", "invalid retryAttempts '%s', expected a positive number", attempts)
+		}
+		policy.Attempts = value
+	}
+
+	delays := []struct {
+		key, env string
+		value    *time.Duration
+	}{
+		{"retryInitialDelay", "ESXI_RETRY_INITIAL_DELAY", &policy.InitialDelay},
+		{"retryMaxDelay", "ESXI_RETRY_MAX_DELAY", &policy.MaxDelay},
+	}
+	for _, delay := range delays {
+		if value, _ := getConfig(vars, delay.key, delay.env); len(value) > 0 {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				return policy, invalidConfig(delay.key, "invalid %s '%s', expected a duration such as '1s' or '500ms'", delay.key, value)
+			}
+			*delay.value = duration
+		}
+	}
+
+	return policy, nil
+}
+
+// getMaxConcurrentSessions reads the bound of the remote operations running at
+// once on a host, zero when it isn't configured to keep the default.
+func getMaxConcurrentSessions(vars map[string]string) (int, error) {
+	value, _ := getConfig(vars, "maxConcurrentSessions", "ESXI_MAX_CONCURRENT_SESSIONS")
+	if len(value) == 0 {
+		return 0, nil
+	}
+	sessions, err := strconv.Atoi(value)
+	if err != nil || sessions < 1 {
+		return 0, invalidConfig("maxConcurrentSessions", "invalid maxConcurrentSessions '%s', expected a positive number", value)
+	}
+	return sessions, nil
+}
+
 func getConfig(vars map[string]string, key, env string) (string, string) {
 	if val, ok := vars[fmt.Sprintf("esxi-native:config:%s", key)]; ok {
 		return val, ""
@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+func newFakeProvider(t *testing.T) (*esxiProvider, *esxi.FakeExecutor) {
+	t.Helper()
+	fake := esxi.NewFakeExecutor()
+	hosts := newHostRegistry()
+	hosts.defaultHost = esxi.NewHostWithExecutor(esxi.ConnectionInfo{Host: "fake"}, fake)
+	return &esxiProvider{
+		name:            "esxi-native",
+		canceler:        makeCancellationContext(),
+		hosts:           hosts,
+		namingService:   esxi.NewAutoNamingService(),
+		resourceService: esxi.NewResourceService(),
+	}, fake
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		token   string
+		inputs  resource.PropertyMap
+		changes resource.PropertyMap
+		// commands are run by the update, unchanged are the commands of the inputs that didn't change.
+		commands  []string
+		unchanged []string
+		outputs   resource.PropertyMap
+	}{
+		{
+			token: "esxi-native:index:VirtualSwitch",
+			inputs: resource.PropertyMap{
+				"name": resource.NewStringProperty("vSwitch1"),
+				"mtu":  resource.NewNumberProperty(1500),
+			},
+			changes:   resource.PropertyMap{"mtu": resource.NewNumberProperty(9000)},
+			commands:  []string{"esxcli network vswitch standard set -m 9000 -c listen -v vSwitch1"},
+			unchanged: []string{"esxcli network vswitch standard policy security set"},
+			outputs:   resource.PropertyMap{"mtu": resource.NewNumberProperty(9000)},
+		},
+		{
+			token: "esxi-native:index:PortGroup",
+			inputs: resource.PropertyMap{
+				"name":    resource.NewStringProperty("pg-web"),
+				"vSwitch": resource.NewStringProperty("vSwitch0"),
+			},
+			changes:   resource.PropertyMap{"vlan": resource.NewNumberProperty(10)},
+			commands:  []string{"esxcli network vswitch standard portgroup set -v 10 -p pg-web"},
+			unchanged: []string{"esxcli network vswitch standard portgroup policy security set"},
+			outputs:   resource.PropertyMap{"vlan": resource.NewNumberProperty(10)},
+		},
+		{
+			token:     "esxi-native:index:ResourcePool",
+			inputs:    resource.PropertyMap{"name": resource.NewStringProperty("pool-web")},
+			changes:   resource.PropertyMap{"cpuMin": resource.NewNumberProperty(500)},
+			commands:  []string{"vim-cmd hostsvc/rsrc/pool_config_set"},
+			unchanged: []string{"vim-cmd hostsvc/rsrc/rename"},
+			outputs:   resource.PropertyMap{"cpuMin": resource.NewNumberProperty(500)},
+		},
+		{
+			token: "esxi-native:index:VirtualDisk",
+			inputs: resource.PropertyMap{
+				"name":      resource.NewStringProperty("disk-web"),
+				"diskStore": resource.NewStringProperty("datastore1"),
+				"directory": resource.NewStringProperty("disks"),
+				"diskType":  resource.NewStringProperty("thin"),
+				"size":      resource.NewNumberProperty(2),
+			},
+			changes:  resource.PropertyMap{"size": resource.NewNumberProperty(4)},
+			commands: []string{"/bin/vmkfstools -X 4G /vmfs/volumes/datastore1/disks/disk-web.vmdk"},
+			outputs:  resource.PropertyMap{"size": resource.NewNumberProperty(4)},
+		},
+		{
+			token: "esxi-native:index:VirtualMachine",
+			inputs: resource.PropertyMap{
+				"name":         resource.NewStringProperty("vm-web"),
+				"diskStore":    resource.NewStringProperty("datastore1"),
+				"memSize":      resource.NewNumberProperty(512),
+				"numVCpus":     resource.NewNumberProperty(1),
+				"bootDiskSize": resource.NewNumberProperty(16),
+				"power":        resource.NewStringProperty("off"),
+			},
+			changes:   resource.PropertyMap{"memSize": resource.NewNumberProperty(1024)},
+			commands:  []string{"vim-cmd vmsvc/reload"},
+			unchanged: []string{"vmkfstools -X", "vim-cmd vmsvc/power.on", "vim-cmd vmsvc/power.off"},
+			outputs:   resource.PropertyMap{"memSize": resource.NewNumberProperty(1024)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.token, func(t *testing.T) {
+			p, fake := newFakeProvider(t)
+			ctx := context.Background()
+			urn := "urn:pulumi:dev::test::" + test.token + "::test"
+
+			properties, err := plugin.MarshalProperties(test.inputs, plugin.MarshalOptions{})
+			require.NoError(t, err)
+			created, err := p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+			require.NoError(t, err)
+
+			news := test.inputs.Copy()
+			for key, value := range test.changes {
+				news[key] = value
+			}
+			newProperties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{})
+			require.NoError(t, err)
+			commands := len(fake.Commands())
+			updated, err := p.Update(ctx, &pulumirpc.UpdateRequest{
+				Urn: urn, Id: created.GetId(), Olds: created.GetProperties(), News: newProperties,
+			})
+			require.NoError(t, err)
+
+			run := fake.Commands()[commands:]
+			for _, command := range test.commands {
+				require.True(t, containsPrefix(run, command), "%s not in %v", command, run)
+			}
+			for _, command := range test.unchanged {
+				require.False(t, containsPrefix(run, command), "%s in %v", command, run)
+			}
+
+			state, err := plugin.UnmarshalProperties(updated.GetProperties(), plugin.MarshalOptions{KeepSecrets: true})
+			require.NoError(t, err)
+			for key, value := range test.outputs {
+				require.Equal(t, value, state[key], key)
+			}
+			require.Equal(t, news, parseCheckpointObject(state))
+		})
+	}
+}
+
+func TestUpdateWithoutChanges(t *testing.T) {
+	p, fake := newFakeProvider(t)
+	ctx := context.Background()
+	urn := "urn:pulumi:dev::test::esxi-native:index:VirtualDisk::test"
+	properties, err := plugin.MarshalProperties(resource.PropertyMap{
+		"name":      resource.NewStringProperty("disk-web"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"directory": resource.NewStringProperty("disks"),
+		"diskType":  resource.NewStringProperty("thin"),
+		"size":      resource.NewNumberProperty(2),
+	}, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	created, err := p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+	require.NoError(t, err)
+
+	// The outputs of the disk are kept, no command is run.
+	commands := len(fake.Commands())
+	updated, err := p.Update(ctx, &pulumirpc.UpdateRequest{
+		Urn: urn, Id: created.GetId(), Olds: created.GetProperties(), News: properties,
+	})
+	require.NoError(t, err)
+	require.Len(t, fake.Commands(), commands)
+	require.Equal(t, created.GetProperties().AsMap(), updated.GetProperties().AsMap())
+}
+
+func containsPrefix(commands []string, prefix string) bool {
+	for _, command := range commands {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRead(t *testing.T) {
+	p, fake := newFakeProvider(t)
+	ctx := context.Background()
+	urn := "urn:pulumi:dev::test::esxi-native:index:VirtualMachine::test"
+	inputs := resource.PropertyMap{
+		"name":      resource.NewStringProperty("vm-web"),
+		"diskStore": resource.NewStringProperty("datastore1"),
+		"memSize":   resource.NewNumberProperty(512),
+		"numVCpus":  resource.NewNumberProperty(1),
+		"power":     resource.NewStringProperty("off"),
+	}
+	properties, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	created, err := p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+	require.NoError(t, err)
+
+	read := func() *pulumirpc.ReadResponse {
+		response, err := p.Read(ctx, &pulumirpc.ReadRequest{Urn: urn, Id: created.GetId(), Properties: created.GetProperties()})
+		require.NoError(t, err)
+		return response
+	}
+	readInputs := func(response *pulumirpc.ReadResponse) resource.PropertyMap {
+		inputs, err := plugin.UnmarshalProperties(response.GetInputs(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		return inputs
+	}
+
+	// Without changes on the host, the inputs are the same.
+	response := read()
+	require.Equal(t, created.GetId(), response.GetId())
+	require.Equal(t, inputs, readInputs(response))
+
+	// The memory changed out of band is refreshed.
+	news := inputs.Copy()
+	news["memSize"] = resource.NewNumberProperty(1024)
+	newProperties, err := plugin.MarshalProperties(news, plugin.MarshalOptions{})
+	require.NoError(t, err)
+	_, err = p.Update(ctx, &pulumirpc.UpdateRequest{
+		Urn: urn, Id: created.GetId(), Olds: created.GetProperties(), News: newProperties,
+	})
+	require.NoError(t, err)
+	require.Equal(t, news, readInputs(read()))
+
+	// The virtual machine deleted out of band is removed from the state.
+	_, err = fake.Execute(ctx, "vim-cmd vmsvc/destroy "+created.GetId(), "destroy the virtual machine")
+	require.NoError(t, err)
+	response = read()
+	require.Empty(t, response.GetId())
+	require.Nil(t, response.GetProperties())
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		token  string
+		inputs resource.PropertyMap
+		// importId is the id the resource is imported with, id the one of the imported resource.
+		importId string
+		id       string
+		expected resource.PropertyMap
+	}{
+		{
+			token:    "esxi-native:index:VirtualSwitch",
+			inputs:   resource.PropertyMap{"name": resource.NewStringProperty("vSwitch1"), "mtu": resource.NewNumberProperty(9000)},
+			importId: "vSwitch1",
+			id:       "vSwitch1",
+			expected: resource.PropertyMap{
+				"name":              resource.NewStringProperty("vSwitch1"),
+				"ports":             resource.NewNumberProperty(128),
+				"mtu":               resource.NewNumberProperty(9000),
+				"linkDiscoveryMode": resource.NewStringProperty("listen"),
+				"promiscuousMode":   resource.NewBoolProperty(false),
+				"macChanges":        resource.NewBoolProperty(false),
+				"forgedTransmits":   resource.NewBoolProperty(false),
+			},
+		},
+		{
+			token: "esxi-native:index:PortGroup",
+			inputs: resource.PropertyMap{
+				"name":    resource.NewStringProperty("pg-web"),
+				"vSwitch": resource.NewStringProperty("vSwitch0"),
+				"vlan":    resource.NewNumberProperty(10),
+			},
+			importId: "vSwitch0/pg-web",
+			id:       "vSwitch0/pg-web",
+			expected: resource.PropertyMap{
+				"name":            resource.NewStringProperty("pg-web"),
+				"vSwitch":         resource.NewStringProperty("vSwitch0"),
+				"vlan":            resource.NewNumberProperty(10),
+				"promiscuousMode": resource.NewBoolProperty(false),
+				"macChanges":      resource.NewBoolProperty(false),
+				"forgedTransmits": resource.NewBoolProperty(false),
+			},
+		},
+		{
+			token:    "esxi-native:index:ResourcePool",
+			inputs:   resource.PropertyMap{"name": resource.NewStringProperty("pool-web"), "cpuMin": resource.NewNumberProperty(500)},
+			importId: "/pool-web",
+			id:       "pool0",
+			expected: resource.PropertyMap{
+				"name":             resource.NewStringProperty("pool-web"),
+				"cpuMin":           resource.NewNumberProperty(500),
+				"cpuMinExpandable": resource.NewStringProperty("true"),
+				"cpuMax":           resource.NewNumberProperty(0),
+				"cpuShares":        resource.NewStringProperty("normal"),
+				"memMin":           resource.NewNumberProperty(200),
+				"memMinExpandable": resource.NewStringProperty("true"),
+				"memMax":           resource.NewNumberProperty(0),
+				"memShares":        resource.NewStringProperty("normal"),
+			},
+		},
+		{
+			token: "esxi-native:index:VirtualDisk",
+			inputs: resource.PropertyMap{
+				"name":      resource.NewStringProperty("disk-web"),
+				"diskStore": resource.NewStringProperty("datastore1"),
+				"directory": resource.NewStringProperty("disks"),
+				"diskType":  resource.NewStringProperty("thin"),
+				"size":      resource.NewNumberProperty(2),
+			},
+			importId: "/vmfs/volumes/datastore1/disks/disk-web.vmdk",
+			id:       "/vmfs/volumes/datastore1/disks/disk-web.vmdk",
+			expected: resource.PropertyMap{
+				"name":      resource.NewStringProperty("disk-web"),
+				"diskStore": resource.NewStringProperty("datastore1"),
+				"directory": resource.NewStringProperty("disks"),
+				"diskType":  resource.NewStringProperty("thin"),
+				"size":      resource.NewNumberProperty(2),
+			},
+		},
+		{
+			token: "esxi-native:index:VirtualMachine",
+			inputs: resource.PropertyMap{
+				"name":      resource.NewStringProperty("vm-web"),
+				"diskStore": resource.NewStringProperty("datastore1"),
+				"memSize":   resource.NewNumberProperty(1024),
+				"numVCpus":  resource.NewNumberProperty(2),
+				"power":     resource.NewStringProperty("off"),
+				"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{"virtualNetwork": resource.NewStringProperty("VM Network")}),
+				}),
+			},
+			importId: "vm-web",
+			id:       "1",
+			expected: resource.PropertyMap{
+				"name":             resource.NewStringProperty("vm-web"),
+				"diskStore":        resource.NewStringProperty("datastore1"),
+				"resourcePoolName": resource.NewStringProperty("/"),
+				"bootDiskSize":     resource.NewNumberProperty(16),
+				"bootDiskType":     resource.NewStringProperty("thin"),
+				"memSize":          resource.NewNumberProperty(1024),
+				"numVCpus":         resource.NewNumberProperty(2),
+				"virtualHWVer":     resource.NewNumberProperty(13),
+				"os":               resource.NewStringProperty("centos"),
+				"bootFirmware":     resource.NewStringProperty("bios"),
+				"efiSecureBoot":    resource.NewBoolProperty(false),
+				"power":            resource.NewStringProperty("off"),
+				"networkInterfaces": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{
+						"virtualNetwork": resource.NewStringProperty("VM Network"),
+						"nicType":        resource.NewStringProperty("e1000"),
+					}),
+				}),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.token, func(t *testing.T) {
+			p, _ := newFakeProvider(t)
+			ctx := context.Background()
+			urn := "urn:pulumi:dev::test::" + test.token + "::test"
+			properties, err := plugin.MarshalProperties(test.inputs, plugin.MarshalOptions{})
+			require.NoError(t, err)
+			_, err = p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: properties})
+			require.NoError(t, err)
+
+			// The imported resource has no state.
+			read, err := p.Read(ctx, &pulumirpc.ReadRequest{Urn: urn, Id: test.importId})
+			require.NoError(t, err)
+			require.Equal(t, test.id, read.GetId())
+			inputs, err := plugin.UnmarshalProperties(read.GetInputs(), plugin.MarshalOptions{})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, inputs)
+
+			// The program generated from the inputs has no diff.
+			checked, err := p.Check(ctx, &pulumirpc.CheckRequest{Urn: urn, Olds: read.GetInputs(), News: read.GetInputs()})
+			require.NoError(t, err)
+			require.Empty(t, checked.GetFailures())
+			diff, err := p.Diff(ctx, &pulumirpc.DiffRequest{Urn: urn, Id: read.GetId(), Olds: read.GetProperties(), News: checked.GetInputs()})
+			require.NoError(t, err)
+			require.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diff.GetChanges(), diff.GetDiffs())
+		})
+	}
+
+	p, _ := newFakeProvider(t)
+	read, err := p.Read(context.Background(), &pulumirpc.ReadRequest{
+		Urn: "urn:pulumi:dev::test::esxi-native:index:VirtualMachine::test", Id: "vm-missing",
+	})
+	require.NoError(t, err)
+	require.Empty(t, read.GetId())
+}
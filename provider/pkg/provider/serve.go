@@ -22,10 +22,17 @@ import (
 
 // Serve launches the gRPC server for the resource provider.
 func Serve(providerName, version string, pulumiSchema []byte) {
+	var server *esxiProvider
 	// Start gRPC service.
 	err := provider.Main(providerName, func(host *provider.HostClient) (rpc.ResourceProviderServer, error) {
-		return newESXiNativeProvider(host, providerName, version, pulumiSchema)
+		var err error
+		server, err = newESXiNativeProvider(host, providerName, version, pulumiSchema)
+		return server, err
 	})
+	// Close the connections to the ESXi host once the gRPC server is stopped.
+	if server != nil {
+		server.close()
+	}
 	if err != nil {
 		cmdutil.ExitError(err.Error())
 	}
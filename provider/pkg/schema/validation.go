@@ -38,9 +38,8 @@ func ValidatePortGroup(resourceToken string, inputs resource.PropertyMap) []*pul
 	// Validate boolean properties.
 	booleanProps := []string{"forgedTransmits", "promiscuousMode", "macChanges"}
 	for _, key := range booleanProps {
-		if value, has := inputs[resource.PropertyKey(key)]; has {
-			strVal := value.StringValue()
-			if strVal != "true" && strVal != "false" && strVal != "" {
+		if value, has := inputs[resource.PropertyKey(key)]; has && !value.IsBool() {
+			if !value.IsString() || (value.StringValue() != "true" && value.StringValue() != "false" && value.StringValue() != "") {
 				failures[key] = fmt.Sprintf(invalidFormat, key, "must be true, false, or empty to inherit")
 			}
 		}
@@ -98,7 +97,7 @@ func ValidateVirtualMachine(resourceToken string, inputs resource.PropertyMap) [
 	failures := map[string]string{}
 
 	// Validate required properties.
-	requiredProps := []string{"name", "diskStore", "resourcePoolName", "memSize", "numVCpus", "os"}
+	requiredProps := []string{"name", "diskStore", "memSize", "numVCpus"}
 	for _, key := range requiredProps {
 		checkRequiredProperty(key, inputs, &failures)
 	}
@@ -168,7 +167,11 @@ func validateDiskType(key string, inputs resource.PropertyMap, failures *map[str
 
 func validateVirtualMachineOs(inputs resource.PropertyMap, failures *map[string]string) {
 	key := "os"
-	if !validateVirtualMachineOsType(inputs[resource.PropertyKey(key)].StringValue()) {
+	property, has := inputs[resource.PropertyKey(key)]
+	if !has || !property.IsString() {
+		return
+	}
+	if !validateVirtualMachineOsType(property.StringValue()) {
 		(*failures)[key] = fmt.Sprintf(invalidFormat, key, "should be from here: https://github.com/josenk/vagrant-vmware-esxi/wiki/VMware-ESXi-6.5-guestOS-types")
 	}
 }
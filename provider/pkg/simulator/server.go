@@ -0,0 +1,342 @@
+// Package simulator serves an in-memory esxi host over ssh, so the provider
+// and the examples can be run end to end without an esxi host.
+package simulator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+const (
+	logLevel = 9
+
+	statusFailure = 1
+)
+
+// Config configures the credentials accepted by the simulator.
+type Config struct {
+	UserName string
+	Password string
+	// AuthorizedKeys are the public keys accepted for the user, in addition to the password.
+	AuthorizedKeys []ssh.PublicKey
+	HostKey        ssh.Signer
+	// PortForwarding accepts the direct-tcpip channels, so the simulator can
+	// stand in for the bastion of another one.
+	PortForwarding bool
+}
+
+// Server runs the commands of the ssh sessions on the in-memory host of a
+// FakeExecutor. It supports the exec requests of the provider and the scp
+// uploads of its files, the interactive shells are rejected.
+type Server struct {
+	executor       *esxi.FakeExecutor
+	serverConfig   *ssh.ServerConfig
+	portForwarding bool
+
+	mutex     sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a server for the in-memory host of the executor.
+func NewServer(executor *esxi.FakeExecutor, config Config) (*Server, error) {
+	if config.HostKey == nil {
+		return nil, errors.New("the simulator requires a host key")
+	}
+	if len(config.Password) == 0 && len(config.AuthorizedKeys) == 0 {
+		return nil, errors.New("the simulator requires a password or authorized keys")
+	}
+
+	serverConfig := &ssh.ServerConfig{}
+	if len(config.Password) > 0 {
+		serverConfig.PasswordCallback = func(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if meta.User() != config.UserName || string(password) != config.Password {
+				return nil, fmt.Errorf("password rejected for %s", meta.User())
+			}
+			return nil, nil
+		}
+	}
+	if len(config.AuthorizedKeys) > 0 {
+		serverConfig.PublicKeyCallback = func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if meta.User() == config.UserName {
+				for _, authorized := range config.AuthorizedKeys {
+					if string(authorized.Marshal()) == string(key.Marshal()) {
+						return nil, nil
+					}
+				}
+			}
+			return nil, fmt.Errorf("public key rejected for %s", meta.User())
+		}
+	}
+	serverConfig.AddHostKey(config.HostKey)
+
+	return &Server{
+		executor:       executor,
+		serverConfig:   serverConfig,
+		portForwarding: config.PortForwarding,
+		conns:          make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Serve accepts the ssh connections on the listener until the server is closed.
+func (server *Server) Serve(listener net.Listener) error {
+	server.mutex.Lock()
+	if server.closed {
+		server.mutex.Unlock()
+		return net.ErrClosed
+	}
+	server.listeners = append(server.listeners, listener)
+	server.mutex.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			server.mutex.Lock()
+			closed := server.closed
+			server.mutex.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		if !server.track(conn) {
+			conn.Close()
+			return nil
+		}
+		server.wg.Add(1)
+		go func() {
+			defer server.wg.Done()
+			defer server.untrack(conn)
+			server.handleConn(conn)
+		}()
+	}
+}
+
+// Close stops the listeners and closes the open connections.
+func (server *Server) Close() error {
+	server.mutex.Lock()
+	server.closed = true
+	var err error
+	for _, listener := range server.listeners {
+		if closeErr := listener.Close(); closeErr != nil && !errors.Is(closeErr, net.ErrClosed) {
+			err = closeErr
+		}
+	}
+	for conn := range server.conns {
+		conn.Close()
+	}
+	server.mutex.Unlock()
+
+	server.wg.Wait()
+	return err
+}
+
+func (server *Server) track(conn net.Conn) bool {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.closed {
+		return false
+	}
+	server.conns[conn] = struct{}{}
+	return true
+}
+
+func (server *Server) untrack(conn net.Conn) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	delete(server.conns, conn)
+	conn.Close()
+}
+
+func (server *Server) handleConn(conn net.Conn) {
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, server.serverConfig)
+	if err != nil {
+		logging.V(logLevel).Infof("handleConn: handshake with %s failed: %s", conn.RemoteAddr(), err)
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	var sessions sync.WaitGroup
+	defer sessions.Wait()
+	for newChannel := range channels {
+		if newChannel.ChannelType() == "direct-tcpip" && server.portForwarding {
+			sessions.Add(1)
+			go func(newChannel ssh.NewChannel) {
+				defer sessions.Done()
+				server.forward(newChannel)
+			}(newChannel)
+			continue
+		}
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			logging.V(logLevel).Infof("handleConn: failed accepting the channel: %s", err)
+			continue
+		}
+		sessions.Add(1)
+		go func() {
+			defer sessions.Done()
+			server.handleSession(channel, channelRequests)
+		}()
+	}
+}
+
+// forward connects the direct-tcpip channel to its destination address.
+func (server *Server) forward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, "invalid direct-tcpip payload")
+		return
+	}
+
+	address := net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port)))
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	defer conn.Close()
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		logging.V(logLevel).Infof("forward: failed accepting the channel: %s", err)
+		return
+	}
+	defer channel.Close()
+	go ssh.DiscardRequests(requests)
+
+	done := make(chan struct{}, 1)
+	go func() {
+		_, _ = io.Copy(channel, conn)
+		_ = channel.CloseWrite()
+		done <- struct{}{}
+	}()
+	_, _ = io.Copy(conn, channel)
+	_ = conn.Close()
+	<-done
+}
+
+// handleSession runs the command of the first exec request of the session,
+// the environment and pty requests are accepted and ignored.
+func (server *Server) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for request := range requests {
+		switch request.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(request.Payload, &payload); err != nil {
+				_ = request.Reply(false, nil)
+				continue
+			}
+			_ = request.Reply(true, nil)
+
+			status := server.exec(channel, payload.Command)
+			_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+			return
+		case "env", "pty-req":
+			_ = request.Reply(true, nil)
+		default:
+			_ = request.Reply(false, nil)
+		}
+	}
+}
+
+// exec runs the command, writes its output to the channel and returns its exit status.
+func (server *Server) exec(channel ssh.Channel, command string) int {
+	if args, err := shellquote.Split(command); err == nil && len(args) == 3 && args[0] == "scp" && args[1] == "-t" {
+		if err = server.receiveFile(channel, args[2]); err != nil {
+			logging.V(logLevel).Infof("exec: scp to %s failed: %s", args[2], err)
+			_, _ = fmt.Fprintf(channel.Stderr(), "scp: %s\n", err)
+			return statusFailure
+		}
+		return 0
+	}
+
+	stdout, err := server.executor.Execute(context.Background(), command, "simulator")
+	if len(stdout) > 0 {
+		_, _ = io.WriteString(channel, stdout+"\n")
+	}
+	var exitErr *esxi.FakeExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.Status
+	case err != nil:
+		_, _ = fmt.Fprintf(channel.Stderr(), "%s\n", err)
+		return statusFailure
+	}
+	return 0
+}
+
+// receiveFile implements the sink side of the scp protocol for a single
+// file. The file is stored at the target, or in it when the target ends with
+// a slash.
+func (server *Server) receiveFile(channel ssh.Channel, target string) error {
+	reader := bufio.NewReader(channel)
+	ack := func() error {
+		_, err := channel.Write([]byte{0})
+		return err
+	}
+	if err := ack(); err != nil {
+		return err
+	}
+
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed reading the file record: %w", err)
+	}
+	fields := strings.SplitN(strings.TrimSuffix(header, "\n"), " ", 3)
+	if len(fields) != 3 || !strings.HasPrefix(fields[0], "C") {
+		return fmt.Errorf("unsupported file record %q", header)
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid file size in %q", header)
+	}
+	if err = ack(); err != nil {
+		return err
+	}
+
+	content := make([]byte, size)
+	if _, err = io.ReadFull(reader, content); err != nil {
+		return fmt.Errorf("failed reading the file content: %w", err)
+	}
+	if end, err := reader.ReadByte(); err != nil || end != 0 {
+		return errors.New("missing end of file marker")
+	}
+
+	path := target
+	if strings.HasSuffix(path, "/") {
+		path += fields[2]
+	}
+	if err = server.executor.StoreFile(path, string(content)); err != nil {
+		return err
+	}
+	return ack()
+}
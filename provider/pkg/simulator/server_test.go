@@ -0,0 +1,130 @@
+package simulator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/pulumiverse/pulumi-esxi-native/provider/pkg/esxi"
+)
+
+func startServer(t *testing.T) (*esxi.FakeExecutor, esxi.ConnectionInfo) {
+	t.Helper()
+	fake := esxi.NewFakeExecutor()
+	return fake, serve(t, fake, false)
+}
+
+// startBastion starts a simulator accepting the port forwards, as a bastion.
+func startBastion(t *testing.T) esxi.ConnectionInfo {
+	t.Helper()
+	return serve(t, esxi.NewFakeExecutor(), true)
+}
+
+func serve(t *testing.T, fake *esxi.FakeExecutor, portForwarding bool) esxi.ConnectionInfo {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+
+	server, err := NewServer(fake, Config{UserName: "root", Password: "secret", HostKey: hostKey, PortForwarding: portForwarding})
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, server.Close())
+	})
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return esxi.ConnectionInfo{
+		Host:               host,
+		SSHPort:            port,
+		SslPort:            "443",
+		UserName:           "root",
+		Password:           "secret",
+		HostKeyFingerprint: ssh.FingerprintSHA256(hostKey.PublicKey()),
+	}
+}
+
+func TestServer(t *testing.T) {
+	fake, connection := startServer(t)
+	ctx := context.Background()
+
+	host, err := esxi.NewHost(ctx, connection)
+	require.NoError(t, err)
+	defer host.Close()
+
+	stdout, err := host.Execute(ctx, `ls -d "/vmfs/volumes/missing"`, "missing directory")
+	require.Error(t, err)
+	require.Equal(t, "ls: /vmfs/volumes/missing: No such file or directory", stdout)
+
+	_, err = host.WriteFile(ctx, "hello", "/tmp/hello.txt", "upload")
+	require.NoError(t, err)
+	content, ok := fake.ReadFile("/tmp/hello.txt")
+	require.True(t, ok)
+	require.Equal(t, "hello\n", content)
+
+	id, result, err := esxi.VirtualSwitchCreate(ctx, resource.PropertyMap{
+		"name":    resource.NewStringProperty("vSwitch1"),
+		"upLinks": resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("vmnic1")})}),
+	}, host)
+	require.NoError(t, err)
+	require.Equal(t, "vSwitch1", result["name"].StringValue())
+	require.NoError(t, esxi.VirtualSwitchDelete(ctx, id, host))
+}
+
+func TestServerRejectsWrongPassword(t *testing.T) {
+	_, connection := startServer(t)
+	connection.Password = "wrong"
+
+	_, err := esxi.NewHost(context.Background(), connection)
+	require.Error(t, err)
+}
+
+func TestServerThroughBastion(t *testing.T) {
+	fake, connection := startServer(t)
+	bastion := startBastion(t)
+	connection.BastionHost = bastion.Host
+	connection.BastionPort = bastion.SSHPort
+	connection.BastionUser = bastion.UserName
+	connection.BastionPassword = bastion.Password
+	connection.TrustOnFirstUse = true
+	ctx := context.Background()
+
+	// The key of the bastion is trusted on first use, the host has its fingerprint.
+	require.NoError(t, esxi.RecordHostKeys(ctx, &connection))
+	require.Equal(t, map[string]string{bastion.Host + ":" + bastion.SSHPort: bastion.HostKeyFingerprint}, connection.TrustedHostKeys)
+
+	host, err := esxi.NewHost(ctx, connection)
+	require.NoError(t, err)
+	defer host.Close()
+
+	_, err = host.WriteFile(ctx, "hello", "/tmp/hello.txt", "upload")
+	require.NoError(t, err)
+	content, ok := fake.ReadFile("/tmp/hello.txt")
+	require.True(t, ok)
+	require.Equal(t, "hello\n", content)
+}
+
+func TestServerBastionRejectsForwarding(t *testing.T) {
+	_, connection := startServer(t)
+	_, bastion := startServer(t)
+	connection.BastionHost = bastion.Host
+	connection.BastionPort = bastion.SSHPort
+	connection.BastionPassword = bastion.Password
+	connection.TrustedHostKeys = map[string]string{bastion.Host + ":" + bastion.SSHPort: bastion.HostKeyFingerprint}
+	connection.TrustOnFirstUse = true
+
+	_, err := esxi.NewHost(context.Background(), connection)
+	require.ErrorContains(t, err, "through bastion")
+}
@@ -32,6 +32,176 @@ namespace Pulumiverse.EsxiNative
 
         private static readonly global::Pulumi.Config __config = new global::Pulumi.Config("esxi-native");
 
+        private static readonly __Value<bool?> _autoNaming = new __Value<bool?>(() => __config.GetBoolean("autoNaming"));
+        /// <summary>
+        /// ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        /// </summary>
+        public static bool? AutoNaming
+        {
+            get => _autoNaming.Get();
+            set => _autoNaming.Set(value);
+        }
+
+        private static readonly __Value<string?> _autoNamingCase = new __Value<string?>(() => __config.Get("autoNamingCase"));
+        /// <summary>
+        /// The case of the generated names, 'lower' or 'upper'
+        /// </summary>
+        public static string? AutoNamingCase
+        {
+            get => _autoNamingCase.Get();
+            set => _autoNamingCase.Set(value);
+        }
+
+        private static readonly __Value<string?> _autoNamingCharset = new __Value<string?>(() => __config.Get("autoNamingCharset"));
+        /// <summary>
+        /// The characters of the random part of the generated names
+        /// </summary>
+        public static string? AutoNamingCharset
+        {
+            get => _autoNamingCharset.Get();
+            set => _autoNamingCharset.Set(value);
+        }
+
+        private static readonly __Value<string?> _autoNamingPattern = new __Value<string?>(() => __config.Get("autoNamingPattern"));
+        /// <summary>
+        /// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        /// </summary>
+        public static string? AutoNamingPattern
+        {
+            get => _autoNamingPattern.Get();
+            set => _autoNamingPattern.Set(value);
+        }
+
+        private static readonly __Value<int?> _autoNamingRandomLength = new __Value<int?>(() => __config.GetInt32("autoNamingRandomLength"));
+        /// <summary>
+        /// The length of the random part of the generated names
+        /// </summary>
+        public static int? AutoNamingRandomLength
+        {
+            get => _autoNamingRandomLength.Get();
+            set => _autoNamingRandomLength.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionHost = new __Value<string?>(() => __config.Get("bastionHost"));
+        /// <summary>
+        /// ESXi SSH bastion (jump host) config, the host is reached through it when set
+        /// </summary>
+        public static string? BastionHost
+        {
+            get => _bastionHost.Get();
+            set => _bastionHost.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionPassword = new __Value<string?>(() => __config.Get("bastionPassword"));
+        /// <summary>
+        /// ESXi SSH bastion password config
+        /// </summary>
+        public static string? BastionPassword
+        {
+            get => _bastionPassword.Get();
+            set => _bastionPassword.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionPort = new __Value<string?>(() => __config.Get("bastionPort"));
+        /// <summary>
+        /// ESXi SSH bastion port config
+        /// </summary>
+        public static string? BastionPort
+        {
+            get => _bastionPort.Get();
+            set => _bastionPort.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionPrivateKey = new __Value<string?>(() => __config.Get("bastionPrivateKey"));
+        /// <summary>
+        /// ESXi SSH bastion private key (PEM encoded) config
+        /// </summary>
+        public static string? BastionPrivateKey
+        {
+            get => _bastionPrivateKey.Get();
+            set => _bastionPrivateKey.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionPrivateKeyPath = new __Value<string?>(() => __config.Get("bastionPrivateKeyPath"));
+        /// <summary>
+        /// ESXi SSH bastion private key path config
+        /// </summary>
+        public static string? BastionPrivateKeyPath
+        {
+            get => _bastionPrivateKeyPath.Get();
+            set => _bastionPrivateKeyPath.Set(value);
+        }
+
+        private static readonly __Value<string?> _bastionUser = new __Value<string?>(() => __config.Get("bastionUser"));
+        /// <summary>
+        /// ESXi SSH bastion username config, the ESXi username is used when not set
+        /// </summary>
+        public static string? BastionUser
+        {
+            get => _bastionUser.Get();
+            set => _bastionUser.Set(value);
+        }
+
+        private static readonly __Value<string?> _defaultDiskStore = new __Value<string?>(() => __config.Get("defaultDiskStore"));
+        /// <summary>
+        /// The disk store of the virtual machines and of the virtual disks which have none
+        /// </summary>
+        public static string? DefaultDiskStore
+        {
+            get => _defaultDiskStore.Get();
+            set => _defaultDiskStore.Set(value);
+        }
+
+        private static readonly __Value<int?> _defaultHardwareVersion = new __Value<int?>(() => __config.GetInt32("defaultHardwareVersion"));
+        /// <summary>
+        /// The virtual hardware version of the virtual machines which have none
+        /// </summary>
+        public static int? DefaultHardwareVersion
+        {
+            get => _defaultHardwareVersion.Get();
+            set => _defaultHardwareVersion.Set(value);
+        }
+
+        private static readonly __Value<string?> _defaultNetwork = new __Value<string?>(() => __config.Get("defaultNetwork"));
+        /// <summary>
+        /// The virtual network of the interface of the virtual machines without networkInterfaces
+        /// </summary>
+        public static string? DefaultNetwork
+        {
+            get => _defaultNetwork.Get();
+            set => _defaultNetwork.Set(value);
+        }
+
+        private static readonly __Value<string?> _defaultOs = new __Value<string?>(() => __config.Get("defaultOs"));
+        /// <summary>
+        /// The guest OS of the virtual machines which have none
+        /// </summary>
+        public static string? DefaultOs
+        {
+            get => _defaultOs.Get();
+            set => _defaultOs.Set(value);
+        }
+
+        private static readonly __Value<string?> _defaultResourcePool = new __Value<string?>(() => __config.Get("defaultResourcePool"));
+        /// <summary>
+        /// The resource pool of the virtual machines which have none
+        /// </summary>
+        public static string? DefaultResourcePool
+        {
+            get => _defaultResourcePool.Get();
+            set => _defaultResourcePool.Set(value);
+        }
+
+        private static readonly __Value<bool?> _dryRun = new __Value<bool?>(() => __config.GetBoolean("dryRun"));
+        /// <summary>
+        /// ESXi dry-run config, the commands changing the hosts are logged instead of being run
+        /// </summary>
+        public static bool? DryRun
+        {
+            get => _dryRun.Get();
+            set => _dryRun.Set(value);
+        }
+
         private static readonly __Value<string?> _host = new __Value<string?>(() => __config.Get("host"));
         /// <summary>
         /// ESXi Host Name config
@@ -42,6 +212,56 @@ namespace Pulumiverse.EsxiNative
             set => _host.Set(value);
         }
 
+        private static readonly __Value<string?> _hostKeyFingerprint = new __Value<string?>(() => __config.Get("hostKeyFingerprint"));
+        /// <summary>
+        /// ESXi SSH host key SHA256 fingerprint config
+        /// </summary>
+        public static string? HostKeyFingerprint
+        {
+            get => _hostKeyFingerprint.Get();
+            set => _hostKeyFingerprint.Set(value);
+        }
+
+        private static readonly __Value<ImmutableDictionary<string, Types.HostConnection>?> _hosts = new __Value<ImmutableDictionary<string, Types.HostConnection>?>(() => __config.GetObject<ImmutableDictionary<string, Types.HostConnection>>("hosts"));
+        /// <summary>
+        /// ESXi named hosts config, the resources select one with their host property
+        /// </summary>
+        public static ImmutableDictionary<string, Types.HostConnection>? Hosts
+        {
+            get => _hosts.Get();
+            set => _hosts.Set(value);
+        }
+
+        private static readonly __Value<bool?> _insecureSkipHostKeyVerification = new __Value<bool?>(() => __config.GetBoolean("insecureSkipHostKeyVerification"));
+        /// <summary>
+        /// ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        /// </summary>
+        public static bool? InsecureSkipHostKeyVerification
+        {
+            get => _insecureSkipHostKeyVerification.Get();
+            set => _insecureSkipHostKeyVerification.Set(value);
+        }
+
+        private static readonly __Value<string?> _knownHostsFile = new __Value<string?>(() => __config.Get("knownHostsFile"));
+        /// <summary>
+        /// ESXi SSH known hosts file config
+        /// </summary>
+        public static string? KnownHostsFile
+        {
+            get => _knownHostsFile.Get();
+            set => _knownHostsFile.Set(value);
+        }
+
+        private static readonly __Value<int?> _maxConcurrentSessions = new __Value<int?>(() => __config.GetInt32("maxConcurrentSessions"));
+        /// <summary>
+        /// ESXi max concurrent sessions config, the number of remote commands run at once on a host
+        /// </summary>
+        public static int? MaxConcurrentSessions
+        {
+            get => _maxConcurrentSessions.Get();
+            set => _maxConcurrentSessions.Set(value);
+        }
+
         private static readonly __Value<string?> _password = new __Value<string?>(() => __config.Get("password"));
         /// <summary>
         /// ESXi Password config
@@ -52,6 +272,96 @@ namespace Pulumiverse.EsxiNative
             set => _password.Set(value);
         }
 
+        private static readonly __Value<bool?> _preflight = new __Value<bool?>(() => __config.GetBoolean("preflight"));
+        /// <summary>
+        /// ESXi preflight config, the hosts are connected to and checked when the config is checked
+        /// </summary>
+        public static bool? Preflight
+        {
+            get => _preflight.Get();
+            set => _preflight.Set(value);
+        }
+
+        private static readonly __Value<string?> _privateKey = new __Value<string?>(() => __config.Get("privateKey"));
+        /// <summary>
+        /// ESXi SSH private key (PEM encoded) config
+        /// </summary>
+        public static string? PrivateKey
+        {
+            get => _privateKey.Get();
+            set => _privateKey.Set(value);
+        }
+
+        private static readonly __Value<string?> _privateKeyPassphrase = new __Value<string?>(() => __config.Get("privateKeyPassphrase"));
+        /// <summary>
+        /// ESXi SSH private key passphrase config
+        /// </summary>
+        public static string? PrivateKeyPassphrase
+        {
+            get => _privateKeyPassphrase.Get();
+            set => _privateKeyPassphrase.Set(value);
+        }
+
+        private static readonly __Value<string?> _privateKeyPath = new __Value<string?>(() => __config.Get("privateKeyPath"));
+        /// <summary>
+        /// ESXi SSH private key path config
+        /// </summary>
+        public static string? PrivateKeyPath
+        {
+            get => _privateKeyPath.Get();
+            set => _privateKeyPath.Set(value);
+        }
+
+        private static readonly __Value<bool?> _readOnly = new __Value<bool?>(() => __config.GetBoolean("readOnly"));
+        /// <summary>
+        /// ESXi read-only config, the commands changing the hosts are refused
+        /// </summary>
+        public static bool? ReadOnly
+        {
+            get => _readOnly.Get();
+            set => _readOnly.Set(value);
+        }
+
+        private static readonly __Value<string?> _recordFile = new __Value<string?>(() => __config.Get("recordFile"));
+        /// <summary>
+        /// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        /// </summary>
+        public static string? RecordFile
+        {
+            get => _recordFile.Get();
+            set => _recordFile.Set(value);
+        }
+
+        private static readonly __Value<int?> _retryAttempts = new __Value<int?>(() => __config.GetInt32("retryAttempts"));
+        /// <summary>
+        /// ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        /// </summary>
+        public static int? RetryAttempts
+        {
+            get => _retryAttempts.Get();
+            set => _retryAttempts.Set(value);
+        }
+
+        private static readonly __Value<string?> _retryInitialDelay = new __Value<string?>(() => __config.Get("retryInitialDelay"));
+        /// <summary>
+        /// ESXi retry initial delay config, doubled on every retry
+        /// </summary>
+        public static string? RetryInitialDelay
+        {
+            get => _retryInitialDelay.Get();
+            set => _retryInitialDelay.Set(value);
+        }
+
+        private static readonly __Value<string?> _retryMaxDelay = new __Value<string?>(() => __config.Get("retryMaxDelay"));
+        /// <summary>
+        /// ESXi retry maximum delay config
+        /// </summary>
+        public static string? RetryMaxDelay
+        {
+            get => _retryMaxDelay.Get();
+            set => _retryMaxDelay.Set(value);
+        }
+
         private static readonly __Value<string?> _sshPort = new __Value<string?>(() => __config.Get("sshPort"));
         /// <summary>
         /// ESXi Host SSH Port config
@@ -72,6 +382,56 @@ namespace Pulumiverse.EsxiNative
             set => _sslPort.Set(value);
         }
 
+        private static readonly __Value<string?> _sslThumbprint = new __Value<string?>(() => __config.Get("sslThumbprint"));
+        /// <summary>
+        /// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        /// </summary>
+        public static string? SslThumbprint
+        {
+            get => _sslThumbprint.Get();
+            set => _sslThumbprint.Set(value);
+        }
+
+        private static readonly __Value<string?> _transport = new __Value<string?>(() => __config.Get("transport"));
+        /// <summary>
+        /// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        /// </summary>
+        public static string? Transport
+        {
+            get => _transport.Get();
+            set => _transport.Set(value);
+        }
+
+        private static readonly __Value<bool?> _trustOnFirstUse = new __Value<bool?>(() => __config.GetBoolean("trustOnFirstUse"));
+        /// <summary>
+        /// ESXi SSH host key trust on first use config
+        /// </summary>
+        public static bool? TrustOnFirstUse
+        {
+            get => _trustOnFirstUse.Get();
+            set => _trustOnFirstUse.Set(value);
+        }
+
+        private static readonly __Value<string?> _trustedHostKeys = new __Value<string?>(() => __config.Get("trustedHostKeys"));
+        /// <summary>
+        /// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        /// </summary>
+        public static string? TrustedHostKeys
+        {
+            get => _trustedHostKeys.Get();
+            set => _trustedHostKeys.Set(value);
+        }
+
+        private static readonly __Value<bool?> _useSshAgent = new __Value<bool?>(() => __config.GetBoolean("useSshAgent"));
+        /// <summary>
+        /// ESXi SSH agent authentication config
+        /// </summary>
+        public static bool? UseSshAgent
+        {
+            get => _useSshAgent.Get();
+            set => _useSshAgent.Set(value);
+        }
+
         private static readonly __Value<string?> _username = new __Value<string?>(() => __config.Get("username"));
         /// <summary>
         /// ESXi Username config
@@ -22,6 +22,12 @@ namespace Pulumiverse.EsxiNative
 
     public sealed class GetVirtualMachineByIdArgs : global::Pulumi.InvokeArgs
     {
+        /// <summary>
+        /// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public string? Host { get; set; }
+
         /// <summary>
         /// Virtual Machine Id to get details of
         /// </summary>
@@ -36,6 +42,12 @@ namespace Pulumiverse.EsxiNative
 
     public sealed class GetVirtualMachineByIdInvokeArgs : global::Pulumi.InvokeArgs
     {
+        /// <summary>
+        /// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public Input<string>? Host { get; set; }
+
         /// <summary>
         /// Virtual Machine Id to get details of
         /// </summary>
@@ -69,6 +81,10 @@ namespace Pulumiverse.EsxiNative
         /// </summary>
         public readonly string? DiskStore;
         /// <summary>
+        /// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        /// </summary>
+        public readonly bool? EfiSecureBoot;
+        /// <summary>
         /// esxi vm id.
         /// </summary>
         public readonly string? Id;
@@ -139,6 +155,8 @@ namespace Pulumiverse.EsxiNative
 
             string? diskStore,
 
+            bool? efiSecureBoot,
+
             string? id,
 
             ImmutableArray<Outputs.KeyValuePair> info,
@@ -173,6 +191,7 @@ namespace Pulumiverse.EsxiNative
             BootDiskType = bootDiskType;
             BootFirmware = bootFirmware;
             DiskStore = diskStore;
+            EfiSecureBoot = efiSecureBoot;
             Id = id;
             Info = info;
             IpAddress = ipAddress;
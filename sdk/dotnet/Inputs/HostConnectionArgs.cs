@@ -0,0 +1,120 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+using Pulumi;
+
+namespace Pulumiverse.EsxiNative.Inputs
+{
+
+    /// <summary>
+    /// Connection to a named host, the settings not set are the ones of the provider config.
+    /// </summary>
+    public sealed class HostConnectionArgs : global::Pulumi.ResourceArgs
+    {
+        /// <summary>
+        /// ESXi Host Name
+        /// </summary>
+        [Input("host", required: true)]
+        public Input<string> Host { get; set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH host key SHA256 fingerprint
+        /// </summary>
+        [Input("hostKeyFingerprint")]
+        public Input<string>? HostKeyFingerprint { get; set; }
+
+        [Input("password")]
+        private Input<string>? _password;
+
+        /// <summary>
+        /// ESXi Password
+        /// </summary>
+        public Input<string>? Password
+        {
+            get => _password;
+            set
+            {
+                var emptySecret = Output.CreateSecret(0);
+                _password = Output.Tuple<Input<string>?, int>(value, emptySecret).Apply(t => t.Item1);
+            }
+        }
+
+        [Input("privateKey")]
+        private Input<string>? _privateKey;
+
+        /// <summary>
+        /// ESXi SSH private key (PEM encoded)
+        /// </summary>
+        public Input<string>? PrivateKey
+        {
+            get => _privateKey;
+            set
+            {
+                var emptySecret = Output.CreateSecret(0);
+                _privateKey = Output.Tuple<Input<string>?, int>(value, emptySecret).Apply(t => t.Item1);
+            }
+        }
+
+        [Input("privateKeyPassphrase")]
+        private Input<string>? _privateKeyPassphrase;
+
+        /// <summary>
+        /// ESXi SSH private key passphrase
+        /// </summary>
+        public Input<string>? PrivateKeyPassphrase
+        {
+            get => _privateKeyPassphrase;
+            set
+            {
+                var emptySecret = Output.CreateSecret(0);
+                _privateKeyPassphrase = Output.Tuple<Input<string>?, int>(value, emptySecret).Apply(t => t.Item1);
+            }
+        }
+
+        /// <summary>
+        /// ESXi SSH private key path
+        /// </summary>
+        [Input("privateKeyPath")]
+        public Input<string>? PrivateKeyPath { get; set; }
+
+        /// <summary>
+        /// ESXi Host SSH Port
+        /// </summary>
+        [Input("sshPort")]
+        public Input<string>? SshPort { get; set; }
+
+        /// <summary>
+        /// ESXi Host SSL Port
+        /// </summary>
+        [Input("sslPort")]
+        public Input<string>? SslPort { get; set; }
+
+        /// <summary>
+        /// ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        /// </summary>
+        [Input("sslThumbprint")]
+        public Input<string>? SslThumbprint { get; set; }
+
+        /// <summary>
+        /// ESXi transport, ssh or api
+        /// </summary>
+        [Input("transport")]
+        public Input<string>? Transport { get; set; }
+
+        /// <summary>
+        /// ESXi Username
+        /// </summary>
+        [Input("username")]
+        public Input<string>? Username { get; set; }
+
+        public HostConnectionArgs()
+        {
+        }
+        public static new HostConnectionArgs Empty => new HostConnectionArgs();
+    }
+}
@@ -0,0 +1,102 @@
+// *** WARNING: this file was generated by pulumigen. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+using Pulumi;
+
+namespace Pulumiverse.EsxiNative.Outputs
+{
+
+    /// <summary>
+    /// Connection to a named host, the settings not set are the ones of the provider config.
+    /// </summary>
+    [OutputType]
+    public sealed class HostConnection
+    {
+        /// <summary>
+        /// ESXi Host Name
+        /// </summary>
+        public readonly string Host;
+        /// <summary>
+        /// ESXi SSH host key SHA256 fingerprint
+        /// </summary>
+        public readonly string? HostKeyFingerprint;
+        /// <summary>
+        /// ESXi Password
+        /// </summary>
+        public readonly string? Password;
+        /// <summary>
+        /// ESXi SSH private key (PEM encoded)
+        /// </summary>
+        public readonly string? PrivateKey;
+        /// <summary>
+        /// ESXi SSH private key passphrase
+        /// </summary>
+        public readonly string? PrivateKeyPassphrase;
+        /// <summary>
+        /// ESXi SSH private key path
+        /// </summary>
+        public readonly string? PrivateKeyPath;
+        /// <summary>
+        /// ESXi Host SSH Port
+        /// </summary>
+        public readonly string? SshPort;
+        /// <summary>
+        /// ESXi Host SSL Port
+        /// </summary>
+        public readonly string? SslPort;
+        /// <summary>
+        /// ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        /// </summary>
+        public readonly string? SslThumbprint;
+        /// <summary>
+        /// ESXi transport, ssh or api
+        /// </summary>
+        public readonly string? Transport;
+        /// <summary>
+        /// ESXi Username
+        /// </summary>
+        public readonly string? Username;
+
+        [OutputConstructor]
+        private HostConnection(
+            string host,
+
+            string? hostKeyFingerprint,
+
+            string? password,
+
+            string? privateKey,
+
+            string? privateKeyPassphrase,
+
+            string? privateKeyPath,
+
+            string? sshPort,
+
+            string? sslPort,
+
+            string? sslThumbprint,
+
+            string? transport,
+
+            string? username)
+        {
+            Host = host;
+            HostKeyFingerprint = hostKeyFingerprint;
+            Password = password;
+            PrivateKey = privateKey;
+            PrivateKeyPassphrase = privateKeyPassphrase;
+            PrivateKeyPath = privateKeyPath;
+            SshPort = sshPort;
+            SslPort = sslPort;
+            SslThumbprint = sslThumbprint;
+            Transport = transport;
+            Username = username;
+        }
+    }
+}
@@ -19,6 +19,12 @@ namespace Pulumiverse.EsxiNative
         [Output("forgedTransmits")]
         public Output<bool?> ForgedTransmits { get; private set; } = null!;
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Output("host")]
+        public Output<string?> Host { get; private set; } = null!;
+
         /// <summary>
         /// MAC address changes (true=Accept/false=Reject).
         /// </summary>
@@ -101,6 +107,12 @@ namespace Pulumiverse.EsxiNative
         [Input("forgedTransmits")]
         public Input<bool>? ForgedTransmits { get; set; }
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public Input<string>? Host { get; set; }
+
         /// <summary>
         /// MAC address changes (true=Accept/false=Reject).
         /// </summary>
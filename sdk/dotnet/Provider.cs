@@ -16,17 +16,143 @@ namespace Pulumiverse.EsxiNative
     [EsxiNativeResourceType("pulumi:providers:esxi-native")]
     public partial class Provider : global::Pulumi.ProviderResource
     {
+        /// <summary>
+        /// The case of the generated names, 'lower' or 'upper'
+        /// </summary>
+        [Output("autoNamingCase")]
+        public Output<string?> AutoNamingCase { get; private set; } = null!;
+
+        /// <summary>
+        /// The characters of the random part of the generated names
+        /// </summary>
+        [Output("autoNamingCharset")]
+        public Output<string?> AutoNamingCharset { get; private set; } = null!;
+
+        /// <summary>
+        /// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        /// </summary>
+        [Output("autoNamingPattern")]
+        public Output<string?> AutoNamingPattern { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion (jump host) config, the host is reached through it when set
+        /// </summary>
+        [Output("bastionHost")]
+        public Output<string?> BastionHost { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion password config
+        /// </summary>
+        [Output("bastionPassword")]
+        public Output<string?> BastionPassword { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion port config
+        /// </summary>
+        [Output("bastionPort")]
+        public Output<string?> BastionPort { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion private key (PEM encoded) config
+        /// </summary>
+        [Output("bastionPrivateKey")]
+        public Output<string?> BastionPrivateKey { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion private key path config
+        /// </summary>
+        [Output("bastionPrivateKeyPath")]
+        public Output<string?> BastionPrivateKeyPath { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH bastion username config, the ESXi username is used when not set
+        /// </summary>
+        [Output("bastionUser")]
+        public Output<string?> BastionUser { get; private set; } = null!;
+
+        /// <summary>
+        /// The disk store of the virtual machines and of the virtual disks which have none
+        /// </summary>
+        [Output("defaultDiskStore")]
+        public Output<string?> DefaultDiskStore { get; private set; } = null!;
+
+        /// <summary>
+        /// The virtual network of the interface of the virtual machines without networkInterfaces
+        /// </summary>
+        [Output("defaultNetwork")]
+        public Output<string?> DefaultNetwork { get; private set; } = null!;
+
+        /// <summary>
+        /// The guest OS of the virtual machines which have none
+        /// </summary>
+        [Output("defaultOs")]
+        public Output<string?> DefaultOs { get; private set; } = null!;
+
+        /// <summary>
+        /// The resource pool of the virtual machines which have none
+        /// </summary>
+        [Output("defaultResourcePool")]
+        public Output<string?> DefaultResourcePool { get; private set; } = null!;
+
         /// <summary>
         /// ESXi Host Name config
         /// </summary>
         [Output("host")]
-        public Output<string> Host { get; private set; } = null!;
+        public Output<string?> Host { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH host key SHA256 fingerprint config
+        /// </summary>
+        [Output("hostKeyFingerprint")]
+        public Output<string?> HostKeyFingerprint { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH known hosts file config
+        /// </summary>
+        [Output("knownHostsFile")]
+        public Output<string?> KnownHostsFile { get; private set; } = null!;
 
         /// <summary>
         /// ESXi Password config
         /// </summary>
         [Output("password")]
-        public Output<string> Password { get; private set; } = null!;
+        public Output<string?> Password { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH private key (PEM encoded) config
+        /// </summary>
+        [Output("privateKey")]
+        public Output<string?> PrivateKey { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH private key passphrase config
+        /// </summary>
+        [Output("privateKeyPassphrase")]
+        public Output<string?> PrivateKeyPassphrase { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH private key path config
+        /// </summary>
+        [Output("privateKeyPath")]
+        public Output<string?> PrivateKeyPath { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        /// </summary>
+        [Output("recordFile")]
+        public Output<string?> RecordFile { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi retry initial delay config, doubled on every retry
+        /// </summary>
+        [Output("retryInitialDelay")]
+        public Output<string?> RetryInitialDelay { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi retry maximum delay config
+        /// </summary>
+        [Output("retryMaxDelay")]
+        public Output<string?> RetryMaxDelay { get; private set; } = null!;
 
         /// <summary>
         /// ESXi Host SSH Port config
@@ -40,6 +166,24 @@ namespace Pulumiverse.EsxiNative
         [Output("sslPort")]
         public Output<string?> SslPort { get; private set; } = null!;
 
+        /// <summary>
+        /// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        /// </summary>
+        [Output("sslThumbprint")]
+        public Output<string?> SslThumbprint { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        /// </summary>
+        [Output("transport")]
+        public Output<string?> Transport { get; private set; } = null!;
+
+        /// <summary>
+        /// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        /// </summary>
+        [Output("trustedHostKeys")]
+        public Output<string?> TrustedHostKeys { get; private set; } = null!;
+
         /// <summary>
         /// ESXi Username config
         /// </summary>
@@ -54,7 +198,7 @@ namespace Pulumiverse.EsxiNative
         /// <param name="name">The unique name of the resource</param>
         /// <param name="args">The arguments used to populate this resource's properties</param>
         /// <param name="options">A bag of options that control this resource's behavior</param>
-        public Provider(string name, ProviderArgs args, CustomResourceOptions? options = null)
+        public Provider(string name, ProviderArgs? args = null, CustomResourceOptions? options = null)
             : base("esxi-native", name, args ?? new ProviderArgs(), MakeResourceOptions(options, ""))
         {
         }
@@ -75,17 +219,209 @@ namespace Pulumiverse.EsxiNative
 
     public sealed class ProviderArgs : global::Pulumi.ResourceArgs
     {
+        /// <summary>
+        /// ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        /// </summary>
+        [Input("autoNaming", json: true)]
+        public Input<bool>? AutoNaming { get; set; }
+
+        /// <summary>
+        /// The case of the generated names, 'lower' or 'upper'
+        /// </summary>
+        [Input("autoNamingCase")]
+        public Input<string>? AutoNamingCase { get; set; }
+
+        /// <summary>
+        /// The characters of the random part of the generated names
+        /// </summary>
+        [Input("autoNamingCharset")]
+        public Input<string>? AutoNamingCharset { get; set; }
+
+        /// <summary>
+        /// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        /// </summary>
+        [Input("autoNamingPattern")]
+        public Input<string>? AutoNamingPattern { get; set; }
+
+        /// <summary>
+        /// The length of the random part of the generated names
+        /// </summary>
+        [Input("autoNamingRandomLength", json: true)]
+        public Input<int>? AutoNamingRandomLength { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion (jump host) config, the host is reached through it when set
+        /// </summary>
+        [Input("bastionHost")]
+        public Input<string>? BastionHost { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion password config
+        /// </summary>
+        [Input("bastionPassword")]
+        public Input<string>? BastionPassword { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion port config
+        /// </summary>
+        [Input("bastionPort")]
+        public Input<string>? BastionPort { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion private key (PEM encoded) config
+        /// </summary>
+        [Input("bastionPrivateKey")]
+        public Input<string>? BastionPrivateKey { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion private key path config
+        /// </summary>
+        [Input("bastionPrivateKeyPath")]
+        public Input<string>? BastionPrivateKeyPath { get; set; }
+
+        /// <summary>
+        /// ESXi SSH bastion username config, the ESXi username is used when not set
+        /// </summary>
+        [Input("bastionUser")]
+        public Input<string>? BastionUser { get; set; }
+
+        /// <summary>
+        /// The disk store of the virtual machines and of the virtual disks which have none
+        /// </summary>
+        [Input("defaultDiskStore")]
+        public Input<string>? DefaultDiskStore { get; set; }
+
+        /// <summary>
+        /// The virtual hardware version of the virtual machines which have none
+        /// </summary>
+        [Input("defaultHardwareVersion", json: true)]
+        public Input<int>? DefaultHardwareVersion { get; set; }
+
+        /// <summary>
+        /// The virtual network of the interface of the virtual machines without networkInterfaces
+        /// </summary>
+        [Input("defaultNetwork")]
+        public Input<string>? DefaultNetwork { get; set; }
+
+        /// <summary>
+        /// The guest OS of the virtual machines which have none
+        /// </summary>
+        [Input("defaultOs")]
+        public Input<string>? DefaultOs { get; set; }
+
+        /// <summary>
+        /// The resource pool of the virtual machines which have none
+        /// </summary>
+        [Input("defaultResourcePool")]
+        public Input<string>? DefaultResourcePool { get; set; }
+
+        /// <summary>
+        /// ESXi dry-run config, the commands changing the hosts are logged instead of being run
+        /// </summary>
+        [Input("dryRun", json: true)]
+        public Input<bool>? DryRun { get; set; }
+
         /// <summary>
         /// ESXi Host Name config
         /// </summary>
-        [Input("host", required: true)]
-        public Input<string> Host { get; set; } = null!;
+        [Input("host")]
+        public Input<string>? Host { get; set; }
+
+        /// <summary>
+        /// ESXi SSH host key SHA256 fingerprint config
+        /// </summary>
+        [Input("hostKeyFingerprint")]
+        public Input<string>? HostKeyFingerprint { get; set; }
+
+        [Input("hosts", json: true)]
+        private InputMap<Inputs.HostConnectionArgs>? _hosts;
+
+        /// <summary>
+        /// ESXi named hosts config, the resources select one with their host property
+        /// </summary>
+        public InputMap<Inputs.HostConnectionArgs> Hosts
+        {
+            get => _hosts ?? (_hosts = new InputMap<Inputs.HostConnectionArgs>());
+            set => _hosts = value;
+        }
+
+        /// <summary>
+        /// ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        /// </summary>
+        [Input("insecureSkipHostKeyVerification", json: true)]
+        public Input<bool>? InsecureSkipHostKeyVerification { get; set; }
+
+        /// <summary>
+        /// ESXi SSH known hosts file config
+        /// </summary>
+        [Input("knownHostsFile")]
+        public Input<string>? KnownHostsFile { get; set; }
+
+        /// <summary>
+        /// ESXi max concurrent sessions config, the number of remote commands run at once on a host
+        /// </summary>
+        [Input("maxConcurrentSessions", json: true)]
+        public Input<int>? MaxConcurrentSessions { get; set; }
 
         /// <summary>
         /// ESXi Password config
         /// </summary>
-        [Input("password", required: true)]
-        public Input<string> Password { get; set; } = null!;
+        [Input("password")]
+        public Input<string>? Password { get; set; }
+
+        /// <summary>
+        /// ESXi preflight config, the hosts are connected to and checked when the config is checked
+        /// </summary>
+        [Input("preflight", json: true)]
+        public Input<bool>? Preflight { get; set; }
+
+        /// <summary>
+        /// ESXi SSH private key (PEM encoded) config
+        /// </summary>
+        [Input("privateKey")]
+        public Input<string>? PrivateKey { get; set; }
+
+        /// <summary>
+        /// ESXi SSH private key passphrase config
+        /// </summary>
+        [Input("privateKeyPassphrase")]
+        public Input<string>? PrivateKeyPassphrase { get; set; }
+
+        /// <summary>
+        /// ESXi SSH private key path config
+        /// </summary>
+        [Input("privateKeyPath")]
+        public Input<string>? PrivateKeyPath { get; set; }
+
+        /// <summary>
+        /// ESXi read-only config, the commands changing the hosts are refused
+        /// </summary>
+        [Input("readOnly", json: true)]
+        public Input<bool>? ReadOnly { get; set; }
+
+        /// <summary>
+        /// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        /// </summary>
+        [Input("recordFile")]
+        public Input<string>? RecordFile { get; set; }
+
+        /// <summary>
+        /// ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        /// </summary>
+        [Input("retryAttempts", json: true)]
+        public Input<int>? RetryAttempts { get; set; }
+
+        /// <summary>
+        /// ESXi retry initial delay config, doubled on every retry
+        /// </summary>
+        [Input("retryInitialDelay")]
+        public Input<string>? RetryInitialDelay { get; set; }
+
+        /// <summary>
+        /// ESXi retry maximum delay config
+        /// </summary>
+        [Input("retryMaxDelay")]
+        public Input<string>? RetryMaxDelay { get; set; }
 
         /// <summary>
         /// ESXi Host SSH Port config
@@ -99,6 +435,36 @@ namespace Pulumiverse.EsxiNative
         [Input("sslPort")]
         public Input<string>? SslPort { get; set; }
 
+        /// <summary>
+        /// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        /// </summary>
+        [Input("sslThumbprint")]
+        public Input<string>? SslThumbprint { get; set; }
+
+        /// <summary>
+        /// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        /// </summary>
+        [Input("transport")]
+        public Input<string>? Transport { get; set; }
+
+        /// <summary>
+        /// ESXi SSH host key trust on first use config
+        /// </summary>
+        [Input("trustOnFirstUse", json: true)]
+        public Input<bool>? TrustOnFirstUse { get; set; }
+
+        /// <summary>
+        /// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        /// </summary>
+        [Input("trustedHostKeys")]
+        public Input<string>? TrustedHostKeys { get; set; }
+
+        /// <summary>
+        /// ESXi SSH agent authentication config
+        /// </summary>
+        [Input("useSshAgent", json: true)]
+        public Input<bool>? UseSshAgent { get; set; }
+
         /// <summary>
         /// ESXi Username config
         /// </summary>
@@ -107,8 +473,21 @@ namespace Pulumiverse.EsxiNative
 
         public ProviderArgs()
         {
+            AutoNaming = true;
+            BastionPort = "22";
+            DryRun = false;
+            InsecureSkipHostKeyVerification = false;
+            MaxConcurrentSessions = 8;
+            Preflight = false;
+            ReadOnly = false;
+            RetryAttempts = 6;
+            RetryInitialDelay = "1s";
+            RetryMaxDelay = "30s";
             SshPort = "22";
             SslPort = "443";
+            Transport = "ssh";
+            TrustOnFirstUse = false;
+            UseSshAgent = false;
             Username = "root";
         }
         public static new ProviderArgs Empty => new ProviderArgs();
@@ -37,6 +37,12 @@ namespace Pulumiverse.EsxiNative
         [Output("cpuShares")]
         public Output<string?> CpuShares { get; private set; } = null!;
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Output("host")]
+        public Output<string?> Host { get; private set; } = null!;
+
         /// <summary>
         /// Memory maximum (in MB).
         /// </summary>
@@ -137,6 +143,12 @@ namespace Pulumiverse.EsxiNative
         [Input("cpuShares")]
         public Input<string>? CpuShares { get; set; }
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public Input<string>? Host { get; set; }
+
         /// <summary>
         /// Memory maximum (in MB).
         /// </summary>
@@ -31,6 +31,12 @@ namespace Pulumiverse.EsxiNative
         [Output("diskType")]
         public Output<Pulumiverse.EsxiNative.DiskType> DiskType { get; private set; } = null!;
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Output("host")]
+        public Output<string?> Host { get; private set; } = null!;
+
         /// <summary>
         /// Virtual Disk Name.
         /// </summary>
@@ -96,10 +102,10 @@ namespace Pulumiverse.EsxiNative
         public Input<string> Directory { get; set; } = null!;
 
         /// <summary>
-        /// Disk Store.
+        /// Disk Store, defaults to the defaultDiskStore of the provider.
         /// </summary>
-        [Input("diskStore", required: true)]
-        public Input<string> DiskStore { get; set; } = null!;
+        [Input("diskStore")]
+        public Input<string>? DiskStore { get; set; }
 
         /// <summary>
         /// Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
@@ -107,6 +113,12 @@ namespace Pulumiverse.EsxiNative
         [Input("diskType", required: true)]
         public Input<Pulumiverse.EsxiNative.DiskType> DiskType { get; set; } = null!;
 
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public Input<string>? Host { get; set; }
+
         /// <summary>
         /// Virtual Disk Name.
         /// </summary>
@@ -37,6 +37,18 @@ namespace Pulumiverse.EsxiNative
         [Output("diskStore")]
         public Output<string> DiskStore { get; private set; } = null!;
 
+        /// <summary>
+        /// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        /// </summary>
+        [Output("efiSecureBoot")]
+        public Output<bool?> EfiSecureBoot { get; private set; } = null!;
+
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Output("host")]
+        public Output<string?> Host { get; private set; } = null!;
+
         /// <summary>
         /// pass data to VM
         /// </summary>
@@ -129,7 +141,7 @@ namespace Pulumiverse.EsxiNative
         /// <param name="name">The unique name of the resource</param>
         /// <param name="args">The arguments used to populate this resource's properties</param>
         /// <param name="options">A bag of options that control this resource's behavior</param>
-        public VirtualMachine(string name, VirtualMachineArgs args, CustomResourceOptions? options = null)
+        public VirtualMachine(string name, VirtualMachineArgs? args = null, CustomResourceOptions? options = null)
             : base("esxi-native:index:VirtualMachine", name, args ?? new VirtualMachineArgs(), MakeResourceOptions(options, ""))
         {
         }
@@ -192,10 +204,22 @@ namespace Pulumiverse.EsxiNative
         public Input<string>? CloneFromVirtualMachine { get; set; }
 
         /// <summary>
-        /// esxi diskstore for boot disk.
+        /// esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
         /// </summary>
-        [Input("diskStore", required: true)]
-        public Input<string> DiskStore { get; set; } = null!;
+        [Input("diskStore")]
+        public Input<string>? DiskStore { get; set; }
+
+        /// <summary>
+        /// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        /// </summary>
+        [Input("efiSecureBoot")]
+        public Input<bool>? EfiSecureBoot { get; set; }
+
+        /// <summary>
+        /// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        /// </summary>
+        [Input("host")]
+        public Input<string>? Host { get; set; }
 
         [Input("info")]
         private InputList<Inputs.KeyValuePairArgs>? _info;
@@ -225,7 +249,7 @@ namespace Pulumiverse.EsxiNative
         private InputList<Inputs.NetworkInterfaceArgs>? _networkInterfaces;
 
         /// <summary>
-        /// VM network interfaces.
+        /// VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
         /// </summary>
         public InputList<Inputs.NetworkInterfaceArgs> NetworkInterfaces
         {
@@ -246,7 +270,7 @@ namespace Pulumiverse.EsxiNative
         public Input<int>? NumVCpus { get; set; }
 
         /// <summary>
-        /// VM OS type.
+        /// VM OS type, defaults to the defaultOs of the provider or to 'centos'.
         /// </summary>
         [Input("os")]
         public Input<string>? Os { get; set; }
@@ -282,7 +306,7 @@ namespace Pulumiverse.EsxiNative
         public Input<string>? Power { get; set; }
 
         /// <summary>
-        /// Resource pool name to place vm.
+        /// Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
         /// </summary>
         [Input("resourcePoolName")]
         public Input<string>? ResourcePoolName { get; set; }
@@ -312,7 +336,7 @@ namespace Pulumiverse.EsxiNative
         }
 
         /// <summary>
-        /// VM Virtual HW version.
+        /// VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
         /// </summary>
         [Input("virtualHWVer")]
         public Input<int>? VirtualHWVer { get; set; }
@@ -322,14 +346,12 @@ namespace Pulumiverse.EsxiNative
             BootDiskSize = 16;
             BootDiskType = Pulumiverse.EsxiNative.DiskType.Thin;
             BootFirmware = Pulumiverse.EsxiNative.BootFirmwareType.BIOS;
+            EfiSecureBoot = false;
             MemSize = 512;
             NumVCpus = 1;
-            Os = "centos";
             OvfPropertiesTimer = 6000;
-            ResourcePoolName = "/";
             ShutdownTimeout = 600;
             StartupTimeout = 600;
-            VirtualHWVer = 13;
         }
         public static new VirtualMachineArgs Empty => new VirtualMachineArgs();
     }
@@ -6,21 +6,177 @@ package config
 import (
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+
 	"github.com/pulumiverse/pulumi-esxi-native/sdk/go/esxi/internal"
 )
 
 var _ = internal.GetEnvOrDefault
 
+// ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+func GetAutoNaming(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:autoNaming")
+}
+
+// The case of the generated names, 'lower' or 'upper'
+func GetAutoNamingCase(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:autoNamingCase")
+}
+
+// The characters of the random part of the generated names
+func GetAutoNamingCharset(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:autoNamingCharset")
+}
+
+// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+func GetAutoNamingPattern(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:autoNamingPattern")
+}
+
+// The length of the random part of the generated names
+func GetAutoNamingRandomLength(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "esxi-native:autoNamingRandomLength")
+}
+
+// ESXi SSH bastion (jump host) config, the host is reached through it when set
+func GetBastionHost(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionHost")
+}
+
+// ESXi SSH bastion password config
+func GetBastionPassword(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionPassword")
+}
+
+// ESXi SSH bastion port config
+func GetBastionPort(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionPort")
+}
+
+// ESXi SSH bastion private key (PEM encoded) config
+func GetBastionPrivateKey(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionPrivateKey")
+}
+
+// ESXi SSH bastion private key path config
+func GetBastionPrivateKeyPath(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionPrivateKeyPath")
+}
+
+// ESXi SSH bastion username config, the ESXi username is used when not set
+func GetBastionUser(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:bastionUser")
+}
+
+// The disk store of the virtual machines and of the virtual disks which have none
+func GetDefaultDiskStore(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:defaultDiskStore")
+}
+
+// The virtual hardware version of the virtual machines which have none
+func GetDefaultHardwareVersion(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "esxi-native:defaultHardwareVersion")
+}
+
+// The virtual network of the interface of the virtual machines without networkInterfaces
+func GetDefaultNetwork(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:defaultNetwork")
+}
+
+// The guest OS of the virtual machines which have none
+func GetDefaultOs(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:defaultOs")
+}
+
+// The resource pool of the virtual machines which have none
+func GetDefaultResourcePool(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:defaultResourcePool")
+}
+
+// ESXi dry-run config, the commands changing the hosts are logged instead of being run
+func GetDryRun(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:dryRun")
+}
+
 // ESXi Host Name config
 func GetHost(ctx *pulumi.Context) string {
 	return config.Get(ctx, "esxi-native:host")
 }
 
+// ESXi SSH host key SHA256 fingerprint config
+func GetHostKeyFingerprint(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:hostKeyFingerprint")
+}
+
+// ESXi named hosts config, the resources select one with their host property
+func GetHosts(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:hosts")
+}
+
+// ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+func GetInsecureSkipHostKeyVerification(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:insecureSkipHostKeyVerification")
+}
+
+// ESXi SSH known hosts file config
+func GetKnownHostsFile(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:knownHostsFile")
+}
+
+// ESXi max concurrent sessions config, the number of remote commands run at once on a host
+func GetMaxConcurrentSessions(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "esxi-native:maxConcurrentSessions")
+}
+
 // ESXi Password config
 func GetPassword(ctx *pulumi.Context) string {
 	return config.Get(ctx, "esxi-native:password")
 }
 
+// ESXi preflight config, the hosts are connected to and checked when the config is checked
+func GetPreflight(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:preflight")
+}
+
+// ESXi SSH private key (PEM encoded) config
+func GetPrivateKey(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:privateKey")
+}
+
+// ESXi SSH private key passphrase config
+func GetPrivateKeyPassphrase(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:privateKeyPassphrase")
+}
+
+// ESXi SSH private key path config
+func GetPrivateKeyPath(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:privateKeyPath")
+}
+
+// ESXi read-only config, the commands changing the hosts are refused
+func GetReadOnly(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:readOnly")
+}
+
+// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+func GetRecordFile(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:recordFile")
+}
+
+// ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+func GetRetryAttempts(ctx *pulumi.Context) int {
+	return config.GetInt(ctx, "esxi-native:retryAttempts")
+}
+
+// ESXi retry initial delay config, doubled on every retry
+func GetRetryInitialDelay(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:retryInitialDelay")
+}
+
+// ESXi retry maximum delay config
+func GetRetryMaxDelay(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:retryMaxDelay")
+}
+
 // ESXi Host SSH Port config
 func GetSshPort(ctx *pulumi.Context) string {
 	return config.Get(ctx, "esxi-native:sshPort")
@@ -31,6 +187,31 @@ func GetSslPort(ctx *pulumi.Context) string {
 	return config.Get(ctx, "esxi-native:sslPort")
 }
 
+// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+func GetSslThumbprint(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:sslThumbprint")
+}
+
+// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+func GetTransport(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:transport")
+}
+
+// ESXi SSH host key trust on first use config
+func GetTrustOnFirstUse(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:trustOnFirstUse")
+}
+
+// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+func GetTrustedHostKeys(ctx *pulumi.Context) string {
+	return config.Get(ctx, "esxi-native:trustedHostKeys")
+}
+
+// ESXi SSH agent authentication config
+func GetUseSshAgent(ctx *pulumi.Context) bool {
+	return config.GetBool(ctx, "esxi-native:useSshAgent")
+}
+
 // ESXi Username config
 func GetUsername(ctx *pulumi.Context) string {
 	return config.Get(ctx, "esxi-native:username")
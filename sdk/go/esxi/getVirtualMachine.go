@@ -22,6 +22,8 @@ func LookupVirtualMachine(ctx *pulumi.Context, args *LookupVirtualMachineArgs, o
 }
 
 type LookupVirtualMachineArgs struct {
+	// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// Virtual Machine Name to get details of
 	Name string `pulumi:"name"`
 }
@@ -35,6 +37,8 @@ type LookupVirtualMachineResult struct {
 	BootFirmware *BootFirmwareType `pulumi:"bootFirmware"`
 	// esxi diskstore for boot disk.
 	DiskStore *string `pulumi:"diskStore"`
+	// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+	EfiSecureBoot *bool `pulumi:"efiSecureBoot"`
 	// esxi vm id.
 	Id *string `pulumi:"id"`
 	// pass data to VM
@@ -98,6 +102,8 @@ func LookupVirtualMachineOutput(ctx *pulumi.Context, args LookupVirtualMachineOu
 }
 
 type LookupVirtualMachineOutputArgs struct {
+	// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+	Host pulumi.StringPtrInput `pulumi:"host"`
 	// Virtual Machine Name to get details of
 	Name pulumi.StringInput `pulumi:"name"`
 }
@@ -140,6 +146,11 @@ func (o LookupVirtualMachineResultOutput) DiskStore() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v LookupVirtualMachineResult) *string { return v.DiskStore }).(pulumi.StringPtrOutput)
 }
 
+// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+func (o LookupVirtualMachineResultOutput) EfiSecureBoot() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v LookupVirtualMachineResult) *bool { return v.EfiSecureBoot }).(pulumi.BoolPtrOutput)
+}
+
 // esxi vm id.
 func (o LookupVirtualMachineResultOutput) Id() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v LookupVirtualMachineResult) *string { return v.Id }).(pulumi.StringPtrOutput)
@@ -22,6 +22,8 @@ func GetVirtualMachineById(ctx *pulumi.Context, args *GetVirtualMachineByIdArgs,
 }
 
 type GetVirtualMachineByIdArgs struct {
+	// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// Virtual Machine Id to get details of
 	Id string `pulumi:"id"`
 }
@@ -35,6 +37,8 @@ type GetVirtualMachineByIdResult struct {
 	BootFirmware *BootFirmwareType `pulumi:"bootFirmware"`
 	// esxi diskstore for boot disk.
 	DiskStore *string `pulumi:"diskStore"`
+	// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+	EfiSecureBoot *bool `pulumi:"efiSecureBoot"`
 	// esxi vm id.
 	Id *string `pulumi:"id"`
 	// pass data to VM
@@ -98,6 +102,8 @@ func GetVirtualMachineByIdOutput(ctx *pulumi.Context, args GetVirtualMachineById
 }
 
 type GetVirtualMachineByIdOutputArgs struct {
+	// Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+	Host pulumi.StringPtrInput `pulumi:"host"`
 	// Virtual Machine Id to get details of
 	Id pulumi.StringInput `pulumi:"id"`
 }
@@ -140,6 +146,11 @@ func (o GetVirtualMachineByIdResultOutput) DiskStore() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v GetVirtualMachineByIdResult) *string { return v.DiskStore }).(pulumi.StringPtrOutput)
 }
 
+// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+func (o GetVirtualMachineByIdResultOutput) EfiSecureBoot() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v GetVirtualMachineByIdResult) *bool { return v.EfiSecureBoot }).(pulumi.BoolPtrOutput)
+}
+
 // esxi vm id.
 func (o GetVirtualMachineByIdResultOutput) Id() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v GetVirtualMachineByIdResult) *string { return v.Id }).(pulumi.StringPtrOutput)
@@ -17,6 +17,8 @@ type PortGroup struct {
 
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits pulumi.BoolPtrOutput `pulumi:"forgedTransmits"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrOutput `pulumi:"host"`
 	// MAC address changes (true=Accept/false=Reject).
 	MacChanges pulumi.BoolPtrOutput `pulumi:"macChanges"`
 	// Port Group name.
@@ -74,6 +76,8 @@ func (PortGroupState) ElementType() reflect.Type {
 type portGroupArgs struct {
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits *bool `pulumi:"forgedTransmits"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// MAC address changes (true=Accept/false=Reject).
 	MacChanges *bool `pulumi:"macChanges"`
 	// Virtual Switch name.
@@ -90,6 +94,8 @@ type portGroupArgs struct {
 type PortGroupArgs struct {
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits pulumi.BoolPtrInput
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrInput
 	// MAC address changes (true=Accept/false=Reject).
 	MacChanges pulumi.BoolPtrInput
 	// Virtual Switch name.
@@ -194,6 +200,11 @@ func (o PortGroupOutput) ForgedTransmits() pulumi.BoolPtrOutput {
 	return o.ApplyT(func(v *PortGroup) pulumi.BoolPtrOutput { return v.ForgedTransmits }).(pulumi.BoolPtrOutput)
 }
 
+// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+func (o PortGroupOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *PortGroup) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
 // MAC address changes (true=Accept/false=Reject).
 func (o PortGroupOutput) MacChanges() pulumi.BoolPtrOutput {
 	return o.ApplyT(func(v *PortGroup) pulumi.BoolPtrOutput { return v.MacChanges }).(pulumi.BoolPtrOutput)
@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumiverse/pulumi-esxi-native/sdk/go/esxi/internal"
 )
@@ -16,14 +15,62 @@ import (
 type Provider struct {
 	pulumi.ProviderResourceState
 
+	// The case of the generated names, 'lower' or 'upper'
+	AutoNamingCase pulumi.StringPtrOutput `pulumi:"autoNamingCase"`
+	// The characters of the random part of the generated names
+	AutoNamingCharset pulumi.StringPtrOutput `pulumi:"autoNamingCharset"`
+	// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+	AutoNamingPattern pulumi.StringPtrOutput `pulumi:"autoNamingPattern"`
+	// ESXi SSH bastion (jump host) config, the host is reached through it when set
+	BastionHost pulumi.StringPtrOutput `pulumi:"bastionHost"`
+	// ESXi SSH bastion password config
+	BastionPassword pulumi.StringPtrOutput `pulumi:"bastionPassword"`
+	// ESXi SSH bastion port config
+	BastionPort pulumi.StringPtrOutput `pulumi:"bastionPort"`
+	// ESXi SSH bastion private key (PEM encoded) config
+	BastionPrivateKey pulumi.StringPtrOutput `pulumi:"bastionPrivateKey"`
+	// ESXi SSH bastion private key path config
+	BastionPrivateKeyPath pulumi.StringPtrOutput `pulumi:"bastionPrivateKeyPath"`
+	// ESXi SSH bastion username config, the ESXi username is used when not set
+	BastionUser pulumi.StringPtrOutput `pulumi:"bastionUser"`
+	// The disk store of the virtual machines and of the virtual disks which have none
+	DefaultDiskStore pulumi.StringPtrOutput `pulumi:"defaultDiskStore"`
+	// The virtual network of the interface of the virtual machines without networkInterfaces
+	DefaultNetwork pulumi.StringPtrOutput `pulumi:"defaultNetwork"`
+	// The guest OS of the virtual machines which have none
+	DefaultOs pulumi.StringPtrOutput `pulumi:"defaultOs"`
+	// The resource pool of the virtual machines which have none
+	DefaultResourcePool pulumi.StringPtrOutput `pulumi:"defaultResourcePool"`
 	// ESXi Host Name config
-	Host pulumi.StringOutput `pulumi:"host"`
+	Host pulumi.StringPtrOutput `pulumi:"host"`
+	// ESXi SSH host key SHA256 fingerprint config
+	HostKeyFingerprint pulumi.StringPtrOutput `pulumi:"hostKeyFingerprint"`
+	// ESXi SSH known hosts file config
+	KnownHostsFile pulumi.StringPtrOutput `pulumi:"knownHostsFile"`
 	// ESXi Password config
-	Password pulumi.StringOutput `pulumi:"password"`
+	Password pulumi.StringPtrOutput `pulumi:"password"`
+	// ESXi SSH private key (PEM encoded) config
+	PrivateKey pulumi.StringPtrOutput `pulumi:"privateKey"`
+	// ESXi SSH private key passphrase config
+	PrivateKeyPassphrase pulumi.StringPtrOutput `pulumi:"privateKeyPassphrase"`
+	// ESXi SSH private key path config
+	PrivateKeyPath pulumi.StringPtrOutput `pulumi:"privateKeyPath"`
+	// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+	RecordFile pulumi.StringPtrOutput `pulumi:"recordFile"`
+	// ESXi retry initial delay config, doubled on every retry
+	RetryInitialDelay pulumi.StringPtrOutput `pulumi:"retryInitialDelay"`
+	// ESXi retry maximum delay config
+	RetryMaxDelay pulumi.StringPtrOutput `pulumi:"retryMaxDelay"`
 	// ESXi Host SSH Port config
 	SshPort pulumi.StringPtrOutput `pulumi:"sshPort"`
 	// ESXi Host SSL Port config
 	SslPort pulumi.StringPtrOutput `pulumi:"sslPort"`
+	// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+	SslThumbprint pulumi.StringPtrOutput `pulumi:"sslThumbprint"`
+	// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+	Transport pulumi.StringPtrOutput `pulumi:"transport"`
+	// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+	TrustedHostKeys pulumi.StringPtrOutput `pulumi:"trustedHostKeys"`
 	// ESXi Username config
 	Username pulumi.StringPtrOutput `pulumi:"username"`
 }
@@ -32,14 +79,38 @@ type Provider struct {
 func NewProvider(ctx *pulumi.Context,
 	name string, args *ProviderArgs, opts ...pulumi.ResourceOption) (*Provider, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &ProviderArgs{}
 	}
 
-	if args.Host == nil {
-		return nil, errors.New("invalid value for required argument 'Host'")
+	if args.AutoNaming == nil {
+		args.AutoNaming = pulumi.BoolPtr(true)
 	}
-	if args.Password == nil {
-		return nil, errors.New("invalid value for required argument 'Password'")
+	if args.BastionPort == nil {
+		args.BastionPort = pulumi.StringPtr("22")
+	}
+	if args.DryRun == nil {
+		args.DryRun = pulumi.BoolPtr(false)
+	}
+	if args.InsecureSkipHostKeyVerification == nil {
+		args.InsecureSkipHostKeyVerification = pulumi.BoolPtr(false)
+	}
+	if args.MaxConcurrentSessions == nil {
+		args.MaxConcurrentSessions = pulumi.IntPtr(8)
+	}
+	if args.Preflight == nil {
+		args.Preflight = pulumi.BoolPtr(false)
+	}
+	if args.ReadOnly == nil {
+		args.ReadOnly = pulumi.BoolPtr(false)
+	}
+	if args.RetryAttempts == nil {
+		args.RetryAttempts = pulumi.IntPtr(6)
+	}
+	if args.RetryInitialDelay == nil {
+		args.RetryInitialDelay = pulumi.StringPtr("1s")
+	}
+	if args.RetryMaxDelay == nil {
+		args.RetryMaxDelay = pulumi.StringPtr("30s")
 	}
 	if args.SshPort == nil {
 		args.SshPort = pulumi.StringPtr("22")
@@ -47,6 +118,15 @@ func NewProvider(ctx *pulumi.Context,
 	if args.SslPort == nil {
 		args.SslPort = pulumi.StringPtr("443")
 	}
+	if args.Transport == nil {
+		args.Transport = pulumi.StringPtr("ssh")
+	}
+	if args.TrustOnFirstUse == nil {
+		args.TrustOnFirstUse = pulumi.BoolPtr(false)
+	}
+	if args.UseSshAgent == nil {
+		args.UseSshAgent = pulumi.BoolPtr(false)
+	}
 	if args.Username == nil {
 		args.Username = pulumi.StringPtr("root")
 	}
@@ -60,28 +140,172 @@ func NewProvider(ctx *pulumi.Context,
 }
 
 type providerArgs struct {
+	// ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+	AutoNaming *bool `pulumi:"autoNaming"`
+	// The case of the generated names, 'lower' or 'upper'
+	AutoNamingCase *string `pulumi:"autoNamingCase"`
+	// The characters of the random part of the generated names
+	AutoNamingCharset *string `pulumi:"autoNamingCharset"`
+	// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+	AutoNamingPattern *string `pulumi:"autoNamingPattern"`
+	// The length of the random part of the generated names
+	AutoNamingRandomLength *int `pulumi:"autoNamingRandomLength"`
+	// ESXi SSH bastion (jump host) config, the host is reached through it when set
+	BastionHost *string `pulumi:"bastionHost"`
+	// ESXi SSH bastion password config
+	BastionPassword *string `pulumi:"bastionPassword"`
+	// ESXi SSH bastion port config
+	BastionPort *string `pulumi:"bastionPort"`
+	// ESXi SSH bastion private key (PEM encoded) config
+	BastionPrivateKey *string `pulumi:"bastionPrivateKey"`
+	// ESXi SSH bastion private key path config
+	BastionPrivateKeyPath *string `pulumi:"bastionPrivateKeyPath"`
+	// ESXi SSH bastion username config, the ESXi username is used when not set
+	BastionUser *string `pulumi:"bastionUser"`
+	// The disk store of the virtual machines and of the virtual disks which have none
+	DefaultDiskStore *string `pulumi:"defaultDiskStore"`
+	// The virtual hardware version of the virtual machines which have none
+	DefaultHardwareVersion *int `pulumi:"defaultHardwareVersion"`
+	// The virtual network of the interface of the virtual machines without networkInterfaces
+	DefaultNetwork *string `pulumi:"defaultNetwork"`
+	// The guest OS of the virtual machines which have none
+	DefaultOs *string `pulumi:"defaultOs"`
+	// The resource pool of the virtual machines which have none
+	DefaultResourcePool *string `pulumi:"defaultResourcePool"`
+	// ESXi dry-run config, the commands changing the hosts are logged instead of being run
+	DryRun *bool `pulumi:"dryRun"`
 	// ESXi Host Name config
-	Host string `pulumi:"host"`
+	Host *string `pulumi:"host"`
+	// ESXi SSH host key SHA256 fingerprint config
+	HostKeyFingerprint *string `pulumi:"hostKeyFingerprint"`
+	// ESXi named hosts config, the resources select one with their host property
+	Hosts map[string]HostConnection `pulumi:"hosts"`
+	// ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+	InsecureSkipHostKeyVerification *bool `pulumi:"insecureSkipHostKeyVerification"`
+	// ESXi SSH known hosts file config
+	KnownHostsFile *string `pulumi:"knownHostsFile"`
+	// ESXi max concurrent sessions config, the number of remote commands run at once on a host
+	MaxConcurrentSessions *int `pulumi:"maxConcurrentSessions"`
 	// ESXi Password config
-	Password string `pulumi:"password"`
+	Password *string `pulumi:"password"`
+	// ESXi preflight config, the hosts are connected to and checked when the config is checked
+	Preflight *bool `pulumi:"preflight"`
+	// ESXi SSH private key (PEM encoded) config
+	PrivateKey *string `pulumi:"privateKey"`
+	// ESXi SSH private key passphrase config
+	PrivateKeyPassphrase *string `pulumi:"privateKeyPassphrase"`
+	// ESXi SSH private key path config
+	PrivateKeyPath *string `pulumi:"privateKeyPath"`
+	// ESXi read-only config, the commands changing the hosts are refused
+	ReadOnly *bool `pulumi:"readOnly"`
+	// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+	RecordFile *string `pulumi:"recordFile"`
+	// ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+	RetryAttempts *int `pulumi:"retryAttempts"`
+	// ESXi retry initial delay config, doubled on every retry
+	RetryInitialDelay *string `pulumi:"retryInitialDelay"`
+	// ESXi retry maximum delay config
+	RetryMaxDelay *string `pulumi:"retryMaxDelay"`
 	// ESXi Host SSH Port config
 	SshPort *string `pulumi:"sshPort"`
 	// ESXi Host SSL Port config
 	SslPort *string `pulumi:"sslPort"`
+	// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+	SslThumbprint *string `pulumi:"sslThumbprint"`
+	// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+	Transport *string `pulumi:"transport"`
+	// ESXi SSH host key trust on first use config
+	TrustOnFirstUse *bool `pulumi:"trustOnFirstUse"`
+	// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+	TrustedHostKeys *string `pulumi:"trustedHostKeys"`
+	// ESXi SSH agent authentication config
+	UseSshAgent *bool `pulumi:"useSshAgent"`
 	// ESXi Username config
 	Username *string `pulumi:"username"`
 }
 
 // The set of arguments for constructing a Provider resource.
 type ProviderArgs struct {
+	// ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+	AutoNaming pulumi.BoolPtrInput
+	// The case of the generated names, 'lower' or 'upper'
+	AutoNamingCase pulumi.StringPtrInput
+	// The characters of the random part of the generated names
+	AutoNamingCharset pulumi.StringPtrInput
+	// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+	AutoNamingPattern pulumi.StringPtrInput
+	// The length of the random part of the generated names
+	AutoNamingRandomLength pulumi.IntPtrInput
+	// ESXi SSH bastion (jump host) config, the host is reached through it when set
+	BastionHost pulumi.StringPtrInput
+	// ESXi SSH bastion password config
+	BastionPassword pulumi.StringPtrInput
+	// ESXi SSH bastion port config
+	BastionPort pulumi.StringPtrInput
+	// ESXi SSH bastion private key (PEM encoded) config
+	BastionPrivateKey pulumi.StringPtrInput
+	// ESXi SSH bastion private key path config
+	BastionPrivateKeyPath pulumi.StringPtrInput
+	// ESXi SSH bastion username config, the ESXi username is used when not set
+	BastionUser pulumi.StringPtrInput
+	// The disk store of the virtual machines and of the virtual disks which have none
+	DefaultDiskStore pulumi.StringPtrInput
+	// The virtual hardware version of the virtual machines which have none
+	DefaultHardwareVersion pulumi.IntPtrInput
+	// The virtual network of the interface of the virtual machines without networkInterfaces
+	DefaultNetwork pulumi.StringPtrInput
+	// The guest OS of the virtual machines which have none
+	DefaultOs pulumi.StringPtrInput
+	// The resource pool of the virtual machines which have none
+	DefaultResourcePool pulumi.StringPtrInput
+	// ESXi dry-run config, the commands changing the hosts are logged instead of being run
+	DryRun pulumi.BoolPtrInput
 	// ESXi Host Name config
-	Host pulumi.StringInput
+	Host pulumi.StringPtrInput
+	// ESXi SSH host key SHA256 fingerprint config
+	HostKeyFingerprint pulumi.StringPtrInput
+	// ESXi named hosts config, the resources select one with their host property
+	Hosts HostConnectionMapInput
+	// ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+	InsecureSkipHostKeyVerification pulumi.BoolPtrInput
+	// ESXi SSH known hosts file config
+	KnownHostsFile pulumi.StringPtrInput
+	// ESXi max concurrent sessions config, the number of remote commands run at once on a host
+	MaxConcurrentSessions pulumi.IntPtrInput
 	// ESXi Password config
-	Password pulumi.StringInput
+	Password pulumi.StringPtrInput
+	// ESXi preflight config, the hosts are connected to and checked when the config is checked
+	Preflight pulumi.BoolPtrInput
+	// ESXi SSH private key (PEM encoded) config
+	PrivateKey pulumi.StringPtrInput
+	// ESXi SSH private key passphrase config
+	PrivateKeyPassphrase pulumi.StringPtrInput
+	// ESXi SSH private key path config
+	PrivateKeyPath pulumi.StringPtrInput
+	// ESXi read-only config, the commands changing the hosts are refused
+	ReadOnly pulumi.BoolPtrInput
+	// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+	RecordFile pulumi.StringPtrInput
+	// ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+	RetryAttempts pulumi.IntPtrInput
+	// ESXi retry initial delay config, doubled on every retry
+	RetryInitialDelay pulumi.StringPtrInput
+	// ESXi retry maximum delay config
+	RetryMaxDelay pulumi.StringPtrInput
 	// ESXi Host SSH Port config
 	SshPort pulumi.StringPtrInput
 	// ESXi Host SSL Port config
 	SslPort pulumi.StringPtrInput
+	// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+	SslThumbprint pulumi.StringPtrInput
+	// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+	Transport pulumi.StringPtrInput
+	// ESXi SSH host key trust on first use config
+	TrustOnFirstUse pulumi.BoolPtrInput
+	// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+	TrustedHostKeys pulumi.StringPtrInput
+	// ESXi SSH agent authentication config
+	UseSshAgent pulumi.BoolPtrInput
 	// ESXi Username config
 	Username pulumi.StringPtrInput
 }
@@ -123,14 +347,119 @@ func (o ProviderOutput) ToProviderOutputWithContext(ctx context.Context) Provide
 	return o
 }
 
+// The case of the generated names, 'lower' or 'upper'
+func (o ProviderOutput) AutoNamingCase() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.AutoNamingCase }).(pulumi.StringPtrOutput)
+}
+
+// The characters of the random part of the generated names
+func (o ProviderOutput) AutoNamingCharset() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.AutoNamingCharset }).(pulumi.StringPtrOutput)
+}
+
+// The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+func (o ProviderOutput) AutoNamingPattern() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.AutoNamingPattern }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion (jump host) config, the host is reached through it when set
+func (o ProviderOutput) BastionHost() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionHost }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion password config
+func (o ProviderOutput) BastionPassword() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionPassword }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion port config
+func (o ProviderOutput) BastionPort() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionPort }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion private key (PEM encoded) config
+func (o ProviderOutput) BastionPrivateKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionPrivateKey }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion private key path config
+func (o ProviderOutput) BastionPrivateKeyPath() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionPrivateKeyPath }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH bastion username config, the ESXi username is used when not set
+func (o ProviderOutput) BastionUser() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.BastionUser }).(pulumi.StringPtrOutput)
+}
+
+// The disk store of the virtual machines and of the virtual disks which have none
+func (o ProviderOutput) DefaultDiskStore() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.DefaultDiskStore }).(pulumi.StringPtrOutput)
+}
+
+// The virtual network of the interface of the virtual machines without networkInterfaces
+func (o ProviderOutput) DefaultNetwork() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.DefaultNetwork }).(pulumi.StringPtrOutput)
+}
+
+// The guest OS of the virtual machines which have none
+func (o ProviderOutput) DefaultOs() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.DefaultOs }).(pulumi.StringPtrOutput)
+}
+
+// The resource pool of the virtual machines which have none
+func (o ProviderOutput) DefaultResourcePool() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.DefaultResourcePool }).(pulumi.StringPtrOutput)
+}
+
 // ESXi Host Name config
-func (o ProviderOutput) Host() pulumi.StringOutput {
-	return o.ApplyT(func(v *Provider) pulumi.StringOutput { return v.Host }).(pulumi.StringOutput)
+func (o ProviderOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH host key SHA256 fingerprint config
+func (o ProviderOutput) HostKeyFingerprint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.HostKeyFingerprint }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH known hosts file config
+func (o ProviderOutput) KnownHostsFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.KnownHostsFile }).(pulumi.StringPtrOutput)
 }
 
 // ESXi Password config
-func (o ProviderOutput) Password() pulumi.StringOutput {
-	return o.ApplyT(func(v *Provider) pulumi.StringOutput { return v.Password }).(pulumi.StringOutput)
+func (o ProviderOutput) Password() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.Password }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key (PEM encoded) config
+func (o ProviderOutput) PrivateKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.PrivateKey }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key passphrase config
+func (o ProviderOutput) PrivateKeyPassphrase() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.PrivateKeyPassphrase }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key path config
+func (o ProviderOutput) PrivateKeyPath() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.PrivateKeyPath }).(pulumi.StringPtrOutput)
+}
+
+// ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+func (o ProviderOutput) RecordFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.RecordFile }).(pulumi.StringPtrOutput)
+}
+
+// ESXi retry initial delay config, doubled on every retry
+func (o ProviderOutput) RetryInitialDelay() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.RetryInitialDelay }).(pulumi.StringPtrOutput)
+}
+
+// ESXi retry maximum delay config
+func (o ProviderOutput) RetryMaxDelay() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.RetryMaxDelay }).(pulumi.StringPtrOutput)
 }
 
 // ESXi Host SSH Port config
@@ -143,6 +472,21 @@ func (o ProviderOutput) SslPort() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.SslPort }).(pulumi.StringPtrOutput)
 }
 
+// ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+func (o ProviderOutput) SslThumbprint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.SslThumbprint }).(pulumi.StringPtrOutput)
+}
+
+// ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+func (o ProviderOutput) Transport() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.Transport }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+func (o ProviderOutput) TrustedHostKeys() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.TrustedHostKeys }).(pulumi.StringPtrOutput)
+}
+
 // ESXi Username config
 func (o ProviderOutput) Username() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v *Provider) pulumi.StringPtrOutput { return v.Username }).(pulumi.StringPtrOutput)
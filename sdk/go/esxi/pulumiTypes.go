@@ -13,6 +13,196 @@ import (
 
 var _ = internal.GetEnvOrDefault
 
+// Connection to a named host, the settings not set are the ones of the provider config.
+type HostConnection struct {
+	// ESXi Host Name
+	Host string `pulumi:"host"`
+	// ESXi SSH host key SHA256 fingerprint
+	HostKeyFingerprint *string `pulumi:"hostKeyFingerprint"`
+	// ESXi Password
+	Password *string `pulumi:"password"`
+	// ESXi SSH private key (PEM encoded)
+	PrivateKey *string `pulumi:"privateKey"`
+	// ESXi SSH private key passphrase
+	PrivateKeyPassphrase *string `pulumi:"privateKeyPassphrase"`
+	// ESXi SSH private key path
+	PrivateKeyPath *string `pulumi:"privateKeyPath"`
+	// ESXi Host SSH Port
+	SshPort *string `pulumi:"sshPort"`
+	// ESXi Host SSL Port
+	SslPort *string `pulumi:"sslPort"`
+	// ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+	SslThumbprint *string `pulumi:"sslThumbprint"`
+	// ESXi transport, ssh or api
+	Transport *string `pulumi:"transport"`
+	// ESXi Username
+	Username *string `pulumi:"username"`
+}
+
+// HostConnectionInput is an input type that accepts HostConnectionArgs and HostConnectionOutput values.
+// You can construct a concrete instance of `HostConnectionInput` via:
+//
+//	HostConnectionArgs{...}
+type HostConnectionInput interface {
+	pulumi.Input
+
+	ToHostConnectionOutput() HostConnectionOutput
+	ToHostConnectionOutputWithContext(context.Context) HostConnectionOutput
+}
+
+// Connection to a named host, the settings not set are the ones of the provider config.
+type HostConnectionArgs struct {
+	// ESXi Host Name
+	Host pulumi.StringInput `pulumi:"host"`
+	// ESXi SSH host key SHA256 fingerprint
+	HostKeyFingerprint pulumi.StringPtrInput `pulumi:"hostKeyFingerprint"`
+	// ESXi Password
+	Password pulumi.StringPtrInput `pulumi:"password"`
+	// ESXi SSH private key (PEM encoded)
+	PrivateKey pulumi.StringPtrInput `pulumi:"privateKey"`
+	// ESXi SSH private key passphrase
+	PrivateKeyPassphrase pulumi.StringPtrInput `pulumi:"privateKeyPassphrase"`
+	// ESXi SSH private key path
+	PrivateKeyPath pulumi.StringPtrInput `pulumi:"privateKeyPath"`
+	// ESXi Host SSH Port
+	SshPort pulumi.StringPtrInput `pulumi:"sshPort"`
+	// ESXi Host SSL Port
+	SslPort pulumi.StringPtrInput `pulumi:"sslPort"`
+	// ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+	SslThumbprint pulumi.StringPtrInput `pulumi:"sslThumbprint"`
+	// ESXi transport, ssh or api
+	Transport pulumi.StringPtrInput `pulumi:"transport"`
+	// ESXi Username
+	Username pulumi.StringPtrInput `pulumi:"username"`
+}
+
+func (HostConnectionArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*HostConnection)(nil)).Elem()
+}
+
+func (i HostConnectionArgs) ToHostConnectionOutput() HostConnectionOutput {
+	return i.ToHostConnectionOutputWithContext(context.Background())
+}
+
+func (i HostConnectionArgs) ToHostConnectionOutputWithContext(ctx context.Context) HostConnectionOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HostConnectionOutput)
+}
+
+// HostConnectionMapInput is an input type that accepts HostConnectionMap and HostConnectionMapOutput values.
+// You can construct a concrete instance of `HostConnectionMapInput` via:
+//
+//	HostConnectionMap{ "key": HostConnectionArgs{...} }
+type HostConnectionMapInput interface {
+	pulumi.Input
+
+	ToHostConnectionMapOutput() HostConnectionMapOutput
+	ToHostConnectionMapOutputWithContext(context.Context) HostConnectionMapOutput
+}
+
+type HostConnectionMap map[string]HostConnectionInput
+
+func (HostConnectionMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]HostConnection)(nil)).Elem()
+}
+
+func (i HostConnectionMap) ToHostConnectionMapOutput() HostConnectionMapOutput {
+	return i.ToHostConnectionMapOutputWithContext(context.Background())
+}
+
+func (i HostConnectionMap) ToHostConnectionMapOutputWithContext(ctx context.Context) HostConnectionMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HostConnectionMapOutput)
+}
+
+// Connection to a named host, the settings not set are the ones of the provider config.
+type HostConnectionOutput struct{ *pulumi.OutputState }
+
+func (HostConnectionOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*HostConnection)(nil)).Elem()
+}
+
+func (o HostConnectionOutput) ToHostConnectionOutput() HostConnectionOutput {
+	return o
+}
+
+func (o HostConnectionOutput) ToHostConnectionOutputWithContext(ctx context.Context) HostConnectionOutput {
+	return o
+}
+
+// ESXi Host Name
+func (o HostConnectionOutput) Host() pulumi.StringOutput {
+	return o.ApplyT(func(v HostConnection) string { return v.Host }).(pulumi.StringOutput)
+}
+
+// ESXi SSH host key SHA256 fingerprint
+func (o HostConnectionOutput) HostKeyFingerprint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.HostKeyFingerprint }).(pulumi.StringPtrOutput)
+}
+
+// ESXi Password
+func (o HostConnectionOutput) Password() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.Password }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key (PEM encoded)
+func (o HostConnectionOutput) PrivateKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.PrivateKey }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key passphrase
+func (o HostConnectionOutput) PrivateKeyPassphrase() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.PrivateKeyPassphrase }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSH private key path
+func (o HostConnectionOutput) PrivateKeyPath() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.PrivateKeyPath }).(pulumi.StringPtrOutput)
+}
+
+// ESXi Host SSH Port
+func (o HostConnectionOutput) SshPort() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.SshPort }).(pulumi.StringPtrOutput)
+}
+
+// ESXi Host SSL Port
+func (o HostConnectionOutput) SslPort() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.SslPort }).(pulumi.StringPtrOutput)
+}
+
+// ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+func (o HostConnectionOutput) SslThumbprint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.SslThumbprint }).(pulumi.StringPtrOutput)
+}
+
+// ESXi transport, ssh or api
+func (o HostConnectionOutput) Transport() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.Transport }).(pulumi.StringPtrOutput)
+}
+
+// ESXi Username
+func (o HostConnectionOutput) Username() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v HostConnection) *string { return v.Username }).(pulumi.StringPtrOutput)
+}
+
+type HostConnectionMapOutput struct{ *pulumi.OutputState }
+
+func (HostConnectionMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]HostConnection)(nil)).Elem()
+}
+
+func (o HostConnectionMapOutput) ToHostConnectionMapOutput() HostConnectionMapOutput {
+	return o
+}
+
+func (o HostConnectionMapOutput) ToHostConnectionMapOutputWithContext(ctx context.Context) HostConnectionMapOutput {
+	return o
+}
+
+func (o HostConnectionMapOutput) MapIndex(k pulumi.StringInput) HostConnectionOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) HostConnection {
+		return vs[0].(map[string]HostConnection)[vs[1].(string)]
+	}).(HostConnectionOutput)
+}
+
 type KeyValuePair struct {
 	Key   string `pulumi:"key"`
 	Value string `pulumi:"value"`
@@ -420,6 +610,8 @@ func (o VMVirtualDiskArrayOutput) Index(i pulumi.IntInput) VMVirtualDiskOutput {
 }
 
 func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*HostConnectionInput)(nil)).Elem(), HostConnectionArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*HostConnectionMapInput)(nil)).Elem(), HostConnectionMap{})
 	pulumi.RegisterInputType(reflect.TypeOf((*KeyValuePairInput)(nil)).Elem(), KeyValuePairArgs{})
 	pulumi.RegisterInputType(reflect.TypeOf((*KeyValuePairArrayInput)(nil)).Elem(), KeyValuePairArray{})
 	pulumi.RegisterInputType(reflect.TypeOf((*NetworkInterfaceInput)(nil)).Elem(), NetworkInterfaceArgs{})
@@ -428,6 +620,8 @@ func init() {
 	pulumi.RegisterInputType(reflect.TypeOf((*UplinkArrayInput)(nil)).Elem(), UplinkArray{})
 	pulumi.RegisterInputType(reflect.TypeOf((*VMVirtualDiskInput)(nil)).Elem(), VMVirtualDiskArgs{})
 	pulumi.RegisterInputType(reflect.TypeOf((*VMVirtualDiskArrayInput)(nil)).Elem(), VMVirtualDiskArray{})
+	pulumi.RegisterOutputType(HostConnectionOutput{})
+	pulumi.RegisterOutputType(HostConnectionMapOutput{})
 	pulumi.RegisterOutputType(KeyValuePairOutput{})
 	pulumi.RegisterOutputType(KeyValuePairArrayOutput{})
 	pulumi.RegisterOutputType(NetworkInterfaceOutput{})
@@ -22,6 +22,8 @@ type ResourcePool struct {
 	CpuMinExpandable pulumi.StringPtrOutput `pulumi:"cpuMinExpandable"`
 	// CPU shares (low/normal/high/<custom>).
 	CpuShares pulumi.StringPtrOutput `pulumi:"cpuShares"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrOutput `pulumi:"host"`
 	// Memory maximum (in MB).
 	MemMax pulumi.IntPtrOutput `pulumi:"memMax"`
 	// Memory minimum (in MB).
@@ -100,6 +102,8 @@ type resourcePoolArgs struct {
 	CpuMinExpandable *string `pulumi:"cpuMinExpandable"`
 	// CPU shares (low/normal/high/<custom>).
 	CpuShares *string `pulumi:"cpuShares"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// Memory maximum (in MB).
 	MemMax *int `pulumi:"memMax"`
 	// Memory minimum (in MB).
@@ -122,6 +126,8 @@ type ResourcePoolArgs struct {
 	CpuMinExpandable pulumi.StringPtrInput
 	// CPU shares (low/normal/high/<custom>).
 	CpuShares pulumi.StringPtrInput
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrInput
 	// Memory maximum (in MB).
 	MemMax pulumi.IntPtrInput
 	// Memory minimum (in MB).
@@ -241,6 +247,11 @@ func (o ResourcePoolOutput) CpuShares() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v *ResourcePool) pulumi.StringPtrOutput { return v.CpuShares }).(pulumi.StringPtrOutput)
 }
 
+// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+func (o ResourcePoolOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *ResourcePool) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
 // Memory maximum (in MB).
 func (o ResourcePoolOutput) MemMax() pulumi.IntPtrOutput {
 	return o.ApplyT(func(v *ResourcePool) pulumi.IntPtrOutput { return v.MemMax }).(pulumi.IntPtrOutput)
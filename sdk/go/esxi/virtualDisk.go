@@ -21,6 +21,8 @@ type VirtualDisk struct {
 	DiskStore pulumi.StringOutput `pulumi:"diskStore"`
 	// Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
 	DiskType DiskTypeOutput `pulumi:"diskType"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrOutput `pulumi:"host"`
 	// Virtual Disk Name.
 	Name pulumi.StringOutput `pulumi:"name"`
 	// Virtual Disk size in GB.
@@ -37,9 +39,6 @@ func NewVirtualDisk(ctx *pulumi.Context,
 	if args.Directory == nil {
 		return nil, errors.New("invalid value for required argument 'Directory'")
 	}
-	if args.DiskStore == nil {
-		return nil, errors.New("invalid value for required argument 'DiskStore'")
-	}
 	if args.DiskType == nil {
 		args.DiskType = DiskType("thin")
 	}
@@ -81,10 +80,12 @@ func (VirtualDiskState) ElementType() reflect.Type {
 type virtualDiskArgs struct {
 	// Disk directory.
 	Directory string `pulumi:"directory"`
-	// Disk Store.
-	DiskStore string `pulumi:"diskStore"`
+	// Disk Store, defaults to the defaultDiskStore of the provider.
+	DiskStore *string `pulumi:"diskStore"`
 	// Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
 	DiskType DiskType `pulumi:"diskType"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// Virtual Disk Name.
 	Name *string `pulumi:"name"`
 	// Virtual Disk size in GB.
@@ -95,10 +96,12 @@ type virtualDiskArgs struct {
 type VirtualDiskArgs struct {
 	// Disk directory.
 	Directory pulumi.StringInput
-	// Disk Store.
-	DiskStore pulumi.StringInput
+	// Disk Store, defaults to the defaultDiskStore of the provider.
+	DiskStore pulumi.StringPtrInput
 	// Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
 	DiskType DiskTypeInput
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrInput
 	// Virtual Disk Name.
 	Name pulumi.StringPtrInput
 	// Virtual Disk size in GB.
@@ -207,6 +210,11 @@ func (o VirtualDiskOutput) DiskType() DiskTypeOutput {
 	return o.ApplyT(func(v *VirtualDisk) DiskTypeOutput { return v.DiskType }).(DiskTypeOutput)
 }
 
+// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+func (o VirtualDiskOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *VirtualDisk) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
 // Virtual Disk Name.
 func (o VirtualDiskOutput) Name() pulumi.StringOutput {
 	return o.ApplyT(func(v *VirtualDisk) pulumi.StringOutput { return v.Name }).(pulumi.StringOutput)
@@ -7,7 +7,6 @@ import (
 	"context"
 	"reflect"
 
-	"errors"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumiverse/pulumi-esxi-native/sdk/go/esxi/internal"
 )
@@ -23,6 +22,10 @@ type VirtualMachine struct {
 	BootFirmware BootFirmwareTypePtrOutput `pulumi:"bootFirmware"`
 	// esxi diskstore for boot disk.
 	DiskStore pulumi.StringOutput `pulumi:"diskStore"`
+	// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+	EfiSecureBoot pulumi.BoolPtrOutput `pulumi:"efiSecureBoot"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrOutput `pulumi:"host"`
 	// pass data to VM
 	Info KeyValuePairArrayOutput `pulumi:"info"`
 	// The IP address reported by VMWare tools.
@@ -57,12 +60,9 @@ type VirtualMachine struct {
 func NewVirtualMachine(ctx *pulumi.Context,
 	name string, args *VirtualMachineArgs, opts ...pulumi.ResourceOption) (*VirtualMachine, error) {
 	if args == nil {
-		return nil, errors.New("missing one or more required arguments")
+		args = &VirtualMachineArgs{}
 	}
 
-	if args.DiskStore == nil {
-		return nil, errors.New("invalid value for required argument 'DiskStore'")
-	}
 	if args.BootDiskSize == nil {
 		args.BootDiskSize = pulumi.IntPtr(16)
 	}
@@ -72,30 +72,24 @@ func NewVirtualMachine(ctx *pulumi.Context,
 	if args.BootFirmware == nil {
 		args.BootFirmware = BootFirmwareType("bios")
 	}
+	if args.EfiSecureBoot == nil {
+		args.EfiSecureBoot = pulumi.BoolPtr(false)
+	}
 	if args.MemSize == nil {
 		args.MemSize = pulumi.IntPtr(512)
 	}
 	if args.NumVCpus == nil {
 		args.NumVCpus = pulumi.IntPtr(1)
 	}
-	if args.Os == nil {
-		args.Os = pulumi.StringPtr("centos")
-	}
 	if args.OvfPropertiesTimer == nil {
 		args.OvfPropertiesTimer = pulumi.IntPtr(6000)
 	}
-	if args.ResourcePoolName == nil {
-		args.ResourcePoolName = pulumi.StringPtr("/")
-	}
 	if args.ShutdownTimeout == nil {
 		args.ShutdownTimeout = pulumi.IntPtr(600)
 	}
 	if args.StartupTimeout == nil {
 		args.StartupTimeout = pulumi.IntPtr(600)
 	}
-	if args.VirtualHWVer == nil {
-		args.VirtualHWVer = pulumi.IntPtr(13)
-	}
 	opts = internal.PkgResourceDefaultOpts(opts)
 	var resource VirtualMachine
 	err := ctx.RegisterResource("esxi-native:index:VirtualMachine", name, args, &resource, opts...)
@@ -137,21 +131,25 @@ type virtualMachineArgs struct {
 	BootFirmware *BootFirmwareType `pulumi:"bootFirmware"`
 	// Source vm path on esxi host to clone.
 	CloneFromVirtualMachine *string `pulumi:"cloneFromVirtualMachine"`
-	// esxi diskstore for boot disk.
-	DiskStore string `pulumi:"diskStore"`
+	// esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+	DiskStore *string `pulumi:"diskStore"`
+	// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+	EfiSecureBoot *bool `pulumi:"efiSecureBoot"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// pass data to VM
 	Info []KeyValuePair `pulumi:"info"`
 	// VM memory size.
 	MemSize *int `pulumi:"memSize"`
 	// esxi vm name.
 	Name *string `pulumi:"name"`
-	// VM network interfaces.
+	// VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
 	NetworkInterfaces []NetworkInterface `pulumi:"networkInterfaces"`
 	// VM memory size.
 	Notes *string `pulumi:"notes"`
 	// VM number of virtual cpus.
 	NumVCpus *int `pulumi:"numVCpus"`
-	// VM OS type.
+	// VM OS type, defaults to the defaultOs of the provider or to 'centos'.
 	Os *string `pulumi:"os"`
 	// VM OVF properties.
 	OvfProperties []KeyValuePair `pulumi:"ovfProperties"`
@@ -161,7 +159,7 @@ type virtualMachineArgs struct {
 	OvfSource *string `pulumi:"ovfSource"`
 	// VM power state.
 	Power *string `pulumi:"power"`
-	// Resource pool name to place vm.
+	// Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
 	ResourcePoolName *string `pulumi:"resourcePoolName"`
 	// The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
 	ShutdownTimeout *int `pulumi:"shutdownTimeout"`
@@ -169,7 +167,7 @@ type virtualMachineArgs struct {
 	StartupTimeout *int `pulumi:"startupTimeout"`
 	// VM virtual disks.
 	VirtualDisks []VMVirtualDisk `pulumi:"virtualDisks"`
-	// VM Virtual HW version.
+	// VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
 	VirtualHWVer *int `pulumi:"virtualHWVer"`
 }
 
@@ -183,21 +181,25 @@ type VirtualMachineArgs struct {
 	BootFirmware BootFirmwareTypePtrInput
 	// Source vm path on esxi host to clone.
 	CloneFromVirtualMachine pulumi.StringPtrInput
-	// esxi diskstore for boot disk.
-	DiskStore pulumi.StringInput
+	// esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+	DiskStore pulumi.StringPtrInput
+	// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+	EfiSecureBoot pulumi.BoolPtrInput
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrInput
 	// pass data to VM
 	Info KeyValuePairArrayInput
 	// VM memory size.
 	MemSize pulumi.IntPtrInput
 	// esxi vm name.
 	Name pulumi.StringPtrInput
-	// VM network interfaces.
+	// VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
 	NetworkInterfaces NetworkInterfaceArrayInput
 	// VM memory size.
 	Notes pulumi.StringPtrInput
 	// VM number of virtual cpus.
 	NumVCpus pulumi.IntPtrInput
-	// VM OS type.
+	// VM OS type, defaults to the defaultOs of the provider or to 'centos'.
 	Os pulumi.StringPtrInput
 	// VM OVF properties.
 	OvfProperties KeyValuePairArrayInput
@@ -207,7 +209,7 @@ type VirtualMachineArgs struct {
 	OvfSource pulumi.StringPtrInput
 	// VM power state.
 	Power pulumi.StringPtrInput
-	// Resource pool name to place vm.
+	// Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
 	ResourcePoolName pulumi.StringPtrInput
 	// The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
 	ShutdownTimeout pulumi.IntPtrInput
@@ -215,7 +217,7 @@ type VirtualMachineArgs struct {
 	StartupTimeout pulumi.IntPtrInput
 	// VM virtual disks.
 	VirtualDisks VMVirtualDiskArrayInput
-	// VM Virtual HW version.
+	// VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
 	VirtualHWVer pulumi.IntPtrInput
 }
 
@@ -326,6 +328,16 @@ func (o VirtualMachineOutput) DiskStore() pulumi.StringOutput {
 	return o.ApplyT(func(v *VirtualMachine) pulumi.StringOutput { return v.DiskStore }).(pulumi.StringOutput)
 }
 
+// Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+func (o VirtualMachineOutput) EfiSecureBoot() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *VirtualMachine) pulumi.BoolPtrOutput { return v.EfiSecureBoot }).(pulumi.BoolPtrOutput)
+}
+
+// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+func (o VirtualMachineOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *VirtualMachine) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
 // pass data to VM
 func (o VirtualMachineOutput) Info() KeyValuePairArrayOutput {
 	return o.ApplyT(func(v *VirtualMachine) KeyValuePairArrayOutput { return v.Info }).(KeyValuePairArrayOutput)
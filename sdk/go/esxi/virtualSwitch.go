@@ -16,6 +16,8 @@ type VirtualSwitch struct {
 
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits pulumi.BoolPtrOutput `pulumi:"forgedTransmits"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrOutput `pulumi:"host"`
 	// Virtual Switch Link Discovery Mode.
 	LinkDiscoveryMode pulumi.StringPtrOutput `pulumi:"linkDiscoveryMode"`
 	// MAC address changes (true=Accept/false=Reject).
@@ -74,6 +76,8 @@ func (VirtualSwitchState) ElementType() reflect.Type {
 type virtualSwitchArgs struct {
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits *bool `pulumi:"forgedTransmits"`
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host *string `pulumi:"host"`
 	// Virtual Switch Link Discovery Mode.
 	LinkDiscoveryMode *string `pulumi:"linkDiscoveryMode"`
 	// MAC address changes (true=Accept/false=Reject).
@@ -94,6 +98,8 @@ type virtualSwitchArgs struct {
 type VirtualSwitchArgs struct {
 	// Forged transmits (true=Accept/false=Reject).
 	ForgedTransmits pulumi.BoolPtrInput
+	// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+	Host pulumi.StringPtrInput
 	// Virtual Switch Link Discovery Mode.
 	LinkDiscoveryMode pulumi.StringPtrInput
 	// MAC address changes (true=Accept/false=Reject).
@@ -202,6 +208,11 @@ func (o VirtualSwitchOutput) ForgedTransmits() pulumi.BoolPtrOutput {
 	return o.ApplyT(func(v *VirtualSwitch) pulumi.BoolPtrOutput { return v.ForgedTransmits }).(pulumi.BoolPtrOutput)
 }
 
+// Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+func (o VirtualSwitchOutput) Host() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *VirtualSwitch) pulumi.StringPtrOutput { return v.Host }).(pulumi.StringPtrOutput)
+}
+
 // Virtual Switch Link Discovery Mode.
 func (o VirtualSwitchOutput) LinkDiscoveryMode() pulumi.StringPtrOutput {
 	return o.ApplyT(func(v *VirtualSwitch) pulumi.StringPtrOutput { return v.LinkDiscoveryMode }).(pulumi.StringPtrOutput)
@@ -2,11 +2,201 @@
 // *** Do not edit by hand unless you're certain you know what you are doing! ***
 
 import * as pulumi from "@pulumi/pulumi";
+import * as inputs from "../types/input";
+import * as outputs from "../types/output";
+import * as enums from "../types/enums";
 import * as utilities from "../utilities";
 
 declare var exports: any;
 const __config = new pulumi.Config("esxi-native");
 
+/**
+ * ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+ */
+export declare const autoNaming: boolean | undefined;
+Object.defineProperty(exports, "autoNaming", {
+    get() {
+        return __config.getObject<boolean>("autoNaming");
+    },
+    enumerable: true,
+});
+
+/**
+ * The case of the generated names, 'lower' or 'upper'
+ */
+export declare const autoNamingCase: string | undefined;
+Object.defineProperty(exports, "autoNamingCase", {
+    get() {
+        return __config.get("autoNamingCase");
+    },
+    enumerable: true,
+});
+
+/**
+ * The characters of the random part of the generated names
+ */
+export declare const autoNamingCharset: string | undefined;
+Object.defineProperty(exports, "autoNamingCharset", {
+    get() {
+        return __config.get("autoNamingCharset");
+    },
+    enumerable: true,
+});
+
+/**
+ * The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+ */
+export declare const autoNamingPattern: string | undefined;
+Object.defineProperty(exports, "autoNamingPattern", {
+    get() {
+        return __config.get("autoNamingPattern");
+    },
+    enumerable: true,
+});
+
+/**
+ * The length of the random part of the generated names
+ */
+export declare const autoNamingRandomLength: number | undefined;
+Object.defineProperty(exports, "autoNamingRandomLength", {
+    get() {
+        return __config.getObject<number>("autoNamingRandomLength");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion (jump host) config, the host is reached through it when set
+ */
+export declare const bastionHost: string | undefined;
+Object.defineProperty(exports, "bastionHost", {
+    get() {
+        return __config.get("bastionHost");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion password config
+ */
+export declare const bastionPassword: string | undefined;
+Object.defineProperty(exports, "bastionPassword", {
+    get() {
+        return __config.get("bastionPassword");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion port config
+ */
+export declare const bastionPort: string | undefined;
+Object.defineProperty(exports, "bastionPort", {
+    get() {
+        return __config.get("bastionPort");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion private key (PEM encoded) config
+ */
+export declare const bastionPrivateKey: string | undefined;
+Object.defineProperty(exports, "bastionPrivateKey", {
+    get() {
+        return __config.get("bastionPrivateKey");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion private key path config
+ */
+export declare const bastionPrivateKeyPath: string | undefined;
+Object.defineProperty(exports, "bastionPrivateKeyPath", {
+    get() {
+        return __config.get("bastionPrivateKeyPath");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH bastion username config, the ESXi username is used when not set
+ */
+export declare const bastionUser: string | undefined;
+Object.defineProperty(exports, "bastionUser", {
+    get() {
+        return __config.get("bastionUser");
+    },
+    enumerable: true,
+});
+
+/**
+ * The disk store of the virtual machines and of the virtual disks which have none
+ */
+export declare const defaultDiskStore: string | undefined;
+Object.defineProperty(exports, "defaultDiskStore", {
+    get() {
+        return __config.get("defaultDiskStore");
+    },
+    enumerable: true,
+});
+
+/**
+ * The virtual hardware version of the virtual machines which have none
+ */
+export declare const defaultHardwareVersion: number | undefined;
+Object.defineProperty(exports, "defaultHardwareVersion", {
+    get() {
+        return __config.getObject<number>("defaultHardwareVersion");
+    },
+    enumerable: true,
+});
+
+/**
+ * The virtual network of the interface of the virtual machines without networkInterfaces
+ */
+export declare const defaultNetwork: string | undefined;
+Object.defineProperty(exports, "defaultNetwork", {
+    get() {
+        return __config.get("defaultNetwork");
+    },
+    enumerable: true,
+});
+
+/**
+ * The guest OS of the virtual machines which have none
+ */
+export declare const defaultOs: string | undefined;
+Object.defineProperty(exports, "defaultOs", {
+    get() {
+        return __config.get("defaultOs");
+    },
+    enumerable: true,
+});
+
+/**
+ * The resource pool of the virtual machines which have none
+ */
+export declare const defaultResourcePool: string | undefined;
+Object.defineProperty(exports, "defaultResourcePool", {
+    get() {
+        return __config.get("defaultResourcePool");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi dry-run config, the commands changing the hosts are logged instead of being run
+ */
+export declare const dryRun: boolean | undefined;
+Object.defineProperty(exports, "dryRun", {
+    get() {
+        return __config.getObject<boolean>("dryRun");
+    },
+    enumerable: true,
+});
+
 /**
  * ESXi Host Name config
  */
@@ -18,6 +208,61 @@ Object.defineProperty(exports, "host", {
     enumerable: true,
 });
 
+/**
+ * ESXi SSH host key SHA256 fingerprint config
+ */
+export declare const hostKeyFingerprint: string | undefined;
+Object.defineProperty(exports, "hostKeyFingerprint", {
+    get() {
+        return __config.get("hostKeyFingerprint");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi named hosts config, the resources select one with their host property
+ */
+export declare const hosts: {[key: string]: outputs.HostConnection} | undefined;
+Object.defineProperty(exports, "hosts", {
+    get() {
+        return __config.getObject<{[key: string]: outputs.HostConnection}>("hosts");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+ */
+export declare const insecureSkipHostKeyVerification: boolean | undefined;
+Object.defineProperty(exports, "insecureSkipHostKeyVerification", {
+    get() {
+        return __config.getObject<boolean>("insecureSkipHostKeyVerification");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH known hosts file config
+ */
+export declare const knownHostsFile: string | undefined;
+Object.defineProperty(exports, "knownHostsFile", {
+    get() {
+        return __config.get("knownHostsFile");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi max concurrent sessions config, the number of remote commands run at once on a host
+ */
+export declare const maxConcurrentSessions: number | undefined;
+Object.defineProperty(exports, "maxConcurrentSessions", {
+    get() {
+        return __config.getObject<number>("maxConcurrentSessions");
+    },
+    enumerable: true,
+});
+
 /**
  * ESXi Password config
  */
@@ -29,6 +274,105 @@ Object.defineProperty(exports, "password", {
     enumerable: true,
 });
 
+/**
+ * ESXi preflight config, the hosts are connected to and checked when the config is checked
+ */
+export declare const preflight: boolean | undefined;
+Object.defineProperty(exports, "preflight", {
+    get() {
+        return __config.getObject<boolean>("preflight");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH private key (PEM encoded) config
+ */
+export declare const privateKey: string | undefined;
+Object.defineProperty(exports, "privateKey", {
+    get() {
+        return __config.get("privateKey");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH private key passphrase config
+ */
+export declare const privateKeyPassphrase: string | undefined;
+Object.defineProperty(exports, "privateKeyPassphrase", {
+    get() {
+        return __config.get("privateKeyPassphrase");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH private key path config
+ */
+export declare const privateKeyPath: string | undefined;
+Object.defineProperty(exports, "privateKeyPath", {
+    get() {
+        return __config.get("privateKeyPath");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi read-only config, the commands changing the hosts are refused
+ */
+export declare const readOnly: boolean | undefined;
+Object.defineProperty(exports, "readOnly", {
+    get() {
+        return __config.getObject<boolean>("readOnly");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+ */
+export declare const recordFile: string | undefined;
+Object.defineProperty(exports, "recordFile", {
+    get() {
+        return __config.get("recordFile");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+ */
+export declare const retryAttempts: number | undefined;
+Object.defineProperty(exports, "retryAttempts", {
+    get() {
+        return __config.getObject<number>("retryAttempts");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi retry initial delay config, doubled on every retry
+ */
+export declare const retryInitialDelay: string | undefined;
+Object.defineProperty(exports, "retryInitialDelay", {
+    get() {
+        return __config.get("retryInitialDelay");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi retry maximum delay config
+ */
+export declare const retryMaxDelay: string | undefined;
+Object.defineProperty(exports, "retryMaxDelay", {
+    get() {
+        return __config.get("retryMaxDelay");
+    },
+    enumerable: true,
+});
+
 /**
  * ESXi Host SSH Port config
  */
@@ -51,6 +395,61 @@ Object.defineProperty(exports, "sslPort", {
     enumerable: true,
 });
 
+/**
+ * ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+ */
+export declare const sslThumbprint: string | undefined;
+Object.defineProperty(exports, "sslThumbprint", {
+    get() {
+        return __config.get("sslThumbprint");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+ */
+export declare const transport: string | undefined;
+Object.defineProperty(exports, "transport", {
+    get() {
+        return __config.get("transport");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH host key trust on first use config
+ */
+export declare const trustOnFirstUse: boolean | undefined;
+Object.defineProperty(exports, "trustOnFirstUse", {
+    get() {
+        return __config.getObject<boolean>("trustOnFirstUse");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+ */
+export declare const trustedHostKeys: string | undefined;
+Object.defineProperty(exports, "trustedHostKeys", {
+    get() {
+        return __config.get("trustedHostKeys");
+    },
+    enumerable: true,
+});
+
+/**
+ * ESXi SSH agent authentication config
+ */
+export declare const useSshAgent: boolean | undefined;
+Object.defineProperty(exports, "useSshAgent", {
+    get() {
+        return __config.getObject<boolean>("useSshAgent");
+    },
+    enumerable: true,
+});
+
 /**
  * ESXi Username config
  */
@@ -11,11 +11,16 @@ export function getVirtualMachine(args: GetVirtualMachineArgs, opts?: pulumi.Inv
 
     opts = pulumi.mergeOptions(utilities.resourceOptsDefaults(), opts || {});
     return pulumi.runtime.invoke("esxi-native:index:getVirtualMachine", {
+        "host": args.host,
         "name": args.name,
     }, opts);
 }
 
 export interface GetVirtualMachineArgs {
+    /**
+     * Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+     */
+    host?: string;
     /**
      * Virtual Machine Name to get details of
      */
@@ -39,6 +44,10 @@ export interface GetVirtualMachineResult {
      * esxi diskstore for boot disk.
      */
     readonly diskStore?: string;
+    /**
+     * Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+     */
+    readonly efiSecureBoot?: boolean;
     /**
      * esxi vm id.
      */
@@ -105,6 +114,10 @@ export function getVirtualMachineOutput(args: GetVirtualMachineOutputArgs, opts?
 }
 
 export interface GetVirtualMachineOutputArgs {
+    /**
+     * Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * Virtual Machine Name to get details of
      */
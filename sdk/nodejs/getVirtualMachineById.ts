@@ -11,11 +11,16 @@ export function getVirtualMachineById(args: GetVirtualMachineByIdArgs, opts?: pu
 
     opts = pulumi.mergeOptions(utilities.resourceOptsDefaults(), opts || {});
     return pulumi.runtime.invoke("esxi-native:index:getVirtualMachineById", {
+        "host": args.host,
         "id": args.id,
     }, opts);
 }
 
 export interface GetVirtualMachineByIdArgs {
+    /**
+     * Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+     */
+    host?: string;
     /**
      * Virtual Machine Id to get details of
      */
@@ -39,6 +44,10 @@ export interface GetVirtualMachineByIdResult {
      * esxi diskstore for boot disk.
      */
     readonly diskStore?: string;
+    /**
+     * Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+     */
+    readonly efiSecureBoot?: boolean;
     /**
      * esxi vm id.
      */
@@ -105,6 +114,10 @@ export function getVirtualMachineByIdOutput(args: GetVirtualMachineByIdOutputArg
 }
 
 export interface GetVirtualMachineByIdOutputArgs {
+    /**
+     * Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * Virtual Machine Id to get details of
      */
@@ -35,6 +35,10 @@ export class PortGroup extends pulumi.CustomResource {
      * Forged transmits (true=Accept/false=Reject).
      */
     public readonly forgedTransmits!: pulumi.Output<boolean | undefined>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    public readonly host!: pulumi.Output<string | undefined>;
     /**
      * MAC address changes (true=Accept/false=Reject).
      */
@@ -71,6 +75,7 @@ export class PortGroup extends pulumi.CustomResource {
                 throw new Error("Missing required property 'vSwitch'");
             }
             resourceInputs["forgedTransmits"] = args ? args.forgedTransmits : undefined;
+            resourceInputs["host"] = args ? args.host : undefined;
             resourceInputs["macChanges"] = args ? args.macChanges : undefined;
             resourceInputs["name"] = args ? args.name : undefined;
             resourceInputs["promiscuousMode"] = args ? args.promiscuousMode : undefined;
@@ -78,6 +83,7 @@ export class PortGroup extends pulumi.CustomResource {
             resourceInputs["vlan"] = args ? args.vlan : undefined;
         } else {
             resourceInputs["forgedTransmits"] = undefined /*out*/;
+            resourceInputs["host"] = undefined /*out*/;
             resourceInputs["macChanges"] = undefined /*out*/;
             resourceInputs["name"] = undefined /*out*/;
             resourceInputs["promiscuousMode"] = undefined /*out*/;
@@ -97,6 +103,10 @@ export interface PortGroupArgs {
      * Forged transmits (true=Accept/false=Reject).
      */
     forgedTransmits?: pulumi.Input<boolean>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * MAC address changes (true=Accept/false=Reject).
      */
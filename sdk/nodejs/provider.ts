@@ -2,6 +2,9 @@
 // *** Do not edit by hand unless you're certain you know what you are doing! ***
 
 import * as pulumi from "@pulumi/pulumi";
+import * as inputs from "./types/input";
+import * as outputs from "./types/output";
+import * as enums from "./types/enums";
 import * as utilities from "./utilities";
 
 /**
@@ -22,14 +25,98 @@ export class Provider extends pulumi.ProviderResource {
         return obj['__pulumiType'] === "pulumi:providers:" + Provider.__pulumiType;
     }
 
+    /**
+     * The case of the generated names, 'lower' or 'upper'
+     */
+    public readonly autoNamingCase!: pulumi.Output<string | undefined>;
+    /**
+     * The characters of the random part of the generated names
+     */
+    public readonly autoNamingCharset!: pulumi.Output<string | undefined>;
+    /**
+     * The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+     */
+    public readonly autoNamingPattern!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion (jump host) config, the host is reached through it when set
+     */
+    public readonly bastionHost!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion password config
+     */
+    public readonly bastionPassword!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion port config
+     */
+    public readonly bastionPort!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion private key (PEM encoded) config
+     */
+    public readonly bastionPrivateKey!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion private key path config
+     */
+    public readonly bastionPrivateKeyPath!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH bastion username config, the ESXi username is used when not set
+     */
+    public readonly bastionUser!: pulumi.Output<string | undefined>;
+    /**
+     * The disk store of the virtual machines and of the virtual disks which have none
+     */
+    public readonly defaultDiskStore!: pulumi.Output<string | undefined>;
+    /**
+     * The virtual network of the interface of the virtual machines without networkInterfaces
+     */
+    public readonly defaultNetwork!: pulumi.Output<string | undefined>;
+    /**
+     * The guest OS of the virtual machines which have none
+     */
+    public readonly defaultOs!: pulumi.Output<string | undefined>;
+    /**
+     * The resource pool of the virtual machines which have none
+     */
+    public readonly defaultResourcePool!: pulumi.Output<string | undefined>;
     /**
      * ESXi Host Name config
      */
-    public readonly host!: pulumi.Output<string>;
+    public readonly host!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH host key SHA256 fingerprint config
+     */
+    public readonly hostKeyFingerprint!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH known hosts file config
+     */
+    public readonly knownHostsFile!: pulumi.Output<string | undefined>;
     /**
      * ESXi Password config
      */
-    public readonly password!: pulumi.Output<string>;
+    public readonly password!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH private key (PEM encoded) config
+     */
+    public readonly privateKey!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH private key passphrase config
+     */
+    public readonly privateKeyPassphrase!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH private key path config
+     */
+    public readonly privateKeyPath!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+     */
+    public readonly recordFile!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi retry initial delay config, doubled on every retry
+     */
+    public readonly retryInitialDelay!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi retry maximum delay config
+     */
+    public readonly retryMaxDelay!: pulumi.Output<string | undefined>;
     /**
      * ESXi Host SSH Port config
      */
@@ -38,6 +125,18 @@ export class Provider extends pulumi.ProviderResource {
      * ESXi Host SSL Port config
      */
     public readonly sslPort!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+     */
+    public readonly sslThumbprint!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+     */
+    public readonly transport!: pulumi.Output<string | undefined>;
+    /**
+     * ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+     */
+    public readonly trustedHostKeys!: pulumi.Output<string | undefined>;
     /**
      * ESXi Username config
      */
@@ -50,20 +149,50 @@ export class Provider extends pulumi.ProviderResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: ProviderArgs, opts?: pulumi.ResourceOptions) {
+    constructor(name: string, args?: ProviderArgs, opts?: pulumi.ResourceOptions) {
         let resourceInputs: pulumi.Inputs = {};
         opts = opts || {};
         {
-            if ((!args || args.host === undefined) && !opts.urn) {
-                throw new Error("Missing required property 'host'");
-            }
-            if ((!args || args.password === undefined) && !opts.urn) {
-                throw new Error("Missing required property 'password'");
-            }
+            resourceInputs["autoNaming"] = pulumi.output((args ? args.autoNaming : undefined) ?? true).apply(JSON.stringify);
+            resourceInputs["autoNamingCase"] = args ? args.autoNamingCase : undefined;
+            resourceInputs["autoNamingCharset"] = args ? args.autoNamingCharset : undefined;
+            resourceInputs["autoNamingPattern"] = args ? args.autoNamingPattern : undefined;
+            resourceInputs["autoNamingRandomLength"] = pulumi.output(args ? args.autoNamingRandomLength : undefined).apply(JSON.stringify);
+            resourceInputs["bastionHost"] = args ? args.bastionHost : undefined;
+            resourceInputs["bastionPassword"] = args ? args.bastionPassword : undefined;
+            resourceInputs["bastionPort"] = (args ? args.bastionPort : undefined) ?? "22";
+            resourceInputs["bastionPrivateKey"] = args ? args.bastionPrivateKey : undefined;
+            resourceInputs["bastionPrivateKeyPath"] = args ? args.bastionPrivateKeyPath : undefined;
+            resourceInputs["bastionUser"] = args ? args.bastionUser : undefined;
+            resourceInputs["defaultDiskStore"] = args ? args.defaultDiskStore : undefined;
+            resourceInputs["defaultHardwareVersion"] = pulumi.output(args ? args.defaultHardwareVersion : undefined).apply(JSON.stringify);
+            resourceInputs["defaultNetwork"] = args ? args.defaultNetwork : undefined;
+            resourceInputs["defaultOs"] = args ? args.defaultOs : undefined;
+            resourceInputs["defaultResourcePool"] = args ? args.defaultResourcePool : undefined;
+            resourceInputs["dryRun"] = pulumi.output((args ? args.dryRun : undefined) ?? false).apply(JSON.stringify);
             resourceInputs["host"] = args ? args.host : undefined;
+            resourceInputs["hostKeyFingerprint"] = args ? args.hostKeyFingerprint : undefined;
+            resourceInputs["hosts"] = pulumi.output(args ? args.hosts : undefined).apply(JSON.stringify);
+            resourceInputs["insecureSkipHostKeyVerification"] = pulumi.output((args ? args.insecureSkipHostKeyVerification : undefined) ?? false).apply(JSON.stringify);
+            resourceInputs["knownHostsFile"] = args ? args.knownHostsFile : undefined;
+            resourceInputs["maxConcurrentSessions"] = pulumi.output((args ? args.maxConcurrentSessions : undefined) ?? 8).apply(JSON.stringify);
             resourceInputs["password"] = args ? args.password : undefined;
+            resourceInputs["preflight"] = pulumi.output((args ? args.preflight : undefined) ?? false).apply(JSON.stringify);
+            resourceInputs["privateKey"] = args ? args.privateKey : undefined;
+            resourceInputs["privateKeyPassphrase"] = args ? args.privateKeyPassphrase : undefined;
+            resourceInputs["privateKeyPath"] = args ? args.privateKeyPath : undefined;
+            resourceInputs["readOnly"] = pulumi.output((args ? args.readOnly : undefined) ?? false).apply(JSON.stringify);
+            resourceInputs["recordFile"] = args ? args.recordFile : undefined;
+            resourceInputs["retryAttempts"] = pulumi.output((args ? args.retryAttempts : undefined) ?? 6).apply(JSON.stringify);
+            resourceInputs["retryInitialDelay"] = (args ? args.retryInitialDelay : undefined) ?? "1s";
+            resourceInputs["retryMaxDelay"] = (args ? args.retryMaxDelay : undefined) ?? "30s";
             resourceInputs["sshPort"] = (args ? args.sshPort : undefined) ?? "22";
             resourceInputs["sslPort"] = (args ? args.sslPort : undefined) ?? "443";
+            resourceInputs["sslThumbprint"] = args ? args.sslThumbprint : undefined;
+            resourceInputs["transport"] = (args ? args.transport : undefined) ?? "ssh";
+            resourceInputs["trustOnFirstUse"] = pulumi.output((args ? args.trustOnFirstUse : undefined) ?? false).apply(JSON.stringify);
+            resourceInputs["trustedHostKeys"] = args ? args.trustedHostKeys : undefined;
+            resourceInputs["useSshAgent"] = pulumi.output((args ? args.useSshAgent : undefined) ?? false).apply(JSON.stringify);
             resourceInputs["username"] = (args ? args.username : undefined) ?? "root";
         }
         opts = pulumi.mergeOptions(utilities.resourceOptsDefaults(), opts);
@@ -75,14 +204,138 @@ export class Provider extends pulumi.ProviderResource {
  * The set of arguments for constructing a Provider resource.
  */
 export interface ProviderArgs {
+    /**
+     * ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+     */
+    autoNaming?: pulumi.Input<boolean>;
+    /**
+     * The case of the generated names, 'lower' or 'upper'
+     */
+    autoNamingCase?: pulumi.Input<string>;
+    /**
+     * The characters of the random part of the generated names
+     */
+    autoNamingCharset?: pulumi.Input<string>;
+    /**
+     * The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+     */
+    autoNamingPattern?: pulumi.Input<string>;
+    /**
+     * The length of the random part of the generated names
+     */
+    autoNamingRandomLength?: pulumi.Input<number>;
+    /**
+     * ESXi SSH bastion (jump host) config, the host is reached through it when set
+     */
+    bastionHost?: pulumi.Input<string>;
+    /**
+     * ESXi SSH bastion password config
+     */
+    bastionPassword?: pulumi.Input<string>;
+    /**
+     * ESXi SSH bastion port config
+     */
+    bastionPort?: pulumi.Input<string>;
+    /**
+     * ESXi SSH bastion private key (PEM encoded) config
+     */
+    bastionPrivateKey?: pulumi.Input<string>;
+    /**
+     * ESXi SSH bastion private key path config
+     */
+    bastionPrivateKeyPath?: pulumi.Input<string>;
+    /**
+     * ESXi SSH bastion username config, the ESXi username is used when not set
+     */
+    bastionUser?: pulumi.Input<string>;
+    /**
+     * The disk store of the virtual machines and of the virtual disks which have none
+     */
+    defaultDiskStore?: pulumi.Input<string>;
+    /**
+     * The virtual hardware version of the virtual machines which have none
+     */
+    defaultHardwareVersion?: pulumi.Input<number>;
+    /**
+     * The virtual network of the interface of the virtual machines without networkInterfaces
+     */
+    defaultNetwork?: pulumi.Input<string>;
+    /**
+     * The guest OS of the virtual machines which have none
+     */
+    defaultOs?: pulumi.Input<string>;
+    /**
+     * The resource pool of the virtual machines which have none
+     */
+    defaultResourcePool?: pulumi.Input<string>;
+    /**
+     * ESXi dry-run config, the commands changing the hosts are logged instead of being run
+     */
+    dryRun?: pulumi.Input<boolean>;
     /**
      * ESXi Host Name config
      */
-    host: pulumi.Input<string>;
+    host?: pulumi.Input<string>;
+    /**
+     * ESXi SSH host key SHA256 fingerprint config
+     */
+    hostKeyFingerprint?: pulumi.Input<string>;
+    /**
+     * ESXi named hosts config, the resources select one with their host property
+     */
+    hosts?: pulumi.Input<{[key: string]: pulumi.Input<inputs.HostConnectionArgs>}>;
+    /**
+     * ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+     */
+    insecureSkipHostKeyVerification?: pulumi.Input<boolean>;
+    /**
+     * ESXi SSH known hosts file config
+     */
+    knownHostsFile?: pulumi.Input<string>;
+    /**
+     * ESXi max concurrent sessions config, the number of remote commands run at once on a host
+     */
+    maxConcurrentSessions?: pulumi.Input<number>;
     /**
      * ESXi Password config
      */
-    password: pulumi.Input<string>;
+    password?: pulumi.Input<string>;
+    /**
+     * ESXi preflight config, the hosts are connected to and checked when the config is checked
+     */
+    preflight?: pulumi.Input<boolean>;
+    /**
+     * ESXi SSH private key (PEM encoded) config
+     */
+    privateKey?: pulumi.Input<string>;
+    /**
+     * ESXi SSH private key passphrase config
+     */
+    privateKeyPassphrase?: pulumi.Input<string>;
+    /**
+     * ESXi SSH private key path config
+     */
+    privateKeyPath?: pulumi.Input<string>;
+    /**
+     * ESXi read-only config, the commands changing the hosts are refused
+     */
+    readOnly?: pulumi.Input<boolean>;
+    /**
+     * ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+     */
+    recordFile?: pulumi.Input<string>;
+    /**
+     * ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+     */
+    retryAttempts?: pulumi.Input<number>;
+    /**
+     * ESXi retry initial delay config, doubled on every retry
+     */
+    retryInitialDelay?: pulumi.Input<string>;
+    /**
+     * ESXi retry maximum delay config
+     */
+    retryMaxDelay?: pulumi.Input<string>;
     /**
      * ESXi Host SSH Port config
      */
@@ -91,6 +344,26 @@ export interface ProviderArgs {
      * ESXi Host SSL Port config
      */
     sslPort?: pulumi.Input<string>;
+    /**
+     * ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+     */
+    sslThumbprint?: pulumi.Input<string>;
+    /**
+     * ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+     */
+    transport?: pulumi.Input<string>;
+    /**
+     * ESXi SSH host key trust on first use config
+     */
+    trustOnFirstUse?: pulumi.Input<boolean>;
+    /**
+     * ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+     */
+    trustedHostKeys?: pulumi.Input<string>;
+    /**
+     * ESXi SSH agent authentication config
+     */
+    useSshAgent?: pulumi.Input<boolean>;
     /**
      * ESXi Username config
      */
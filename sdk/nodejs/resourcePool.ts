@@ -47,6 +47,10 @@ export class ResourcePool extends pulumi.CustomResource {
      * CPU shares (low/normal/high/<custom>).
      */
     public readonly cpuShares!: pulumi.Output<string | undefined>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    public readonly host!: pulumi.Output<string | undefined>;
     /**
      * Memory maximum (in MB).
      */
@@ -83,6 +87,7 @@ export class ResourcePool extends pulumi.CustomResource {
             resourceInputs["cpuMin"] = (args ? args.cpuMin : undefined) ?? 100;
             resourceInputs["cpuMinExpandable"] = (args ? args.cpuMinExpandable : undefined) ?? "true";
             resourceInputs["cpuShares"] = (args ? args.cpuShares : undefined) ?? "normal";
+            resourceInputs["host"] = args ? args.host : undefined;
             resourceInputs["memMax"] = args ? args.memMax : undefined;
             resourceInputs["memMin"] = (args ? args.memMin : undefined) ?? 200;
             resourceInputs["memMinExpandable"] = (args ? args.memMinExpandable : undefined) ?? "true";
@@ -93,6 +98,7 @@ export class ResourcePool extends pulumi.CustomResource {
             resourceInputs["cpuMin"] = undefined /*out*/;
             resourceInputs["cpuMinExpandable"] = undefined /*out*/;
             resourceInputs["cpuShares"] = undefined /*out*/;
+            resourceInputs["host"] = undefined /*out*/;
             resourceInputs["memMax"] = undefined /*out*/;
             resourceInputs["memMin"] = undefined /*out*/;
             resourceInputs["memMinExpandable"] = undefined /*out*/;
@@ -124,6 +130,10 @@ export interface ResourcePoolArgs {
      * CPU shares (low/normal/high/<custom>).
      */
     cpuShares?: pulumi.Input<string>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * Memory maximum (in MB).
      */
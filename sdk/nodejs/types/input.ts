@@ -6,6 +6,56 @@ import * as inputs from "../types/input";
 import * as outputs from "../types/output";
 import * as enums from "../types/enums";
 
+/**
+ * Connection to a named host, the settings not set are the ones of the provider config.
+ */
+export interface HostConnectionArgs {
+    /**
+     * ESXi Host Name
+     */
+    host: pulumi.Input<string>;
+    /**
+     * ESXi SSH host key SHA256 fingerprint
+     */
+    hostKeyFingerprint?: pulumi.Input<string>;
+    /**
+     * ESXi Password
+     */
+    password?: pulumi.Input<string>;
+    /**
+     * ESXi SSH private key (PEM encoded)
+     */
+    privateKey?: pulumi.Input<string>;
+    /**
+     * ESXi SSH private key passphrase
+     */
+    privateKeyPassphrase?: pulumi.Input<string>;
+    /**
+     * ESXi SSH private key path
+     */
+    privateKeyPath?: pulumi.Input<string>;
+    /**
+     * ESXi Host SSH Port
+     */
+    sshPort?: pulumi.Input<string>;
+    /**
+     * ESXi Host SSL Port
+     */
+    sslPort?: pulumi.Input<string>;
+    /**
+     * ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+     */
+    sslThumbprint?: pulumi.Input<string>;
+    /**
+     * ESXi transport, ssh or api
+     */
+    transport?: pulumi.Input<string>;
+    /**
+     * ESXi Username
+     */
+    username?: pulumi.Input<string>;
+}
+
 export interface KeyValuePairArgs {
     key: pulumi.Input<string>;
     value: pulumi.Input<string>;
@@ -6,6 +6,56 @@ import * as inputs from "../types/input";
 import * as outputs from "../types/output";
 import * as enums from "../types/enums";
 
+/**
+ * Connection to a named host, the settings not set are the ones of the provider config.
+ */
+export interface HostConnection {
+    /**
+     * ESXi Host Name
+     */
+    host: string;
+    /**
+     * ESXi SSH host key SHA256 fingerprint
+     */
+    hostKeyFingerprint?: string;
+    /**
+     * ESXi Password
+     */
+    password?: string;
+    /**
+     * ESXi SSH private key (PEM encoded)
+     */
+    privateKey?: string;
+    /**
+     * ESXi SSH private key passphrase
+     */
+    privateKeyPassphrase?: string;
+    /**
+     * ESXi SSH private key path
+     */
+    privateKeyPath?: string;
+    /**
+     * ESXi Host SSH Port
+     */
+    sshPort?: string;
+    /**
+     * ESXi Host SSL Port
+     */
+    sslPort?: string;
+    /**
+     * ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+     */
+    sslThumbprint?: string;
+    /**
+     * ESXi transport, ssh or api
+     */
+    transport?: string;
+    /**
+     * ESXi Username
+     */
+    username?: string;
+}
+
 export interface KeyValuePair {
     key: string;
     value: string;
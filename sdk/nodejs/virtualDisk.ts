@@ -46,6 +46,10 @@ export class VirtualDisk extends pulumi.CustomResource {
      * Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
      */
     public readonly diskType!: pulumi.Output<enums.DiskType>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    public readonly host!: pulumi.Output<string | undefined>;
     /**
      * Virtual Disk Name.
      */
@@ -69,21 +73,20 @@ export class VirtualDisk extends pulumi.CustomResource {
             if ((!args || args.directory === undefined) && !opts.urn) {
                 throw new Error("Missing required property 'directory'");
             }
-            if ((!args || args.diskStore === undefined) && !opts.urn) {
-                throw new Error("Missing required property 'diskStore'");
-            }
             if ((!args || args.diskType === undefined) && !opts.urn) {
                 throw new Error("Missing required property 'diskType'");
             }
             resourceInputs["directory"] = args ? args.directory : undefined;
             resourceInputs["diskStore"] = args ? args.diskStore : undefined;
             resourceInputs["diskType"] = (args ? args.diskType : undefined) ?? "thin";
+            resourceInputs["host"] = args ? args.host : undefined;
             resourceInputs["name"] = args ? args.name : undefined;
             resourceInputs["size"] = (args ? args.size : undefined) ?? 1;
         } else {
             resourceInputs["directory"] = undefined /*out*/;
             resourceInputs["diskStore"] = undefined /*out*/;
             resourceInputs["diskType"] = undefined /*out*/;
+            resourceInputs["host"] = undefined /*out*/;
             resourceInputs["name"] = undefined /*out*/;
             resourceInputs["size"] = undefined /*out*/;
         }
@@ -101,13 +104,17 @@ export interface VirtualDiskArgs {
      */
     directory: pulumi.Input<string>;
     /**
-     * Disk Store.
+     * Disk Store, defaults to the defaultDiskStore of the provider.
      */
-    diskStore: pulumi.Input<string>;
+    diskStore?: pulumi.Input<string>;
     /**
      * Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
      */
     diskType: pulumi.Input<enums.DiskType>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * Virtual Disk Name.
      */
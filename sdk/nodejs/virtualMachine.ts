@@ -50,6 +50,14 @@ export class VirtualMachine extends pulumi.CustomResource {
      * esxi diskstore for boot disk.
      */
     public readonly diskStore!: pulumi.Output<string>;
+    /**
+     * Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+     */
+    public readonly efiSecureBoot!: pulumi.Output<boolean | undefined>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    public readonly host!: pulumi.Output<string | undefined>;
     /**
      * pass data to VM
      */
@@ -114,40 +122,41 @@ export class VirtualMachine extends pulumi.CustomResource {
      * @param args The arguments to use to populate this resource's properties.
      * @param opts A bag of options that control this resource's behavior.
      */
-    constructor(name: string, args: VirtualMachineArgs, opts?: pulumi.CustomResourceOptions) {
+    constructor(name: string, args?: VirtualMachineArgs, opts?: pulumi.CustomResourceOptions) {
         let resourceInputs: pulumi.Inputs = {};
         opts = opts || {};
         if (!opts.id) {
-            if ((!args || args.diskStore === undefined) && !opts.urn) {
-                throw new Error("Missing required property 'diskStore'");
-            }
             resourceInputs["bootDiskSize"] = (args ? args.bootDiskSize : undefined) ?? 16;
             resourceInputs["bootDiskType"] = (args ? args.bootDiskType : undefined) ?? "thin";
             resourceInputs["bootFirmware"] = (args ? args.bootFirmware : undefined) ?? "bios";
             resourceInputs["cloneFromVirtualMachine"] = args ? args.cloneFromVirtualMachine : undefined;
             resourceInputs["diskStore"] = args ? args.diskStore : undefined;
+            resourceInputs["efiSecureBoot"] = (args ? args.efiSecureBoot : undefined) ?? false;
+            resourceInputs["host"] = args ? args.host : undefined;
             resourceInputs["info"] = args ? args.info : undefined;
             resourceInputs["memSize"] = (args ? args.memSize : undefined) ?? 512;
             resourceInputs["name"] = args ? args.name : undefined;
             resourceInputs["networkInterfaces"] = args ? args.networkInterfaces : undefined;
             resourceInputs["notes"] = args ? args.notes : undefined;
             resourceInputs["numVCpus"] = (args ? args.numVCpus : undefined) ?? 1;
-            resourceInputs["os"] = (args ? args.os : undefined) ?? "centos";
+            resourceInputs["os"] = args ? args.os : undefined;
             resourceInputs["ovfProperties"] = args ? args.ovfProperties : undefined;
             resourceInputs["ovfPropertiesTimer"] = (args ? args.ovfPropertiesTimer : undefined) ?? 6000;
             resourceInputs["ovfSource"] = args ? args.ovfSource : undefined;
             resourceInputs["power"] = args ? args.power : undefined;
-            resourceInputs["resourcePoolName"] = (args ? args.resourcePoolName : undefined) ?? "/";
+            resourceInputs["resourcePoolName"] = args ? args.resourcePoolName : undefined;
             resourceInputs["shutdownTimeout"] = (args ? args.shutdownTimeout : undefined) ?? 600;
             resourceInputs["startupTimeout"] = (args ? args.startupTimeout : undefined) ?? 600;
             resourceInputs["virtualDisks"] = args ? args.virtualDisks : undefined;
-            resourceInputs["virtualHWVer"] = (args ? args.virtualHWVer : undefined) ?? 13;
+            resourceInputs["virtualHWVer"] = args ? args.virtualHWVer : undefined;
             resourceInputs["ipAddress"] = undefined /*out*/;
         } else {
             resourceInputs["bootDiskSize"] = undefined /*out*/;
             resourceInputs["bootDiskType"] = undefined /*out*/;
             resourceInputs["bootFirmware"] = undefined /*out*/;
             resourceInputs["diskStore"] = undefined /*out*/;
+            resourceInputs["efiSecureBoot"] = undefined /*out*/;
+            resourceInputs["host"] = undefined /*out*/;
             resourceInputs["info"] = undefined /*out*/;
             resourceInputs["ipAddress"] = undefined /*out*/;
             resourceInputs["memSize"] = undefined /*out*/;
@@ -189,9 +198,17 @@ export interface VirtualMachineArgs {
      */
     cloneFromVirtualMachine?: pulumi.Input<string>;
     /**
-     * esxi diskstore for boot disk.
+     * esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+     */
+    diskStore?: pulumi.Input<string>;
+    /**
+     * Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
      */
-    diskStore: pulumi.Input<string>;
+    efiSecureBoot?: pulumi.Input<boolean>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * pass data to VM
      */
@@ -205,7 +222,7 @@ export interface VirtualMachineArgs {
      */
     name?: pulumi.Input<string>;
     /**
-     * VM network interfaces.
+     * VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
      */
     networkInterfaces?: pulumi.Input<pulumi.Input<inputs.NetworkInterfaceArgs>[]>;
     /**
@@ -217,7 +234,7 @@ export interface VirtualMachineArgs {
      */
     numVCpus?: pulumi.Input<number>;
     /**
-     * VM OS type.
+     * VM OS type, defaults to the defaultOs of the provider or to 'centos'.
      */
     os?: pulumi.Input<string>;
     /**
@@ -237,7 +254,7 @@ export interface VirtualMachineArgs {
      */
     power?: pulumi.Input<string>;
     /**
-     * Resource pool name to place vm.
+     * Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
      */
     resourcePoolName?: pulumi.Input<string>;
     /**
@@ -253,7 +270,7 @@ export interface VirtualMachineArgs {
      */
     virtualDisks?: pulumi.Input<pulumi.Input<inputs.VMVirtualDiskArgs>[]>;
     /**
-     * VM Virtual HW version.
+     * VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
      */
     virtualHWVer?: pulumi.Input<number>;
 }
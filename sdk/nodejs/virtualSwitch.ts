@@ -38,6 +38,10 @@ export class VirtualSwitch extends pulumi.CustomResource {
      * Forged transmits (true=Accept/false=Reject).
      */
     public readonly forgedTransmits!: pulumi.Output<boolean | undefined>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    public readonly host!: pulumi.Output<string | undefined>;
     /**
      * Virtual Switch Link Discovery Mode.
      */
@@ -79,6 +83,7 @@ export class VirtualSwitch extends pulumi.CustomResource {
         opts = opts || {};
         if (!opts.id) {
             resourceInputs["forgedTransmits"] = args ? args.forgedTransmits : undefined;
+            resourceInputs["host"] = args ? args.host : undefined;
             resourceInputs["linkDiscoveryMode"] = args ? args.linkDiscoveryMode : undefined;
             resourceInputs["macChanges"] = args ? args.macChanges : undefined;
             resourceInputs["mtu"] = args ? args.mtu : undefined;
@@ -88,6 +93,7 @@ export class VirtualSwitch extends pulumi.CustomResource {
             resourceInputs["uplinks"] = args ? args.uplinks : undefined;
         } else {
             resourceInputs["forgedTransmits"] = undefined /*out*/;
+            resourceInputs["host"] = undefined /*out*/;
             resourceInputs["linkDiscoveryMode"] = undefined /*out*/;
             resourceInputs["macChanges"] = undefined /*out*/;
             resourceInputs["mtu"] = undefined /*out*/;
@@ -109,6 +115,10 @@ export interface VirtualSwitchArgs {
      * Forged transmits (true=Accept/false=Reject).
      */
     forgedTransmits?: pulumi.Input<boolean>;
+    /**
+     * Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+     */
+    host?: pulumi.Input<string>;
     /**
      * Virtual Switch Link Discovery Mode.
      */
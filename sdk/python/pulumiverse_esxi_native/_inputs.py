@@ -11,12 +11,196 @@ from . import _utilities
 from ._enums import *
 
 __all__ = [
+    'HostConnectionArgs',
     'KeyValuePairArgs',
     'NetworkInterfaceArgs',
     'UplinkArgs',
     'VMVirtualDiskArgs',
 ]
 
+@pulumi.input_type
+class HostConnectionArgs:
+    def __init__(__self__, *,
+                 host: pulumi.Input[str],
+                 host_key_fingerprint: Optional[pulumi.Input[str]] = None,
+                 password: Optional[pulumi.Input[str]] = None,
+                 private_key: Optional[pulumi.Input[str]] = None,
+                 private_key_passphrase: Optional[pulumi.Input[str]] = None,
+                 private_key_path: Optional[pulumi.Input[str]] = None,
+                 ssh_port: Optional[pulumi.Input[str]] = None,
+                 ssl_port: Optional[pulumi.Input[str]] = None,
+                 ssl_thumbprint: Optional[pulumi.Input[str]] = None,
+                 transport: Optional[pulumi.Input[str]] = None,
+                 username: Optional[pulumi.Input[str]] = None):
+        """
+        Connection to a named host, the settings not set are the ones of the provider config.
+        :param pulumi.Input[str] host: ESXi Host Name
+        :param pulumi.Input[str] host_key_fingerprint: ESXi SSH host key SHA256 fingerprint
+        :param pulumi.Input[str] password: ESXi Password
+        :param pulumi.Input[str] private_key: ESXi SSH private key (PEM encoded)
+        :param pulumi.Input[str] private_key_passphrase: ESXi SSH private key passphrase
+        :param pulumi.Input[str] private_key_path: ESXi SSH private key path
+        :param pulumi.Input[str] ssh_port: ESXi Host SSH Port
+        :param pulumi.Input[str] ssl_port: ESXi Host SSL Port
+        :param pulumi.Input[str] ssl_thumbprint: ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        :param pulumi.Input[str] transport: ESXi transport, ssh or api
+        :param pulumi.Input[str] username: ESXi Username
+        """
+        pulumi.set(__self__, "host", host)
+        if host_key_fingerprint is not None:
+            pulumi.set(__self__, "host_key_fingerprint", host_key_fingerprint)
+        if password is not None:
+            pulumi.set(__self__, "password", password)
+        if private_key is not None:
+            pulumi.set(__self__, "private_key", private_key)
+        if private_key_passphrase is not None:
+            pulumi.set(__self__, "private_key_passphrase", private_key_passphrase)
+        if private_key_path is not None:
+            pulumi.set(__self__, "private_key_path", private_key_path)
+        if ssh_port is not None:
+            pulumi.set(__self__, "ssh_port", ssh_port)
+        if ssl_port is not None:
+            pulumi.set(__self__, "ssl_port", ssl_port)
+        if ssl_thumbprint is not None:
+            pulumi.set(__self__, "ssl_thumbprint", ssl_thumbprint)
+        if transport is not None:
+            pulumi.set(__self__, "transport", transport)
+        if username is not None:
+            pulumi.set(__self__, "username", username)
+
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Input[str]:
+        """
+        ESXi Host Name
+        """
+        return pulumi.get(self, "host")
+
+    @host.setter
+    def host(self, value: pulumi.Input[str]):
+        pulumi.set(self, "host", value)
+
+    @property
+    @pulumi.getter(name="hostKeyFingerprint")
+    def host_key_fingerprint(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH host key SHA256 fingerprint
+        """
+        return pulumi.get(self, "host_key_fingerprint")
+
+    @host_key_fingerprint.setter
+    def host_key_fingerprint(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host_key_fingerprint", value)
+
+    @property
+    @pulumi.getter
+    def password(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi Password
+        """
+        return pulumi.get(self, "password")
+
+    @password.setter
+    def password(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "password", value)
+
+    @property
+    @pulumi.getter(name="privateKey")
+    def private_key(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key (PEM encoded)
+        """
+        return pulumi.get(self, "private_key")
+
+    @private_key.setter
+    def private_key(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key", value)
+
+    @property
+    @pulumi.getter(name="privateKeyPassphrase")
+    def private_key_passphrase(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key passphrase
+        """
+        return pulumi.get(self, "private_key_passphrase")
+
+    @private_key_passphrase.setter
+    def private_key_passphrase(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key_passphrase", value)
+
+    @property
+    @pulumi.getter(name="privateKeyPath")
+    def private_key_path(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key path
+        """
+        return pulumi.get(self, "private_key_path")
+
+    @private_key_path.setter
+    def private_key_path(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key_path", value)
+
+    @property
+    @pulumi.getter(name="sshPort")
+    def ssh_port(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi Host SSH Port
+        """
+        return pulumi.get(self, "ssh_port")
+
+    @ssh_port.setter
+    def ssh_port(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ssh_port", value)
+
+    @property
+    @pulumi.getter(name="sslPort")
+    def ssl_port(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi Host SSL Port
+        """
+        return pulumi.get(self, "ssl_port")
+
+    @ssl_port.setter
+    def ssl_port(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ssl_port", value)
+
+    @property
+    @pulumi.getter(name="sslThumbprint")
+    def ssl_thumbprint(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        """
+        return pulumi.get(self, "ssl_thumbprint")
+
+    @ssl_thumbprint.setter
+    def ssl_thumbprint(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ssl_thumbprint", value)
+
+    @property
+    @pulumi.getter
+    def transport(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi transport, ssh or api
+        """
+        return pulumi.get(self, "transport")
+
+    @transport.setter
+    def transport(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "transport", value)
+
+    @property
+    @pulumi.getter
+    def username(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi Username
+        """
+        return pulumi.get(self, "username")
+
+    @username.setter
+    def username(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "username", value)
+
+
 @pulumi.input_type
 class KeyValuePairArgs:
     def __init__(__self__, *,
@@ -8,17 +8,173 @@ import pulumi
 import pulumi.runtime
 from typing import Any, Mapping, Optional, Sequence, Union, overload
 from .. import _utilities
+from . import outputs as _root_outputs
+
+autoNaming: Optional[bool]
+"""
+ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+"""
+
+autoNamingCase: Optional[str]
+"""
+The case of the generated names, 'lower' or 'upper'
+"""
+
+autoNamingCharset: Optional[str]
+"""
+The characters of the random part of the generated names
+"""
+
+autoNamingPattern: Optional[str]
+"""
+The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+"""
+
+autoNamingRandomLength: Optional[int]
+"""
+The length of the random part of the generated names
+"""
+
+bastionHost: Optional[str]
+"""
+ESXi SSH bastion (jump host) config, the host is reached through it when set
+"""
+
+bastionPassword: Optional[str]
+"""
+ESXi SSH bastion password config
+"""
+
+bastionPort: Optional[str]
+"""
+ESXi SSH bastion port config
+"""
+
+bastionPrivateKey: Optional[str]
+"""
+ESXi SSH bastion private key (PEM encoded) config
+"""
+
+bastionPrivateKeyPath: Optional[str]
+"""
+ESXi SSH bastion private key path config
+"""
+
+bastionUser: Optional[str]
+"""
+ESXi SSH bastion username config, the ESXi username is used when not set
+"""
+
+defaultDiskStore: Optional[str]
+"""
+The disk store of the virtual machines and of the virtual disks which have none
+"""
+
+defaultHardwareVersion: Optional[int]
+"""
+The virtual hardware version of the virtual machines which have none
+"""
+
+defaultNetwork: Optional[str]
+"""
+The virtual network of the interface of the virtual machines without networkInterfaces
+"""
+
+defaultOs: Optional[str]
+"""
+The guest OS of the virtual machines which have none
+"""
+
+defaultResourcePool: Optional[str]
+"""
+The resource pool of the virtual machines which have none
+"""
+
+dryRun: Optional[bool]
+"""
+ESXi dry-run config, the commands changing the hosts are logged instead of being run
+"""
 
 host: Optional[str]
 """
 ESXi Host Name config
 """
 
+hostKeyFingerprint: Optional[str]
+"""
+ESXi SSH host key SHA256 fingerprint config
+"""
+
+hosts: Optional[str]
+"""
+ESXi named hosts config, the resources select one with their host property
+"""
+
+insecureSkipHostKeyVerification: Optional[bool]
+"""
+ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+"""
+
+knownHostsFile: Optional[str]
+"""
+ESXi SSH known hosts file config
+"""
+
+maxConcurrentSessions: Optional[int]
+"""
+ESXi max concurrent sessions config, the number of remote commands run at once on a host
+"""
+
 password: Optional[str]
 """
 ESXi Password config
 """
 
+preflight: Optional[bool]
+"""
+ESXi preflight config, the hosts are connected to and checked when the config is checked
+"""
+
+privateKey: Optional[str]
+"""
+ESXi SSH private key (PEM encoded) config
+"""
+
+privateKeyPassphrase: Optional[str]
+"""
+ESXi SSH private key passphrase config
+"""
+
+privateKeyPath: Optional[str]
+"""
+ESXi SSH private key path config
+"""
+
+readOnly: Optional[bool]
+"""
+ESXi read-only config, the commands changing the hosts are refused
+"""
+
+recordFile: Optional[str]
+"""
+ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+"""
+
+retryAttempts: Optional[int]
+"""
+ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+"""
+
+retryInitialDelay: Optional[str]
+"""
+ESXi retry initial delay config, doubled on every retry
+"""
+
+retryMaxDelay: Optional[str]
+"""
+ESXi retry maximum delay config
+"""
+
 sshPort: Optional[str]
 """
 ESXi Host SSH Port config
@@ -29,6 +185,31 @@ sslPort: Optional[str]
 ESXi Host SSL Port config
 """
 
+sslThumbprint: Optional[str]
+"""
+ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+"""
+
+transport: Optional[str]
+"""
+ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+"""
+
+trustOnFirstUse: Optional[bool]
+"""
+ESXi SSH host key trust on first use config
+"""
+
+trustedHostKeys: Optional[str]
+"""
+ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+"""
+
+useSshAgent: Optional[bool]
+"""
+ESXi SSH agent authentication config
+"""
+
 username: Optional[str]
 """
 ESXi Username config
@@ -8,6 +8,7 @@ import pulumi
 import pulumi.runtime
 from typing import Any, Mapping, Optional, Sequence, Union, overload
 from .. import _utilities
+from . import outputs as _root_outputs
 
 import types
 
@@ -15,6 +16,125 @@ __config__ = pulumi.Config('esxi-native')
 
 
 class _ExportableConfig(types.ModuleType):
+    @property
+    def auto_naming(self) -> Optional[bool]:
+        """
+        ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        """
+        return __config__.get_bool('autoNaming')
+
+    @property
+    def auto_naming_case(self) -> Optional[str]:
+        """
+        The case of the generated names, 'lower' or 'upper'
+        """
+        return __config__.get('autoNamingCase')
+
+    @property
+    def auto_naming_charset(self) -> Optional[str]:
+        """
+        The characters of the random part of the generated names
+        """
+        return __config__.get('autoNamingCharset')
+
+    @property
+    def auto_naming_pattern(self) -> Optional[str]:
+        """
+        The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        """
+        return __config__.get('autoNamingPattern')
+
+    @property
+    def auto_naming_random_length(self) -> Optional[int]:
+        """
+        The length of the random part of the generated names
+        """
+        return __config__.get_int('autoNamingRandomLength')
+
+    @property
+    def bastion_host(self) -> Optional[str]:
+        """
+        ESXi SSH bastion (jump host) config, the host is reached through it when set
+        """
+        return __config__.get('bastionHost')
+
+    @property
+    def bastion_password(self) -> Optional[str]:
+        """
+        ESXi SSH bastion password config
+        """
+        return __config__.get('bastionPassword')
+
+    @property
+    def bastion_port(self) -> Optional[str]:
+        """
+        ESXi SSH bastion port config
+        """
+        return __config__.get('bastionPort')
+
+    @property
+    def bastion_private_key(self) -> Optional[str]:
+        """
+        ESXi SSH bastion private key (PEM encoded) config
+        """
+        return __config__.get('bastionPrivateKey')
+
+    @property
+    def bastion_private_key_path(self) -> Optional[str]:
+        """
+        ESXi SSH bastion private key path config
+        """
+        return __config__.get('bastionPrivateKeyPath')
+
+    @property
+    def bastion_user(self) -> Optional[str]:
+        """
+        ESXi SSH bastion username config, the ESXi username is used when not set
+        """
+        return __config__.get('bastionUser')
+
+    @property
+    def default_disk_store(self) -> Optional[str]:
+        """
+        The disk store of the virtual machines and of the virtual disks which have none
+        """
+        return __config__.get('defaultDiskStore')
+
+    @property
+    def default_hardware_version(self) -> Optional[int]:
+        """
+        The virtual hardware version of the virtual machines which have none
+        """
+        return __config__.get_int('defaultHardwareVersion')
+
+    @property
+    def default_network(self) -> Optional[str]:
+        """
+        The virtual network of the interface of the virtual machines without networkInterfaces
+        """
+        return __config__.get('defaultNetwork')
+
+    @property
+    def default_os(self) -> Optional[str]:
+        """
+        The guest OS of the virtual machines which have none
+        """
+        return __config__.get('defaultOs')
+
+    @property
+    def default_resource_pool(self) -> Optional[str]:
+        """
+        The resource pool of the virtual machines which have none
+        """
+        return __config__.get('defaultResourcePool')
+
+    @property
+    def dry_run(self) -> Optional[bool]:
+        """
+        ESXi dry-run config, the commands changing the hosts are logged instead of being run
+        """
+        return __config__.get_bool('dryRun')
+
     @property
     def host(self) -> Optional[str]:
         """
@@ -22,6 +142,41 @@ class _ExportableConfig(types.ModuleType):
         """
         return __config__.get('host')
 
+    @property
+    def host_key_fingerprint(self) -> Optional[str]:
+        """
+        ESXi SSH host key SHA256 fingerprint config
+        """
+        return __config__.get('hostKeyFingerprint')
+
+    @property
+    def hosts(self) -> Optional[str]:
+        """
+        ESXi named hosts config, the resources select one with their host property
+        """
+        return __config__.get('hosts')
+
+    @property
+    def insecure_skip_host_key_verification(self) -> Optional[bool]:
+        """
+        ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        """
+        return __config__.get_bool('insecureSkipHostKeyVerification')
+
+    @property
+    def known_hosts_file(self) -> Optional[str]:
+        """
+        ESXi SSH known hosts file config
+        """
+        return __config__.get('knownHostsFile')
+
+    @property
+    def max_concurrent_sessions(self) -> Optional[int]:
+        """
+        ESXi max concurrent sessions config, the number of remote commands run at once on a host
+        """
+        return __config__.get_int('maxConcurrentSessions')
+
     @property
     def password(self) -> Optional[str]:
         """
@@ -29,6 +184,69 @@ class _ExportableConfig(types.ModuleType):
         """
         return __config__.get('password')
 
+    @property
+    def preflight(self) -> Optional[bool]:
+        """
+        ESXi preflight config, the hosts are connected to and checked when the config is checked
+        """
+        return __config__.get_bool('preflight')
+
+    @property
+    def private_key(self) -> Optional[str]:
+        """
+        ESXi SSH private key (PEM encoded) config
+        """
+        return __config__.get('privateKey')
+
+    @property
+    def private_key_passphrase(self) -> Optional[str]:
+        """
+        ESXi SSH private key passphrase config
+        """
+        return __config__.get('privateKeyPassphrase')
+
+    @property
+    def private_key_path(self) -> Optional[str]:
+        """
+        ESXi SSH private key path config
+        """
+        return __config__.get('privateKeyPath')
+
+    @property
+    def read_only(self) -> Optional[bool]:
+        """
+        ESXi read-only config, the commands changing the hosts are refused
+        """
+        return __config__.get_bool('readOnly')
+
+    @property
+    def record_file(self) -> Optional[str]:
+        """
+        ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        """
+        return __config__.get('recordFile')
+
+    @property
+    def retry_attempts(self) -> Optional[int]:
+        """
+        ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        """
+        return __config__.get_int('retryAttempts')
+
+    @property
+    def retry_initial_delay(self) -> Optional[str]:
+        """
+        ESXi retry initial delay config, doubled on every retry
+        """
+        return __config__.get('retryInitialDelay')
+
+    @property
+    def retry_max_delay(self) -> Optional[str]:
+        """
+        ESXi retry maximum delay config
+        """
+        return __config__.get('retryMaxDelay')
+
     @property
     def ssh_port(self) -> Optional[str]:
         """
@@ -43,6 +261,41 @@ class _ExportableConfig(types.ModuleType):
         """
         return __config__.get('sslPort')
 
+    @property
+    def ssl_thumbprint(self) -> Optional[str]:
+        """
+        ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        """
+        return __config__.get('sslThumbprint')
+
+    @property
+    def transport(self) -> Optional[str]:
+        """
+        ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        """
+        return __config__.get('transport')
+
+    @property
+    def trust_on_first_use(self) -> Optional[bool]:
+        """
+        ESXi SSH host key trust on first use config
+        """
+        return __config__.get_bool('trustOnFirstUse')
+
+    @property
+    def trusted_host_keys(self) -> Optional[str]:
+        """
+        ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        """
+        return __config__.get('trustedHostKeys')
+
+    @property
+    def use_ssh_agent(self) -> Optional[bool]:
+        """
+        ESXi SSH agent authentication config
+        """
+        return __config__.get_bool('useSshAgent')
+
     @property
     def username(self) -> Optional[str]:
         """
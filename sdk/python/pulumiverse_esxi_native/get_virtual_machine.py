@@ -20,7 +20,7 @@ __all__ = [
 
 @pulumi.output_type
 class GetVirtualMachineResult:
-    def __init__(__self__, boot_disk_size=None, boot_disk_type=None, boot_firmware=None, disk_store=None, id=None, info=None, ip_address=None, mem_size=None, name=None, network_interfaces=None, notes=None, num_v_cpus=None, os=None, power=None, resource_pool_name=None, shutdown_timeout=None, startup_timeout=None, virtual_disks=None, virtual_hw_ver=None):
+    def __init__(__self__, boot_disk_size=None, boot_disk_type=None, boot_firmware=None, disk_store=None, efi_secure_boot=None, id=None, info=None, ip_address=None, mem_size=None, name=None, network_interfaces=None, notes=None, num_v_cpus=None, os=None, power=None, resource_pool_name=None, shutdown_timeout=None, startup_timeout=None, virtual_disks=None, virtual_hw_ver=None):
         if boot_disk_size and not isinstance(boot_disk_size, int):
             raise TypeError("Expected argument 'boot_disk_size' to be a int")
         pulumi.set(__self__, "boot_disk_size", boot_disk_size)
@@ -33,6 +33,9 @@ class GetVirtualMachineResult:
         if disk_store and not isinstance(disk_store, str):
             raise TypeError("Expected argument 'disk_store' to be a str")
         pulumi.set(__self__, "disk_store", disk_store)
+        if efi_secure_boot and not isinstance(efi_secure_boot, bool):
+            raise TypeError("Expected argument 'efi_secure_boot' to be a bool")
+        pulumi.set(__self__, "efi_secure_boot", efi_secure_boot)
         if id and not isinstance(id, str):
             raise TypeError("Expected argument 'id' to be a str")
         pulumi.set(__self__, "id", id)
@@ -111,6 +114,14 @@ class GetVirtualMachineResult:
         """
         return pulumi.get(self, "disk_store")
 
+    @property
+    @pulumi.getter(name="efiSecureBoot")
+    def efi_secure_boot(self) -> Optional[bool]:
+        """
+        Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        """
+        return pulumi.get(self, "efi_secure_boot")
+
     @property
     @pulumi.getter
     def id(self) -> Optional[str]:
@@ -242,6 +253,7 @@ class AwaitableGetVirtualMachineResult(GetVirtualMachineResult):
             boot_disk_type=self.boot_disk_type,
             boot_firmware=self.boot_firmware,
             disk_store=self.disk_store,
+            efi_secure_boot=self.efi_secure_boot,
             id=self.id,
             info=self.info,
             ip_address=self.ip_address,
@@ -259,14 +271,17 @@ class AwaitableGetVirtualMachineResult(GetVirtualMachineResult):
             virtual_hw_ver=self.virtual_hw_ver)
 
 
-def get_virtual_machine(name: Optional[str] = None,
+def get_virtual_machine(host: Optional[str] = None,
+                        name: Optional[str] = None,
                         opts: Optional[pulumi.InvokeOptions] = None) -> AwaitableGetVirtualMachineResult:
     """
     Use this data source to access information about an existing resource.
 
+    :param str host: Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
     :param str name: Virtual Machine Name to get details of
     """
     __args__ = dict()
+    __args__['host'] = host
     __args__['name'] = name
     opts = pulumi.InvokeOptions.merge(_utilities.get_invoke_opts_defaults(), opts)
     __ret__ = pulumi.runtime.invoke('esxi-native:index:getVirtualMachine', __args__, opts=opts, typ=GetVirtualMachineResult).value
@@ -276,6 +291,7 @@ def get_virtual_machine(name: Optional[str] = None,
         boot_disk_type=pulumi.get(__ret__, 'boot_disk_type'),
         boot_firmware=pulumi.get(__ret__, 'boot_firmware'),
         disk_store=pulumi.get(__ret__, 'disk_store'),
+        efi_secure_boot=pulumi.get(__ret__, 'efi_secure_boot'),
         id=pulumi.get(__ret__, 'id'),
         info=pulumi.get(__ret__, 'info'),
         ip_address=pulumi.get(__ret__, 'ip_address'),
@@ -294,11 +310,13 @@ def get_virtual_machine(name: Optional[str] = None,
 
 
 @_utilities.lift_output_func(get_virtual_machine)
-def get_virtual_machine_output(name: Optional[pulumi.Input[str]] = None,
+def get_virtual_machine_output(host: Optional[pulumi.Input[Optional[str]]] = None,
+                               name: Optional[pulumi.Input[str]] = None,
                                opts: Optional[pulumi.InvokeOptions] = None) -> pulumi.Output[GetVirtualMachineResult]:
     """
     Use this data source to access information about an existing resource.
 
+    :param str host: Name of the host of the hosts provider config the virtual machine is on, the default host when not set.
     :param str name: Virtual Machine Name to get details of
     """
     ...
@@ -11,12 +11,155 @@ from . import _utilities
 from ._enums import *
 
 __all__ = [
+    'HostConnection',
     'KeyValuePair',
     'NetworkInterface',
     'Uplink',
     'VMVirtualDisk',
 ]
 
+@pulumi.output_type
+class HostConnection(dict):
+    """
+    Connection to a named host, the settings not set are the ones of the provider config.
+    """
+    def __init__(__self__, *,
+                 host: str,
+                 host_key_fingerprint: Optional[str] = None,
+                 password: Optional[str] = None,
+                 private_key: Optional[str] = None,
+                 private_key_passphrase: Optional[str] = None,
+                 private_key_path: Optional[str] = None,
+                 ssh_port: Optional[str] = None,
+                 ssl_port: Optional[str] = None,
+                 ssl_thumbprint: Optional[str] = None,
+                 transport: Optional[str] = None,
+                 username: Optional[str] = None):
+        """
+        Connection to a named host, the settings not set are the ones of the provider config.
+        :param str host: ESXi Host Name
+        :param str host_key_fingerprint: ESXi SSH host key SHA256 fingerprint
+        :param str password: ESXi Password
+        :param str private_key: ESXi SSH private key (PEM encoded)
+        :param str private_key_passphrase: ESXi SSH private key passphrase
+        :param str private_key_path: ESXi SSH private key path
+        :param str ssh_port: ESXi Host SSH Port
+        :param str ssl_port: ESXi Host SSL Port
+        :param str ssl_thumbprint: ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        :param str transport: ESXi transport, ssh or api
+        :param str username: ESXi Username
+        """
+        pulumi.set(__self__, "host", host)
+        if host_key_fingerprint is not None:
+            pulumi.set(__self__, "host_key_fingerprint", host_key_fingerprint)
+        if password is not None:
+            pulumi.set(__self__, "password", password)
+        if private_key is not None:
+            pulumi.set(__self__, "private_key", private_key)
+        if private_key_passphrase is not None:
+            pulumi.set(__self__, "private_key_passphrase", private_key_passphrase)
+        if private_key_path is not None:
+            pulumi.set(__self__, "private_key_path", private_key_path)
+        if ssh_port is not None:
+            pulumi.set(__self__, "ssh_port", ssh_port)
+        if ssl_port is not None:
+            pulumi.set(__self__, "ssl_port", ssl_port)
+        if ssl_thumbprint is not None:
+            pulumi.set(__self__, "ssl_thumbprint", ssl_thumbprint)
+        if transport is not None:
+            pulumi.set(__self__, "transport", transport)
+        if username is not None:
+            pulumi.set(__self__, "username", username)
+
+    @property
+    @pulumi.getter
+    def host(self) -> str:
+        """
+        ESXi Host Name
+        """
+        return pulumi.get(self, "host")
+
+    @property
+    @pulumi.getter(name="hostKeyFingerprint")
+    def host_key_fingerprint(self) -> Optional[str]:
+        """
+        ESXi SSH host key SHA256 fingerprint
+        """
+        return pulumi.get(self, "host_key_fingerprint")
+
+    @property
+    @pulumi.getter
+    def password(self) -> Optional[str]:
+        """
+        ESXi Password
+        """
+        return pulumi.get(self, "password")
+
+    @property
+    @pulumi.getter(name="privateKey")
+    def private_key(self) -> Optional[str]:
+        """
+        ESXi SSH private key (PEM encoded)
+        """
+        return pulumi.get(self, "private_key")
+
+    @property
+    @pulumi.getter(name="privateKeyPassphrase")
+    def private_key_passphrase(self) -> Optional[str]:
+        """
+        ESXi SSH private key passphrase
+        """
+        return pulumi.get(self, "private_key_passphrase")
+
+    @property
+    @pulumi.getter(name="privateKeyPath")
+    def private_key_path(self) -> Optional[str]:
+        """
+        ESXi SSH private key path
+        """
+        return pulumi.get(self, "private_key_path")
+
+    @property
+    @pulumi.getter(name="sshPort")
+    def ssh_port(self) -> Optional[str]:
+        """
+        ESXi Host SSH Port
+        """
+        return pulumi.get(self, "ssh_port")
+
+    @property
+    @pulumi.getter(name="sslPort")
+    def ssl_port(self) -> Optional[str]:
+        """
+        ESXi Host SSL Port
+        """
+        return pulumi.get(self, "ssl_port")
+
+    @property
+    @pulumi.getter(name="sslThumbprint")
+    def ssl_thumbprint(self) -> Optional[str]:
+        """
+        ESXi SSL certificate SHA-1 or SHA-256 thumbprint
+        """
+        return pulumi.get(self, "ssl_thumbprint")
+
+    @property
+    @pulumi.getter
+    def transport(self) -> Optional[str]:
+        """
+        ESXi transport, ssh or api
+        """
+        return pulumi.get(self, "transport")
+
+    @property
+    @pulumi.getter
+    def username(self) -> Optional[str]:
+        """
+        ESXi Username
+        """
+        return pulumi.get(self, "username")
+
+
 @pulumi.output_type
 class KeyValuePair(dict):
     def __init__(__self__, *,
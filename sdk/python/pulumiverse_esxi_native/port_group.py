@@ -16,6 +16,7 @@ class PortGroupArgs:
     def __init__(__self__, *,
                  v_switch: pulumi.Input[str],
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  promiscuous_mode: Optional[pulumi.Input[bool]] = None,
@@ -24,6 +25,7 @@ class PortGroupArgs:
         The set of arguments for constructing a PortGroup resource.
         :param pulumi.Input[str] v_switch: Virtual Switch Name.
         :param pulumi.Input[bool] forged_transmits: Forged transmits (true=Accept/false=Reject).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[bool] mac_changes: MAC address changes (true=Accept/false=Reject).
         :param pulumi.Input[str] name: Virtual Switch name.
         :param pulumi.Input[bool] promiscuous_mode: Promiscuous mode (true=Accept/false=Reject).
@@ -32,6 +34,8 @@ class PortGroupArgs:
         pulumi.set(__self__, "v_switch", v_switch)
         if forged_transmits is not None:
             pulumi.set(__self__, "forged_transmits", forged_transmits)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
         if mac_changes is not None:
             pulumi.set(__self__, "mac_changes", mac_changes)
         if name is not None:
@@ -65,6 +69,18 @@ class PortGroupArgs:
     def forged_transmits(self, value: Optional[pulumi.Input[bool]]):
         pulumi.set(self, "forged_transmits", value)
 
+    @property
+    @pulumi.getter
+    def host(self) -> Optional[pulumi.Input[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
+    @host.setter
+    def host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host", value)
+
     @property
     @pulumi.getter(name="macChanges")
     def mac_changes(self) -> Optional[pulumi.Input[bool]]:
@@ -120,6 +136,7 @@ class PortGroup(pulumi.CustomResource):
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  promiscuous_mode: Optional[pulumi.Input[bool]] = None,
@@ -131,6 +148,7 @@ class PortGroup(pulumi.CustomResource):
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
         :param pulumi.Input[bool] forged_transmits: Forged transmits (true=Accept/false=Reject).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[bool] mac_changes: MAC address changes (true=Accept/false=Reject).
         :param pulumi.Input[str] name: Virtual Switch name.
         :param pulumi.Input[bool] promiscuous_mode: Promiscuous mode (true=Accept/false=Reject).
@@ -161,6 +179,7 @@ class PortGroup(pulumi.CustomResource):
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  promiscuous_mode: Optional[pulumi.Input[bool]] = None,
@@ -176,6 +195,7 @@ class PortGroup(pulumi.CustomResource):
             __props__ = PortGroupArgs.__new__(PortGroupArgs)
 
             __props__.__dict__["forged_transmits"] = forged_transmits
+            __props__.__dict__["host"] = host
             __props__.__dict__["mac_changes"] = mac_changes
             __props__.__dict__["name"] = name
             __props__.__dict__["promiscuous_mode"] = promiscuous_mode
@@ -206,6 +226,7 @@ class PortGroup(pulumi.CustomResource):
         __props__ = PortGroupArgs.__new__(PortGroupArgs)
 
         __props__.__dict__["forged_transmits"] = None
+        __props__.__dict__["host"] = None
         __props__.__dict__["mac_changes"] = None
         __props__.__dict__["name"] = None
         __props__.__dict__["promiscuous_mode"] = None
@@ -221,6 +242,14 @@ class PortGroup(pulumi.CustomResource):
         """
         return pulumi.get(self, "forged_transmits")
 
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Output[Optional[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
     @property
     @pulumi.getter(name="macChanges")
     def mac_changes(self) -> pulumi.Output[Optional[bool]]:
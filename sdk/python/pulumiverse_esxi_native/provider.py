@@ -8,27 +8,184 @@ import pulumi
 import pulumi.runtime
 from typing import Any, Mapping, Optional, Sequence, Union, overload
 from . import _utilities
+from ._inputs import *
 
 __all__ = ['ProviderArgs', 'Provider']
 
 @pulumi.input_type
 class ProviderArgs:
     def __init__(__self__, *,
-                 host: pulumi.Input[str],
-                 password: pulumi.Input[str],
+                 auto_naming: Optional[pulumi.Input[bool]] = None,
+                 auto_naming_case: Optional[pulumi.Input[str]] = None,
+                 auto_naming_charset: Optional[pulumi.Input[str]] = None,
+                 auto_naming_pattern: Optional[pulumi.Input[str]] = None,
+                 auto_naming_random_length: Optional[pulumi.Input[int]] = None,
+                 bastion_host: Optional[pulumi.Input[str]] = None,
+                 bastion_password: Optional[pulumi.Input[str]] = None,
+                 bastion_port: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key_path: Optional[pulumi.Input[str]] = None,
+                 bastion_user: Optional[pulumi.Input[str]] = None,
+                 default_disk_store: Optional[pulumi.Input[str]] = None,
+                 default_hardware_version: Optional[pulumi.Input[int]] = None,
+                 default_network: Optional[pulumi.Input[str]] = None,
+                 default_os: Optional[pulumi.Input[str]] = None,
+                 default_resource_pool: Optional[pulumi.Input[str]] = None,
+                 dry_run: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
+                 host_key_fingerprint: Optional[pulumi.Input[str]] = None,
+                 hosts: Optional[pulumi.Input[Mapping[str, pulumi.Input['HostConnectionArgs']]]] = None,
+                 insecure_skip_host_key_verification: Optional[pulumi.Input[bool]] = None,
+                 known_hosts_file: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_sessions: Optional[pulumi.Input[int]] = None,
+                 password: Optional[pulumi.Input[str]] = None,
+                 preflight: Optional[pulumi.Input[bool]] = None,
+                 private_key: Optional[pulumi.Input[str]] = None,
+                 private_key_passphrase: Optional[pulumi.Input[str]] = None,
+                 private_key_path: Optional[pulumi.Input[str]] = None,
+                 read_only: Optional[pulumi.Input[bool]] = None,
+                 record_file: Optional[pulumi.Input[str]] = None,
+                 retry_attempts: Optional[pulumi.Input[int]] = None,
+                 retry_initial_delay: Optional[pulumi.Input[str]] = None,
+                 retry_max_delay: Optional[pulumi.Input[str]] = None,
                  ssh_port: Optional[pulumi.Input[str]] = None,
                  ssl_port: Optional[pulumi.Input[str]] = None,
+                 ssl_thumbprint: Optional[pulumi.Input[str]] = None,
+                 transport: Optional[pulumi.Input[str]] = None,
+                 trust_on_first_use: Optional[pulumi.Input[bool]] = None,
+                 trusted_host_keys: Optional[pulumi.Input[str]] = None,
+                 use_ssh_agent: Optional[pulumi.Input[bool]] = None,
                  username: Optional[pulumi.Input[str]] = None):
         """
         The set of arguments for constructing a Provider resource.
+        :param pulumi.Input[bool] auto_naming: ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        :param pulumi.Input[str] auto_naming_case: The case of the generated names, 'lower' or 'upper'
+        :param pulumi.Input[str] auto_naming_charset: The characters of the random part of the generated names
+        :param pulumi.Input[str] auto_naming_pattern: The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        :param pulumi.Input[int] auto_naming_random_length: The length of the random part of the generated names
+        :param pulumi.Input[str] bastion_host: ESXi SSH bastion (jump host) config, the host is reached through it when set
+        :param pulumi.Input[str] bastion_password: ESXi SSH bastion password config
+        :param pulumi.Input[str] bastion_port: ESXi SSH bastion port config
+        :param pulumi.Input[str] bastion_private_key: ESXi SSH bastion private key (PEM encoded) config
+        :param pulumi.Input[str] bastion_private_key_path: ESXi SSH bastion private key path config
+        :param pulumi.Input[str] bastion_user: ESXi SSH bastion username config, the ESXi username is used when not set
+        :param pulumi.Input[str] default_disk_store: The disk store of the virtual machines and of the virtual disks which have none
+        :param pulumi.Input[int] default_hardware_version: The virtual hardware version of the virtual machines which have none
+        :param pulumi.Input[str] default_network: The virtual network of the interface of the virtual machines without networkInterfaces
+        :param pulumi.Input[str] default_os: The guest OS of the virtual machines which have none
+        :param pulumi.Input[str] default_resource_pool: The resource pool of the virtual machines which have none
+        :param pulumi.Input[bool] dry_run: ESXi dry-run config, the commands changing the hosts are logged instead of being run
         :param pulumi.Input[str] host: ESXi Host Name config
+        :param pulumi.Input[str] host_key_fingerprint: ESXi SSH host key SHA256 fingerprint config
+        :param pulumi.Input[Mapping[str, pulumi.Input['HostConnectionArgs']]] hosts: ESXi named hosts config, the resources select one with their host property
+        :param pulumi.Input[bool] insecure_skip_host_key_verification: ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        :param pulumi.Input[str] known_hosts_file: ESXi SSH known hosts file config
+        :param pulumi.Input[int] max_concurrent_sessions: ESXi max concurrent sessions config, the number of remote commands run at once on a host
         :param pulumi.Input[str] password: ESXi Password config
+        :param pulumi.Input[bool] preflight: ESXi preflight config, the hosts are connected to and checked when the config is checked
+        :param pulumi.Input[str] private_key: ESXi SSH private key (PEM encoded) config
+        :param pulumi.Input[str] private_key_passphrase: ESXi SSH private key passphrase config
+        :param pulumi.Input[str] private_key_path: ESXi SSH private key path config
+        :param pulumi.Input[bool] read_only: ESXi read-only config, the commands changing the hosts are refused
+        :param pulumi.Input[str] record_file: ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        :param pulumi.Input[int] retry_attempts: ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        :param pulumi.Input[str] retry_initial_delay: ESXi retry initial delay config, doubled on every retry
+        :param pulumi.Input[str] retry_max_delay: ESXi retry maximum delay config
         :param pulumi.Input[str] ssh_port: ESXi Host SSH Port config
         :param pulumi.Input[str] ssl_port: ESXi Host SSL Port config
+        :param pulumi.Input[str] ssl_thumbprint: ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        :param pulumi.Input[str] transport: ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        :param pulumi.Input[bool] trust_on_first_use: ESXi SSH host key trust on first use config
+        :param pulumi.Input[str] trusted_host_keys: ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        :param pulumi.Input[bool] use_ssh_agent: ESXi SSH agent authentication config
         :param pulumi.Input[str] username: ESXi Username config
         """
-        pulumi.set(__self__, "host", host)
-        pulumi.set(__self__, "password", password)
+        if auto_naming is None:
+            auto_naming = True
+        if auto_naming is not None:
+            pulumi.set(__self__, "auto_naming", auto_naming)
+        if auto_naming_case is not None:
+            pulumi.set(__self__, "auto_naming_case", auto_naming_case)
+        if auto_naming_charset is not None:
+            pulumi.set(__self__, "auto_naming_charset", auto_naming_charset)
+        if auto_naming_pattern is not None:
+            pulumi.set(__self__, "auto_naming_pattern", auto_naming_pattern)
+        if auto_naming_random_length is not None:
+            pulumi.set(__self__, "auto_naming_random_length", auto_naming_random_length)
+        if bastion_host is not None:
+            pulumi.set(__self__, "bastion_host", bastion_host)
+        if bastion_password is not None:
+            pulumi.set(__self__, "bastion_password", bastion_password)
+        if bastion_port is None:
+            bastion_port = '22'
+        if bastion_port is not None:
+            pulumi.set(__self__, "bastion_port", bastion_port)
+        if bastion_private_key is not None:
+            pulumi.set(__self__, "bastion_private_key", bastion_private_key)
+        if bastion_private_key_path is not None:
+            pulumi.set(__self__, "bastion_private_key_path", bastion_private_key_path)
+        if bastion_user is not None:
+            pulumi.set(__self__, "bastion_user", bastion_user)
+        if default_disk_store is not None:
+            pulumi.set(__self__, "default_disk_store", default_disk_store)
+        if default_hardware_version is not None:
+            pulumi.set(__self__, "default_hardware_version", default_hardware_version)
+        if default_network is not None:
+            pulumi.set(__self__, "default_network", default_network)
+        if default_os is not None:
+            pulumi.set(__self__, "default_os", default_os)
+        if default_resource_pool is not None:
+            pulumi.set(__self__, "default_resource_pool", default_resource_pool)
+        if dry_run is None:
+            dry_run = False
+        if dry_run is not None:
+            pulumi.set(__self__, "dry_run", dry_run)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
+        if host_key_fingerprint is not None:
+            pulumi.set(__self__, "host_key_fingerprint", host_key_fingerprint)
+        if hosts is not None:
+            pulumi.set(__self__, "hosts", hosts)
+        if insecure_skip_host_key_verification is None:
+            insecure_skip_host_key_verification = False
+        if insecure_skip_host_key_verification is not None:
+            pulumi.set(__self__, "insecure_skip_host_key_verification", insecure_skip_host_key_verification)
+        if known_hosts_file is not None:
+            pulumi.set(__self__, "known_hosts_file", known_hosts_file)
+        if max_concurrent_sessions is None:
+            max_concurrent_sessions = 8
+        if max_concurrent_sessions is not None:
+            pulumi.set(__self__, "max_concurrent_sessions", max_concurrent_sessions)
+        if password is not None:
+            pulumi.set(__self__, "password", password)
+        if preflight is None:
+            preflight = False
+        if preflight is not None:
+            pulumi.set(__self__, "preflight", preflight)
+        if private_key is not None:
+            pulumi.set(__self__, "private_key", private_key)
+        if private_key_passphrase is not None:
+            pulumi.set(__self__, "private_key_passphrase", private_key_passphrase)
+        if private_key_path is not None:
+            pulumi.set(__self__, "private_key_path", private_key_path)
+        if read_only is None:
+            read_only = False
+        if read_only is not None:
+            pulumi.set(__self__, "read_only", read_only)
+        if record_file is not None:
+            pulumi.set(__self__, "record_file", record_file)
+        if retry_attempts is None:
+            retry_attempts = 6
+        if retry_attempts is not None:
+            pulumi.set(__self__, "retry_attempts", retry_attempts)
+        if retry_initial_delay is None:
+            retry_initial_delay = '1s'
+        if retry_initial_delay is not None:
+            pulumi.set(__self__, "retry_initial_delay", retry_initial_delay)
+        if retry_max_delay is None:
+            retry_max_delay = '30s'
+        if retry_max_delay is not None:
+            pulumi.set(__self__, "retry_max_delay", retry_max_delay)
         if ssh_port is None:
             ssh_port = '22'
         if ssh_port is not None:
@@ -37,35 +194,423 @@ class ProviderArgs:
             ssl_port = '443'
         if ssl_port is not None:
             pulumi.set(__self__, "ssl_port", ssl_port)
+        if ssl_thumbprint is not None:
+            pulumi.set(__self__, "ssl_thumbprint", ssl_thumbprint)
+        if transport is None:
+            transport = 'ssh'
+        if transport is not None:
+            pulumi.set(__self__, "transport", transport)
+        if trust_on_first_use is None:
+            trust_on_first_use = False
+        if trust_on_first_use is not None:
+            pulumi.set(__self__, "trust_on_first_use", trust_on_first_use)
+        if trusted_host_keys is not None:
+            pulumi.set(__self__, "trusted_host_keys", trusted_host_keys)
+        if use_ssh_agent is None:
+            use_ssh_agent = False
+        if use_ssh_agent is not None:
+            pulumi.set(__self__, "use_ssh_agent", use_ssh_agent)
         if username is None:
             username = 'root'
         if username is not None:
             pulumi.set(__self__, "username", username)
 
+    @property
+    @pulumi.getter(name="autoNaming")
+    def auto_naming(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        """
+        return pulumi.get(self, "auto_naming")
+
+    @auto_naming.setter
+    def auto_naming(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "auto_naming", value)
+
+    @property
+    @pulumi.getter(name="autoNamingCase")
+    def auto_naming_case(self) -> Optional[pulumi.Input[str]]:
+        """
+        The case of the generated names, 'lower' or 'upper'
+        """
+        return pulumi.get(self, "auto_naming_case")
+
+    @auto_naming_case.setter
+    def auto_naming_case(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "auto_naming_case", value)
+
+    @property
+    @pulumi.getter(name="autoNamingCharset")
+    def auto_naming_charset(self) -> Optional[pulumi.Input[str]]:
+        """
+        The characters of the random part of the generated names
+        """
+        return pulumi.get(self, "auto_naming_charset")
+
+    @auto_naming_charset.setter
+    def auto_naming_charset(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "auto_naming_charset", value)
+
+    @property
+    @pulumi.getter(name="autoNamingPattern")
+    def auto_naming_pattern(self) -> Optional[pulumi.Input[str]]:
+        """
+        The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        """
+        return pulumi.get(self, "auto_naming_pattern")
+
+    @auto_naming_pattern.setter
+    def auto_naming_pattern(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "auto_naming_pattern", value)
+
+    @property
+    @pulumi.getter(name="autoNamingRandomLength")
+    def auto_naming_random_length(self) -> Optional[pulumi.Input[int]]:
+        """
+        The length of the random part of the generated names
+        """
+        return pulumi.get(self, "auto_naming_random_length")
+
+    @auto_naming_random_length.setter
+    def auto_naming_random_length(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "auto_naming_random_length", value)
+
+    @property
+    @pulumi.getter(name="bastionHost")
+    def bastion_host(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion (jump host) config, the host is reached through it when set
+        """
+        return pulumi.get(self, "bastion_host")
+
+    @bastion_host.setter
+    def bastion_host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_host", value)
+
+    @property
+    @pulumi.getter(name="bastionPassword")
+    def bastion_password(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion password config
+        """
+        return pulumi.get(self, "bastion_password")
+
+    @bastion_password.setter
+    def bastion_password(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_password", value)
+
+    @property
+    @pulumi.getter(name="bastionPort")
+    def bastion_port(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion port config
+        """
+        return pulumi.get(self, "bastion_port")
+
+    @bastion_port.setter
+    def bastion_port(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_port", value)
+
+    @property
+    @pulumi.getter(name="bastionPrivateKey")
+    def bastion_private_key(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion private key (PEM encoded) config
+        """
+        return pulumi.get(self, "bastion_private_key")
+
+    @bastion_private_key.setter
+    def bastion_private_key(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_private_key", value)
+
+    @property
+    @pulumi.getter(name="bastionPrivateKeyPath")
+    def bastion_private_key_path(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion private key path config
+        """
+        return pulumi.get(self, "bastion_private_key_path")
+
+    @bastion_private_key_path.setter
+    def bastion_private_key_path(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_private_key_path", value)
+
+    @property
+    @pulumi.getter(name="bastionUser")
+    def bastion_user(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH bastion username config, the ESXi username is used when not set
+        """
+        return pulumi.get(self, "bastion_user")
+
+    @bastion_user.setter
+    def bastion_user(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "bastion_user", value)
+
+    @property
+    @pulumi.getter(name="defaultDiskStore")
+    def default_disk_store(self) -> Optional[pulumi.Input[str]]:
+        """
+        The disk store of the virtual machines and of the virtual disks which have none
+        """
+        return pulumi.get(self, "default_disk_store")
+
+    @default_disk_store.setter
+    def default_disk_store(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "default_disk_store", value)
+
+    @property
+    @pulumi.getter(name="defaultHardwareVersion")
+    def default_hardware_version(self) -> Optional[pulumi.Input[int]]:
+        """
+        The virtual hardware version of the virtual machines which have none
+        """
+        return pulumi.get(self, "default_hardware_version")
+
+    @default_hardware_version.setter
+    def default_hardware_version(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "default_hardware_version", value)
+
+    @property
+    @pulumi.getter(name="defaultNetwork")
+    def default_network(self) -> Optional[pulumi.Input[str]]:
+        """
+        The virtual network of the interface of the virtual machines without networkInterfaces
+        """
+        return pulumi.get(self, "default_network")
+
+    @default_network.setter
+    def default_network(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "default_network", value)
+
+    @property
+    @pulumi.getter(name="defaultOs")
+    def default_os(self) -> Optional[pulumi.Input[str]]:
+        """
+        The guest OS of the virtual machines which have none
+        """
+        return pulumi.get(self, "default_os")
+
+    @default_os.setter
+    def default_os(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "default_os", value)
+
+    @property
+    @pulumi.getter(name="defaultResourcePool")
+    def default_resource_pool(self) -> Optional[pulumi.Input[str]]:
+        """
+        The resource pool of the virtual machines which have none
+        """
+        return pulumi.get(self, "default_resource_pool")
+
+    @default_resource_pool.setter
+    def default_resource_pool(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "default_resource_pool", value)
+
+    @property
+    @pulumi.getter(name="dryRun")
+    def dry_run(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi dry-run config, the commands changing the hosts are logged instead of being run
+        """
+        return pulumi.get(self, "dry_run")
+
+    @dry_run.setter
+    def dry_run(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "dry_run", value)
+
     @property
     @pulumi.getter
-    def host(self) -> pulumi.Input[str]:
+    def host(self) -> Optional[pulumi.Input[str]]:
         """
         ESXi Host Name config
         """
         return pulumi.get(self, "host")
 
     @host.setter
-    def host(self, value: pulumi.Input[str]):
+    def host(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "host", value)
 
+    @property
+    @pulumi.getter(name="hostKeyFingerprint")
+    def host_key_fingerprint(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH host key SHA256 fingerprint config
+        """
+        return pulumi.get(self, "host_key_fingerprint")
+
+    @host_key_fingerprint.setter
+    def host_key_fingerprint(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host_key_fingerprint", value)
+
+    @property
+    @pulumi.getter
+    def hosts(self) -> Optional[pulumi.Input[Mapping[str, pulumi.Input['HostConnectionArgs']]]]:
+        """
+        ESXi named hosts config, the resources select one with their host property
+        """
+        return pulumi.get(self, "hosts")
+
+    @hosts.setter
+    def hosts(self, value: Optional[pulumi.Input[Mapping[str, pulumi.Input['HostConnectionArgs']]]]):
+        pulumi.set(self, "hosts", value)
+
+    @property
+    @pulumi.getter(name="insecureSkipHostKeyVerification")
+    def insecure_skip_host_key_verification(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        """
+        return pulumi.get(self, "insecure_skip_host_key_verification")
+
+    @insecure_skip_host_key_verification.setter
+    def insecure_skip_host_key_verification(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "insecure_skip_host_key_verification", value)
+
+    @property
+    @pulumi.getter(name="knownHostsFile")
+    def known_hosts_file(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH known hosts file config
+        """
+        return pulumi.get(self, "known_hosts_file")
+
+    @known_hosts_file.setter
+    def known_hosts_file(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "known_hosts_file", value)
+
+    @property
+    @pulumi.getter(name="maxConcurrentSessions")
+    def max_concurrent_sessions(self) -> Optional[pulumi.Input[int]]:
+        """
+        ESXi max concurrent sessions config, the number of remote commands run at once on a host
+        """
+        return pulumi.get(self, "max_concurrent_sessions")
+
+    @max_concurrent_sessions.setter
+    def max_concurrent_sessions(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "max_concurrent_sessions", value)
+
     @property
     @pulumi.getter
-    def password(self) -> pulumi.Input[str]:
+    def password(self) -> Optional[pulumi.Input[str]]:
         """
         ESXi Password config
         """
         return pulumi.get(self, "password")
 
     @password.setter
-    def password(self, value: pulumi.Input[str]):
+    def password(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "password", value)
 
+    @property
+    @pulumi.getter
+    def preflight(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi preflight config, the hosts are connected to and checked when the config is checked
+        """
+        return pulumi.get(self, "preflight")
+
+    @preflight.setter
+    def preflight(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "preflight", value)
+
+    @property
+    @pulumi.getter(name="privateKey")
+    def private_key(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key (PEM encoded) config
+        """
+        return pulumi.get(self, "private_key")
+
+    @private_key.setter
+    def private_key(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key", value)
+
+    @property
+    @pulumi.getter(name="privateKeyPassphrase")
+    def private_key_passphrase(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key passphrase config
+        """
+        return pulumi.get(self, "private_key_passphrase")
+
+    @private_key_passphrase.setter
+    def private_key_passphrase(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key_passphrase", value)
+
+    @property
+    @pulumi.getter(name="privateKeyPath")
+    def private_key_path(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH private key path config
+        """
+        return pulumi.get(self, "private_key_path")
+
+    @private_key_path.setter
+    def private_key_path(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "private_key_path", value)
+
+    @property
+    @pulumi.getter(name="readOnly")
+    def read_only(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi read-only config, the commands changing the hosts are refused
+        """
+        return pulumi.get(self, "read_only")
+
+    @read_only.setter
+    def read_only(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "read_only", value)
+
+    @property
+    @pulumi.getter(name="recordFile")
+    def record_file(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        """
+        return pulumi.get(self, "record_file")
+
+    @record_file.setter
+    def record_file(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "record_file", value)
+
+    @property
+    @pulumi.getter(name="retryAttempts")
+    def retry_attempts(self) -> Optional[pulumi.Input[int]]:
+        """
+        ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        """
+        return pulumi.get(self, "retry_attempts")
+
+    @retry_attempts.setter
+    def retry_attempts(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "retry_attempts", value)
+
+    @property
+    @pulumi.getter(name="retryInitialDelay")
+    def retry_initial_delay(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi retry initial delay config, doubled on every retry
+        """
+        return pulumi.get(self, "retry_initial_delay")
+
+    @retry_initial_delay.setter
+    def retry_initial_delay(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "retry_initial_delay", value)
+
+    @property
+    @pulumi.getter(name="retryMaxDelay")
+    def retry_max_delay(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi retry maximum delay config
+        """
+        return pulumi.get(self, "retry_max_delay")
+
+    @retry_max_delay.setter
+    def retry_max_delay(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "retry_max_delay", value)
+
     @property
     @pulumi.getter(name="sshPort")
     def ssh_port(self) -> Optional[pulumi.Input[str]]:
@@ -90,6 +635,66 @@ class ProviderArgs:
     def ssl_port(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "ssl_port", value)
 
+    @property
+    @pulumi.getter(name="sslThumbprint")
+    def ssl_thumbprint(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        """
+        return pulumi.get(self, "ssl_thumbprint")
+
+    @ssl_thumbprint.setter
+    def ssl_thumbprint(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ssl_thumbprint", value)
+
+    @property
+    @pulumi.getter
+    def transport(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        """
+        return pulumi.get(self, "transport")
+
+    @transport.setter
+    def transport(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "transport", value)
+
+    @property
+    @pulumi.getter(name="trustOnFirstUse")
+    def trust_on_first_use(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi SSH host key trust on first use config
+        """
+        return pulumi.get(self, "trust_on_first_use")
+
+    @trust_on_first_use.setter
+    def trust_on_first_use(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "trust_on_first_use", value)
+
+    @property
+    @pulumi.getter(name="trustedHostKeys")
+    def trusted_host_keys(self) -> Optional[pulumi.Input[str]]:
+        """
+        ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        """
+        return pulumi.get(self, "trusted_host_keys")
+
+    @trusted_host_keys.setter
+    def trusted_host_keys(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "trusted_host_keys", value)
+
+    @property
+    @pulumi.getter(name="useSshAgent")
+    def use_ssh_agent(self) -> Optional[pulumi.Input[bool]]:
+        """
+        ESXi SSH agent authentication config
+        """
+        return pulumi.get(self, "use_ssh_agent")
+
+    @use_ssh_agent.setter
+    def use_ssh_agent(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "use_ssh_agent", value)
+
     @property
     @pulumi.getter
     def username(self) -> Optional[pulumi.Input[str]]:
@@ -108,10 +713,46 @@ class Provider(pulumi.ProviderResource):
     def __init__(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 auto_naming: Optional[pulumi.Input[bool]] = None,
+                 auto_naming_case: Optional[pulumi.Input[str]] = None,
+                 auto_naming_charset: Optional[pulumi.Input[str]] = None,
+                 auto_naming_pattern: Optional[pulumi.Input[str]] = None,
+                 auto_naming_random_length: Optional[pulumi.Input[int]] = None,
+                 bastion_host: Optional[pulumi.Input[str]] = None,
+                 bastion_password: Optional[pulumi.Input[str]] = None,
+                 bastion_port: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key_path: Optional[pulumi.Input[str]] = None,
+                 bastion_user: Optional[pulumi.Input[str]] = None,
+                 default_disk_store: Optional[pulumi.Input[str]] = None,
+                 default_hardware_version: Optional[pulumi.Input[int]] = None,
+                 default_network: Optional[pulumi.Input[str]] = None,
+                 default_os: Optional[pulumi.Input[str]] = None,
+                 default_resource_pool: Optional[pulumi.Input[str]] = None,
+                 dry_run: Optional[pulumi.Input[bool]] = None,
                  host: Optional[pulumi.Input[str]] = None,
+                 host_key_fingerprint: Optional[pulumi.Input[str]] = None,
+                 hosts: Optional[pulumi.Input[Mapping[str, pulumi.Input[pulumi.InputType['HostConnectionArgs']]]]] = None,
+                 insecure_skip_host_key_verification: Optional[pulumi.Input[bool]] = None,
+                 known_hosts_file: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_sessions: Optional[pulumi.Input[int]] = None,
                  password: Optional[pulumi.Input[str]] = None,
+                 preflight: Optional[pulumi.Input[bool]] = None,
+                 private_key: Optional[pulumi.Input[str]] = None,
+                 private_key_passphrase: Optional[pulumi.Input[str]] = None,
+                 private_key_path: Optional[pulumi.Input[str]] = None,
+                 read_only: Optional[pulumi.Input[bool]] = None,
+                 record_file: Optional[pulumi.Input[str]] = None,
+                 retry_attempts: Optional[pulumi.Input[int]] = None,
+                 retry_initial_delay: Optional[pulumi.Input[str]] = None,
+                 retry_max_delay: Optional[pulumi.Input[str]] = None,
                  ssh_port: Optional[pulumi.Input[str]] = None,
                  ssl_port: Optional[pulumi.Input[str]] = None,
+                 ssl_thumbprint: Optional[pulumi.Input[str]] = None,
+                 transport: Optional[pulumi.Input[str]] = None,
+                 trust_on_first_use: Optional[pulumi.Input[bool]] = None,
+                 trusted_host_keys: Optional[pulumi.Input[str]] = None,
+                 use_ssh_agent: Optional[pulumi.Input[bool]] = None,
                  username: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         """
@@ -119,17 +760,53 @@ class Provider(pulumi.ProviderResource):
 
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[bool] auto_naming: ESXi auto-naming config, the resources without a name get a generated one, else their check fails
+        :param pulumi.Input[str] auto_naming_case: The case of the generated names, 'lower' or 'upper'
+        :param pulumi.Input[str] auto_naming_charset: The characters of the random part of the generated names
+        :param pulumi.Input[str] auto_naming_pattern: The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        :param pulumi.Input[int] auto_naming_random_length: The length of the random part of the generated names
+        :param pulumi.Input[str] bastion_host: ESXi SSH bastion (jump host) config, the host is reached through it when set
+        :param pulumi.Input[str] bastion_password: ESXi SSH bastion password config
+        :param pulumi.Input[str] bastion_port: ESXi SSH bastion port config
+        :param pulumi.Input[str] bastion_private_key: ESXi SSH bastion private key (PEM encoded) config
+        :param pulumi.Input[str] bastion_private_key_path: ESXi SSH bastion private key path config
+        :param pulumi.Input[str] bastion_user: ESXi SSH bastion username config, the ESXi username is used when not set
+        :param pulumi.Input[str] default_disk_store: The disk store of the virtual machines and of the virtual disks which have none
+        :param pulumi.Input[int] default_hardware_version: The virtual hardware version of the virtual machines which have none
+        :param pulumi.Input[str] default_network: The virtual network of the interface of the virtual machines without networkInterfaces
+        :param pulumi.Input[str] default_os: The guest OS of the virtual machines which have none
+        :param pulumi.Input[str] default_resource_pool: The resource pool of the virtual machines which have none
+        :param pulumi.Input[bool] dry_run: ESXi dry-run config, the commands changing the hosts are logged instead of being run
         :param pulumi.Input[str] host: ESXi Host Name config
+        :param pulumi.Input[str] host_key_fingerprint: ESXi SSH host key SHA256 fingerprint config
+        :param pulumi.Input[Mapping[str, pulumi.Input[pulumi.InputType['HostConnectionArgs']]]] hosts: ESXi named hosts config, the resources select one with their host property
+        :param pulumi.Input[bool] insecure_skip_host_key_verification: ESXi SSH host key and SSL certificate verification is skipped, the connections can be intercepted
+        :param pulumi.Input[str] known_hosts_file: ESXi SSH known hosts file config
+        :param pulumi.Input[int] max_concurrent_sessions: ESXi max concurrent sessions config, the number of remote commands run at once on a host
         :param pulumi.Input[str] password: ESXi Password config
+        :param pulumi.Input[bool] preflight: ESXi preflight config, the hosts are connected to and checked when the config is checked
+        :param pulumi.Input[str] private_key: ESXi SSH private key (PEM encoded) config
+        :param pulumi.Input[str] private_key_passphrase: ESXi SSH private key passphrase config
+        :param pulumi.Input[str] private_key_path: ESXi SSH private key path config
+        :param pulumi.Input[bool] read_only: ESXi read-only config, the commands changing the hosts are refused
+        :param pulumi.Input[str] record_file: ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        :param pulumi.Input[int] retry_attempts: ESXi retry attempts config, the number of runs of the connections and of the remote commands safe to retry
+        :param pulumi.Input[str] retry_initial_delay: ESXi retry initial delay config, doubled on every retry
+        :param pulumi.Input[str] retry_max_delay: ESXi retry maximum delay config
         :param pulumi.Input[str] ssh_port: ESXi Host SSH Port config
         :param pulumi.Input[str] ssl_port: ESXi Host SSL Port config
+        :param pulumi.Input[str] ssl_thumbprint: ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        :param pulumi.Input[str] transport: ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        :param pulumi.Input[bool] trust_on_first_use: ESXi SSH host key trust on first use config
+        :param pulumi.Input[str] trusted_host_keys: ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        :param pulumi.Input[bool] use_ssh_agent: ESXi SSH agent authentication config
         :param pulumi.Input[str] username: ESXi Username config
         """
         ...
     @overload
     def __init__(__self__,
                  resource_name: str,
-                 args: ProviderArgs,
+                 args: Optional[ProviderArgs] = None,
                  opts: Optional[pulumi.ResourceOptions] = None):
         """
         The provider type for the ESXi native package. By default, resources use package-wide configuration settings, however an explicit `Provider` instance may be created and passed during resource construction to achieve fine-grained programmatic control over provider settings. See the [documentation](https://www.pulumi.com/docs/reference/programming-model/#providers) for more information.
@@ -149,10 +826,46 @@ class Provider(pulumi.ProviderResource):
     def _internal_init(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 auto_naming: Optional[pulumi.Input[bool]] = None,
+                 auto_naming_case: Optional[pulumi.Input[str]] = None,
+                 auto_naming_charset: Optional[pulumi.Input[str]] = None,
+                 auto_naming_pattern: Optional[pulumi.Input[str]] = None,
+                 auto_naming_random_length: Optional[pulumi.Input[int]] = None,
+                 bastion_host: Optional[pulumi.Input[str]] = None,
+                 bastion_password: Optional[pulumi.Input[str]] = None,
+                 bastion_port: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key: Optional[pulumi.Input[str]] = None,
+                 bastion_private_key_path: Optional[pulumi.Input[str]] = None,
+                 bastion_user: Optional[pulumi.Input[str]] = None,
+                 default_disk_store: Optional[pulumi.Input[str]] = None,
+                 default_hardware_version: Optional[pulumi.Input[int]] = None,
+                 default_network: Optional[pulumi.Input[str]] = None,
+                 default_os: Optional[pulumi.Input[str]] = None,
+                 default_resource_pool: Optional[pulumi.Input[str]] = None,
+                 dry_run: Optional[pulumi.Input[bool]] = None,
                  host: Optional[pulumi.Input[str]] = None,
+                 host_key_fingerprint: Optional[pulumi.Input[str]] = None,
+                 hosts: Optional[pulumi.Input[Mapping[str, pulumi.Input[pulumi.InputType['HostConnectionArgs']]]]] = None,
+                 insecure_skip_host_key_verification: Optional[pulumi.Input[bool]] = None,
+                 known_hosts_file: Optional[pulumi.Input[str]] = None,
+                 max_concurrent_sessions: Optional[pulumi.Input[int]] = None,
                  password: Optional[pulumi.Input[str]] = None,
+                 preflight: Optional[pulumi.Input[bool]] = None,
+                 private_key: Optional[pulumi.Input[str]] = None,
+                 private_key_passphrase: Optional[pulumi.Input[str]] = None,
+                 private_key_path: Optional[pulumi.Input[str]] = None,
+                 read_only: Optional[pulumi.Input[bool]] = None,
+                 record_file: Optional[pulumi.Input[str]] = None,
+                 retry_attempts: Optional[pulumi.Input[int]] = None,
+                 retry_initial_delay: Optional[pulumi.Input[str]] = None,
+                 retry_max_delay: Optional[pulumi.Input[str]] = None,
                  ssh_port: Optional[pulumi.Input[str]] = None,
                  ssl_port: Optional[pulumi.Input[str]] = None,
+                 ssl_thumbprint: Optional[pulumi.Input[str]] = None,
+                 transport: Optional[pulumi.Input[str]] = None,
+                 trust_on_first_use: Optional[pulumi.Input[bool]] = None,
+                 trusted_host_keys: Optional[pulumi.Input[str]] = None,
+                 use_ssh_agent: Optional[pulumi.Input[bool]] = None,
                  username: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         opts = pulumi.ResourceOptions.merge(_utilities.get_resource_opts_defaults(), opts)
@@ -163,18 +876,76 @@ class Provider(pulumi.ProviderResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = ProviderArgs.__new__(ProviderArgs)
 
-            if host is None and not opts.urn:
-                raise TypeError("Missing required property 'host'")
+            if auto_naming is None:
+                auto_naming = True
+            __props__.__dict__["auto_naming"] = pulumi.Output.from_input(auto_naming).apply(pulumi.runtime.to_json) if auto_naming is not None else None
+            __props__.__dict__["auto_naming_case"] = auto_naming_case
+            __props__.__dict__["auto_naming_charset"] = auto_naming_charset
+            __props__.__dict__["auto_naming_pattern"] = auto_naming_pattern
+            __props__.__dict__["auto_naming_random_length"] = pulumi.Output.from_input(auto_naming_random_length).apply(pulumi.runtime.to_json) if auto_naming_random_length is not None else None
+            __props__.__dict__["bastion_host"] = bastion_host
+            __props__.__dict__["bastion_password"] = bastion_password
+            if bastion_port is None:
+                bastion_port = '22'
+            __props__.__dict__["bastion_port"] = bastion_port
+            __props__.__dict__["bastion_private_key"] = bastion_private_key
+            __props__.__dict__["bastion_private_key_path"] = bastion_private_key_path
+            __props__.__dict__["bastion_user"] = bastion_user
+            __props__.__dict__["default_disk_store"] = default_disk_store
+            __props__.__dict__["default_hardware_version"] = pulumi.Output.from_input(default_hardware_version).apply(pulumi.runtime.to_json) if default_hardware_version is not None else None
+            __props__.__dict__["default_network"] = default_network
+            __props__.__dict__["default_os"] = default_os
+            __props__.__dict__["default_resource_pool"] = default_resource_pool
+            if dry_run is None:
+                dry_run = False
+            __props__.__dict__["dry_run"] = pulumi.Output.from_input(dry_run).apply(pulumi.runtime.to_json) if dry_run is not None else None
             __props__.__dict__["host"] = host
-            if password is None and not opts.urn:
-                raise TypeError("Missing required property 'password'")
+            __props__.__dict__["host_key_fingerprint"] = host_key_fingerprint
+            __props__.__dict__["hosts"] = pulumi.Output.from_input(hosts).apply(pulumi.runtime.to_json) if hosts is not None else None
+            if insecure_skip_host_key_verification is None:
+                insecure_skip_host_key_verification = False
+            __props__.__dict__["insecure_skip_host_key_verification"] = pulumi.Output.from_input(insecure_skip_host_key_verification).apply(pulumi.runtime.to_json) if insecure_skip_host_key_verification is not None else None
+            __props__.__dict__["known_hosts_file"] = known_hosts_file
+            if max_concurrent_sessions is None:
+                max_concurrent_sessions = 8
+            __props__.__dict__["max_concurrent_sessions"] = pulumi.Output.from_input(max_concurrent_sessions).apply(pulumi.runtime.to_json) if max_concurrent_sessions is not None else None
             __props__.__dict__["password"] = password
+            if preflight is None:
+                preflight = False
+            __props__.__dict__["preflight"] = pulumi.Output.from_input(preflight).apply(pulumi.runtime.to_json) if preflight is not None else None
+            __props__.__dict__["private_key"] = private_key
+            __props__.__dict__["private_key_passphrase"] = private_key_passphrase
+            __props__.__dict__["private_key_path"] = private_key_path
+            if read_only is None:
+                read_only = False
+            __props__.__dict__["read_only"] = pulumi.Output.from_input(read_only).apply(pulumi.runtime.to_json) if read_only is not None else None
+            __props__.__dict__["record_file"] = record_file
+            if retry_attempts is None:
+                retry_attempts = 6
+            __props__.__dict__["retry_attempts"] = pulumi.Output.from_input(retry_attempts).apply(pulumi.runtime.to_json) if retry_attempts is not None else None
+            if retry_initial_delay is None:
+                retry_initial_delay = '1s'
+            __props__.__dict__["retry_initial_delay"] = retry_initial_delay
+            if retry_max_delay is None:
+                retry_max_delay = '30s'
+            __props__.__dict__["retry_max_delay"] = retry_max_delay
             if ssh_port is None:
                 ssh_port = '22'
             __props__.__dict__["ssh_port"] = ssh_port
             if ssl_port is None:
                 ssl_port = '443'
             __props__.__dict__["ssl_port"] = ssl_port
+            __props__.__dict__["ssl_thumbprint"] = ssl_thumbprint
+            if transport is None:
+                transport = 'ssh'
+            __props__.__dict__["transport"] = transport
+            if trust_on_first_use is None:
+                trust_on_first_use = False
+            __props__.__dict__["trust_on_first_use"] = pulumi.Output.from_input(trust_on_first_use).apply(pulumi.runtime.to_json) if trust_on_first_use is not None else None
+            __props__.__dict__["trusted_host_keys"] = trusted_host_keys
+            if use_ssh_agent is None:
+                use_ssh_agent = False
+            __props__.__dict__["use_ssh_agent"] = pulumi.Output.from_input(use_ssh_agent).apply(pulumi.runtime.to_json) if use_ssh_agent is not None else None
             if username is None:
                 username = 'root'
             __props__.__dict__["username"] = username
@@ -184,22 +955,190 @@ class Provider(pulumi.ProviderResource):
             __props__,
             opts)
 
+    @property
+    @pulumi.getter(name="autoNamingCase")
+    def auto_naming_case(self) -> pulumi.Output[Optional[str]]:
+        """
+        The case of the generated names, 'lower' or 'upper'
+        """
+        return pulumi.get(self, "auto_naming_case")
+
+    @property
+    @pulumi.getter(name="autoNamingCharset")
+    def auto_naming_charset(self) -> pulumi.Output[Optional[str]]:
+        """
+        The characters of the random part of the generated names
+        """
+        return pulumi.get(self, "auto_naming_charset")
+
+    @property
+    @pulumi.getter(name="autoNamingPattern")
+    def auto_naming_pattern(self) -> pulumi.Output[Optional[str]]:
+        """
+        The pattern of the generated names, with the ${name}, ${stack}, ${project} and ${random} placeholders
+        """
+        return pulumi.get(self, "auto_naming_pattern")
+
+    @property
+    @pulumi.getter(name="bastionHost")
+    def bastion_host(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion (jump host) config, the host is reached through it when set
+        """
+        return pulumi.get(self, "bastion_host")
+
+    @property
+    @pulumi.getter(name="bastionPassword")
+    def bastion_password(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion password config
+        """
+        return pulumi.get(self, "bastion_password")
+
+    @property
+    @pulumi.getter(name="bastionPort")
+    def bastion_port(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion port config
+        """
+        return pulumi.get(self, "bastion_port")
+
+    @property
+    @pulumi.getter(name="bastionPrivateKey")
+    def bastion_private_key(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion private key (PEM encoded) config
+        """
+        return pulumi.get(self, "bastion_private_key")
+
+    @property
+    @pulumi.getter(name="bastionPrivateKeyPath")
+    def bastion_private_key_path(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion private key path config
+        """
+        return pulumi.get(self, "bastion_private_key_path")
+
+    @property
+    @pulumi.getter(name="bastionUser")
+    def bastion_user(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH bastion username config, the ESXi username is used when not set
+        """
+        return pulumi.get(self, "bastion_user")
+
+    @property
+    @pulumi.getter(name="defaultDiskStore")
+    def default_disk_store(self) -> pulumi.Output[Optional[str]]:
+        """
+        The disk store of the virtual machines and of the virtual disks which have none
+        """
+        return pulumi.get(self, "default_disk_store")
+
+    @property
+    @pulumi.getter(name="defaultNetwork")
+    def default_network(self) -> pulumi.Output[Optional[str]]:
+        """
+        The virtual network of the interface of the virtual machines without networkInterfaces
+        """
+        return pulumi.get(self, "default_network")
+
+    @property
+    @pulumi.getter(name="defaultOs")
+    def default_os(self) -> pulumi.Output[Optional[str]]:
+        """
+        The guest OS of the virtual machines which have none
+        """
+        return pulumi.get(self, "default_os")
+
+    @property
+    @pulumi.getter(name="defaultResourcePool")
+    def default_resource_pool(self) -> pulumi.Output[Optional[str]]:
+        """
+        The resource pool of the virtual machines which have none
+        """
+        return pulumi.get(self, "default_resource_pool")
+
     @property
     @pulumi.getter
-    def host(self) -> pulumi.Output[str]:
+    def host(self) -> pulumi.Output[Optional[str]]:
         """
         ESXi Host Name config
         """
         return pulumi.get(self, "host")
 
+    @property
+    @pulumi.getter(name="hostKeyFingerprint")
+    def host_key_fingerprint(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH host key SHA256 fingerprint config
+        """
+        return pulumi.get(self, "host_key_fingerprint")
+
+    @property
+    @pulumi.getter(name="knownHostsFile")
+    def known_hosts_file(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH known hosts file config
+        """
+        return pulumi.get(self, "known_hosts_file")
+
     @property
     @pulumi.getter
-    def password(self) -> pulumi.Output[str]:
+    def password(self) -> pulumi.Output[Optional[str]]:
         """
         ESXi Password config
         """
         return pulumi.get(self, "password")
 
+    @property
+    @pulumi.getter(name="privateKey")
+    def private_key(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH private key (PEM encoded) config
+        """
+        return pulumi.get(self, "private_key")
+
+    @property
+    @pulumi.getter(name="privateKeyPassphrase")
+    def private_key_passphrase(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH private key passphrase config
+        """
+        return pulumi.get(self, "private_key_passphrase")
+
+    @property
+    @pulumi.getter(name="privateKeyPath")
+    def private_key_path(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH private key path config
+        """
+        return pulumi.get(self, "private_key_path")
+
+    @property
+    @pulumi.getter(name="recordFile")
+    def record_file(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi record file config, the remote commands and their outputs are appended to it as JSON lines
+        """
+        return pulumi.get(self, "record_file")
+
+    @property
+    @pulumi.getter(name="retryInitialDelay")
+    def retry_initial_delay(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi retry initial delay config, doubled on every retry
+        """
+        return pulumi.get(self, "retry_initial_delay")
+
+    @property
+    @pulumi.getter(name="retryMaxDelay")
+    def retry_max_delay(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi retry maximum delay config
+        """
+        return pulumi.get(self, "retry_max_delay")
+
     @property
     @pulumi.getter(name="sshPort")
     def ssh_port(self) -> pulumi.Output[Optional[str]]:
@@ -216,6 +1155,30 @@ class Provider(pulumi.ProviderResource):
         """
         return pulumi.get(self, "ssl_port")
 
+    @property
+    @pulumi.getter(name="sslThumbprint")
+    def ssl_thumbprint(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSL certificate SHA-1 or SHA-256 thumbprint config, verified by the api transport
+        """
+        return pulumi.get(self, "ssl_thumbprint")
+
+    @property
+    @pulumi.getter
+    def transport(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi transport config, ssh runs commands over SSH, api calls the SOAP API of the host
+        """
+        return pulumi.get(self, "transport")
+
+    @property
+    @pulumi.getter(name="trustedHostKeys")
+    def trusted_host_keys(self) -> pulumi.Output[Optional[str]]:
+        """
+        ESXi SSH host key fingerprints recorded by trust on first use, by host:port, as a JSON object
+        """
+        return pulumi.get(self, "trusted_host_keys")
+
     @property
     @pulumi.getter
     def username(self) -> pulumi.Output[Optional[str]]:
@@ -18,6 +18,7 @@ class ResourcePoolArgs:
                  cpu_min: Optional[pulumi.Input[int]] = None,
                  cpu_min_expandable: Optional[pulumi.Input[str]] = None,
                  cpu_shares: Optional[pulumi.Input[str]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mem_max: Optional[pulumi.Input[int]] = None,
                  mem_min: Optional[pulumi.Input[int]] = None,
                  mem_min_expandable: Optional[pulumi.Input[str]] = None,
@@ -29,6 +30,7 @@ class ResourcePoolArgs:
         :param pulumi.Input[int] cpu_min: CPU minimum (in MHz).
         :param pulumi.Input[str] cpu_min_expandable: Can pool borrow CPU resources from parent?
         :param pulumi.Input[str] cpu_shares: CPU shares (low/normal/high/<custom>).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[int] mem_max: Memory maximum (in MB).
         :param pulumi.Input[int] mem_min: Memory minimum (in MB).
         :param pulumi.Input[str] mem_min_expandable: Can pool borrow memory resources from parent?
@@ -49,6 +51,8 @@ class ResourcePoolArgs:
             cpu_shares = 'normal'
         if cpu_shares is not None:
             pulumi.set(__self__, "cpu_shares", cpu_shares)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
         if mem_max is not None:
             pulumi.set(__self__, "mem_max", mem_max)
         if mem_min is None:
@@ -114,6 +118,18 @@ class ResourcePoolArgs:
     def cpu_shares(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "cpu_shares", value)
 
+    @property
+    @pulumi.getter
+    def host(self) -> Optional[pulumi.Input[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
+    @host.setter
+    def host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host", value)
+
     @property
     @pulumi.getter(name="memMax")
     def mem_max(self) -> Optional[pulumi.Input[int]]:
@@ -184,6 +200,7 @@ class ResourcePool(pulumi.CustomResource):
                  cpu_min: Optional[pulumi.Input[int]] = None,
                  cpu_min_expandable: Optional[pulumi.Input[str]] = None,
                  cpu_shares: Optional[pulumi.Input[str]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mem_max: Optional[pulumi.Input[int]] = None,
                  mem_min: Optional[pulumi.Input[int]] = None,
                  mem_min_expandable: Optional[pulumi.Input[str]] = None,
@@ -198,6 +215,7 @@ class ResourcePool(pulumi.CustomResource):
         :param pulumi.Input[int] cpu_min: CPU minimum (in MHz).
         :param pulumi.Input[str] cpu_min_expandable: Can pool borrow CPU resources from parent?
         :param pulumi.Input[str] cpu_shares: CPU shares (low/normal/high/<custom>).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[int] mem_max: Memory maximum (in MB).
         :param pulumi.Input[int] mem_min: Memory minimum (in MB).
         :param pulumi.Input[str] mem_min_expandable: Can pool borrow memory resources from parent?
@@ -231,6 +249,7 @@ class ResourcePool(pulumi.CustomResource):
                  cpu_min: Optional[pulumi.Input[int]] = None,
                  cpu_min_expandable: Optional[pulumi.Input[str]] = None,
                  cpu_shares: Optional[pulumi.Input[str]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  mem_max: Optional[pulumi.Input[int]] = None,
                  mem_min: Optional[pulumi.Input[int]] = None,
                  mem_min_expandable: Optional[pulumi.Input[str]] = None,
@@ -255,6 +274,7 @@ class ResourcePool(pulumi.CustomResource):
             if cpu_shares is None:
                 cpu_shares = 'normal'
             __props__.__dict__["cpu_shares"] = cpu_shares
+            __props__.__dict__["host"] = host
             __props__.__dict__["mem_max"] = mem_max
             if mem_min is None:
                 mem_min = 200
@@ -292,6 +312,7 @@ class ResourcePool(pulumi.CustomResource):
         __props__.__dict__["cpu_min"] = None
         __props__.__dict__["cpu_min_expandable"] = None
         __props__.__dict__["cpu_shares"] = None
+        __props__.__dict__["host"] = None
         __props__.__dict__["mem_max"] = None
         __props__.__dict__["mem_min"] = None
         __props__.__dict__["mem_min_expandable"] = None
@@ -331,6 +352,14 @@ class ResourcePool(pulumi.CustomResource):
         """
         return pulumi.get(self, "cpu_shares")
 
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Output[Optional[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
     @property
     @pulumi.getter(name="memMax")
     def mem_max(self) -> pulumi.Output[Optional[int]]:
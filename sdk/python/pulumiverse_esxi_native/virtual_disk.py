@@ -16,23 +16,28 @@ __all__ = ['VirtualDiskArgs', 'VirtualDisk']
 class VirtualDiskArgs:
     def __init__(__self__, *,
                  directory: pulumi.Input[str],
-                 disk_store: pulumi.Input[str],
                  disk_type: pulumi.Input['DiskType'],
+                 disk_store: Optional[pulumi.Input[str]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  size: Optional[pulumi.Input[int]] = None):
         """
         The set of arguments for constructing a VirtualDisk resource.
         :param pulumi.Input[str] directory: Disk directory.
-        :param pulumi.Input[str] disk_store: Disk Store.
         :param pulumi.Input['DiskType'] disk_type: Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
+        :param pulumi.Input[str] disk_store: Disk Store, defaults to the defaultDiskStore of the provider.
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[str] name: Virtual Disk Name.
         :param pulumi.Input[int] size: Virtual Disk size in GB.
         """
         pulumi.set(__self__, "directory", directory)
-        pulumi.set(__self__, "disk_store", disk_store)
         if disk_type is None:
             disk_type = 'thin'
         pulumi.set(__self__, "disk_type", disk_type)
+        if disk_store is not None:
+            pulumi.set(__self__, "disk_store", disk_store)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
         if name is not None:
             pulumi.set(__self__, "name", name)
         if size is None:
@@ -52,29 +57,41 @@ class VirtualDiskArgs:
     def directory(self, value: pulumi.Input[str]):
         pulumi.set(self, "directory", value)
 
+    @property
+    @pulumi.getter(name="diskType")
+    def disk_type(self) -> pulumi.Input['DiskType']:
+        """
+        Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
+        """
+        return pulumi.get(self, "disk_type")
+
+    @disk_type.setter
+    def disk_type(self, value: pulumi.Input['DiskType']):
+        pulumi.set(self, "disk_type", value)
+
     @property
     @pulumi.getter(name="diskStore")
-    def disk_store(self) -> pulumi.Input[str]:
+    def disk_store(self) -> Optional[pulumi.Input[str]]:
         """
-        Disk Store.
+        Disk Store, defaults to the defaultDiskStore of the provider.
         """
         return pulumi.get(self, "disk_store")
 
     @disk_store.setter
-    def disk_store(self, value: pulumi.Input[str]):
+    def disk_store(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "disk_store", value)
 
     @property
-    @pulumi.getter(name="diskType")
-    def disk_type(self) -> pulumi.Input['DiskType']:
+    @pulumi.getter
+    def host(self) -> Optional[pulumi.Input[str]]:
         """
-        Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         """
-        return pulumi.get(self, "disk_type")
+        return pulumi.get(self, "host")
 
-    @disk_type.setter
-    def disk_type(self, value: pulumi.Input['DiskType']):
-        pulumi.set(self, "disk_type", value)
+    @host.setter
+    def host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host", value)
 
     @property
     @pulumi.getter
@@ -109,6 +126,7 @@ class VirtualDisk(pulumi.CustomResource):
                  directory: Optional[pulumi.Input[str]] = None,
                  disk_store: Optional[pulumi.Input[str]] = None,
                  disk_type: Optional[pulumi.Input['DiskType']] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  size: Optional[pulumi.Input[int]] = None,
                  __props__=None):
@@ -117,8 +135,9 @@ class VirtualDisk(pulumi.CustomResource):
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
         :param pulumi.Input[str] directory: Disk directory.
-        :param pulumi.Input[str] disk_store: Disk Store.
+        :param pulumi.Input[str] disk_store: Disk Store, defaults to the defaultDiskStore of the provider.
         :param pulumi.Input['DiskType'] disk_type: Virtual Disk type. (thin, zeroedthick or eagerzeroedthick)
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[str] name: Virtual Disk Name.
         :param pulumi.Input[int] size: Virtual Disk size in GB.
         """
@@ -148,6 +167,7 @@ class VirtualDisk(pulumi.CustomResource):
                  directory: Optional[pulumi.Input[str]] = None,
                  disk_store: Optional[pulumi.Input[str]] = None,
                  disk_type: Optional[pulumi.Input['DiskType']] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  name: Optional[pulumi.Input[str]] = None,
                  size: Optional[pulumi.Input[int]] = None,
                  __props__=None):
@@ -162,14 +182,13 @@ class VirtualDisk(pulumi.CustomResource):
             if directory is None and not opts.urn:
                 raise TypeError("Missing required property 'directory'")
             __props__.__dict__["directory"] = directory
-            if disk_store is None and not opts.urn:
-                raise TypeError("Missing required property 'disk_store'")
             __props__.__dict__["disk_store"] = disk_store
             if disk_type is None:
                 disk_type = 'thin'
             if disk_type is None and not opts.urn:
                 raise TypeError("Missing required property 'disk_type'")
             __props__.__dict__["disk_type"] = disk_type
+            __props__.__dict__["host"] = host
             __props__.__dict__["name"] = name
             if size is None:
                 size = 1
@@ -199,6 +218,7 @@ class VirtualDisk(pulumi.CustomResource):
         __props__.__dict__["directory"] = None
         __props__.__dict__["disk_store"] = None
         __props__.__dict__["disk_type"] = None
+        __props__.__dict__["host"] = None
         __props__.__dict__["name"] = None
         __props__.__dict__["size"] = None
         return VirtualDisk(resource_name, opts=opts, __props__=__props__)
@@ -227,6 +247,14 @@ class VirtualDisk(pulumi.CustomResource):
         """
         return pulumi.get(self, "disk_type")
 
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Output[Optional[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
     @property
     @pulumi.getter
     def name(self) -> pulumi.Output[str]:
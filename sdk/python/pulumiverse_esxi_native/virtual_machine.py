@@ -17,11 +17,13 @@ __all__ = ['VirtualMachineArgs', 'VirtualMachine']
 @pulumi.input_type
 class VirtualMachineArgs:
     def __init__(__self__, *,
-                 disk_store: pulumi.Input[str],
                  boot_disk_size: Optional[pulumi.Input[int]] = None,
                  boot_disk_type: Optional[pulumi.Input['DiskType']] = None,
                  boot_firmware: Optional[pulumi.Input['BootFirmwareType']] = None,
                  clone_from_virtual_machine: Optional[pulumi.Input[str]] = None,
+                 disk_store: Optional[pulumi.Input[str]] = None,
+                 efi_secure_boot: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  info: Optional[pulumi.Input[Sequence[pulumi.Input['KeyValuePairArgs']]]] = None,
                  mem_size: Optional[pulumi.Input[int]] = None,
                  name: Optional[pulumi.Input[str]] = None,
@@ -40,29 +42,30 @@ class VirtualMachineArgs:
                  virtual_hw_ver: Optional[pulumi.Input[int]] = None):
         """
         The set of arguments for constructing a VirtualMachine resource.
-        :param pulumi.Input[str] disk_store: esxi diskstore for boot disk.
         :param pulumi.Input[int] boot_disk_size: VM boot disk size. Will expand boot disk to this size.
         :param pulumi.Input['DiskType'] boot_disk_type: VM boot disk type. thin, zeroedthick, eagerzeroedthick
         :param pulumi.Input['BootFirmwareType'] boot_firmware: Boot type('efi' is boot uefi mode)
         :param pulumi.Input[str] clone_from_virtual_machine: Source vm path on esxi host to clone.
+        :param pulumi.Input[str] disk_store: esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+        :param pulumi.Input[bool] efi_secure_boot: Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[Sequence[pulumi.Input['KeyValuePairArgs']]] info: pass data to VM
         :param pulumi.Input[int] mem_size: VM memory size.
         :param pulumi.Input[str] name: esxi vm name.
-        :param pulumi.Input[Sequence[pulumi.Input['NetworkInterfaceArgs']]] network_interfaces: VM network interfaces.
+        :param pulumi.Input[Sequence[pulumi.Input['NetworkInterfaceArgs']]] network_interfaces: VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
         :param pulumi.Input[str] notes: VM memory size.
         :param pulumi.Input[int] num_v_cpus: VM number of virtual cpus.
-        :param pulumi.Input[str] os: VM OS type.
+        :param pulumi.Input[str] os: VM OS type, defaults to the defaultOs of the provider or to 'centos'.
         :param pulumi.Input[Sequence[pulumi.Input['KeyValuePairArgs']]] ovf_properties: VM OVF properties.
         :param pulumi.Input[int] ovf_properties_timer: The amount of time, in seconds, to wait for the guest to boot and run ovfProperties. (0-6000)
         :param pulumi.Input[str] ovf_source: Path or URL of ovf file source.
         :param pulumi.Input[str] power: VM power state.
-        :param pulumi.Input[str] resource_pool_name: Resource pool name to place vm.
+        :param pulumi.Input[str] resource_pool_name: Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
         :param pulumi.Input[int] shutdown_timeout: The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
         :param pulumi.Input[int] startup_timeout: The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
         :param pulumi.Input[Sequence[pulumi.Input['VMVirtualDiskArgs']]] virtual_disks: VM virtual disks.
-        :param pulumi.Input[int] virtual_hw_ver: VM Virtual HW version.
+        :param pulumi.Input[int] virtual_hw_ver: VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
         """
-        pulumi.set(__self__, "disk_store", disk_store)
         if boot_disk_size is None:
             boot_disk_size = 16
         if boot_disk_size is not None:
@@ -77,6 +80,14 @@ class VirtualMachineArgs:
             pulumi.set(__self__, "boot_firmware", boot_firmware)
         if clone_from_virtual_machine is not None:
             pulumi.set(__self__, "clone_from_virtual_machine", clone_from_virtual_machine)
+        if disk_store is not None:
+            pulumi.set(__self__, "disk_store", disk_store)
+        if efi_secure_boot is None:
+            efi_secure_boot = False
+        if efi_secure_boot is not None:
+            pulumi.set(__self__, "efi_secure_boot", efi_secure_boot)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
         if info is not None:
             pulumi.set(__self__, "info", info)
         if mem_size is None:
@@ -93,8 +104,6 @@ class VirtualMachineArgs:
             num_v_cpus = 1
         if num_v_cpus is not None:
             pulumi.set(__self__, "num_v_cpus", num_v_cpus)
-        if os is None:
-            os = 'centos'
         if os is not None:
             pulumi.set(__self__, "os", os)
         if ovf_properties is not None:
@@ -107,8 +116,6 @@ class VirtualMachineArgs:
             pulumi.set(__self__, "ovf_source", ovf_source)
         if power is not None:
             pulumi.set(__self__, "power", power)
-        if resource_pool_name is None:
-            resource_pool_name = '/'
         if resource_pool_name is not None:
             pulumi.set(__self__, "resource_pool_name", resource_pool_name)
         if shutdown_timeout is None:
@@ -121,23 +128,9 @@ class VirtualMachineArgs:
             pulumi.set(__self__, "startup_timeout", startup_timeout)
         if virtual_disks is not None:
             pulumi.set(__self__, "virtual_disks", virtual_disks)
-        if virtual_hw_ver is None:
-            virtual_hw_ver = 13
         if virtual_hw_ver is not None:
             pulumi.set(__self__, "virtual_hw_ver", virtual_hw_ver)
 
-    @property
-    @pulumi.getter(name="diskStore")
-    def disk_store(self) -> pulumi.Input[str]:
-        """
-        esxi diskstore for boot disk.
-        """
-        return pulumi.get(self, "disk_store")
-
-    @disk_store.setter
-    def disk_store(self, value: pulumi.Input[str]):
-        pulumi.set(self, "disk_store", value)
-
     @property
     @pulumi.getter(name="bootDiskSize")
     def boot_disk_size(self) -> Optional[pulumi.Input[int]]:
@@ -186,6 +179,42 @@ class VirtualMachineArgs:
     def clone_from_virtual_machine(self, value: Optional[pulumi.Input[str]]):
         pulumi.set(self, "clone_from_virtual_machine", value)
 
+    @property
+    @pulumi.getter(name="diskStore")
+    def disk_store(self) -> Optional[pulumi.Input[str]]:
+        """
+        esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+        """
+        return pulumi.get(self, "disk_store")
+
+    @disk_store.setter
+    def disk_store(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "disk_store", value)
+
+    @property
+    @pulumi.getter(name="efiSecureBoot")
+    def efi_secure_boot(self) -> Optional[pulumi.Input[bool]]:
+        """
+        Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        """
+        return pulumi.get(self, "efi_secure_boot")
+
+    @efi_secure_boot.setter
+    def efi_secure_boot(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "efi_secure_boot", value)
+
+    @property
+    @pulumi.getter
+    def host(self) -> Optional[pulumi.Input[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
+    @host.setter
+    def host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host", value)
+
     @property
     @pulumi.getter
     def info(self) -> Optional[pulumi.Input[Sequence[pulumi.Input['KeyValuePairArgs']]]]:
@@ -226,7 +255,7 @@ class VirtualMachineArgs:
     @pulumi.getter(name="networkInterfaces")
     def network_interfaces(self) -> Optional[pulumi.Input[Sequence[pulumi.Input['NetworkInterfaceArgs']]]]:
         """
-        VM network interfaces.
+        VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
         """
         return pulumi.get(self, "network_interfaces")
 
@@ -262,7 +291,7 @@ class VirtualMachineArgs:
     @pulumi.getter
     def os(self) -> Optional[pulumi.Input[str]]:
         """
-        VM OS type.
+        VM OS type, defaults to the defaultOs of the provider or to 'centos'.
         """
         return pulumi.get(self, "os")
 
@@ -322,7 +351,7 @@ class VirtualMachineArgs:
     @pulumi.getter(name="resourcePoolName")
     def resource_pool_name(self) -> Optional[pulumi.Input[str]]:
         """
-        Resource pool name to place vm.
+        Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
         """
         return pulumi.get(self, "resource_pool_name")
 
@@ -370,7 +399,7 @@ class VirtualMachineArgs:
     @pulumi.getter(name="virtualHWVer")
     def virtual_hw_ver(self) -> Optional[pulumi.Input[int]]:
         """
-        VM Virtual HW version.
+        VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
         """
         return pulumi.get(self, "virtual_hw_ver")
 
@@ -389,6 +418,8 @@ class VirtualMachine(pulumi.CustomResource):
                  boot_firmware: Optional[pulumi.Input['BootFirmwareType']] = None,
                  clone_from_virtual_machine: Optional[pulumi.Input[str]] = None,
                  disk_store: Optional[pulumi.Input[str]] = None,
+                 efi_secure_boot: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  info: Optional[pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['KeyValuePairArgs']]]]] = None,
                  mem_size: Optional[pulumi.Input[int]] = None,
                  name: Optional[pulumi.Input[str]] = None,
@@ -414,29 +445,31 @@ class VirtualMachine(pulumi.CustomResource):
         :param pulumi.Input['DiskType'] boot_disk_type: VM boot disk type. thin, zeroedthick, eagerzeroedthick
         :param pulumi.Input['BootFirmwareType'] boot_firmware: Boot type('efi' is boot uefi mode)
         :param pulumi.Input[str] clone_from_virtual_machine: Source vm path on esxi host to clone.
-        :param pulumi.Input[str] disk_store: esxi diskstore for boot disk.
+        :param pulumi.Input[str] disk_store: esxi diskstore for boot disk, defaults to the defaultDiskStore of the provider.
+        :param pulumi.Input[bool] efi_secure_boot: Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['KeyValuePairArgs']]]] info: pass data to VM
         :param pulumi.Input[int] mem_size: VM memory size.
         :param pulumi.Input[str] name: esxi vm name.
-        :param pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['NetworkInterfaceArgs']]]] network_interfaces: VM network interfaces.
+        :param pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['NetworkInterfaceArgs']]]] network_interfaces: VM network interfaces, defaults to an interface on the defaultNetwork of the provider when it is set.
         :param pulumi.Input[str] notes: VM memory size.
         :param pulumi.Input[int] num_v_cpus: VM number of virtual cpus.
-        :param pulumi.Input[str] os: VM OS type.
+        :param pulumi.Input[str] os: VM OS type, defaults to the defaultOs of the provider or to 'centos'.
         :param pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['KeyValuePairArgs']]]] ovf_properties: VM OVF properties.
         :param pulumi.Input[int] ovf_properties_timer: The amount of time, in seconds, to wait for the guest to boot and run ovfProperties. (0-6000)
         :param pulumi.Input[str] ovf_source: Path or URL of ovf file source.
         :param pulumi.Input[str] power: VM power state.
-        :param pulumi.Input[str] resource_pool_name: Resource pool name to place vm.
+        :param pulumi.Input[str] resource_pool_name: Resource pool name to place vm, defaults to the defaultResourcePool of the provider or to '/'.
         :param pulumi.Input[int] shutdown_timeout: The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
         :param pulumi.Input[int] startup_timeout: The amount of vm uptime, in seconds, to wait for an available IP address on this virtual machine. (0-600)
         :param pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['VMVirtualDiskArgs']]]] virtual_disks: VM virtual disks.
-        :param pulumi.Input[int] virtual_hw_ver: VM Virtual HW version.
+        :param pulumi.Input[int] virtual_hw_ver: VM Virtual HW version, defaults to the defaultHardwareVersion of the provider or to 13.
         """
         ...
     @overload
     def __init__(__self__,
                  resource_name: str,
-                 args: VirtualMachineArgs,
+                 args: Optional[VirtualMachineArgs] = None,
                  opts: Optional[pulumi.ResourceOptions] = None):
         """
         Create a VirtualMachine resource with the given unique name, props, and options.
@@ -460,6 +493,8 @@ class VirtualMachine(pulumi.CustomResource):
                  boot_firmware: Optional[pulumi.Input['BootFirmwareType']] = None,
                  clone_from_virtual_machine: Optional[pulumi.Input[str]] = None,
                  disk_store: Optional[pulumi.Input[str]] = None,
+                 efi_secure_boot: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  info: Optional[pulumi.Input[Sequence[pulumi.Input[pulumi.InputType['KeyValuePairArgs']]]]] = None,
                  mem_size: Optional[pulumi.Input[int]] = None,
                  name: Optional[pulumi.Input[str]] = None,
@@ -495,9 +530,11 @@ class VirtualMachine(pulumi.CustomResource):
                 boot_firmware = 'bios'
             __props__.__dict__["boot_firmware"] = boot_firmware
             __props__.__dict__["clone_from_virtual_machine"] = clone_from_virtual_machine
-            if disk_store is None and not opts.urn:
-                raise TypeError("Missing required property 'disk_store'")
             __props__.__dict__["disk_store"] = disk_store
+            if efi_secure_boot is None:
+                efi_secure_boot = False
+            __props__.__dict__["efi_secure_boot"] = efi_secure_boot
+            __props__.__dict__["host"] = host
             __props__.__dict__["info"] = info
             if mem_size is None:
                 mem_size = 512
@@ -508,8 +545,6 @@ class VirtualMachine(pulumi.CustomResource):
             if num_v_cpus is None:
                 num_v_cpus = 1
             __props__.__dict__["num_v_cpus"] = num_v_cpus
-            if os is None:
-                os = 'centos'
             __props__.__dict__["os"] = os
             __props__.__dict__["ovf_properties"] = ovf_properties
             if ovf_properties_timer is None:
@@ -517,8 +552,6 @@ class VirtualMachine(pulumi.CustomResource):
             __props__.__dict__["ovf_properties_timer"] = ovf_properties_timer
             __props__.__dict__["ovf_source"] = ovf_source
             __props__.__dict__["power"] = power
-            if resource_pool_name is None:
-                resource_pool_name = '/'
             __props__.__dict__["resource_pool_name"] = resource_pool_name
             if shutdown_timeout is None:
                 shutdown_timeout = 600
@@ -527,8 +560,6 @@ class VirtualMachine(pulumi.CustomResource):
                 startup_timeout = 600
             __props__.__dict__["startup_timeout"] = startup_timeout
             __props__.__dict__["virtual_disks"] = virtual_disks
-            if virtual_hw_ver is None:
-                virtual_hw_ver = 13
             __props__.__dict__["virtual_hw_ver"] = virtual_hw_ver
             __props__.__dict__["ip_address"] = None
         super(VirtualMachine, __self__).__init__(
@@ -557,6 +588,8 @@ class VirtualMachine(pulumi.CustomResource):
         __props__.__dict__["boot_disk_type"] = None
         __props__.__dict__["boot_firmware"] = None
         __props__.__dict__["disk_store"] = None
+        __props__.__dict__["efi_secure_boot"] = None
+        __props__.__dict__["host"] = None
         __props__.__dict__["info"] = None
         __props__.__dict__["ip_address"] = None
         __props__.__dict__["mem_size"] = None
@@ -605,6 +638,22 @@ class VirtualMachine(pulumi.CustomResource):
         """
         return pulumi.get(self, "disk_store")
 
+    @property
+    @pulumi.getter(name="efiSecureBoot")
+    def efi_secure_boot(self) -> pulumi.Output[Optional[bool]]:
+        """
+        Enable the UEFI secure boot, requires the efi boot firmware and virtualHWVer 13 or later.
+        """
+        return pulumi.get(self, "efi_secure_boot")
+
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Output[Optional[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
     @property
     @pulumi.getter
     def info(self) -> pulumi.Output[Optional[Sequence['outputs.KeyValuePair']]]:
@@ -17,6 +17,7 @@ __all__ = ['VirtualSwitchArgs', 'VirtualSwitch']
 class VirtualSwitchArgs:
     def __init__(__self__, *,
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  link_discovery_mode: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  mtu: Optional[pulumi.Input[int]] = None,
@@ -27,6 +28,7 @@ class VirtualSwitchArgs:
         """
         The set of arguments for constructing a VirtualSwitch resource.
         :param pulumi.Input[bool] forged_transmits: Forged transmits (true=Accept/false=Reject).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[str] link_discovery_mode: Virtual Switch Link Discovery Mode.
         :param pulumi.Input[bool] mac_changes: MAC address changes (true=Accept/false=Reject).
         :param pulumi.Input[int] mtu: Virtual Switch mtu. (1280-9000)
@@ -37,6 +39,8 @@ class VirtualSwitchArgs:
         """
         if forged_transmits is not None:
             pulumi.set(__self__, "forged_transmits", forged_transmits)
+        if host is not None:
+            pulumi.set(__self__, "host", host)
         if link_discovery_mode is not None:
             pulumi.set(__self__, "link_discovery_mode", link_discovery_mode)
         if mac_changes is not None:
@@ -64,6 +68,18 @@ class VirtualSwitchArgs:
     def forged_transmits(self, value: Optional[pulumi.Input[bool]]):
         pulumi.set(self, "forged_transmits", value)
 
+    @property
+    @pulumi.getter
+    def host(self) -> Optional[pulumi.Input[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
+    @host.setter
+    def host(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "host", value)
+
     @property
     @pulumi.getter(name="linkDiscoveryMode")
     def link_discovery_mode(self) -> Optional[pulumi.Input[str]]:
@@ -155,6 +171,7 @@ class VirtualSwitch(pulumi.CustomResource):
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  link_discovery_mode: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  mtu: Optional[pulumi.Input[int]] = None,
@@ -168,6 +185,7 @@ class VirtualSwitch(pulumi.CustomResource):
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
         :param pulumi.Input[bool] forged_transmits: Forged transmits (true=Accept/false=Reject).
+        :param pulumi.Input[str] host: Name of the host of the hosts provider config the resource is managed on, the default host when not set.
         :param pulumi.Input[str] link_discovery_mode: Virtual Switch Link Discovery Mode.
         :param pulumi.Input[bool] mac_changes: MAC address changes (true=Accept/false=Reject).
         :param pulumi.Input[int] mtu: Virtual Switch mtu. (1280-9000)
@@ -200,6 +218,7 @@ class VirtualSwitch(pulumi.CustomResource):
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
                  forged_transmits: Optional[pulumi.Input[bool]] = None,
+                 host: Optional[pulumi.Input[str]] = None,
                  link_discovery_mode: Optional[pulumi.Input[str]] = None,
                  mac_changes: Optional[pulumi.Input[bool]] = None,
                  mtu: Optional[pulumi.Input[int]] = None,
@@ -217,6 +236,7 @@ class VirtualSwitch(pulumi.CustomResource):
             __props__ = VirtualSwitchArgs.__new__(VirtualSwitchArgs)
 
             __props__.__dict__["forged_transmits"] = forged_transmits
+            __props__.__dict__["host"] = host
             __props__.__dict__["link_discovery_mode"] = link_discovery_mode
             __props__.__dict__["mac_changes"] = mac_changes
             __props__.__dict__["mtu"] = mtu
@@ -247,6 +267,7 @@ class VirtualSwitch(pulumi.CustomResource):
         __props__ = VirtualSwitchArgs.__new__(VirtualSwitchArgs)
 
         __props__.__dict__["forged_transmits"] = None
+        __props__.__dict__["host"] = None
         __props__.__dict__["link_discovery_mode"] = None
         __props__.__dict__["mac_changes"] = None
         __props__.__dict__["mtu"] = None
@@ -264,6 +285,14 @@ class VirtualSwitch(pulumi.CustomResource):
         """
         return pulumi.get(self, "forged_transmits")
 
+    @property
+    @pulumi.getter
+    def host(self) -> pulumi.Output[Optional[str]]:
+        """
+        Name of the host of the hosts provider config the resource is managed on, the default host when not set.
+        """
+        return pulumi.get(self, "host")
+
     @property
     @pulumi.getter(name="linkDiscoveryMode")
     def link_discovery_mode(self) -> pulumi.Output[Optional[str]]: